@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+const youtubeProvider = "youtube"
+
+// youtubeTokenKey names the stored token of a YouTube account;
+// the unnamed default account keeps the original "youtube" entry.
+func youtubeTokenKey(account string) string {
+	if account == "" {
+		return youtubeProvider
+	}
+	return youtubeProvider + "-" + account
+}
+
+func tokenStore(cfg config.Config) oauth.TokenStore {
+	if cfg.TokenStore == config.TokenStoreKeyring {
+		return oauth.NewKeyringStorage("feedmix")
+	}
+	return oauth.NewTokenStorage(cfg.Dir)
+}
+
+// tokenStoreLocation describes where the token stored under key lives.
+func tokenStoreLocation(cfg config.Config, key string) string {
+	if cfg.TokenStore == config.TokenStoreKeyring {
+		return "the OS keyring"
+	}
+	return filepath.Join(cfg.Dir, key+"_token.json")
+}
+
+// youtubeToken returns the token to start from for account: for the default
+// account FEEDMIX_YOUTUBE_REFRESH_TOKEN when set, otherwise the token saved by
+// 'feedmix auth youtube'. stored reports whether it came from the token
+// store, in which case refreshed tokens are written back. It returns a nil
+// token when neither exists.
+func youtubeToken(cfg config.Config, account string) (token *oauth.Token, stored bool, err error) {
+	if account == "" && cfg.YouTube.RefreshToken != "" {
+		return &oauth.Token{RefreshToken: cfg.YouTube.RefreshToken}, false, nil
+	}
+	token, err = tokenStore(cfg).Load(youtubeTokenKey(account))
+	if errors.Is(err, oauth.ErrTokenNotFound) || (err == nil && token.RefreshToken == "") {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return token, true, nil
+}
+
+// youtubeAccounts returns the accounts to aggregate: the selected ones, or
+// every configured account, or the single unnamed account ("").
+func youtubeAccounts(cfg config.Config, selected []string) ([]string, error) {
+	if len(selected) == 0 {
+		if len(cfg.YouTube.Accounts) == 0 {
+			return []string{""}, nil
+		}
+		return cfg.YouTube.Accounts, nil
+	}
+	for _, account := range selected {
+		if !containsString(cfg.YouTube.Accounts, account) {
+			return nil, fmt.Errorf("unknown YouTube account %q: add it to FEEDMIX_YOUTUBE_ACCOUNTS", account)
+		}
+	}
+	return selected, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func youtubeOAuthConfig(cfg config.Config) oauth.Config {
+	oauthConfig := oauth.YouTubeOAuthConfig(
+		resolveCredential(cfg.YouTube.ClientID, clientID),
+		resolveCredential(cfg.YouTube.ClientSecret, clientSecret),
+	)
+	if cfg.YouTube.TokenURL != "" {
+		oauthConfig.TokenURL = cfg.YouTube.TokenURL
+	}
+	if cfg.YouTube.DeviceURL != "" {
+		oauthConfig.DeviceAuthURL = cfg.YouTube.DeviceURL
+	}
+	return oauthConfig
+}
+
+// verifyPastedToken reads a refresh token from stdin and checks it with the token endpoint.
+func verifyPastedToken(cmd *cobra.Command, flow *oauth.Flow) (*oauth.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	fmt.Fprint(cmd.ErrOrStderr(), "Paste your YouTube refresh token: ")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	refreshToken := strings.TrimSpace(line)
+	if refreshToken == "" {
+		if err != nil {
+			return nil, fmt.Errorf("failed to read refresh token: %w", err)
+		}
+		return nil, fmt.Errorf("no refresh token given")
+	}
+
+	token, err := flow.RefreshAccessToken(ctx, refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token was rejected: %w", err)
+	}
+	token.RefreshToken = refreshToken
+	return token, nil
+}
+
+// authorizeDevice runs the device authorization grant, waiting until the
+// user approves on another device or the code expires.
+func authorizeDevice(cmd *cobra.Command, flow *oauth.Flow) (*oauth.Token, error) {
+	code, err := flow.RequestDeviceCode(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	expiresIn := time.Duration(code.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 30 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), expiresIn)
+	defer cancel()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "On any device, open %s and enter code: %s\nWaiting for authorization...\n", code.VerificationURL, code.UserCode)
+	token, err := flow.PollDeviceToken(ctx, code)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, oauth.ErrDeviceCodeExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	return token, nil
+}
+
+func newAuthCmd() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Store credentials for a source",
+	}
+
+	var device bool
+	var account string
+	youtubeCmd := &cobra.Command{
+		Use:   "youtube",
+		Short: "Authorize YouTube access and store the token",
+		Long: "Reads a YouTube refresh token (see 'feedmix config' for how to get one) from stdin, checks it with Google " +
+			"and saves it to the token store selected by FEEDMIX_TOKEN_STORE (file or keyring), " +
+			"so it no longer needs to live in your shell profile.\n\n" +
+			"With --device, feedmix instead prints a code and URL to open on any other device, which works over SSH " +
+			"and on headless servers. Device authorization needs an OAuth client of type \"TVs and Limited Input devices\".\n\n" +
+			"With --account, the token is stored for one of the accounts listed in FEEDMIX_YOUTUBE_ACCOUNTS.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if account != "" {
+				if _, err := youtubeAccounts(cfg, []string{account}); err != nil {
+					return err
+				}
+			}
+
+			var token *oauth.Token
+			if device {
+				token, err = authorizeDevice(cmd, oauth.NewFlow(youtubeOAuthConfig(cfg)))
+			} else {
+				token, err = verifyPastedToken(cmd, oauth.NewFlow(youtubeOAuthConfig(cfg)))
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := tokenStore(cfg).Save(youtubeTokenKey(account), token); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "YouTube token saved to %s\n", tokenStoreLocation(cfg, youtubeTokenKey(account)))
+			return nil
+		},
+	}
+	youtubeCmd.Flags().StringVar(&account, "account", "", "Named account from FEEDMIX_YOUTUBE_ACCOUNTS to store the token for")
+	youtubeCmd.Flags().BoolVar(&device, "device", false, "Authorize with a code entered on another device (for SSH and headless machines)")
+	authCmd.AddCommand(youtubeCmd)
+	authCmd.AddCommand(newAuthTwitchCmd())
+
+	return authCmd
+}
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var binaryPath string
@@ -22,6 +28,9 @@ func TestMain(m *testing.M) {
 	defer func() { _ = os.RemoveAll(dir) }()
 
 	binaryPath = filepath.Join(dir, "feedmix")
+	// Keep state written by feed runs (e.g. quota usage) out of the real home directory.
+	_ = os.Setenv("FEEDMIX_CONFIG_DIR", filepath.Join(dir, "config"))
+	_ = os.Setenv("FEEDMIX_CACHE_DIR", filepath.Join(dir, "cache"))
 
 	versionCmd := exec.Command("git", "describe", "--tags", "--always", "--dirty")
 	versionOutput, err := versionCmd.Output()
@@ -212,7 +221,7 @@ func TestFeedCommand_AggregatesMultipleChannels(t *testing.T) {
 }
 
 const substackRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
   <channel>
     <title>Test Newsletter</title>
     <item>
@@ -221,6 +230,7 @@ const substackRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
       <dc:creator>Test Author</dc:creator>
       <pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate>
       <description>An interesting article.</description>
+      <content:encoded><![CDATA[<p>The whole of an interesting article.</p>]]></content:encoded>
       <guid>https://testnewsletter.substack.com/p/my-article</guid>
     </item>
   </channel>
@@ -253,6 +263,56 @@ func TestFeedCommand_ShowsSubstackItems(t *testing.T) {
 	}
 }
 
+// TestFeedCommand_LayoutFlagsOverrideConfig documents configurable item layout:
+// - FEEDMIX_SHOW_DESCRIPTION=false hides descriptions by default
+// - feed --description shows them anyway
+func TestFeedCommand_LayoutFlagsOverrideConfig(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_SHOW_DESCRIPTION"] = "false"
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--no-cache")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if strings.Contains(stdout, "An interesting article.") {
+		t.Errorf("description should be hidden when FEEDMIX_SHOW_DESCRIPTION=false, got: %s", stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, env, "feed", "--no-cache", "--description")
+	if exitCode != 0 {
+		t.Fatalf("feed --description should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "An interesting article.") {
+		t.Errorf("--description should override the configured layout, got: %s", stdout)
+	}
+
+	stdout, _, _ = runCLI(t, env, "feed", "--no-cache", "--compact")
+	if !strings.Contains(stdout, "✉ ") || !strings.Contains(stdout, " My Substack Article\n") || strings.Contains(stdout, "https://") {
+		t.Errorf("--compact should show the item on one line without its URL, got: %q", stdout)
+	}
+
+	stdout, _, _ = runCLI(t, env, "feed", "--no-cache", "--hyperlinks", "always")
+	if !strings.Contains(stdout, "\x1b]8;;https://testnewsletter.substack.com/p/my-article\x1b\\") {
+		t.Errorf("--hyperlinks always should link titles with OSC 8, got: %q", stdout)
+	}
+	if _, stderr, exitCode = runCLI(t, env, "feed", "--hyperlinks", "sometimes"); exitCode == 0 || !strings.Contains(stderr, "--hyperlinks") {
+		t.Errorf("an invalid --hyperlinks mode should be rejected, got exit %d: %s", exitCode, stderr)
+	}
+}
+
 // TestFeedCommand_WorksWithoutSubstack documents optional Substack integration:
 // - FEEDMIX_SUBSTACK_URLS not set → feed runs normally, no error
 func TestFeedCommand_WorksWithoutSubstack(t *testing.T) {
@@ -424,3 +484,1421 @@ func TestFeedCommand_DisplaysVideoURLs(t *testing.T) {
 		t.Errorf("feed should NOT display channel URL %q (should show videos instead), got: %s", channelURL, stdout)
 	}
 }
+
+func TestRootCommand_ProfileFlagWritesProfile(t *testing.T) {
+	for _, mode := range []string{"cpu", "mem", "trace"} {
+		out := filepath.Join(t.TempDir(), mode+".prof")
+		_, stderr, exitCode := runCLI(t, nil, "config", "--profile", mode, "--profile-out", out)
+		if exitCode != 0 {
+			t.Fatalf("config with --profile %s should succeed, got exit code %d\nstderr: %s", mode, exitCode, stderr)
+		}
+		info, err := os.Stat(out)
+		if err != nil {
+			t.Fatalf("--profile %s should write a profile file: %v", mode, err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("--profile %s should write a non-empty profile", mode)
+		}
+	}
+}
+
+func TestRootCommand_ProfileFlagWritesProfileWhenCommandFails(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "trace.prof")
+	env := map[string]string{"FEEDMIX_CACHE_DIR": t.TempDir()}
+	if _, _, exitCode := runCLI(t, env, "open", "1", "--profile", "trace", "--profile-out", out); exitCode == 0 {
+		t.Fatal("open should fail before any feed was displayed")
+	}
+	if info, err := os.Stat(out); err != nil || info.Size() == 0 {
+		t.Errorf("a failed command should still flush its profile, got %v", err)
+	}
+}
+
+func TestRootCommand_ProfileFlagIsHidden(t *testing.T) {
+	stdout, _, _ := runCLI(t, nil, "--help")
+	if strings.Contains(stdout, "--profile") {
+		t.Errorf("--profile is a maintainer flag and should not appear in help, got: %s", stdout)
+	}
+}
+
+func TestRootCommand_ProfileFlagRejectsUnknownMode(t *testing.T) {
+	_, stderr, exitCode := runCLI(t, nil, "config", "--profile", "block")
+	if exitCode == 0 {
+		t.Error("unknown profile mode should fail")
+	}
+	if !strings.Contains(stderr, "cpu, mem or trace") {
+		t.Errorf("error should list supported profile modes, got: %s", stderr)
+	}
+}
+
+func TestFeedCommand_HonorsPerChannelFetchLimits(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]string{}
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/subscriptions") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_busy"}, "title": "Busy"}},
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_quiet"}, "title": "Quiet"}},
+				},
+			})
+			return
+		}
+		if strings.Contains(r.URL.Path, "/search") {
+			mu.Lock()
+			requested[r.URL.Query().Get("channelId")] = r.URL.Query().Get("maxResults")
+			mu.Unlock()
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_YOUTUBE_FETCH_LIMIT"] = "3"
+	env["FEEDMIX_FETCH_LIMITS"] = "UC_busy=10"
+
+	_, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if requested["UC_busy"] != "10" {
+		t.Errorf("overridden channel should request 10 videos, got %q", requested["UC_busy"])
+	}
+	if requested["UC_quiet"] != "3" {
+		t.Errorf("other channels should use FEEDMIX_YOUTUBE_FETCH_LIMIT, got %q", requested["UC_quiet"])
+	}
+}
+
+func TestFeedCommand_RejectsInvalidFetchLimit(t *testing.T) {
+	env := map[string]string{"FEEDMIX_YOUTUBE_REFRESH_TOKEN": "token", "FEEDMIX_YOUTUBE_FETCH_LIMIT": "many"}
+	_, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode == 0 {
+		t.Error("feed should fail on an invalid fetch limit")
+	}
+	if !strings.Contains(stderr, "FEEDMIX_YOUTUBE_FETCH_LIMIT") {
+		t.Errorf("error should name the invalid variable, got: %s", stderr)
+	}
+}
+
+func TestFeedCommand_RecordsLifecycleEventsWhenEventLogConfigured(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "events.jsonl")
+	env := feedEnv(server)
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_EVENT_LOG"] = logPath
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+
+	for run := 0; run < 2; run++ {
+		_, stderr, exitCode := runCLI(t, env, "feed")
+		if exitCode != 0 {
+			t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+		}
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("event log should be written: %v", err)
+	}
+	log := string(data)
+	if !strings.Contains(log, `"type":"discovered"`) || !strings.Contains(log, `"type":"displayed"`) {
+		t.Errorf("event log should contain discovered and displayed events, got: %s", log)
+	}
+	if discovered, displayed := strings.Count(log, `"type":"discovered"`), strings.Count(log, `"type":"displayed"`); discovered*2 != displayed {
+		t.Errorf("a second run should not rediscover the same items, got %d discovered and %d displayed events", discovered, displayed)
+	}
+}
+
+func TestFeedCommand_ShowQuotaReportsEstimatedUsage(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/subscriptions") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_A"}, "title": "Channel A"}},
+				},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_YOUTUBE_QUOTA_BUDGET"] = "150"
+	env["FEEDMIX_YOUTUBE_CACHE_TTL"] = "0"
+
+	_, stderr, exitCode := runCLI(t, env, "feed", "--show-quota")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stderr, "102 units this run, 102/150 used today") {
+		t.Errorf("user should see subscriptions (1) + channel handles (1) + one channel search (100) counted, got: %s", stderr)
+	}
+
+	_, stderr, _ = runCLI(t, env, "feed")
+	if !strings.Contains(stderr, "only 48 of today's 150 remain") {
+		t.Errorf("user should be warned before a run that would exceed the budget, got: %s", stderr)
+	}
+
+	stdout, _, exitCode := runCLI(t, env, "quota")
+	if exitCode != 0 || !strings.Contains(stdout, "203 / 150 units") {
+		t.Errorf("quota command should report today's usage, with handles served from their cache on the second run, got: %s", stdout)
+	}
+}
+
+func TestFeedCommand_ReusesCachedResponsesUnlessNoCache(t *testing.T) {
+	var mu sync.Mutex
+	apiCalls := 0
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		apiCalls++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+
+	for _, args := range [][]string{{"feed"}, {"feed"}} {
+		if _, stderr, exitCode := runCLI(t, env, args...); exitCode != 0 {
+			t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+		}
+	}
+	calls := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return apiCalls
+	}
+	if n := calls(); n != 1 {
+		t.Errorf("a repeated run within the TTL should not call the API again, got %d calls", n)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--no-cache"); exitCode != 0 {
+		t.Fatalf("feed --no-cache should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if n := calls(); n != 2 {
+		t.Errorf("--no-cache should fetch fresh responses, got %d calls", n)
+	}
+}
+
+func TestAuthCommand_StoresRefreshTokenForFeed(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	configDir := t.TempDir()
+	env := feedEnv(server)
+	env["FEEDMIX_YOUTUBE_REFRESH_TOKEN"] = ""
+	env["FEEDMIX_CONFIG_DIR"] = configDir
+
+	cmd := exec.Command(binaryPath, "auth", "youtube")
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin = strings.NewReader("1//stored-refresh-token\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("auth youtube should succeed, got %v: %s", err, out)
+	}
+
+	info, err := os.Stat(filepath.Join(configDir, "youtube_token.json"))
+	if err != nil {
+		t.Fatalf("token should be saved in the config directory: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("token file should be private (0600), got %v", info.Mode().Perm())
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Errorf("feed should use the stored refresh token, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_ReusesStoredAccessTokenUntilExpiry(t *testing.T) {
+	var mu sync.Mutex
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			mu.Lock()
+			refreshes++
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "fresh", "token_type": "Bearer", "expires_in": 3600})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	}))
+	defer server.Close()
+
+	configDir := t.TempDir()
+	token := `{"access_token":"stored","refresh_token":"1//stored","expiry":"` + time.Now().Add(-time.Minute).Format(time.RFC3339) + `"}`
+	if err := os.WriteFile(filepath.Join(configDir, "youtube_token.json"), []byte(token), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := feedEnv(server)
+	env["FEEDMIX_YOUTUBE_REFRESH_TOKEN"] = ""
+	env["FEEDMIX_CONFIG_DIR"] = configDir
+	env["FEEDMIX_YOUTUBE_CACHE_TTL"] = "0"
+
+	for i := 0; i < 2; i++ {
+		if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+			t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if refreshes != 1 {
+		t.Errorf("an expired stored token should be refreshed once and the new one reused, got %d refreshes", refreshes)
+	}
+	data, _ := os.ReadFile(filepath.Join(configDir, "youtube_token.json"))
+	if !strings.Contains(string(data), `"access_token":"fresh"`) || !strings.Contains(string(data), `"refresh_token":"1//stored"`) {
+		t.Errorf("refreshed token should be persisted with the original refresh token, got: %s", data)
+	}
+}
+
+func TestConfigCommand_ListsSubstackHeaderNamesNotValues(t *testing.T) {
+	env := map[string]string{
+		"FEEDMIX_SUBSTACK_URLS":    "https://paid.substack.com",
+		"FEEDMIX_SUBSTACK_HEADERS": "https://paid.substack.com Cookie: substack.sid=top-secret",
+	}
+	stdout, stderr, exitCode := runCLI(t, env, "config")
+	if exitCode != 0 {
+		t.Fatalf("config should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "headers: Cookie") {
+		t.Errorf("should show which headers are sent, got: %s", stdout)
+	}
+	if strings.Contains(stdout, "top-secret") {
+		t.Errorf("header values may be secrets and must not be printed, got: %s", stdout)
+	}
+}
+
+func TestAuthCommand_DeviceFlowPrintsCodeAndStoresToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/device/code" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"device_code": "dev-123", "user_code": "ABCD-EFGH",
+				"verification_url": "https://www.google.com/device", "expires_in": 60, "interval": 1,
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "ya29.device", "refresh_token": "1//device-refresh", "expires_in": 3600,
+		})
+	}))
+	defer server.Close()
+
+	configDir := t.TempDir()
+	env := map[string]string{
+		"FEEDMIX_CONFIG_DIR":       configDir,
+		"FEEDMIX_OAUTH_TOKEN_URL":  server.URL + "/token",
+		"FEEDMIX_OAUTH_DEVICE_URL": server.URL + "/device/code",
+	}
+	stdout, stderr, exitCode := runCLI(t, env, "auth", "youtube", "--device")
+	if exitCode != 0 {
+		t.Fatalf("device authorization should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stderr, "https://www.google.com/device") || !strings.Contains(stderr, "ABCD-EFGH") {
+		t.Errorf("user should be told where to go and which code to enter, got: %s", stderr)
+	}
+	if !strings.Contains(stdout, "saved") {
+		t.Errorf("user should be told the token was saved, got: %s", stdout)
+	}
+	data, _ := os.ReadFile(filepath.Join(configDir, "youtube_token.json"))
+	if !strings.Contains(string(data), "1//device-refresh") {
+		t.Errorf("refresh token from the device flow should be stored, got: %s", data)
+	}
+}
+
+func TestFeedCommand_MarksEditedPostsAsUpdated(t *testing.T) {
+	var description atomic.Value
+	description.Store("Original text")
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<rss><channel><title>Pub</title><item><title>Post</title><description>%s</description><guid>p1</guid><pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate></item></channel></rss>`, description.Load())
+	}))
+	defer rssServer.Close()
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_SUBSTACK_CACHE_TTL"] = "0"
+
+	if stdout, _, _ := runCLI(t, env, "feed"); strings.Contains(stdout, "updated") {
+		t.Errorf("first sighting of a post should not be marked updated, got: %s", stdout)
+	}
+	description.Store("Corrected text")
+	if stdout, _, _ := runCLI(t, env, "feed"); !strings.Contains(stdout, "updated just now") {
+		t.Errorf("a post whose content changed should be marked updated, got: %s", stdout)
+	}
+}
+
+func TestFeedCommand_MergesYouTubeAccountsUnlessOneIsSelected(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/subscriptions"):
+			channelID := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": channelID}, "title": channelID}},
+				},
+			})
+		case strings.Contains(r.URL.Path, "/search"):
+			channelID := r.URL.Query().Get("channelId")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": map[string]interface{}{"videoId": "vid_" + channelID}, "snippet": map[string]interface{}{"title": "Video for " + channelID, "channelId": channelID, "channelTitle": channelID, "publishedAt": "2024-01-15T00:00:00Z"}},
+				},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+		}
+	})
+	defer server.Close()
+
+	configDir := t.TempDir()
+	expiry := time.Now().Add(time.Hour).Format(time.RFC3339)
+	for _, account := range []string{"personal", "work"} {
+		token := `{"access_token":"` + account + `","refresh_token":"1//` + account + `","expiry":"` + expiry + `"}`
+		if err := os.WriteFile(filepath.Join(configDir, "youtube-"+account+"_token.json"), []byte(token), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env := feedEnv(server)
+	env["FEEDMIX_YOUTUBE_REFRESH_TOKEN"] = ""
+	env["FEEDMIX_CONFIG_DIR"] = configDir
+	env["FEEDMIX_YOUTUBE_ACCOUNTS"] = "personal,work"
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Video for personal") || !strings.Contains(stdout, "Video for work") {
+		t.Errorf("feed should merge the subscriptions of every account, got: %s", stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, env, "feed", "--account", "work")
+	if exitCode != 0 {
+		t.Fatalf("feed --account should succeed, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if strings.Contains(stdout, "Video for personal") || !strings.Contains(stdout, "Video for work") {
+		t.Errorf("--account should limit the feed to that account's subscriptions, got: %s", stdout)
+	}
+
+	if _, stderr, exitCode = runCLI(t, env, "feed", "--account", "school"); exitCode == 0 || !strings.Contains(stderr, "FEEDMIX_YOUTUBE_ACCOUNTS") {
+		t.Errorf("an unknown account should be rejected with a hint, got exit code %d\nstderr: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_StripsTrackingParametersFromURLs(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, strings.ReplaceAll(substackRSSXML, "<link>https://testnewsletter.substack.com/p/my-article</link>",
+			"<link>https://TestNewsletter.substack.com/p/my-article?utm_source=rss&amp;utm_medium=feed</link>"))
+	}))
+	defer rssServer.Close()
+
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if strings.Contains(stdout, "utm_") || !strings.Contains(stdout, "https://testnewsletter.substack.com/p/my-article") {
+		t.Errorf("feed should show the canonical article URL, got: %s", stdout)
+	}
+}
+
+func TestFeedCommand_StreamShowsItemsFromEverySource(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/subscriptions"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_A"}, "title": "Channel A"}},
+				},
+			})
+		case strings.Contains(r.URL.Path, "/search"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": map[string]interface{}{"videoId": "vid_a"}, "snippet": map[string]interface{}{"title": "Streamed video", "channelId": "UC_A", "channelTitle": "Channel A", "publishedAt": "2024-01-15T00:00:00Z"}},
+				},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+		}
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--stream")
+	if exitCode != 0 {
+		t.Fatalf("feed --stream should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Streamed video") || !strings.Contains(stdout, "My Substack Article") {
+		t.Errorf("streamed feed should include items from every source, got: %s", stdout)
+	}
+}
+
+func TestOpenCommand_ResolvesItemNumberFromLastFeed(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	if _, _, exitCode := runCLI(t, env, "open", "1"); exitCode == 0 {
+		t.Error("open should fail before any feed was displayed")
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "1. [SUBSTACK] My Substack Article") {
+		t.Errorf("feed items should be numbered, got: %s", stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, env, "open", "1", "--print")
+	if exitCode != 0 || strings.TrimSpace(stdout) != "https://testnewsletter.substack.com/p/my-article" {
+		t.Errorf("open 1 should resolve to the first item's URL, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	if _, stderr, exitCode = runCLI(t, env, "open", "2"); exitCode == 0 || !strings.Contains(stderr, "from 1 to 1") {
+		t.Errorf("an out-of-range item should be rejected with the valid range, got exit %d\nstderr: %s", exitCode, stderr)
+	}
+	prompt := exec.Command(binaryPath, "open", "--print")
+	for k, v := range env {
+		prompt.Env = append(prompt.Env, k+"="+v)
+	}
+	prompt.Stdin = strings.NewReader("1\n")
+	out, err := prompt.CombinedOutput()
+	if err != nil || !strings.Contains(string(out), "1. My Substack Article") || !strings.Contains(string(out), "https://testnewsletter.substack.com/p/my-article") {
+		t.Errorf("open without N should list the items and open the chosen one, got %v: %s", err, out)
+	}
+}
+
+// TestReadCommand_PrintsWholeArticle documents 'feedmix read':
+// - read N prints the full text of item N of the last feed, from content:encoded for Substack posts
+// - the item then counts as opened for 'feedmix open --unread'
+func TestReadCommand_PrintsWholeArticle(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "read", "1", "--no-pager")
+	if exitCode != 0 || !strings.Contains(stdout, "[SUBSTACK] My Substack Article") || !strings.Contains(stdout, "\nThe whole of an interesting article.\n") {
+		t.Errorf("read 1 should print the whole article, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	if _, stderr, _ := runCLI(t, env, "open", "--unread", "--print"); !strings.Contains(stderr, "No items to open.") {
+		t.Errorf("a read item should count as opened, got stderr: %s", stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "read", "2"); exitCode == 0 || !strings.Contains(stderr, "from 1 to 1") {
+		t.Errorf("an out-of-range item should be rejected, got exit %d\nstderr: %s", exitCode, stderr)
+	}
+}
+
+func TestPickCommand_ActsOnItemsPickedInTheFinder(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_FINDER"] = "builtin"
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+
+	pick := exec.Command(binaryPath, "pick", "--action", "read", "--no-pager")
+	for k, v := range env {
+		pick.Env = append(pick.Env, k+"="+v)
+	}
+	pick.Stdin = strings.NewReader("substack article\n1\n")
+	var stdout, stderr strings.Builder
+	pick.Stdout, pick.Stderr = &stdout, &stderr
+	if err := pick.Run(); err != nil || !strings.Contains(stderr.String(), "1. ") || !strings.Contains(stdout.String(), "My Substack Article") {
+		t.Errorf("the built-in finder should list the items and show the picked one, got %v\nstdout: %s\nstderr: %s", err, stdout.String(), stderr.String())
+	}
+
+	out, stderrOut, exitCode := runCLI(t, env, "pick", "--action", "save", "--finder", "head -n 1")
+	if exitCode != 0 || !strings.Contains(out, "Saved: My Substack Article") {
+		t.Errorf("the item an external finder prints should be saved, got %q (exit %d)\nstderr: %s", out, exitCode, stderrOut)
+	}
+
+	if _, stderrOut, exitCode := runCLI(t, env, "pick", "--finder", "false"); exitCode != 0 || !strings.Contains(stderrOut, "Nothing picked.") {
+		t.Errorf("a canceled finder should pick nothing, got exit %d\nstderr: %s", exitCode, stderrOut)
+	}
+	if _, stderrOut, exitCode := runCLI(t, env, "pick", "--action", "delete"); exitCode == 0 || !strings.Contains(stderrOut, "--action") {
+		t.Errorf("an unknown action should be rejected, got exit %d\nstderr: %s", exitCode, stderrOut)
+	}
+}
+
+func TestSaveCommand_SavesItemsFromLastFeedAndExportsThem(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if stdout, stderr, exitCode := runCLI(t, env, "save", "1"); exitCode != 0 || !strings.Contains(stdout, "Saved: My Substack Article") {
+		t.Fatalf("save 1 should save the first item, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	stdout, _, _ := runCLI(t, env, "saved")
+	if !strings.Contains(stdout, "My Substack Article") {
+		t.Errorf("saved should list the saved item, got: %s", stdout)
+	}
+	stdout, _, _ = runCLI(t, env, "saved", "--format", "markdown")
+	if !strings.Contains(stdout, "- [My Substack Article](https://testnewsletter.substack.com/p/my-article)") {
+		t.Errorf("markdown export should link the saved item, got: %s", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "saved", "remove", "1"); exitCode != 0 {
+		t.Fatalf("saved remove 1 should succeed, exit %d\nstderr: %s", exitCode, stderr)
+	}
+	if stdout, _, _ = runCLI(t, env, "saved", "--format", "json"); !strings.Contains(stdout, `"items": []`) {
+		t.Errorf("removed item should no longer be exported, got: %s", stdout)
+	}
+}
+
+func TestExportObsidian_WritesNotesOnce(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	vault := t.TempDir()
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	stdout, stderr, exitCode := runCLI(t, env, "export", "obsidian", "--dir", vault)
+	if exitCode != 0 || !strings.Contains(stdout, "Exported 1 new items") {
+		t.Fatalf("export should write the feed item, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(vault, "My Substack Article.md"))
+	if err != nil || !strings.Contains(string(data), `url: "https://testnewsletter.substack.com/p/my-article"`) {
+		t.Errorf("note should be written with the item URL in its frontmatter, got %q (err %v)", data, err)
+	}
+
+	if stdout, _, _ := runCLI(t, env, "export", "obsidian", "--dir", vault); !strings.Contains(stdout, "Exported 0 new items") {
+		t.Errorf("already exported items should be skipped, got: %s", stdout)
+	}
+}
+
+func TestExportAtom_WritesTheLastFeedAsAnAtomFile(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	out := filepath.Join(t.TempDir(), "feed.xml")
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if stdout, stderr, exitCode := runCLI(t, env, "export", "atom", "--out", out); exitCode != 0 || stdout != "" {
+		t.Fatalf("export should write the file quietly, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || !strings.Contains(string(data), `<feed xmlns="http://www.w3.org/2005/Atom">`) ||
+		!strings.Contains(string(data), `<link rel="alternate" href="https://testnewsletter.substack.com/p/my-article"></link>`) ||
+		!strings.Contains(string(data), `<category term="substack"></category>`) {
+		t.Errorf("expected an Atom feed with the article, got %s (err %v)", data, err)
+	}
+
+	if stdout, _, _ := runCLI(t, env, "export", "atom", "--title", "Mine"); !strings.Contains(stdout, "<title>Mine</title>") {
+		t.Errorf("without --out the feed should go to stdout, got %s", stdout)
+	}
+}
+
+func TestRunsCommand_ShowsManifestOfEachFeedRun(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	if stdout, _, _ := runCLI(t, env, "runs", "list"); !strings.Contains(stdout, "No runs recorded yet") {
+		t.Errorf("runs list should say when nothing was recorded, got: %s", stdout)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--limit", "5"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+
+	stdout, _, exitCode := runCLI(t, env, "runs", "list")
+	if exitCode != 0 || !strings.Contains(stdout, "FETCHED") || !strings.Contains(stdout, "ok") {
+		t.Errorf("runs list should show the recorded run, got: %s", stdout)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "runs", "show")
+	if exitCode != 0 {
+		t.Fatalf("runs show should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{"feedmix feed --limit 5", "substack", "GET 200", rssServer.URL, "* [SUBSTACK] Test Newsletter — Test Author — My Substack Article"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("runs show should include %q, got: %s", want, stdout)
+		}
+	}
+}
+
+func TestRunsCommand_DiffsAndPrunesRuns(t *testing.T) {
+	var requests int32
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		if atomic.AddInt32(&requests, 1) == 1 {
+			fmt.Fprint(w, substackRSSXML)
+			return
+		}
+		fmt.Fprint(w, strings.ReplaceAll(substackRSSXML, "my-article", "next-article"))
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_SUBSTACK_CACHE_TTL"] = "0"
+
+	for i := 0; i < 2; i++ {
+		if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+			t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+		}
+		time.Sleep(5 * time.Millisecond) // runs are named by their start time
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "runs", "diff", "2", "1")
+	if exitCode != 0 {
+		t.Fatalf("runs diff should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Newly fetched (1)") || !strings.Contains(stdout, "No longer fetched (1)") || !strings.Contains(stdout, "substack   1 items → 1 items") {
+		t.Errorf("runs diff should show the replaced item and source counts, got: %s", stdout)
+	}
+
+	if stdout, _, _ := runCLI(t, env, "runs", "prune", "--keep", "1"); !strings.Contains(stdout, "Removed 1 runs") {
+		t.Errorf("runs prune --keep 1 should remove the older run, got: %s", stdout)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "runs", "show", "2"); exitCode == 0 || !strings.Contains(stderr, "must be a number from 1 to 1") {
+		t.Errorf("the pruned run should be gone, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_GroupByShowsSectionPerSource(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--group-by", "source")
+	if exitCode != 0 || !strings.HasPrefix(stdout, "── SUBSTACK ──") {
+		t.Errorf("feed --group-by source should open with the source's header, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--group-by", "topic"); exitCode == 0 || !strings.Contains(stderr, "invalid --group-by") {
+		t.Errorf("an unknown grouping should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_NowFlagPinsTheCurrentTime(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--now", "2024-01-01T14:00:00Z")
+	if exitCode != 0 || !strings.Contains(stdout, "2 hours ago") {
+		t.Errorf("the article should be 2 hours old at the pinned time, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	history, err := os.ReadFile(filepath.Join(env["FEEDMIX_CONFIG_DIR"], "history.json"))
+	if err != nil || !strings.Contains(string(history), `"first_seen":"2024-01-01T14:00:00Z"`) {
+		t.Errorf("the item history should record the pinned time, got %s (%v)", history, err)
+	}
+	runCLI(t, env, "save", "1", "--now", "2024-01-02T09:00:00Z")
+	if stdout, _, _ := runCLI(t, env, "saved", "--format", "json"); !strings.Contains(stdout, `"saved_at": "2024-01-02T09:00:00Z"`) {
+		t.Errorf("saved items should be stamped with the pinned time, got %s", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--now", "yesterday"); exitCode == 0 || !strings.Contains(stderr, "--now") {
+		t.Errorf("an unparseable --now should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_TemplateFormatsEachItem(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--now", "2024-01-01T14:00:00Z", "--template", "{{.N}}\t{{.Title}}\t{{ago .PublishedAt}}\t{{.URL}}")
+	want := "1\tMy Substack Article\t2 hours ago\thttps://testnewsletter.substack.com/p/my-article\n"
+	if exitCode != 0 || stdout != want {
+		t.Errorf("expected only the templated line, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--template", "{{.Titel}}"); exitCode == 0 || !strings.Contains(stderr, "--template") {
+		t.Errorf("a template using a missing field should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_FormatCSVAndJSONFeed(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+
+	for _, args := range [][]string{{"feed", "--format", "csv"}, {"feed", "--format", "csv", "--stream"}} {
+		stdout, stderr, exitCode := runCLI(t, env, args...)
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if exitCode != 0 || len(lines) != 2 || lines[0] != "id,source,type,title,author,url,published_at,views,likes,comments" ||
+			!strings.Contains(lines[1], ",substack,article,My Substack Article,") || !strings.Contains(lines[1], ",2024-01-01T12:00:00Z,0,0,0") {
+			t.Errorf("%v: expected a header and one CSV row, got %q (exit %d)\nstderr: %s", args, stdout, exitCode, stderr)
+		}
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--format", "jsonfeed")
+	var feed struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ID          string `json:"id"`
+			URL         string `json:"url"`
+			ContentHTML string `json:"content_html"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &feed); exitCode != 0 || err != nil || feed.Version != "https://jsonfeed.org/version/1.1" ||
+		len(feed.Items) != 1 || feed.Items[0].URL != "https://testnewsletter.substack.com/p/my-article" || feed.Items[0].ContentHTML == "" {
+		t.Errorf("expected a JSON Feed with the article, got %q (exit %d, err %v)\nstderr: %s", stdout, exitCode, err, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--format", "jsonfeed", "--stream"); exitCode == 0 || !strings.Contains(stderr, "--stream") {
+		t.Errorf("a JSON Feed can't be streamed, got exit code %d: %s", exitCode, stderr)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--format", "xml"); exitCode == 0 || !strings.Contains(stderr, "--format") {
+		t.Errorf("an unknown format should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_FormatICSListsUpcomingBroadcasts(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var items []map[string]interface{}
+		switch {
+		case strings.Contains(r.URL.Path, "/subscriptions"):
+			items = []map[string]interface{}{{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC123"}, "title": "Tech Channel"}}}
+		case strings.Contains(r.URL.Path, "/search"):
+			for _, id := range []string{"premiere", "upload"} {
+				items = append(items, map[string]interface{}{
+					"id":      map[string]interface{}{"videoId": id},
+					"snippet": map[string]interface{}{"title": "Video " + id, "channelId": "UC123", "channelTitle": "Tech Channel", "publishedAt": "2024-01-15T12:00:00Z"},
+				})
+			}
+		case strings.Contains(r.URL.Path, "/videos"):
+			items = []map[string]interface{}{
+				{"id": "premiere", "liveStreamingDetails": map[string]interface{}{"scheduledStartTime": "2024-01-20T18:00:00Z"}},
+				{"id": "upload"},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--format", "ics", "--now", "2024-01-16T00:00:00Z")
+	if exitCode != 0 || !strings.HasPrefix(stdout, "BEGIN:VCALENDAR\r\n") || strings.Count(stdout, "BEGIN:VEVENT") != 1 ||
+		!strings.Contains(stdout, "SUMMARY:Tech Channel: Video premiere\r\n") || !strings.Contains(stdout, "DTSTART:20240120T180000Z\r\n") {
+		t.Errorf("expected a calendar with the scheduled premiere only, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--format", "ics", "--stream"); exitCode == 0 || !strings.Contains(stderr, "--stream") {
+		t.Errorf("a calendar can't be streamed, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_StaleOnlySkipsRecentlyFetchedPublications(t *testing.T) {
+	var requests atomic.Int32
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	var youtubeRequests atomic.Int32
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		youtubeRequests.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	env["FEEDMIX_SUBSTACK_CACHE_TTL"] = "0"
+	env["FEEDMIX_YOUTUBE_CACHE_TTL"] = "0"
+
+	for run := 1; run <= 3; run++ {
+		args := []string{"feed", "--stale-only"}
+		if run == 3 {
+			args = append(args, "--now", "2099-01-01")
+		}
+		stdout, stderr, exitCode := runCLI(t, env, args...)
+		if exitCode != 0 || !strings.Contains(stdout, "My Substack Article") {
+			t.Fatalf("run %d should show the article, got %q (exit %d)\nstderr: %s", run, stdout, exitCode, stderr)
+		}
+		if want := map[int]int32{1: 1, 2: 1, 3: 2}[run]; requests.Load() != want {
+			t.Errorf("after run %d, expected %d feed requests, got %d", run, want, requests.Load())
+		}
+		if run == 1 {
+			youtubeRequests.Store(0)
+		}
+		if run == 2 && youtubeRequests.Load() != 0 {
+			t.Errorf("a fresh subscription list should not be fetched again, got %d YouTube requests", youtubeRequests.Load())
+		}
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--stale-only", "--no-cache"); exitCode == 0 || !strings.Contains(stderr, "--stale-only") {
+		t.Errorf("--stale-only with --no-cache should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_InlineThumbnailsDrawImagesOrFallBackToURLs(t *testing.T) {
+	var thumbnail bytes.Buffer
+	if err := png.Encode(&thumbnail, image.NewRGBA(image.Rect(0, 0, 32, 18))); err != nil {
+		t.Fatal(err)
+	}
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/thumb.png":
+			_, _ = w.Write(thumbnail.Bytes())
+		case r.URL.Path == "/missing.png":
+			http.NotFound(w, r)
+		case strings.Contains(r.URL.Path, "/subscriptions"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+				{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_A"}, "title": "Channel A", "publishedAt": "2024-01-01T00:00:00Z"}},
+			}})
+		case strings.Contains(r.URL.Path, "/search"):
+			video := func(id, thumb string) map[string]interface{} {
+				return map[string]interface{}{"id": map[string]interface{}{"videoId": id}, "snippet": map[string]interface{}{"title": "Video " + id, "channelId": "UC_A", "channelTitle": "Channel A", "publishedAt": "2024-01-15T00:00:00Z", "thumbnails": map[string]interface{}{"default": map[string]interface{}{"url": "http://" + r.Host + thumb}}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{video("drawn", "/thumb.png"), video("broken", "/missing.png")}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+		}
+	})
+	defer server.Close()
+
+	env := feedEnv(server)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--inline-thumbnails", "kitty")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, exit %d\nstderr: %s", exitCode, stderr)
+	}
+	if strings.Count(stdout, "\x1b_Ga=T,f=100") != 1 {
+		t.Errorf("the downloaded thumbnail should be drawn with the kitty protocol, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "thumbnail: "+server.URL+"/missing.png") {
+		t.Errorf("a thumbnail that can't be downloaded should fall back to its URL, got %q", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, env, "feed", "--inline-thumbnails", "ascii"); exitCode == 0 || !strings.Contains(stderr, "invalid --inline-thumbnails") {
+		t.Errorf("an unknown protocol should be rejected, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestOpenCommand_OpensFilteredItemsAsTabs(t *testing.T) {
+	rssServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, substackRSSXML)
+	}))
+	defer rssServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_SUBSTACK_URLS"] = rssServer.URL
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "open", "--top", "5", "--source", "substack", "--print")
+	if exitCode != 0 || strings.TrimSpace(stdout) != "https://testnewsletter.substack.com/p/my-article" {
+		t.Errorf("open --top should list the matching items' URLs, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	if stdout, _, _ := runCLI(t, env, "open", "--source", "youtube", "--print"); stdout != "" {
+		t.Errorf("items from other sources should be left out, got %q", stdout)
+	}
+
+	stdout, stderr, exitCode = runCLI(t, env, "open", "--unread", "--print")
+	if exitCode != 0 || strings.TrimSpace(stdout) != "https://testnewsletter.substack.com/p/my-article" {
+		t.Errorf("open --unread should list the unread item, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	for _, args := range [][]string{{"open", "1", "--top", "2"}, {"open", "--top", "0"}, {"open", "--source", "rss"}} {
+		if _, _, exitCode := runCLI(t, env, args...); exitCode == 0 {
+			t.Errorf("%v should be rejected", args)
+		}
+	}
+}
+
+func TestDemoCommand_ShowsTheSampleFeedWithoutCredentials(t *testing.T) {
+	configDir, cacheDir := t.TempDir(), t.TempDir()
+	env := map[string]string{
+		"FEEDMIX_CONFIG_DIR":            configDir,
+		"FEEDMIX_CACHE_DIR":             cacheDir,
+		"FEEDMIX_YOUTUBE_REFRESH_TOKEN": "",
+		"FEEDMIX_SUBSTACK_URLS":         "",
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "demo", "--now", "2025-06-01T09:00:00Z")
+	if exitCode != 0 || !strings.HasPrefix(stdout, "1. [YOUTUBE] LIVE Launch day Q&A") || !strings.Contains(stdout, "Premieres in 3h") ||
+		!strings.Contains(stdout, "[SUBSTACK] The quiet return of the personal website\n  by Lena Ortiz • 4 hours ago") {
+		t.Errorf("demo should show the sample feed dated at the current time, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	stdout, _, exitCode = runCLI(t, env, "demo", "--format", "csv", "--limit", "3")
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); exitCode != 0 || len(lines) != 4 {
+		t.Errorf("demo --format csv should write a header and 3 rows, got %q (exit %d)", stdout, exitCode)
+	}
+	if stdout, _, _ = runCLI(t, env, "demo", "--format", "ics"); strings.Count(stdout, "BEGIN:VEVENT") != 2 {
+		t.Errorf("demo --format ics should list the sample live stream and premiere, got %q", stdout)
+	}
+
+	for _, dir := range []string{configDir, cacheDir} {
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("demo should not remember anything, found %v in %s", entries, dir)
+		}
+	}
+}
+
+func TestConfigDumpCommand_PrintsTheEffectiveConfiguration(t *testing.T) {
+	env := map[string]string{
+		"FEEDMIX_YOUTUBE_REFRESH_TOKEN": "top-secret-token",
+		"FEEDMIX_SUBSTACK_URLS":         "https://example.substack.com",
+		"FEEDMIX_COMPACT":               "true",
+	}
+	stdout, stderr, exitCode := runCLI(t, env, "config", "dump")
+	if exitCode != 0 || !strings.Contains(stdout, "\n  compact: true\n") || !strings.Contains(stdout, "  urls:\n    - \"https://example.substack.com\"\n") ||
+		!strings.Contains(stdout, `refresh_token: "<redacted>"`) || strings.Contains(stdout, "top-secret-token") {
+		t.Errorf("config dump should print the settings as YAML with secrets redacted, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+
+	stdout, _, exitCode = runCLI(t, env, "config", "dump", "--format", "json")
+	var dump struct {
+		Display struct {
+			Compact          bool   `json:"compact"`
+			Theme            string `json:"theme"`
+			InlineThumbnails string `json:"inline_thumbnails"`
+		} `json:"display"`
+		Limits struct {
+			YouTube int `json:"youtube"`
+		} `json:"limits"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &dump); exitCode != 0 || err != nil || !dump.Display.Compact || dump.Limits.YouTube != 5 {
+		t.Errorf("config dump --format json should print the settings as JSON, got %q (exit %d, err %v)", stdout, exitCode, err)
+	}
+	if dump.Display.Theme != "default" || dump.Display.InlineThumbnails != "off" {
+		t.Errorf("config dump should print the defaults that apply rather than empty values, got %+v", dump.Display)
+	}
+}
+
+func TestStrictMode_RejectsUnknownSettings(t *testing.T) {
+	env := map[string]string{"FEEDMIX_SUBSTACK_URL": "https://example.substack.com"}
+	if _, stderr, exitCode := runCLI(t, env, "config"); exitCode != 0 {
+		t.Errorf("unknown settings should be ignored by default, got exit code %d: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "config", "--strict"); exitCode == 0 || !strings.Contains(stderr, "did you mean FEEDMIX_SUBSTACK_URLS?") {
+		t.Errorf("--strict should reject unknown settings, got exit code %d: %s", exitCode, stderr)
+	}
+	env["FEEDMIX_STRICT"] = "true"
+	if _, stderr, exitCode := runCLI(t, env, "demo"); exitCode == 0 || !strings.Contains(stderr, "FEEDMIX_SUBSTACK_URL") {
+		t.Errorf("FEEDMIX_STRICT should enable strict mode, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestYouTubeResolveCommand_PrintsChannelIDs(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{{"id": "UC_" + strings.TrimPrefix(r.URL.Query().Get("forHandle"), "@")}},
+		})
+	})
+	defer server.Close()
+
+	stdout, stderr, exitCode := runCLI(t, feedEnv(server), "youtube", "resolve", "@first", "https://www.youtube.com/@second")
+	if exitCode != 0 {
+		t.Fatalf("youtube resolve should succeed, got exit code %d: %s", exitCode, stderr)
+	}
+	if stdout != "UC_first\nUC_second\n" {
+		t.Errorf("expected one channel ID per line, got %q", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, feedEnv(server), "youtube", "resolve", "not a channel"); exitCode == 0 || !strings.Contains(stderr, "invalid channel") {
+		t.Errorf("an invalid channel should be reported, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestTranscriptCommand_PrintsCaptions(t *testing.T) {
+	server := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/timedtext" || r.URL.Query().Get("v") != "dQw4w9WgXcQ" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("type") == "list" {
+			fmt.Fprint(w, `<transcript_list><track lang_code="en"/></transcript_list>`)
+			return
+		}
+		fmt.Fprint(w, `<transcript><text start="0" dur="2">Never gonna</text><text start="65.5" dur="2">give you up</text></transcript>`)
+	})
+	defer server.Close()
+
+	stdout, stderr, exitCode := runCLI(t, feedEnv(server), "transcript", "https://youtu.be/dQw4w9WgXcQ")
+	if exitCode != 0 {
+		t.Fatalf("transcript should succeed, got exit code %d: %s", exitCode, stderr)
+	}
+	if stdout != "Never gonna give you up\n" {
+		t.Errorf("expected the captions as running text, got %q", stdout)
+	}
+
+	stdout, _, _ = runCLI(t, feedEnv(server), "transcript", "dQw4w9WgXcQ", "--timestamps")
+	if stdout != "[0:00] Never gonna\n[1:05] give you up\n" {
+		t.Errorf("expected one caption per line after its time, got %q", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, feedEnv(server), "transcript", "dQw4w9WgXcQ", "--lang", "de"); exitCode == 0 || !strings.Contains(stderr, "no transcript available in de") {
+		t.Errorf("a missing language should be reported, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_IncludesSelfHostedFeeds(t *testing.T) {
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+	readerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/accounts/ClientLogin" {
+			fmt.Fprint(w, "Auth=token\n")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{{
+				"id": "1", "title": "Release notes", "author": "Jane", "published": time.Now().Add(-time.Hour).Unix(),
+				"canonical": []map[string]string{{"href": "https://blog.example.com/release"}},
+				"origin":    map[string]string{"title": "Example Blog"},
+			}},
+		})
+	}))
+	defer readerServer.Close()
+	bridgeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"title": "Issues", "items": [{"id": "7", "url": "https://github.com/o/r/issues/7", "title": "Crash on start", "date_modified": %q}]}`, time.Now().Add(-2*time.Hour).Format(time.RFC3339))
+	}))
+	defer bridgeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_READER_URL"] = readerServer.URL
+	env["FEEDMIX_READER_USER"] = "me"
+	env["FEEDMIX_READER_PASSWORD"] = "secret"
+	env["FEEDMIX_BRIDGE_URLS"] = bridgeServer.URL + "/?action=display&bridge=GithubIssueBridge"
+	stdout, stderr, exitCode := runCLI(t, env, "feed", "--no-cache")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed, got exit code %d: %s", exitCode, stderr)
+	}
+	for _, want := range []string{"[READER] Release notes", "by Example Blog — Jane", "[BRIDGE] Crash on start", "by Issues"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in the feed, got:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestSyncCommand_SyncsReadAndStarredItemsBothWays(t *testing.T) {
+	var mu sync.Mutex
+	tags := map[string][]string{"1": {"user/1/state/com.google/read", "user/1/state/com.google/starred"}}
+	readerServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/accounts/ClientLogin":
+			fmt.Fprint(w, "Auth=token\n")
+		case r.URL.Path == "/reader/api/0/token":
+			fmt.Fprint(w, "edit-token")
+		case r.URL.Path == "/reader/api/0/edit-tag":
+			_ = r.ParseForm()
+			for _, id := range r.Form["i"] {
+				if tag := r.FormValue("a"); tag != "" {
+					tags[id] = append(tags[id], tag)
+				}
+			}
+			fmt.Fprint(w, "OK")
+		default:
+			var items []map[string]interface{}
+			for i, id := range []string{"1", "2"} {
+				items = append(items, map[string]interface{}{
+					"id": id, "title": "Post " + id, "published": time.Now().Add(-time.Duration(i+1) * time.Hour).Unix(),
+					"canonical":  []map[string]string{{"href": "https://blog.example.com/" + id}},
+					"categories": tags[id],
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+		}
+	}))
+	defer readerServer.Close()
+	youtubeServer := mockFeedServer(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	})
+	defer youtubeServer.Close()
+
+	env := feedEnv(youtubeServer)
+	env["FEEDMIX_CONFIG_DIR"] = t.TempDir()
+	env["FEEDMIX_CACHE_DIR"] = t.TempDir()
+	env["FEEDMIX_READER_URL"] = readerServer.URL
+	env["FEEDMIX_READER_USER"] = "me"
+	env["FEEDMIX_READER_PASSWORD"] = "secret"
+
+	if _, stderr, exitCode := runCLI(t, env, "feed"); exitCode != 0 {
+		t.Fatalf("feed should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode := runCLI(t, env, "save", "2"); exitCode != 0 {
+		t.Fatalf("save should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, env, "sync")
+	if exitCode != 0 {
+		t.Fatalf("sync should succeed, exit code %d\nstderr: %s", exitCode, stderr)
+	}
+	if want := "Synced 2 items with " + readerServer.URL + ": 1 starred on the server; 1 marked opened, 1 saved in feedmix.\n"; stdout != want {
+		t.Errorf("expected %q, got %q", want, stdout)
+	}
+	mu.Lock()
+	if got := strings.Join(tags["2"], ","); got != "user/-/state/com.google/starred" {
+		t.Errorf("the item saved in feedmix should be starred on the server, got tags %q", got)
+	}
+	mu.Unlock()
+	if stdout, _, _ := runCLI(t, env, "saved"); !strings.Contains(stdout, "Post 1") || !strings.Contains(stdout, "Post 2") {
+		t.Errorf("the item starred on the server should be saved, got:\n%s", stdout)
+	}
+
+	if stdout, _, _ := runCLI(t, env, "sync"); !strings.Contains(stdout, "already in step") {
+		t.Errorf("a second sync should change nothing, got %q", stdout)
+	}
+	delete(env, "FEEDMIX_READER_URL")
+	if _, stderr, exitCode := runCLI(t, env, "sync"); exitCode == 0 || !strings.Contains(stderr, "FEEDMIX_READER_URL") {
+		t.Errorf("sync without a reader should fail, got exit code %d: %s", exitCode, stderr)
+	}
+}
+
+func TestFeedCommand_KeylessYouTubeChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feeds/videos.xml" {
+			t.Errorf("keyless mode should only fetch channel feeds, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:yt="http://www.youtube.com/xml/schemas/2015"><entry><yt:videoId>abc123xyz00</yt:videoId><yt:channelId>%s</yt:channelId><title>Keyless video</title><author><name>Fireship</name></author><published>%s</published></entry></feed>`,
+			r.URL.Query().Get("channel_id"), time.Now().Add(-time.Hour).Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	env := map[string]string{
+		"FEEDMIX_API_URL":          server.URL,
+		"FEEDMIX_CONFIG_DIR":       t.TempDir(),
+		"FEEDMIX_CACHE_DIR":        t.TempDir(),
+		"FEEDMIX_YOUTUBE_CHANNELS": "UCsBjURrPoezykLs9EqgamOA",
+	}
+	stdout, stderr, exitCode := runCLI(t, env, "feed")
+	if exitCode != 0 {
+		t.Fatalf("feed should succeed without credentials, got exit code %d: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "Keyless video") || !strings.Contains(stdout, "watch?v=abc123xyz00") {
+		t.Errorf("expected the channel's video, got:\n%s", stdout)
+	}
+
+	if stdout, _, _ := runCLI(t, env, "config"); !strings.Contains(stdout, "YouTube (keyless)") {
+		t.Errorf("config should report keyless mode, got:\n%s", stdout)
+	}
+}
+
+// TestSchemaCommand_PrintsJSONSchemas documents 'feedmix schema':
+// - without a name, the schemas are listed with the outputs they describe
+// - with a name, its JSON Schema is printed; unknown names are rejected
+// - with --dir, every schema is written to its own file
+func TestSchemaCommand_PrintsJSONSchemas(t *testing.T) {
+	stdout, _, exitCode := runCLI(t, nil, "schema")
+	if exitCode != 0 || !strings.Contains(stdout, "items") || !strings.Contains(stdout, "jsonfeed") || !strings.Contains(stdout, "events") {
+		t.Errorf("schema should list the schemas, got %q (exit %d)", stdout, exitCode)
+	}
+
+	stdout, stderr, exitCode := runCLI(t, nil, "schema", "items")
+	var schema struct {
+		Schema     string                     `json:"$schema"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]json.RawMessage `json:"$defs"`
+	}
+	if exitCode != 0 || json.Unmarshal([]byte(stdout), &schema) != nil {
+		t.Fatalf("schema items should print a JSON document, got %q (exit %d)\nstderr: %s", stdout, exitCode, stderr)
+	}
+	if schema.Schema == "" || schema.Properties["schema_version"] == nil || !strings.Contains(string(schema.Defs["Item"]), `"saved_at"`) {
+		t.Errorf("the items schema should describe the envelope and saved items, got: %s", stdout)
+	}
+
+	if _, stderr, exitCode := runCLI(t, nil, "schema", "yaml"); exitCode == 0 || !strings.Contains(stderr, "unknown schema") {
+		t.Errorf("an unknown schema should be rejected, got exit %d\nstderr: %s", exitCode, stderr)
+	}
+
+	dir := t.TempDir()
+	if _, stderr, exitCode := runCLI(t, nil, "schema", "--dir", dir); exitCode != 0 {
+		t.Fatalf("schema --dir should succeed, exit %d\nstderr: %s", exitCode, stderr)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "jsonfeed.schema.json")); err != nil || !strings.Contains(string(data), `"_feedmix"`) {
+		t.Errorf("schema --dir should write the JSON Feed schema, got %v: %s", err, data)
+	}
+}
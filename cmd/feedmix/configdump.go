@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+)
+
+func newConfigDumpCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Print the effective configuration as YAML or JSON",
+		Long: "Prints the configuration feedmix runs with, after reading the environment and .env file and applying defaults, " +
+			"to check why a setting is not taking effect. Client secrets, refresh tokens and Substack header values are redacted.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			dump := config.Dump(withDefaults(cfg))
+			switch format {
+			case "yaml":
+				return writeYAML(cmd.OutOrStdout(), dump)
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(dump)
+			default:
+				return fmt.Errorf("invalid --format %q: must be yaml or json", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "yaml", "Output format: yaml or json")
+	return cmd
+}
+
+// withDefaults spells out the display settings whose empty value stands for
+// a default, so the dump shows the one that applies.
+func withDefaults(cfg config.Config) config.Config {
+	if cfg.Display.Theme == "" {
+		cfg.Display.Theme = display.DefaultTheme
+	}
+	if cfg.Display.InlineThumbnails == "" {
+		cfg.Display.InlineThumbnails = "off"
+	}
+	if cfg.Display.GroupBy == "" {
+		cfg.Display.GroupBy = "none"
+	}
+	return cfg
+}
+
+// plainYAMLKey matches keys that need no quotes in YAML.
+var plainYAMLKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// writeYAML writes v, made of the maps, slices and scalars config.Dump
+// returns, as a YAML document with sorted keys. Strings are always quoted,
+// so values such as "no" or "1.0" keep their type.
+func writeYAML(w io.Writer, v map[string]any) error {
+	var b strings.Builder
+	yamlMap(&b, v, 0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func yamlMap(b *strings.Builder, m map[string]any, indent int) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.WriteString(strings.Repeat(" ", indent))
+		if plainYAMLKey.MatchString(key) {
+			b.WriteString(key)
+		} else {
+			b.WriteString(strconv.Quote(key))
+		}
+		b.WriteString(":")
+		yamlValue(b, m[key], indent)
+	}
+}
+
+// yamlValue writes v after a "key:" or "-", on the same line if it is a
+// scalar or empty, else on the following lines indented by two more spaces.
+func yamlValue(b *strings.Builder, v any, indent int) {
+	switch x := v.(type) {
+	case map[string]any:
+		if len(x) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		yamlMap(b, x, indent+2)
+	case []any:
+		if len(x) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		for _, item := range x {
+			b.WriteString(strings.Repeat(" ", indent+2) + "-")
+			yamlValue(b, item, indent+2)
+		}
+	case string:
+		b.WriteString(" " + strconv.Quote(x) + "\n")
+	case nil:
+		b.WriteString(" null\n")
+	default:
+		fmt.Fprintf(b, " %v\n", x)
+	}
+}
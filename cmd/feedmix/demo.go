@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/demo"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func newDemoCmd() *cobra.Command {
+	var limit int
+	var layout config.Display
+	var noColor bool
+	var itemTemplate, format, sortBy string
+
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Show a sample feed, without credentials or network access",
+		Long: "Shows a bundled sample feed of YouTube videos, live streams and Substack posts the way 'feedmix feed' would, " +
+			"with the same layout flags and output formats. Nothing is fetched or remembered, so it is safe for screenshots, " +
+			"trying out themes and checking output formats.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			now = clock.Fixed(now())
+			switch format {
+			case formatText, formatCSV, formatJSONFeed, formatICS:
+			default:
+				return fmt.Errorf("invalid --format %q: must be %s, %s, %s or %s", format, formatText, formatCSV, formatJSONFeed, formatICS)
+			}
+			if format != formatText && cmd.Flags().Changed("template") {
+				return fmt.Errorf("--template only applies to --format %s", formatText)
+			}
+			var order aggregator.Sort
+			switch sortBy {
+			case "newest":
+			case string(aggregator.SortGrowth):
+				order = aggregator.SortGrowth
+			default:
+				return fmt.Errorf("invalid --sort %q: must be newest or %s", sortBy, aggregator.SortGrowth)
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if err := overrideDisplay(cmd, &cfg.Display, layout); err != nil {
+				return err
+			}
+			if noColor {
+				cfg.Display.Color = false
+			}
+
+			items, err := demo.Items(now())
+			if err != nil {
+				return err
+			}
+			agg := aggregator.New()
+			agg.AddItems(items)
+			feedOpts := aggregator.FeedOptions{Limit: limit, GroupBy: aggregator.GroupBy(cfg.Display.GroupBy), Sort: order}
+			if format == formatICS {
+				feedOpts.Limit = 0
+			}
+			items = agg.GetFeed(feedOpts)
+
+			out := cmd.OutOrStdout()
+			switch format {
+			case formatCSV:
+				return display.WriteCSV(out, items)
+			case formatJSONFeed:
+				return display.WriteJSONFeed(out, "feedmix demo", items)
+			case formatICS:
+				return display.WriteICS(out, items, now())
+			}
+			layoutOpts, err := formatterOptions(cfg, out, now)
+			if err != nil {
+				return err
+			}
+			if order == aggregator.SortGrowth {
+				layoutOpts = append(layoutOpts, display.WithDayHeaders(false))
+			}
+			if cmd.Flags().Changed("template") {
+				tmpl, err := display.ParseTemplate(itemTemplate)
+				if err != nil {
+					return fmt.Errorf("--template: %w", err)
+				}
+				layoutOpts = append(layoutOpts, display.WithTemplate(tmpl))
+			}
+			return writePaged(cmd, cfg.Pager, display.NewTerminalFormatter(layoutOpts...).FormatFeed(items))
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Maximum items to display")
+	cmd.Flags().StringVar(&sortBy, "sort", "newest", "Order the feed: newest, or growth for the items gaining views fastest")
+	cmd.Flags().StringVar(&format, "format", formatText, "Output format: "+strings.Join([]string{formatText, formatCSV, formatJSONFeed, formatICS}, ", "))
+	cmd.Flags().StringVar(&itemTemplate, "template", "", "Format each item with a Go template (see README)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colors (also NO_COLOR)")
+	addLayoutFlags(cmd, &layout)
+	return cmd
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/obsidian"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export items to other tools",
+	}
+
+	var dir string
+	var fromSaved bool
+	obsidianCmd := &cobra.Command{
+		Use:   "obsidian",
+		Short: "Write items as Markdown notes into an Obsidian vault folder",
+		Long: "Writes one note per item of the last 'feedmix feed' (or, with --saved, of the saved list) into --dir, " +
+			"with the source, author, URL and date as frontmatter, and links the new notes from a daily note named YYYY-MM-DD. " +
+			"Items exported before are skipped, so the command can run after every feed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+
+			items, err := exportItems(cfg, fromSaved, now)
+			if err != nil {
+				return err
+			}
+
+			n, err := obsidian.NewExporter(dir, now).Export(items)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d new items to %s\n", n, dir)
+			return nil
+		},
+	}
+	obsidianCmd.Flags().StringVar(&dir, "dir", "", "Vault folder to write notes into")
+	obsidianCmd.Flags().BoolVar(&fromSaved, "saved", false, "Export saved items instead of the last feed")
+	_ = obsidianCmd.MarkFlagRequired("dir")
+	exportCmd.AddCommand(obsidianCmd)
+	exportCmd.AddCommand(newExportAtomCmd())
+	exportCmd.AddCommand(newExportMinifluxCmd())
+
+	return exportCmd
+}
+
+func newExportAtomCmd() *cobra.Command {
+	var out, title string
+	var fromSaved bool
+
+	cmd := &cobra.Command{
+		Use:   "atom",
+		Short: "Write items as an Atom feed",
+		Long: "Writes the last 'feedmix feed' (or, with --saved, the saved list) as an Atom feed to --out, or to standard output, " +
+			"with each item's source as a category. The file is replaced in one step, so it can be published from a cron job " +
+			"after 'feedmix feed --stale-only'.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			items, err := exportItems(cfg, fromSaved, now)
+			if err != nil {
+				return err
+			}
+
+			var doc bytes.Buffer
+			if err := display.WriteAtom(&doc, title, items, now()); err != nil {
+				return fmt.Errorf("failed to encode Atom feed: %w", err)
+			}
+			if out == "" {
+				_, err := cmd.OutOrStdout().Write(doc.Bytes())
+				return err
+			}
+			return writeFileAtomically(out, doc.Bytes())
+		},
+	}
+	cmd.Flags().StringVarP(&out, "out", "o", "", "File to write the feed to (default: standard output)")
+	cmd.Flags().StringVar(&title, "title", "feedmix", "Title of the feed")
+	cmd.Flags().BoolVar(&fromSaved, "saved", false, "Export saved items instead of the last feed")
+	return cmd
+}
+
+// exportItems returns the saved items if fromSaved is set, else the last feed.
+func exportItems(cfg config.Config, fromSaved bool, now clock.Clock) ([]aggregator.FeedItem, error) {
+	if !fromSaved {
+		return loadLastFeed(cfg)
+	}
+	store, err := openSaved(cfg, now)
+	if err != nil {
+		return nil, err
+	}
+	return feedItems(store.Items()), nil
+}
+
+// writeFileAtomically replaces the file at path with data, through a
+// temporary file renamed into place, so readers never see it half written.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0644); err != nil { // #nosec G302 -- the feed is meant to be public
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,537 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/arxiv"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+	"github.com/gauthierbraillon/feedmix/internal/freshness"
+	"github.com/gauthierbraillon/feedmix/internal/github"
+	"github.com/gauthierbraillon/feedmix/internal/greader"
+	"github.com/gauthierbraillon/feedmix/internal/lobsters"
+	"github.com/gauthierbraillon/feedmix/internal/medium"
+	"github.com/gauthierbraillon/feedmix/internal/peertube"
+	"github.com/gauthierbraillon/feedmix/internal/podcast"
+	"github.com/gauthierbraillon/feedmix/internal/rssbridge"
+	"github.com/gauthierbraillon/feedmix/internal/runs"
+	"github.com/gauthierbraillon/feedmix/internal/source"
+	"github.com/gauthierbraillon/feedmix/internal/substack"
+	"github.com/gauthierbraillon/feedmix/internal/twitch"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+// feedFlags are the flags of the feed command.
+type feedFlags struct {
+	limit         int
+	showQuota     bool
+	noCache       bool
+	staleOnly     bool
+	accountNames  []string
+	stream        bool
+	layout        config.Display
+	groups        []string
+	hidePaywalled bool
+	noColor       bool
+	itemTemplate  string
+	format        string
+	sortBy        string
+}
+
+func newFeedCmd() *cobra.Command {
+	var flags feedFlags
+
+	cmd := &cobra.Command{
+		Use:   "feed",
+		Short: "Display unified feed",
+		Long:  "Display your YouTube subscriptions and Substack newsletters in a unified feed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFeed(cmd, flags)
+		},
+	}
+
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", 20, "Maximum items to display")
+	cmd.Flags().BoolVar(&flags.showQuota, "show-quota", false, "Report estimated YouTube quota usage after the run")
+	cmd.Flags().BoolVar(&flags.noCache, "no-cache", false, "Ignore cached API responses and fetch everything fresh")
+	cmd.Flags().BoolVar(&flags.staleOnly, "stale-only", false, "Only fetch channels and publications not fetched recently for how often they post; show the others' last items")
+	cmd.Flags().BoolVar(&flags.stream, "stream", false, "Show items as each channel finishes instead of waiting to sort the whole feed")
+	addLayoutFlags(cmd, &flags.layout)
+	cmd.Flags().StringVar(&flags.sortBy, "sort", "newest", "Order the feed: newest, or growth for the items gaining views (likes on Substack) fastest between runs")
+	cmd.Flags().StringVar(&flags.format, "format", formatText, "Output format: text, csv for spreadsheets and scripts, jsonfeed for feed readers, or ics for a calendar of upcoming premieres and live streams")
+	cmd.Flags().StringVar(&flags.itemTemplate, "template", "", "Format each item with a Go template, e.g. '{{.N}} {{.Title}} {{ago .PublishedAt}}' (see README)")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "Disable colors (also NO_COLOR)")
+	cmd.Flags().StringSliceVar(&flags.groups, "group", nil, "Only show channels in these groups, e.g. tech,gaming (see FEEDMIX_YOUTUBE_GROUPS)")
+	cmd.Flags().StringSliceVar(&flags.accountNames, "account", nil, "YouTube account(s) from FEEDMIX_YOUTUBE_ACCOUNTS to include (default: all)")
+	cmd.Flags().BoolVar(&flags.hidePaywalled, "hide-paywalled", false, "Leave out Substack posts only paid subscribers can read in full (FEEDMIX_HIDE_PAYWALLED)")
+	return cmd
+}
+
+// feedRun is what the steps of one feed command run share.
+type feedRun struct {
+	cmd      *cobra.Command
+	flags    feedFlags
+	cfg      config.Config
+	now      clock.Clock
+	order    aggregator.Sort
+	tmpl     *template.Template
+	recorder *runs.Recorder
+	warn     func(error)
+	client   *http.Client
+	meter    *youtube.QuotaMeter
+	// ttl is how long cached responses stay fresh, zero with --no-cache.
+	ttl config.CacheTTL
+}
+
+// runFeed fetches every source configured, shows the feed in the format
+// asked for, and records the run.
+func runFeed(cmd *cobra.Command, flags feedFlags) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	r, err := newFeedRun(cmd, flags)
+	if err != nil {
+		return err
+	}
+	accounts, err := youtubeAccounts(r.cfg, flags.accountNames)
+	if err != nil {
+		return err
+	}
+	styles, err := parseNotificationStyles(r.cfg)
+	if err != nil {
+		return err
+	}
+
+	usage, err := youtube.LoadQuotaUsage(quotaPath(r.cfg), r.now())
+	if err != nil {
+		r.warn(err)
+	}
+	warnIfOverBudget(usage, r.cfg.YouTube.QuotaBudget, r.warn)
+	opts, saveKeys := r.youtubeOptions()
+	defer saveKeys()
+	registry, err := r.registry(ctx, accounts, opts)
+	if err != nil {
+		return err
+	}
+
+	pipeline := openItemPipeline(r.cfg, r.client, r.now, r.warn)
+	defer pipeline.save()
+	fetchOpts, saveFetchTimes := r.fetchOptions()
+	defer saveFetchTimes()
+	feedOpts := r.feedOptions()
+	formatter, thumbnails, err := r.formatter(ctx)
+	if err != nil {
+		return err
+	}
+
+	agg := aggregator.New()
+	var fetched, items []aggregator.FeedItem
+	if flags.stream {
+		fetched, items, err = r.stream(ctx, registry, fetchOpts, pipeline, agg, feedOpts, formatter)
+	} else {
+		fetched, err = registry.FetchAll(ctx, fetchOpts)
+	}
+	r.recordQuota(usage)
+	if err != nil {
+		saveManifest(r.cfg, r.recorder.Finish(fetched, items, r.meter.Units(), err), r.now, r.warn)
+		return err
+	}
+	if !flags.stream {
+		fetched = pipeline.process(ctx, fetched)
+		agg.AddItems(fetched)
+		items = r.show(agg, feedOpts, formatter, thumbnails)
+	}
+
+	r.finish(styles, pipeline, items)
+	saveManifest(r.cfg, r.recorder.Finish(fetched, items, r.meter.Units(), nil), r.now, r.warn)
+	return nil
+}
+
+// newFeedRun checks flags and loads the configuration they adjust.
+func newFeedRun(cmd *cobra.Command, flags feedFlags) (*feedRun, error) {
+	now, err := commandClock(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFeedFlags(cmd, flags); err != nil {
+		return nil, err
+	}
+	order, err := parseFeedSort(flags.sortBy)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := parseFeedTemplate(cmd, flags)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := loadFeedConfig(cmd, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &feedRun{cmd: cmd, flags: flags, cfg: cfg, now: now, order: order, tmpl: tmpl, meter: youtube.NewQuotaMeter(), ttl: cfg.Cache}
+	r.recorder = runs.NewRecorder(now, version, runtime.Version(), os.Args[1:])
+	r.warn = func(err error) {
+		r.recorder.Warn(err)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+	r.client = httpx.NewClient(httpx.WithTransport(httpx.NewRecordTransport(nil, r.recorder.Request)))
+	if flags.noCache {
+		r.ttl = config.CacheTTL{}
+	}
+	return r, nil
+}
+
+// checkFeedFlags rejects flags that don't go together, and an unknown
+// --format.
+func checkFeedFlags(cmd *cobra.Command, flags feedFlags) error {
+	if flags.staleOnly && flags.noCache {
+		return fmt.Errorf("--stale-only and --no-cache can't be combined")
+	}
+	switch flags.format {
+	case formatText:
+	case formatCSV, formatJSONFeed, formatICS:
+		if cmd.Flags().Changed("template") {
+			return fmt.Errorf("--template only applies to --format %s", formatText)
+		}
+		if flags.format != formatCSV && flags.stream {
+			return fmt.Errorf("--stream doesn't apply to --format %s, which is one document", flags.format)
+		}
+	default:
+		return fmt.Errorf("invalid --format %q: must be %s, %s, %s or %s", flags.format, formatText, formatCSV, formatJSONFeed, formatICS)
+	}
+	return nil
+}
+
+// parseFeedSort reads --sort: newest, the feed's natural order, or growth.
+func parseFeedSort(sortBy string) (aggregator.Sort, error) {
+	switch sortBy {
+	case "newest":
+		return "", nil
+	case string(aggregator.SortGrowth):
+		return aggregator.SortGrowth, nil
+	}
+	return "", fmt.Errorf("invalid --sort %q: must be newest or %s", sortBy, aggregator.SortGrowth)
+}
+
+// parseFeedTemplate parses --template, returning nil when it isn't set.
+func parseFeedTemplate(cmd *cobra.Command, flags feedFlags) (*template.Template, error) {
+	if !cmd.Flags().Changed("template") {
+		return nil, nil
+	}
+	tmpl, err := display.ParseTemplate(flags.itemTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("--template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// loadFeedConfig loads the configuration, with the layout flags set on top
+// of it.
+func loadFeedConfig(cmd *cobra.Command, flags feedFlags) (config.Config, error) {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		return config.Config{}, err
+	}
+	if err := overrideDisplay(cmd, &cfg.Display, flags.layout); err != nil {
+		return config.Config{}, err
+	}
+	if flags.noColor {
+		cfg.Display.Color = false
+	}
+	return cfg, nil
+}
+
+// cached returns the run's HTTP client with a response cache named name,
+// whose entries stay fresh for ttl.
+func (r *feedRun) cached(name string, ttl time.Duration, opts ...httpx.CacheOption) *http.Client {
+	return cachedClient(r.client, filepath.Join(r.cfg.CacheDir, "http", name), ttl, r.now, opts...)
+}
+
+// youtubeOptions configures the YouTube API clients with the quota meter,
+// rate limit, API URL and key pool set up. save writes the key pool back.
+func (r *feedRun) youtubeOptions() (opts []youtube.ClientOption, save func()) {
+	opts = []youtube.ClientOption{youtube.WithQuotaMeter(r.meter), youtube.WithClock(r.now)}
+	if r.cfg.YouTube.RateLimit > 0 {
+		opts = append(opts, youtube.WithRateLimiter(youtube.NewRateLimiter(r.cfg.YouTube.RateLimit, int(math.Ceil(r.cfg.YouTube.RateLimit)))))
+	}
+	if r.cfg.YouTube.APIURL != "" {
+		opts = append(opts, youtube.WithBaseURL(r.cfg.YouTube.APIURL))
+	}
+	if len(r.cfg.YouTube.APIKeys) == 0 {
+		return opts, func() {}
+	}
+	keys, err := youtube.OpenKeyPool(keyPoolPath(r.cfg), r.cfg.YouTube.APIKeys, r.now)
+	if err != nil {
+		r.warn(err)
+		return opts, func() {}
+	}
+	save = func() {
+		if err := keys.Save(); err != nil {
+			r.warn(err)
+		}
+	}
+	return append(opts, youtube.WithKeyPool(keys)), save
+}
+
+// registry returns a registry of every source configured, YouTube read
+// with opts for accounts.
+func (r *feedRun) registry(ctx context.Context, accounts []string, opts []youtube.ClientOption) (*source.Registry, error) {
+	registry := source.NewRegistry()
+	if err := r.registerYouTube(ctx, registry, accounts, opts); err != nil {
+		return nil, err
+	}
+	r.registerFeeds(registry)
+	if r.cfg.Twitch.ClientID != "" {
+		tokens, err := twitchTokenSource(ctx, r.cfg)
+		if err != nil {
+			return nil, err
+		}
+		registry.Register(source.NewTwitch(twitch.NewClient(r.cfg.Twitch.ClientID, tokens, twitch.WithHTTPClient(r.client)), r.cfg.Limits.Twitch))
+	}
+	if len(r.cfg.Podcast.URLs) > 0 {
+		registry.Register(source.NewPodcast(podcast.NewClient(podcast.WithHTTPClient(r.cached("podcast", r.ttl.Podcast))), r.cfg.Podcast.URLs, r.cfg.Limits.PodcastFeed))
+	}
+	return registry, nil
+}
+
+// registerYouTube registers the YouTube channels followed by their feeds,
+// if any are set, or else the subscriptions of each of accounts.
+func (r *feedRun) registerYouTube(ctx context.Context, registry *source.Registry, accounts []string, opts []youtube.ClientOption) error {
+	cfg := r.cfg
+	if len(cfg.YouTube.Channels) > 0 {
+		feedOpts := []youtube.ClientOption{youtube.WithHTTPClient(r.cached("feeds", r.ttl.YouTube))}
+		if cfg.YouTube.APIURL != "" {
+			feedOpts = append(feedOpts, youtube.WithFeedURL(strings.TrimRight(cfg.YouTube.APIURL, "/")+"/feeds/videos.xml"))
+		}
+		registry.Register(source.NewYouTubeFeeds(youtube.NewClient(nil, feedOpts...), cfg.YouTube.Channels, cfg.Limits.YouTubeChannel, cfg.YouTube.Groups))
+		return nil
+	}
+	for _, account := range accounts {
+		tokens, err := youtubeTokenSource(ctx, cfg, account)
+		if err != nil {
+			return err
+		}
+		accountOpts := append([]youtube.ClientOption{
+			youtube.WithHTTPClient(r.cached(youtubeTokenKey(account), r.ttl.YouTube)),
+			youtube.WithTokenSource(tokens),
+			youtube.WithChannelCache(filepath.Join(cfg.CacheDir, "channels", youtubeTokenKey(account)+".json")),
+		}, opts...)
+		registry.Register(source.NewYouTube(youtube.NewClient(nil, accountOpts...), account, cfg.Limits.YouTubeChannel, cfg.YouTube.Groups, cfg.YouTube.Region))
+	}
+	return nil
+}
+
+// registerFeeds registers the sources read without credentials, from
+// Substack to GitHub.
+func (r *feedRun) registerFeeds(registry *source.Registry) {
+	cfg, ttl := r.cfg, r.ttl
+	if len(cfg.Substack.URLs) > 0 {
+		registry.Register(source.NewSubstack(substack.NewClient(substack.WithHTTPClient(r.cached("substack", ttl.Substack)), substack.WithCacheDir(filepath.Join(cfg.CacheDir, "substack")), substack.WithHeaders(cfg.Substack.HeadersFor)), cfg.Substack.URLs, cfg.Limits.SubstackPublication, cfg.Substack.AuthorsFor, cfg.Substack.SectionsFor))
+	}
+	if len(cfg.Substack.Notes) > 0 {
+		registry.Register(source.NewSubstackNotes(substack.NewClient(substack.WithHTTPClient(r.cached("substack", ttl.Substack)), substack.WithHeaders(cfg.Substack.HeadersFor)), cfg.Substack.Notes, cfg.Limits.Substack))
+	}
+	if cfg.Reader.URL != "" {
+		registry.Register(source.NewReader(greader.NewClient(cfg.Reader.URL, cfg.Reader.User, cfg.Reader.Password, greader.WithHTTPClient(r.client)), cfg.Reader.URL, cfg.Limits.Reader))
+	}
+	if len(cfg.Bridge.URLs) > 0 {
+		registry.Register(source.NewBridge(rssbridge.NewClient(rssbridge.WithHTTPClient(r.client)), cfg.Bridge.URLs, cfg.Limits.BridgeFeed))
+	}
+	if len(cfg.PeerTube.Channels) > 0 {
+		registry.Register(source.NewPeerTube(peertube.NewClient(peertube.WithHTTPClient(r.cached("peertube", ttl.PeerTube))), cfg.PeerTube.Channels, cfg.Limits.PeerTubeChannel))
+	}
+	if cfg.Arxiv.Enabled() {
+		query := arxiv.Query{Categories: cfg.Arxiv.Categories, Authors: cfg.Arxiv.Authors, Keywords: cfg.Arxiv.Keywords}
+		registry.Register(source.NewArxiv(arxiv.NewClient(arxiv.WithHTTPClient(r.cached("arxiv", ttl.Arxiv))), query, cfg.Limits.Arxiv))
+	}
+	if len(cfg.Lobsters.URLs) > 0 {
+		registry.Register(source.NewLobsters(lobsters.NewClient(lobsters.WithHTTPClient(r.cached("lobsters", ttl.Lobsters))), cfg.Lobsters.URLs, cfg.Limits.LobstersSite, cfg.Lobsters.TagsFor))
+	}
+	if len(cfg.Medium.URLs) > 0 {
+		registry.Register(source.NewMedium(medium.NewClient(medium.WithHTTPClient(r.cached("medium", ttl.Medium))), cfg.Medium.URLs, cfg.Limits.MediumPage))
+	}
+	if len(cfg.GitHub.Repos) > 0 || cfg.GitHub.Starred != "" {
+		client := github.NewClient(github.WithHTTPClient(r.cached("github", ttl.GitHub)), github.WithToken(cfg.GitHub.Token))
+		registry.Register(source.NewGitHub(client, cfg.GitHub.Repos, cfg.GitHub.Starred, cfg.Limits.GitHubRepo))
+	}
+}
+
+// fetchOptions has fetches report to the run's recorder and record when
+// each feed was fetched, serving the last items of fresh ones with
+// --stale-only. save writes the fetch times back.
+func (r *feedRun) fetchOptions() (opts source.FetchOptions, save func()) {
+	opts = source.FetchOptions{Warn: r.warn, Concurrency: r.cfg.Concurrency, Finished: r.recorder.Source, Unhealthy: r.recorder.Unhealthy, Clock: r.now}
+	fetchTimes, err := freshness.Open(filepath.Join(r.cfg.CacheDir, "freshness.json"), r.now)
+	if err != nil {
+		r.warn(err)
+		return opts, func() {}
+	}
+	opts.Fetched = fetchTimes.Record
+	opts.Listed = fetchTimes.RecordList
+	if r.flags.staleOnly {
+		opts.Cached = lastShown(r.cfg, fetchTimes)
+		opts.CachedList = fetchTimes.List
+	}
+	save = func() {
+		if err := fetchTimes.Save(); err != nil {
+			r.warn(err)
+		}
+	}
+	return opts, save
+}
+
+// feedOptions selects and orders the items to show as the configuration
+// and flags ask.
+func (r *feedRun) feedOptions() aggregator.FeedOptions {
+	opts := aggregator.FeedOptions{Limit: r.flags.limit, GroupBy: aggregator.GroupBy(r.cfg.Display.GroupBy), Sort: r.order, GroupLimits: r.cfg.Caps.Groups}
+	if len(r.cfg.Caps.Sources) > 0 {
+		opts.SourceLimits = make(map[aggregator.Source]int)
+		for source, n := range r.cfg.Caps.Sources {
+			opts.SourceLimits[aggregator.Source(source)] = n
+		}
+	}
+	for _, group := range r.flags.groups {
+		opts.Groups = append(opts.Groups, strings.ToLower(group))
+	}
+	opts.HidePaywalled = r.cfg.HidePaywalled
+	if r.cmd.Flags().Changed("hide-paywalled") {
+		opts.HidePaywalled = r.flags.hidePaywalled
+	}
+	return opts
+}
+
+// formatter lays the feed out for the command's output, with inline
+// thumbnails, loaded by the returned loader, where the terminal shows them.
+func (r *feedRun) formatter(ctx context.Context) (*display.TerminalFormatter, *thumbnailLoader, error) {
+	out := r.cmd.OutOrStdout()
+	layoutOpts, err := formatterOptions(r.cfg, out, r.now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.order == aggregator.SortGrowth {
+		layoutOpts = append(layoutOpts, display.WithDayHeaders(false))
+	}
+	var thumbnails *thumbnailLoader
+	if r.tmpl != nil {
+		layoutOpts = append(layoutOpts, display.WithTemplate(r.tmpl))
+	} else if protocol := imageProtocol(r.cfg.Display.InlineThumbnails, out, os.Getenv); protocol != "" && r.flags.format == formatText {
+		thumbnailTTL := thumbnailCacheTTL
+		if r.flags.noCache {
+			thumbnailTTL = 0
+		}
+		thumbnails = newThumbnailLoader(ctx, r.cached("thumbnails", thumbnailTTL, httpx.WithCacheMaxSize(thumbnailCacheSize)))
+		layoutOpts = append(layoutOpts, display.WithInlineThumbnails(protocol, thumbnails.load))
+	}
+	return display.NewTerminalFormatter(layoutOpts...), thumbnails, nil
+}
+
+// stream shows items as each source finishes, returning the items fetched,
+// those shown and why the fetch failed, if it did.
+func (r *feedRun) stream(ctx context.Context, registry *source.Registry, fetchOpts source.FetchOptions, pipeline *itemPipeline, agg *aggregator.Aggregator, feedOpts aggregator.FeedOptions, formatter *display.TerminalFormatter) (fetched, items []aggregator.FeedItem, err error) {
+	var mu sync.Mutex
+	batches := make(chan []aggregator.FeedItem)
+	fetchOpts.Progress = func(batch []aggregator.FeedItem) {
+		mu.Lock()
+		batch = pipeline.process(ctx, batch)
+		fetched = append(fetched, batch...)
+		mu.Unlock()
+		batches <- batch
+	}
+	go func() {
+		_, err = registry.FetchAll(ctx, fetchOpts)
+		close(batches)
+	}()
+	out := r.cmd.OutOrStdout()
+	if r.flags.format != formatCSV {
+		items = formatter.StreamFeed(out, agg.Stream(batches, feedOpts))
+		return fetched, items, err
+	}
+	items, writeErr := display.StreamCSV(out, agg.Stream(batches, feedOpts))
+	if writeErr != nil {
+		r.warn(writeErr)
+	}
+	return fetched, items, err
+}
+
+// recordQuota adds the YouTube quota the run used to today's usage, and
+// reports it as --show-quota and the budget ask.
+func (r *feedRun) recordQuota(usage youtube.QuotaUsage) {
+	usage.Add(r.meter.Units())
+	if err := usage.Save(quotaPath(r.cfg)); err != nil {
+		r.warn(err)
+	}
+	if r.flags.showQuota {
+		printQuotaSummary(r.cmd.ErrOrStderr(), r.meter.Units(), usage, r.cfg.YouTube.QuotaBudget)
+	}
+	if usage.Units > r.cfg.YouTube.QuotaBudget {
+		r.warn(fmt.Errorf("estimated YouTube quota usage today (%d units) exceeds the budget of %d", usage.Units, r.cfg.YouTube.QuotaBudget))
+	}
+}
+
+// show writes the feed of the items in agg in the format asked for,
+// returning the items shown. An ICS calendar lists every item.
+func (r *feedRun) show(agg *aggregator.Aggregator, feedOpts aggregator.FeedOptions, formatter *display.TerminalFormatter, thumbnails *thumbnailLoader) []aggregator.FeedItem {
+	if r.flags.format == formatICS {
+		feedOpts.Limit = 0
+	}
+	items := agg.GetFeed(feedOpts)
+	out := r.cmd.OutOrStdout()
+	var err error
+	switch r.flags.format {
+	case formatCSV:
+		err = display.WriteCSV(out, items)
+	case formatJSONFeed:
+		err = display.WriteJSONFeed(out, "feedmix", items)
+	case formatICS:
+		err = display.WriteICS(out, items, r.now())
+	default:
+		pager := r.cfg.Pager
+		if thumbnails != nil && !r.cfg.Display.Compact {
+			thumbnails.prefetch(items, r.cfg.Concurrency)
+			pager = ""
+		}
+		err = writePaged(r.cmd, pager, formatter.FormatFeed(items))
+	}
+	if err != nil {
+		r.warn(err)
+	}
+	return items
+}
+
+// finish remembers the items shown for 'feedmix open', notifies about the
+// newly discovered ones and logs both.
+func (r *feedRun) finish(styles notificationStyles, pipeline *itemPipeline, items []aggregator.FeedItem) {
+	if err := saveLastFeed(r.cfg, items); err != nil {
+		r.warn(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	notifyDiscovered(ctx, r.cfg, styles, r.client, r.now, pipeline.announced(), r.warn)
+
+	if r.cfg.EventLog == "" {
+		return
+	}
+	events := eventlog.New(r.cfg.EventLog, eventlog.WithClock(r.now))
+	if err := events.Record(eventlog.Discovered, pipeline.discovered); err != nil {
+		r.warn(err)
+	}
+	if err := events.Record(eventlog.Displayed, items); err != nil {
+		r.warn(err)
+	}
+}
@@ -4,20 +4,23 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"path/filepath"
 	"runtime/debug"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 
 	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
 	"github.com/gauthierbraillon/feedmix/internal/display"
-	"github.com/gauthierbraillon/feedmix/internal/substack"
-	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/internal/freshness"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
 	"github.com/gauthierbraillon/feedmix/pkg/oauth"
 )
 
@@ -65,20 +68,20 @@ func main() {
 	// Load .env file if it exists (silently ignore if not found)
 	_ = godotenv.Load()
 
-	if err := newRootCmd().Execute(); err != nil {
-		os.Exit(1)
+	prof := &profiler{}
+	err := newRootCmd(prof).Execute()
+	if stopErr := prof.stop(); stopErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", stopErr)
+		err = stopErr
 	}
-}
-
-func getConfigDir() string {
-	if dir := os.Getenv("FEEDMIX_CONFIG_DIR"); dir != "" {
-		return dir
+	if err != nil {
+		os.Exit(1)
 	}
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "feedmix")
 }
 
-func newRootCmd() *cobra.Command {
+// newRootCmd builds the command tree. prof is started before the command
+// runs; the caller stops it once Execute returns, whether or not it failed.
+func newRootCmd(prof *profiler) *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "feedmix",
 		Short:   "Aggregate feeds from YouTube and Substack",
@@ -86,142 +89,258 @@ func newRootCmd() *cobra.Command {
 		Version: version,
 	}
 
+	addProfileFlags(rootCmd, prof)
+	rootCmd.PersistentFlags().String("now", "", "Pretend the current time is this, e.g. 2024-01-15T12:00:00Z")
+	_ = rootCmd.PersistentFlags().MarkHidden("now")
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Don't show long output through the pager (FEEDMIX_PAGER, PAGER)")
+	rootCmd.PersistentFlags().Bool("strict", false, "Fail if FEEDMIX_ environment variables that feedmix doesn't read are set, e.g. misspelled ones (FEEDMIX_STRICT)")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkStrict(cmd); err != nil {
+			return err
+		}
+		return prof.start()
+	}
+
 	rootCmd.SetVersionTemplate("feedmix version {{.Version}}\n")
 	rootCmd.AddCommand(newFeedCmd())
 	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newQuotaCmd())
+	rootCmd.AddCommand(newAuthCmd())
+	rootCmd.AddCommand(newOpenCmd())
+	rootCmd.AddCommand(newReadCmd())
+	rootCmd.AddCommand(newSaveCmd())
+	rootCmd.AddCommand(newSavedCmd())
+	rootCmd.AddCommand(newPickCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newDemoCmd())
+	rootCmd.AddCommand(newRunsCmd())
+	rootCmd.AddCommand(newYouTubeCmd())
+	rootCmd.AddCommand(newTranscriptCmd())
+	rootCmd.AddCommand(newSyncCmd())
+	rootCmd.AddCommand(newSchemaCmd())
 
 	return rootCmd
 }
 
-func newFeedCmd() *cobra.Command {
-	var limit int
-
-	cmd := &cobra.Command{
-		Use:   "feed",
-		Short: "Display unified feed",
-		Long:  "Display your YouTube subscriptions and Substack newsletters in a unified feed.",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
+// checkStrict fails in strict mode, set with --strict or FEEDMIX_STRICT, if
+// the environment has FEEDMIX_ variables feedmix doesn't read. Invalid
+// settings are left for the command to report.
+func checkStrict(cmd *cobra.Command) error {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		return nil
+	}
+	if strict, _ := cmd.Flags().GetBool("strict"); !strict && !cfg.Strict {
+		return nil
+	}
+	return cfg.CheckEnvironment(os.Environ())
+}
 
-			refreshToken := os.Getenv("FEEDMIX_YOUTUBE_REFRESH_TOKEN")
-			if refreshToken == "" {
-				return fmt.Errorf("missing credentials: set FEEDMIX_YOUTUBE_REFRESH_TOKEN (run 'feedmix config' for setup instructions)")
-			}
+// commandClock returns the clock cmd measures time with: the system clock,
+// or the time set with the hidden --now flag.
+func commandClock(cmd *cobra.Command) (clock.Clock, error) {
+	raw, _ := cmd.Flags().GetString("now")
+	if raw == "" {
+		return clock.System, nil
+	}
+	t, err := clock.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("--now: %w", err)
+	}
+	return clock.Fixed(t), nil
+}
 
-			id := os.Getenv("FEEDMIX_YOUTUBE_CLIENT_ID")
-			if id == "" {
-				id = clientID
-			}
-			secret := os.Getenv("FEEDMIX_YOUTUBE_CLIENT_SECRET")
-			if secret == "" {
-				secret = clientSecret
-			}
+// Output formats of the feed and saved lists.
+const (
+	formatText     = "text"
+	formatCSV      = "csv"
+	formatJSONFeed = "jsonfeed"
+	formatICS      = "ics"
+)
 
-			config := oauth.YouTubeOAuthConfig(id, secret)
-			if tokenURL := os.Getenv("FEEDMIX_OAUTH_TOKEN_URL"); tokenURL != "" {
-				config.TokenURL = tokenURL
-			}
+// youtubeTokenSource returns a refreshing token source for account, checking
+// up front that its refresh token is still accepted.
+func youtubeTokenSource(ctx context.Context, cfg config.Config, account string) (oauth.TokenSource, error) {
+	startToken, stored, err := youtubeToken(cfg, account)
+	if err != nil {
+		return nil, err
+	}
+	if startToken == nil && account != "" {
+		return nil, fmt.Errorf("missing credentials for YouTube account %q: run 'feedmix auth youtube --account %s'", account, account)
+	}
+	if startToken == nil {
+		return nil, fmt.Errorf("missing credentials: set FEEDMIX_YOUTUBE_REFRESH_TOKEN or run 'feedmix auth youtube' (run 'feedmix config' for setup instructions)")
+	}
 
-			token, err := oauth.NewFlow(config).RefreshAccessToken(ctx, refreshToken)
-			if err != nil {
-				return fmt.Errorf("failed to refresh token: %w", err)
-			}
+	var tokenOpts []oauth.TokenSourceOption
+	if stored {
+		tokenOpts = append(tokenOpts, oauth.WithTokenStore(tokenStore(cfg), youtubeTokenKey(account)))
+	}
+	tokens := oauth.NewRefreshingTokenSource(oauth.NewFlow(youtubeOAuthConfig(cfg)), startToken, tokenOpts...)
+	if _, err := tokens.Token(ctx); err != nil {
+		if account != "" {
+			return nil, fmt.Errorf("failed to refresh token for YouTube account %q: %w", account, err)
+		}
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return tokens, nil
+}
 
-			opts := []youtube.ClientOption{}
-			if apiURL := os.Getenv("FEEDMIX_API_URL"); apiURL != "" {
-				opts = append(opts, youtube.WithBaseURL(apiURL))
-			}
-			client := youtube.NewClient(token, opts...)
+// addLayoutFlags adds the flags overrideDisplay reads to cmd, storing
+// their values in layout.
+func addLayoutFlags(cmd *cobra.Command, layout *config.Display) {
+	flags := cmd.Flags()
+	flags.BoolVar(&layout.Description, "description", false, "Show item descriptions (FEEDMIX_SHOW_DESCRIPTION)")
+	flags.IntVar(&layout.DescriptionLength, "description-length", config.DefaultDescriptionLength, "Characters of each description to show (FEEDMIX_DESCRIPTION_LENGTH)")
+	flags.IntVar(&layout.TitleLength, "title-length", 0, "Truncate titles to this many characters, 0 for no limit (FEEDMIX_TITLE_LENGTH)")
+	flags.IntVar(&layout.Width, "width", 0, "Wrap titles and descriptions to this many columns, 0 to fit the terminal (FEEDMIX_WIDTH)")
+	flags.BoolVar(&layout.Engagement, "engagement", true, "Show view, like and comment counts (FEEDMIX_SHOW_ENGAGEMENT)")
+	flags.BoolVar(&layout.Thumbnails, "thumbnails", false, "Show thumbnail URLs (FEEDMIX_SHOW_THUMBNAILS)")
+	flags.BoolVar(&layout.Details, "details", false, "List the chapters of videos under them (FEEDMIX_SHOW_DETAILS)")
+	flags.StringVar(&layout.InlineThumbnails, "inline-thumbnails", "off", "Draw thumbnails in the terminal: auto, kitty, iterm, sixel or off (FEEDMIX_INLINE_THUMBNAILS)")
+	flags.BoolVar(&layout.Compact, "compact", false, "Show each item on one line (FEEDMIX_COMPACT)")
+	flags.BoolVar(&layout.DayHeaders, "day-headers", false, "Start each day with a Today, Yesterday or date header (FEEDMIX_DAY_HEADERS)")
+	flags.StringVar(&layout.GroupBy, "group-by", "", "Show the feed in sections per source or author: source, author or none (FEEDMIX_GROUP_BY)")
+	flags.StringVar(&layout.Theme, "theme", display.DefaultTheme, "Color theme: "+strings.Join(display.ThemeNames(), ", ")+" (FEEDMIX_THEME)")
+	flags.StringVar(&layout.Hyperlinks, "hyperlinks", config.HyperlinksAuto, "Make titles clickable links: auto, always or never (FEEDMIX_HYPERLINKS)")
+	flags.BoolVar(&layout.CalmTitles, "calm-titles", false, "Tone down clickbait titles: no emoji, [TAGS] or SHOUTING (FEEDMIX_CALM_TITLES)")
+}
 
-			subs, err := client.FetchSubscriptions(ctx)
-			if err != nil {
-				return err
-			}
+// overrideDisplay applies the layout flags the user set explicitly on top of
+// the layout configured in the environment.
+func overrideDisplay(cmd *cobra.Command, d *config.Display, flags config.Display) error {
+	if cmd.Flags().Changed("description") {
+		d.Description = flags.Description
+	}
+	if cmd.Flags().Changed("description-length") {
+		d.DescriptionLength = flags.DescriptionLength
+	}
+	if cmd.Flags().Changed("title-length") {
+		d.TitleLength = flags.TitleLength
+	}
+	if cmd.Flags().Changed("width") {
+		if flags.Width < 0 {
+			return fmt.Errorf("invalid --width %d: must be 0 or a positive number of columns", flags.Width)
+		}
+		d.Width = flags.Width
+	}
+	if cmd.Flags().Changed("engagement") {
+		d.Engagement = flags.Engagement
+	}
+	if cmd.Flags().Changed("thumbnails") {
+		d.Thumbnails = flags.Thumbnails
+	}
+	if cmd.Flags().Changed("details") {
+		d.Details = flags.Details
+	}
+	if cmd.Flags().Changed("inline-thumbnails") {
+		mode, err := config.ParseInlineThumbnails("--inline-thumbnails", flags.InlineThumbnails)
+		if err != nil {
+			return err
+		}
+		d.InlineThumbnails = mode
+	}
+	if cmd.Flags().Changed("calm-titles") {
+		d.CalmTitles = flags.CalmTitles
+	}
+	if cmd.Flags().Changed("compact") {
+		d.Compact = flags.Compact
+	}
+	if cmd.Flags().Changed("day-headers") {
+		d.DayHeaders = flags.DayHeaders
+	}
+	if cmd.Flags().Changed("group-by") {
+		by, err := config.ParseGroupBy("--group-by", flags.GroupBy)
+		if err != nil {
+			return err
+		}
+		d.GroupBy = by
+	}
+	if cmd.Flags().Changed("theme") {
+		d.Theme = flags.Theme
+	}
+	if cmd.Flags().Changed("hyperlinks") {
+		mode, err := config.ParseHyperlinks("--hyperlinks", flags.Hyperlinks)
+		if err != nil {
+			return err
+		}
+		d.Hyperlinks = mode
+	}
+	return nil
+}
 
-			agg := aggregator.New()
-			var mu sync.Mutex
-			var wg sync.WaitGroup
-			for _, sub := range subs {
-				wg.Add(1)
-				go func(sub youtube.Subscription) {
-					defer wg.Done()
-					videos, err := client.FetchRecentVideos(ctx, sub.ChannelID, 5)
-					if err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to fetch videos from %s: %v\n", sub.ChannelTitle, err)
-						return
-					}
-					items := make([]aggregator.FeedItem, 0, len(videos))
-					for _, video := range videos {
-						items = append(items, aggregator.FeedItem{
-							ID:          video.ID,
-							Source:      aggregator.SourceYouTube,
-							Type:        aggregator.ItemTypeVideo,
-							Title:       video.Title,
-							Description: video.Description,
-							Author:      video.ChannelTitle,
-							AuthorID:    video.ChannelID,
-							URL:         video.URL,
-							Thumbnail:   video.Thumbnail,
-							PublishedAt: video.PublishedAt,
-							Engagement: aggregator.Engagement{
-								Views: video.ViewCount,
-								Likes: video.LikeCount,
-							},
-						})
-					}
-					mu.Lock()
-					agg.AddItems(items)
-					mu.Unlock()
-				}(sub)
-			}
-			wg.Wait()
-
-			substackURLs := parseSubstackURLs(os.Getenv("FEEDMIX_SUBSTACK_URLS"))
-			if len(substackURLs) > 0 {
-				substackClient := substack.NewClient()
-				var substackMu sync.Mutex
-				var substackWg sync.WaitGroup
-				for _, pubURL := range substackURLs {
-					substackWg.Add(1)
-					go func(pubURL string) {
-						defer substackWg.Done()
-						posts, err := substackClient.FetchPosts(ctx, pubURL, 5)
-						if err != nil {
-							fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to fetch Substack feed from %s: %v\n", pubURL, err)
-							return
-						}
-						items := make([]aggregator.FeedItem, 0, len(posts))
-						for _, post := range posts {
-							items = append(items, aggregator.FeedItem{
-								ID:          post.ID,
-								Source:      aggregator.SourceSubstack,
-								Type:        aggregator.ItemTypeArticle,
-								Title:       post.Title,
-								Description: post.Description,
-								Author:      post.Author,
-								URL:         post.URL,
-								PublishedAt: post.PublishedAt,
-							})
-						}
-						substackMu.Lock()
-						agg.AddItems(items)
-						substackMu.Unlock()
-					}(pubURL)
-				}
-				substackWg.Wait()
+// lastShown answers FetchOptions.Cached for --stale-only: the items of a
+// fresh feed are those of its last fetch that the last feed displayed.
+// Without a last feed to take them from, every feed is fetched.
+func lastShown(cfg config.Config, fetchTimes *freshness.Store) func(key string) ([]aggregator.FeedItem, bool) {
+	shown, err := loadLastFeed(cfg)
+	if err != nil {
+		return nil
+	}
+	byKey := make(map[string]aggregator.FeedItem, len(shown))
+	for _, item := range shown {
+		byKey[string(item.Source)+":"+item.ID] = item
+	}
+	return func(key string) ([]aggregator.FeedItem, bool) {
+		ids, ok := fetchTimes.Fresh(key)
+		if !ok {
+			return nil, false
+		}
+		source, _, _ := strings.Cut(key, ":")
+		var items []aggregator.FeedItem
+		for _, id := range ids {
+			if item, ok := byKey[source+":"+id]; ok {
+				items = append(items, item)
 			}
+		}
+		return items, true
+	}
+}
 
-			items := agg.GetFeed(aggregator.FeedOptions{Limit: limit})
-			formatter := display.NewTerminalFormatter()
-			fmt.Fprint(cmd.OutOrStdout(), formatter.FormatFeed(items))
+// formatterOptions configures the feed layout for output written to out,
+// with item ages measured at now. Colors are only used on a terminal.
+func formatterOptions(cfg config.Config, out io.Writer, now clock.Clock) ([]display.FormatterOption, error) {
+	opts := []display.FormatterOption{
+		display.WithClock(now),
+		display.WithLocale(cfg.Locale),
+		display.WithTitleLength(cfg.Display.TitleLength),
+		display.WithWidth(outputWidth(cfg.Display.Width, out, os.Getenv)),
+		display.WithEngagement(cfg.Display.Engagement),
+		display.WithThumbnails(cfg.Display.Thumbnails || cfg.Display.InlineThumbnails != ""),
+		display.WithDetails(cfg.Display.Details),
+		display.WithCalmTitles(cfg.Display.CalmTitles),
+		display.WithHyperlinks(hyperlinksEnabled(cfg.Display.Hyperlinks, out, os.Getenv)),
+		display.WithCompact(cfg.Display.Compact),
+		display.WithDayHeaders(cfg.Display.DayHeaders),
+		display.WithGroupBy(aggregator.GroupBy(cfg.Display.GroupBy)),
+	}
+	if cfg.Display.Description {
+		opts = append(opts, display.WithDescription(cfg.Display.DescriptionLength))
+	}
 
-			return nil
-		},
+	name := cfg.Display.Theme
+	if name == "" {
+		name = display.DefaultTheme
+	}
+	theme, ok := display.LookupTheme(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q: must be one of %s", name, strings.Join(display.ThemeNames(), ", "))
+	}
+	if cfg.Display.Color && isTerminal(out) && os.Getenv("TERM") != "dumb" {
+		opts = append(opts, display.WithTheme(theme))
 	}
+	return opts, nil
+}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Maximum items to display")
-	return cmd
+// cachedClient wraps client with a response cache in dir whose entries age
+// against now, further configured by opts; a zero ttl returns client
+// unchanged.
+func cachedClient(client *http.Client, dir string, ttl time.Duration, now clock.Clock, opts ...httpx.CacheOption) *http.Client {
+	if ttl <= 0 {
+		return client
+	}
+	opts = append([]httpx.CacheOption{httpx.WithCacheClock(now)}, opts...)
+	return &http.Client{Transport: httpx.NewCacheTransport(client.Transport, dir, ttl, opts...), Timeout: client.Timeout}
 }
 
 func credStatus(val string) string {
@@ -238,49 +357,86 @@ func resolveCredential(envVal, embedded string) string {
 	return embedded
 }
 
+// printYouTubeSetup shows the state of the YouTube Data API credentials and
+// how to get the missing ones.
+func printYouTubeSetup(out io.Writer, cfg config.Config) {
+	ytID := resolveCredential(cfg.YouTube.ClientID, clientID)
+	ytSecret := resolveCredential(cfg.YouTube.ClientSecret, clientSecret)
+	ytToken := cfg.YouTube.RefreshToken
+	if token, _, _ := youtubeToken(cfg, ""); token != nil {
+		ytToken = token.RefreshToken
+	}
+
+	fmt.Fprintf(out, "YouTube (required)\n")
+	fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_CLIENT_ID      %s\n", credStatus(ytID))
+	fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_CLIENT_SECRET  %s\n", credStatus(ytSecret))
+	fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_REFRESH_TOKEN  %s\n", credStatus(ytToken))
+	for _, account := range cfg.YouTube.Accounts {
+		status := "✓ authorized"
+		if token, _, _ := youtubeToken(cfg, account); token == nil {
+			status = "✗ run 'feedmix auth youtube --account " + account + "'"
+		}
+		fmt.Fprintf(out, "  account %-22s %s\n", account, status)
+	}
+
+	if ytID == "" || ytSecret == "" || ytToken == "" {
+		fmt.Fprint(out, "\n  To get credentials:\n")
+		fmt.Fprint(out, "    1. Create OAuth credentials (Desktop app):\n")
+		fmt.Fprint(out, "       https://console.cloud.google.com/apis/credentials\n")
+		fmt.Fprint(out, "    2. Enable YouTube Data API v3:\n")
+		fmt.Fprint(out, "       https://console.cloud.google.com/apis/library\n")
+		fmt.Fprint(out, "    3. Get a refresh token:\n")
+		fmt.Fprint(out, "       https://developers.google.com/oauthplayground\n")
+		fmt.Fprint(out, "       • Gear icon → Use your own OAuth credentials → enter Client ID + Secret\n")
+		fmt.Fprint(out, "       • Select scope: https://www.googleapis.com/auth/youtube.readonly\n")
+		fmt.Fprint(out, "       • Authorize APIs → Exchange authorization code → copy Refresh token\n")
+		fmt.Fprint(out, "    4. Add to your shell config:\n")
+		fmt.Fprint(out, "       # bash\n")
+		if ytID == "" {
+			fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_CLIENT_ID=<client-id>' >> ~/.bashrc\n")
+		}
+		if ytSecret == "" {
+			fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_CLIENT_SECRET=<client-secret>' >> ~/.bashrc\n")
+		}
+		if ytToken == "" {
+			fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_REFRESH_TOKEN=<refresh-token>' >> ~/.bashrc\n")
+		}
+		fmt.Fprint(out, "       # zsh: replace ~/.bashrc with ~/.zshrc\n")
+		if ytToken == "" {
+			fmt.Fprint(out, "       Or keep the refresh token out of your shell config:\n")
+			fmt.Fprint(out, "       export FEEDMIX_TOKEN_STORE=keyring   # optional: OS keyring instead of a 0600 file\n")
+			fmt.Fprint(out, "       feedmix auth youtube                 # paste the refresh token when prompted\n")
+		}
+		fmt.Fprint(out, "\n  Or skip the setup and follow channels by ID through their public RSS feeds,\n")
+		fmt.Fprint(out, "  with less detail and without your subscriptions:\n")
+		fmt.Fprint(out, "    export FEEDMIX_YOUTUBE_CHANNELS=UCxYz123ABC...,UCdef456...\n")
+	}
+}
+
 func newConfigCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Show configuration and setup instructions",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+
 			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "Configuration directory: %s\n\n", getConfigDir())
-
-			ytID := resolveCredential(os.Getenv("FEEDMIX_YOUTUBE_CLIENT_ID"), clientID)
-			ytSecret := resolveCredential(os.Getenv("FEEDMIX_YOUTUBE_CLIENT_SECRET"), clientSecret)
-			ytToken := os.Getenv("FEEDMIX_YOUTUBE_REFRESH_TOKEN")
-
-			fmt.Fprintf(out, "YouTube (required)\n")
-			fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_CLIENT_ID      %s\n", credStatus(ytID))
-			fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_CLIENT_SECRET  %s\n", credStatus(ytSecret))
-			fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_REFRESH_TOKEN  %s\n", credStatus(ytToken))
-
-			if ytID == "" || ytSecret == "" || ytToken == "" {
-				fmt.Fprint(out, "\n  To get credentials:\n")
-				fmt.Fprint(out, "    1. Create OAuth credentials (Desktop app):\n")
-				fmt.Fprint(out, "       https://console.cloud.google.com/apis/credentials\n")
-				fmt.Fprint(out, "    2. Enable YouTube Data API v3:\n")
-				fmt.Fprint(out, "       https://console.cloud.google.com/apis/library\n")
-				fmt.Fprint(out, "    3. Get a refresh token:\n")
-				fmt.Fprint(out, "       https://developers.google.com/oauthplayground\n")
-				fmt.Fprint(out, "       • Gear icon → Use your own OAuth credentials → enter Client ID + Secret\n")
-				fmt.Fprint(out, "       • Select scope: https://www.googleapis.com/auth/youtube.readonly\n")
-				fmt.Fprint(out, "       • Authorize APIs → Exchange authorization code → copy Refresh token\n")
-				fmt.Fprint(out, "    4. Add to your shell config:\n")
-				fmt.Fprint(out, "       # bash\n")
-				if ytID == "" {
-					fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_CLIENT_ID=<client-id>' >> ~/.bashrc\n")
-				}
-				if ytSecret == "" {
-					fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_CLIENT_SECRET=<client-secret>' >> ~/.bashrc\n")
-				}
-				if ytToken == "" {
-					fmt.Fprint(out, "       echo 'export FEEDMIX_YOUTUBE_REFRESH_TOKEN=<refresh-token>' >> ~/.bashrc\n")
-				}
-				fmt.Fprint(out, "       # zsh: replace ~/.bashrc with ~/.zshrc\n")
+			fmt.Fprintf(out, "Configuration directory: %s\n\n", cfg.Dir)
+
+			if channels := cfg.YouTube.Channels; len(channels) > 0 {
+				fmt.Fprintf(out, "YouTube (keyless)\n")
+				fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_CHANNELS  ✓ %d configured\n", len(channels))
+				fmt.Fprint(out, "\n  Channels are read from their public RSS feeds: no credentials or quota, but only\n")
+				fmt.Fprint(out, "  their 15 latest videos, without your subscriptions, topics or restrictions.\n")
+				fmt.Fprint(out, "  Unset FEEDMIX_YOUTUBE_CHANNELS to use the YouTube Data API instead.\n")
+			} else {
+				printYouTubeSetup(out, cfg)
 			}
 
-			substackURLs := parseSubstackURLs(os.Getenv("FEEDMIX_SUBSTACK_URLS"))
+			substackURLs := cfg.Substack.URLs
 			fmt.Fprint(out, "\nSubstack (optional)\n")
 			if len(substackURLs) == 0 {
 				fmt.Fprint(out, "  FEEDMIX_SUBSTACK_URLS  ✗ not configured\n")
@@ -296,25 +452,200 @@ func newConfigCmd() *cobra.Command {
 			} else {
 				fmt.Fprintf(out, "  FEEDMIX_SUBSTACK_URLS  ✓ %d configured\n", len(substackURLs))
 				for _, u := range substackURLs {
+					var details []string
+					if authors := cfg.Substack.AuthorsFor(u); len(authors) > 0 {
+						details = append(details, "authors: "+strings.Join(authors, ", "))
+					}
+					if sections := cfg.Substack.SectionsFor(u); len(sections) > 0 {
+						details = append(details, "sections: "+strings.Join(sections, ", "))
+					}
+					if headers := cfg.Substack.HeadersFor(u); len(headers) > 0 {
+						details = append(details, "headers: "+strings.Join(headerNames(headers), ", "))
+					}
+					if len(details) > 0 {
+						fmt.Fprintf(out, "    • %s (%s)\n", u, strings.Join(details, "; "))
+						continue
+					}
+					fmt.Fprintf(out, "    • %s\n", u)
+				}
+			}
+			if cfg.Substack.Cookie != "" {
+				fmt.Fprint(out, "  FEEDMIX_SUBSTACK_COOKIE  ✓ set (sent to publications on substack.com)\n")
+			}
+			if notes := cfg.Substack.Notes; len(notes) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_SUBSTACK_NOTES  ✓ %d configured\n", len(notes))
+			}
+
+			if cfg.Reader.URL != "" || len(cfg.Bridge.URLs) > 0 {
+				fmt.Fprint(out, "\nSelf-hosted feeds (optional)\n")
+				if cfg.Reader.URL != "" {
+					fmt.Fprintf(out, "  FEEDMIX_READER_URL       ✓ %s\n", cfg.Reader.URL)
+					fmt.Fprintf(out, "  FEEDMIX_READER_USER      %s\n", credStatus(cfg.Reader.User))
+					fmt.Fprintf(out, "  FEEDMIX_READER_PASSWORD  %s\n", credStatus(cfg.Reader.Password))
+				}
+				if len(cfg.Bridge.URLs) > 0 {
+					fmt.Fprintf(out, "  FEEDMIX_BRIDGE_URLS      ✓ %d configured\n", len(cfg.Bridge.URLs))
+				}
+			}
+			if len(cfg.Podcast.URLs) > 0 {
+				fmt.Fprint(out, "\nPodcasts (optional)\n")
+				fmt.Fprintf(out, "  FEEDMIX_PODCAST_URLS  ✓ %d configured\n", len(cfg.Podcast.URLs))
+			}
+			if len(cfg.PeerTube.Channels) > 0 {
+				fmt.Fprint(out, "\nPeerTube (optional)\n")
+				fmt.Fprintf(out, "  FEEDMIX_PEERTUBE_CHANNELS  ✓ %d configured\n", len(cfg.PeerTube.Channels))
+				for _, channel := range cfg.PeerTube.Channels {
+					fmt.Fprintf(out, "    • %s\n", channel)
+				}
+			}
+			if cfg.Arxiv.Enabled() {
+				fmt.Fprint(out, "\narXiv (optional)\n")
+				for _, setting := range []struct {
+					name   string
+					values []string
+				}{
+					{"FEEDMIX_ARXIV_CATEGORIES", cfg.Arxiv.Categories},
+					{"FEEDMIX_ARXIV_AUTHORS", cfg.Arxiv.Authors},
+					{"FEEDMIX_ARXIV_KEYWORDS", cfg.Arxiv.Keywords},
+				} {
+					if len(setting.values) > 0 {
+						fmt.Fprintf(out, "  %-24s ✓ %s\n", setting.name, strings.Join(setting.values, ", "))
+					}
+				}
+			}
+			if len(cfg.Lobsters.URLs) > 0 {
+				fmt.Fprint(out, "\nLink aggregators (optional)\n")
+				fmt.Fprintf(out, "  FEEDMIX_LOBSTERS_URLS  ✓ %d configured\n", len(cfg.Lobsters.URLs))
+				for _, u := range cfg.Lobsters.URLs {
+					if tags := cfg.Lobsters.TagsFor(u); len(tags) > 0 {
+						fmt.Fprintf(out, "    • %s (tags: %s)\n", u, strings.Join(tags, ", "))
+						continue
+					}
 					fmt.Fprintf(out, "    • %s\n", u)
 				}
 			}
+			if len(cfg.Medium.URLs) > 0 {
+				fmt.Fprint(out, "\nMedium (optional)\n")
+				fmt.Fprintf(out, "  FEEDMIX_MEDIUM_URLS  ✓ %d configured\n", len(cfg.Medium.URLs))
+				for _, u := range cfg.Medium.URLs {
+					fmt.Fprintf(out, "    • %s\n", u)
+				}
+			}
+			if len(cfg.GitHub.Repos) > 0 || cfg.GitHub.Starred != "" {
+				fmt.Fprint(out, "\nGitHub releases (optional)\n")
+				if len(cfg.GitHub.Repos) > 0 {
+					fmt.Fprintf(out, "  FEEDMIX_GITHUB_REPOS    ✓ %d configured\n", len(cfg.GitHub.Repos))
+				}
+				if cfg.GitHub.Starred != "" {
+					fmt.Fprintf(out, "  FEEDMIX_GITHUB_STARRED  ✓ %s\n", cfg.GitHub.Starred)
+				}
+				if cfg.GitHub.Token != "" {
+					fmt.Fprint(out, "  FEEDMIX_GITHUB_TOKEN    ✓ set\n")
+				} else {
+					fmt.Fprint(out, "  FEEDMIX_GITHUB_TOKEN    not set (GitHub allows 60 requests an hour without one)\n")
+				}
+			}
+			if cfg.Twitch.ClientID != "" {
+				fmt.Fprint(out, "\nTwitch (optional)\n")
+				fmt.Fprint(out, "  FEEDMIX_TWITCH_CLIENT_ID      ✓ set\n")
+				fmt.Fprintf(out, "  FEEDMIX_TWITCH_CLIENT_SECRET  %s\n", credStatus(cfg.Twitch.ClientSecret))
+				if cfg.Twitch.RefreshToken != "" {
+					fmt.Fprint(out, "  FEEDMIX_TWITCH_REFRESH_TOKEN  ✓ set\n")
+				} else {
+					fmt.Fprint(out, "  Run 'feedmix auth twitch' unless FEEDMIX_TWITCH_REFRESH_TOKEN is set.\n")
+				}
+			}
+
+			if cfg.Slack.WebhookURL != "" {
+				fmt.Fprint(out, "\nSlack notifications (optional)\n")
+				fmt.Fprint(out, "  FEEDMIX_SLACK_WEBHOOK_URL  ✓ set\n")
+				sources := make([]string, 0, len(cfg.Slack.Channels))
+				for source := range cfg.Slack.Channels {
+					sources = append(sources, source)
+				}
+				sort.Strings(sources)
+				for _, source := range sources {
+					fmt.Fprintf(out, "    • %s → %s\n", source, cfg.Slack.Channels[source])
+				}
+			}
+
+			if cfg.Discord.WebhookURL != "" {
+				fmt.Fprint(out, "\nDiscord notifications (optional)\n")
+				fmt.Fprint(out, "  FEEDMIX_DISCORD_WEBHOOK_URL  ✓ set\n")
+			}
+
+			if cfg.Push.Enabled() {
+				fmt.Fprint(out, "\nPush notifications (optional)\n")
+				if cfg.Push.NtfyTopic != "" {
+					fmt.Fprint(out, "  FEEDMIX_NTFY_TOPIC      ✓ set\n")
+				}
+				if cfg.Push.PushoverToken != "" {
+					fmt.Fprint(out, "  FEEDMIX_PUSHOVER_TOKEN  ✓ set\n")
+				}
+				if len(cfg.Push.Sources) > 0 {
+					fmt.Fprintf(out, "  FEEDMIX_PUSH_SOURCES    %s\n", strings.Join(cfg.Push.Sources, ", "))
+				}
+				if len(cfg.Push.Keywords) > 0 {
+					fmt.Fprintf(out, "  FEEDMIX_PUSH_KEYWORDS   %s\n", strings.Join(cfg.Push.Keywords, ", "))
+				}
+			}
+
+			fmt.Fprint(out, "\nFetch limits\n")
+			fmt.Fprintf(out, "  FEEDMIX_YOUTUBE_FETCH_LIMIT   %d per channel\n", cfg.Limits.YouTube)
+			fmt.Fprintf(out, "  FEEDMIX_SUBSTACK_FETCH_LIMIT  %d per publication\n", cfg.Limits.Substack)
+			if cfg.Reader.URL != "" {
+				fmt.Fprintf(out, "  FEEDMIX_READER_FETCH_LIMIT    %d in all\n", cfg.Limits.Reader)
+			}
+			if len(cfg.Bridge.URLs) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_BRIDGE_FETCH_LIMIT    %d per feed\n", cfg.Limits.Bridge)
+			}
+			if len(cfg.Podcast.URLs) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_PODCAST_FETCH_LIMIT   %d per feed\n", cfg.Limits.Podcast)
+			}
+			if len(cfg.PeerTube.Channels) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_PEERTUBE_FETCH_LIMIT  %d per channel\n", cfg.Limits.PeerTube)
+			}
+			if cfg.Arxiv.Enabled() {
+				fmt.Fprintf(out, "  FEEDMIX_ARXIV_FETCH_LIMIT     %d in all\n", cfg.Limits.Arxiv)
+			}
+			if len(cfg.Lobsters.URLs) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_LOBSTERS_FETCH_LIMIT  %d per site\n", cfg.Limits.Lobsters)
+			}
+			if len(cfg.Medium.URLs) > 0 {
+				fmt.Fprintf(out, "  FEEDMIX_MEDIUM_FETCH_LIMIT    %d per writer or publication\n", cfg.Limits.Medium)
+			}
+			if len(cfg.GitHub.Repos) > 0 || cfg.GitHub.Starred != "" {
+				fmt.Fprintf(out, "  FEEDMIX_GITHUB_FETCH_LIMIT    %d per repository\n", cfg.Limits.GitHub)
+			}
+			if cfg.Twitch.ClientID != "" {
+				fmt.Fprintf(out, "  FEEDMIX_TWITCH_FETCH_LIMIT    %d per channel\n", cfg.Limits.Twitch)
+			}
+			for _, key := range sortedKeys(cfg.Limits.Overrides) {
+				fmt.Fprintf(out, "    • %s = %d\n", key, cfg.Limits.Overrides[key])
+			}
+			fmt.Fprint(out, "\nRun 'feedmix config dump' for every setting.\n")
 			return nil
 		},
 	}
+	cmd.AddCommand(newConfigDumpCmd())
+	return cmd
 }
 
-func parseSubstackURLs(raw string) []string {
-	if raw == "" {
-		return nil
+// headerNames lists configured header names without their values, which may be secrets.
+func headerNames(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
 	}
-	parts := strings.Split(raw, ",")
-	urls := make([]string, 0, len(parts))
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		if p != "" {
-			urls = append(urls, p)
-		}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
-	return urls
+	sort.Strings(keys)
+	return keys
 }
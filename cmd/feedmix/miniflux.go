@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/medium"
+	"github.com/gauthierbraillon/feedmix/internal/miniflux"
+	"github.com/gauthierbraillon/feedmix/internal/substack"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+func newExportMinifluxCmd() *cobra.Command {
+	var category string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "miniflux",
+		Short: "Subscribe Miniflux to the feeds of what feedmix follows",
+		Long: "Subscribes the Miniflux instance at FEEDMIX_MINIFLUX_URL to the public feed of every YouTube channel, Substack " +
+			"publication, Medium page, podcast, RSS-Bridge feed, GitHub repository and Lobsters site feedmix follows, in " +
+			"--category. YouTube channels are those of FEEDMIX_YOUTUBE_CHANNELS or, without it, the subscriptions of your " +
+			"YouTube accounts, which feedmix reads with its own credentials so Miniflux needs none. Feeds Miniflux already " +
+			"has are skipped, so the command can run again as you follow more.\n\n" +
+			"Miniflux serves the Google Reader API too: set FEEDMIX_READER_URL to it and run 'feedmix sync' to keep read " +
+			"and starred items in step both ways.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if cfg.Miniflux.URL == "" || cfg.Miniflux.Token == "" {
+				return errors.New("no Miniflux to export to: set FEEDMIX_MINIFLUX_URL and FEEDMIX_MINIFLUX_TOKEN")
+			}
+			ctx := context.Background()
+			httpClient := httpx.NewClient()
+
+			channels := cfg.YouTube.Channels
+			if len(channels) == 0 {
+				if channels, err = subscribedChannels(ctx, cfg); err != nil {
+					return err
+				}
+			}
+			client := miniflux.NewClient(cfg.Miniflux.URL, cfg.Miniflux.Token, miniflux.WithHTTPClient(httpClient))
+			warn := func(err error) { fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err) }
+			return subscribeMiniflux(ctx, client, bridgedFeeds(cfg, channels), category, dryRun, cmd.OutOrStdout(), warn)
+		},
+	}
+	cmd.Flags().StringVar(&category, "category", "feedmix", "Miniflux category to add the feeds to, created if missing")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the feeds that would be added without changing Miniflux")
+	return cmd
+}
+
+// subscribedChannels returns the channel IDs the YouTube accounts subscribe
+// to, or none when no account has credentials.
+func subscribedChannels(ctx context.Context, cfg config.Config) ([]string, error) {
+	accounts, err := youtubeAccounts(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	var channels []string
+	for _, account := range accounts {
+		if token, _, err := youtubeToken(cfg, account); err != nil || token == nil {
+			continue
+		}
+		tokens, err := youtubeTokenSource(ctx, cfg, account)
+		if err != nil {
+			return nil, err
+		}
+		opts := []youtube.ClientOption{youtube.WithTokenSource(tokens)}
+		if cfg.YouTube.APIURL != "" {
+			opts = append(opts, youtube.WithBaseURL(cfg.YouTube.APIURL))
+		}
+		subs, err := youtube.NewClient(nil, opts...).FetchSubscriptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, sub := range subs {
+			channels = append(channels, sub.ChannelID)
+		}
+	}
+	return channels, nil
+}
+
+// bridgedFeeds returns the feed URLs of the YouTube channels and of the
+// other sources cfg follows that publish a feed, without repeats.
+func bridgedFeeds(cfg config.Config, channels []string) []string {
+	var feeds []string
+	for _, id := range channels {
+		feeds = append(feeds, youtube.FeedURL(id))
+	}
+	for _, u := range cfg.Substack.URLs {
+		feeds = append(feeds, substack.FeedURL(u))
+	}
+	for _, u := range cfg.Medium.URLs {
+		feeds = append(feeds, medium.FeedURL(u))
+	}
+	feeds = append(feeds, cfg.Podcast.URLs...)
+	feeds = append(feeds, cfg.Bridge.URLs...)
+	for _, repo := range cfg.GitHub.Repos {
+		feeds = append(feeds, "https://github.com/"+repo+"/releases.atom")
+	}
+	for _, site := range cfg.Lobsters.URLs {
+		feeds = append(feeds, strings.TrimRight(site, "/")+"/rss")
+	}
+
+	seen := make(map[string]bool)
+	unique := feeds[:0]
+	for _, feed := range feeds {
+		if !seen[feed] {
+			seen[feed] = true
+			unique = append(unique, feed)
+		}
+	}
+	return unique
+}
+
+// subscribeMiniflux adds the feeds Miniflux doesn't have yet to category,
+// creating it if needed. A feed Miniflux refuses is reported via warn.
+func subscribeMiniflux(ctx context.Context, client *miniflux.Client, feeds []string, category string, dryRun bool, out io.Writer, warn func(error)) error {
+	existing, err := client.Feeds(ctx)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool)
+	for _, feed := range existing {
+		have[strings.TrimRight(feed.FeedURL, "/")] = true
+	}
+	var missing []string
+	for _, feed := range feeds {
+		if !have[strings.TrimRight(feed, "/")] {
+			missing = append(missing, feed)
+		}
+	}
+	if dryRun {
+		for _, feed := range missing {
+			fmt.Fprintf(out, "Would subscribe to %s\n", feed)
+		}
+		fmt.Fprintf(out, "%d of %d feeds are missing from Miniflux\n", len(missing), len(feeds))
+		return nil
+	}
+	if len(missing) == 0 {
+		fmt.Fprintf(out, "Miniflux already has all %d feeds\n", len(feeds))
+		return nil
+	}
+
+	categoryID, err := minifluxCategory(ctx, client, category)
+	if err != nil {
+		return err
+	}
+	added := 0
+	for _, feed := range missing {
+		if _, err := client.CreateFeed(ctx, feed, categoryID); err != nil {
+			warn(err)
+			continue
+		}
+		added++
+	}
+	fmt.Fprintf(out, "Subscribed Miniflux to %d new feeds in %q (%d were already there)\n", added, category, len(feeds)-len(missing))
+	return nil
+}
+
+// minifluxCategory returns the ID of the category titled title, matched
+// regardless of case, creating it when Miniflux has none.
+func minifluxCategory(ctx context.Context, client *miniflux.Client, title string) (int64, error) {
+	categories, err := client.Categories(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Title, title) {
+			return c.ID, nil
+		}
+	}
+	created, err := client.CreateCategory(ctx, title)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Miniflux category %q: %w", title, err)
+	}
+	return created.ID, nil
+}
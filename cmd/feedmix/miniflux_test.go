@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/miniflux"
+)
+
+// TestBridgedFeeds_ListsTheFeedOfEverySource verifies that each followed
+// source is turned into the public feed Miniflux can read.
+func TestBridgedFeeds_ListsTheFeedOfEverySource(t *testing.T) {
+	cfg := config.Config{
+		Substack: config.Substack{URLs: []string{"https://review.substack.com/"}},
+		Medium:   config.Medium{URLs: []string{"https://medium.com/@jane"}},
+		Podcast:  config.Podcast{URLs: []string{"https://example.com/show.xml"}},
+		GitHub:   config.GitHub{Repos: []string{"cli/cli"}},
+		Lobsters: config.Lobsters{URLs: []string{"https://lobste.rs/"}},
+	}
+	got := bridgedFeeds(cfg, []string{"UC_x5XG1OV2P6uZZ5FSM9Ttw", "UC_x5XG1OV2P6uZZ5FSM9Ttw"})
+	want := []string{
+		"https://www.youtube.com/feeds/videos.xml?channel_id=UC_x5XG1OV2P6uZZ5FSM9Ttw",
+		"https://review.substack.com/feed",
+		"https://medium.com/feed/@jane",
+		"https://example.com/show.xml",
+		"https://github.com/cli/cli/releases.atom",
+		"https://lobste.rs/rss",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got feeds\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestSubscribeMiniflux_AddsOnlyMissingFeeds verifies that feeds Miniflux
+// has are skipped, the others go to the category, and a refused feed
+// doesn't stop the rest.
+func TestSubscribeMiniflux_AddsOnlyMissingFeeds(t *testing.T) {
+	var created []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/feeds":
+			fmt.Fprint(w, `[{"id":1,"feed_url":"https://review.substack.com/feed/"}]`)
+		case "GET /v1/categories":
+			fmt.Fprint(w, `[{"id":3,"title":"FeedMix"}]`)
+		case "POST /v1/feeds":
+			var body struct {
+				FeedURL    string `json:"feed_url"`
+				CategoryID int64  `json:"category_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.CategoryID != 3 || strings.Contains(body.FeedURL, "broken") {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error_message":"unable to parse feed"}`)
+				return
+			}
+			created = append(created, body.FeedURL)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"feed_id":9}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := miniflux.NewClient(server.URL, "key")
+	feeds := []string{"https://review.substack.com/feed", "https://example.com/show.xml", "https://broken.example.com/feed"}
+	var out bytes.Buffer
+	var warnings []error
+	err := subscribeMiniflux(context.Background(), client, feeds, "feedmix", false, &out, func(err error) { warnings = append(warnings, err) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 1 || created[0] != "https://example.com/show.xml" || len(warnings) != 1 {
+		t.Errorf("only the missing feed should be added and the refused one reported, got %v and %v", created, warnings)
+	}
+	if !strings.Contains(out.String(), "1 new feeds") {
+		t.Errorf("the summary should count the new feeds, got %q", out.String())
+	}
+
+	out.Reset()
+	created = nil
+	if err := subscribeMiniflux(context.Background(), client, feeds, "feedmix", true, &out, func(err error) { t.Error(err) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(created) != 0 || !strings.Contains(out.String(), "2 of 3 feeds are missing") {
+		t.Errorf("a dry run should only list the missing feeds, got %q (created %v)", out.String(), created)
+	}
+}
@@ -0,0 +1,600 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/message"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/delivery"
+	"github.com/gauthierbraillon/feedmix/internal/discord"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/ntfy"
+	"github.com/gauthierbraillon/feedmix/internal/pushover"
+	"github.com/gauthierbraillon/feedmix/internal/slack"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// notifyTimeout caps how long a run spends posting notifications; items
+// not posted by then are posted on the next run.
+const notifyTimeout = 2 * time.Minute
+
+// The notification services, as the delivery store names them.
+const (
+	deliverToSlack    = "slack"
+	deliverToDiscord  = "discord"
+	deliverToNtfy     = "ntfy"
+	deliverToPushover = "pushover"
+)
+
+// notificationTextLength caps the description a notification shows, in runes.
+const notificationTextLength = 300
+
+// templateTextLength caps the text a notification template produces, in
+// runes, below what Pushover, the most restrictive service, accepts.
+const templateTextLength = 900
+
+// discordTitleLength and discordAuthorLength are the longest embed title
+// and author name Discord accepts, in runes.
+const (
+	discordTitleLength  = 256
+	discordAuthorLength = 256
+)
+
+// pushoverTitleLength is the longest title Pushover accepts, in runes.
+const pushoverTitleLength = 250
+
+// sourceColors are the brand colors of the sources, for the side bar of
+// notification cards.
+var sourceColors = map[aggregator.Source]int{
+	aggregator.SourceYouTube:  0xFF0000,
+	aggregator.SourceSubstack: 0xFF6719,
+	aggregator.SourceReader:   0x0062BE,
+	aggregator.SourceBridge:   0x1D8EC2,
+	aggregator.SourcePodcast:  0x8E44AD,
+	aggregator.SourceTwitch:   0x9146FF,
+	aggregator.SourceGitHub:   0x24292F,
+	aggregator.SourceMedium:   0x1A1A1A,
+	aggregator.SourceLobsters: 0xAC130D,
+	aggregator.SourceArxiv:    0xB31B1B,
+	aggregator.SourcePeerTube: 0xF1680D,
+}
+
+// slackPostInterval is the least time between two Slack posts.
+var slackPostInterval = slack.MinInterval
+
+func deliveriesPath(cfg config.Config) string {
+	return filepath.Join(cfg.Dir, "deliveries.json")
+}
+
+// notificationStyle is what a notification service shows of each item.
+type notificationStyle struct {
+	// body returns the text under the item's title; nil shows none.
+	body       func(aggregator.FeedItem) string
+	thumbnail  bool
+	engagement bool
+}
+
+// notificationStyles are the styles of the notification services, by
+// their delivery names.
+type notificationStyles map[string]notificationStyle
+
+// parseNotificationStyles reads the FEEDMIX_*_TEMPLATE setting of each
+// notification service.
+func parseNotificationStyles(cfg config.Config) (notificationStyles, error) {
+	styles := make(notificationStyles)
+	for _, setting := range []struct{ to, name, raw string }{
+		{deliverToSlack, "FEEDMIX_SLACK_TEMPLATE", cfg.Slack.Template},
+		{deliverToDiscord, "FEEDMIX_DISCORD_TEMPLATE", cfg.Discord.Template},
+		{deliverToNtfy, "FEEDMIX_NTFY_TEMPLATE", cfg.Push.NtfyTemplate},
+		{deliverToPushover, "FEEDMIX_PUSHOVER_TEMPLATE", cfg.Push.PushoverTemplate},
+	} {
+		style, err := parseNotificationStyle(setting.raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", setting.name, err)
+		}
+		styles[setting.to] = style
+	}
+	return styles, nil
+}
+
+// parseNotificationStyle reads a style preset — "summary", "thumbnail" or
+// "title" — or a template for the text under the item's title, which keeps
+// the thumbnail and engagement of "summary".
+func parseNotificationStyle(raw string) (notificationStyle, error) {
+	switch raw {
+	case "", "summary":
+		return notificationStyle{body: summary, thumbnail: true, engagement: true}, nil
+	case "thumbnail":
+		return notificationStyle{thumbnail: true, engagement: true}, nil
+	case "title":
+		return notificationStyle{}, nil
+	}
+	t, err := display.ParseTemplate(raw)
+	if err != nil {
+		return notificationStyle{}, err
+	}
+	body := func(item aggregator.FeedItem) string {
+		text, err := display.ExecuteTemplate(t, item)
+		if err != nil {
+			return summary(item)
+		}
+		return shorten(strings.TrimSpace(text), templateTextLength)
+	}
+	return notificationStyle{body: body, thumbnail: true, engagement: true}, nil
+}
+
+// summary is the start of item's description, as plain text.
+func summary(item aggregator.FeedItem) string {
+	return shorten(display.PlainText(item.Description), notificationTextLength)
+}
+
+// text returns the text style shows under item's title.
+func (style notificationStyle) text(item aggregator.FeedItem) string {
+	if style.body == nil {
+		return ""
+	}
+	return style.body(item)
+}
+
+// notifyDiscovered sends the items no earlier run had seen to the
+// notification services the user configured, oldest first so they read in
+// order, each in the service's style. Each service's deliveries are
+// recorded once it accepted them, so a repeated run doesn't send an item
+// twice; an item a service refused is reported via warn and retried on the
+// next run, without holding up the others. During quiet hours, items wait
+// for the first run after them. When the delivery store can't be read,
+// nothing is sent.
+func notifyDiscovered(ctx context.Context, cfg config.Config, styles notificationStyles, client *http.Client, now clock.Clock, items []aggregator.FeedItem, warn func(error)) {
+	if cfg.Slack.WebhookURL == "" && cfg.Discord.WebhookURL == "" && !cfg.Push.Enabled() {
+		return
+	}
+	deliveries, err := delivery.Open(deliveriesPath(cfg), now)
+	if err != nil {
+		warn(err)
+		return
+	}
+	defer func() {
+		if err := deliveries.Save(); err != nil {
+			warn(err)
+		}
+	}()
+	quiet := quietHours{Quiet: cfg.Quiet, now: now()}
+
+	if cfg.Slack.WebhookURL != "" {
+		poster := slack.NewClient(slack.WithHTTPClient(client), slack.WithMinInterval(slackPostInterval))
+		style := styles[deliverToSlack]
+		post := func(msg slack.Message, channel string) error {
+			msg.Channel = channel
+			return poster.Post(ctx, cfg.Slack.WebhookURL, msg)
+		}
+		deliver(ctx, deliveries, quiet, service{
+			name:  deliverToSlack,
+			batch: cfg.Slack.Batch,
+			group: func(item aggregator.FeedItem) string { return cfg.Slack.ChannelFor(string(item.Source)) },
+			send: func(item aggregator.FeedItem) error {
+				if err := post(slackMessage(item, style), cfg.Slack.ChannelFor(string(item.Source))); err != nil {
+					return fmt.Errorf("failed to post %q to Slack: %w", item.Title, err)
+				}
+				return nil
+			},
+			digest: func(items []aggregator.FeedItem) error {
+				if err := post(slackDigest(items, style), cfg.Slack.ChannelFor(string(items[0].Source))); err != nil {
+					return fmt.Errorf("failed to post %d items to Slack: %w", len(items), err)
+				}
+				return nil
+			},
+		}, items, warn)
+	}
+	if cfg.Discord.WebhookURL != "" {
+		poster := discord.NewClient(discord.WithHTTPClient(client))
+		style := styles[deliverToDiscord]
+		p := message.NewPrinter(cfg.Locale)
+		post := func(items []aggregator.FeedItem) error {
+			return poster.Post(ctx, cfg.Discord.WebhookURL, discordMessage(items, style, p))
+		}
+		deliver(ctx, deliveries, quiet, service{
+			name:  deliverToDiscord,
+			batch: cfg.Discord.Batch,
+			pack:  func(items []aggregator.FeedItem) [][]aggregator.FeedItem { return discordBatches(items, style, p) },
+			send: func(item aggregator.FeedItem) error {
+				if err := post([]aggregator.FeedItem{item}); err != nil {
+					return fmt.Errorf("failed to post %q to Discord: %w", item.Title, err)
+				}
+				return nil
+			},
+			digest: func(items []aggregator.FeedItem) error {
+				if err := post(items); err != nil {
+					return fmt.Errorf("failed to post %d items to Discord: %w", len(items), err)
+				}
+				return nil
+			},
+		}, items, warn)
+	}
+	if cfg.Push.Enabled() {
+		pushDiscovered(ctx, cfg.Push, styles, client, deliveries, quiet, items, warn)
+	}
+}
+
+// digestSize is the most items one digest notification lists.
+const digestSize = 20
+
+// service is a notification service items are delivered to one by one or,
+// with a batching window, in digests.
+type service struct {
+	// name is the service's name in the delivery store.
+	name string
+	// batch is how long items collect, from the first, before they are
+	// sent as digests; zero sends each item at once.
+	batch time.Duration
+	// group, if set, keeps items apart in digests, such as items posted
+	// to different channels.
+	group func(aggregator.FeedItem) string
+	// pack, if set, splits items into those the service takes in one
+	// message, so that items are sent packed together even outside
+	// digests; nil sends them one by one.
+	pack   func([]aggregator.FeedItem) [][]aggregator.FeedItem
+	send   func(aggregator.FeedItem) error
+	digest func([]aggregator.FeedItem) error
+}
+
+// quietHours holds notifications back during the quiet hours set up, as
+// of now.
+type quietHours struct {
+	config.Quiet
+	now time.Time
+}
+
+// holding reports whether the notifications of service wait for the end of
+// quiet hours.
+func (q quietHours) holding(service string) bool {
+	return q.AppliesTo(service) && q.Contains(q.now)
+}
+
+// held reports whether items pending for service waited for quiet hours
+// to end.
+func (q quietHours) held(deliveries *delivery.Store, service string) bool {
+	return q.AppliesTo(service) && deliveries.QueuedDuring(service, q.Contains)
+}
+
+// deliver sends the items queued for svc, oldest first, and records those
+// it accepted. During quiet hours nothing is sent; once they end, the
+// items they held go as digests of up to digestSize items. With a batching
+// window, nothing is sent either until the first queued item has waited
+// it out. A failed send is reported via warn; when ctx ends, the items
+// left are reported at once. Both are retried on the next run.
+func deliver(ctx context.Context, deliveries *delivery.Store, quiet quietHours, svc service, items []aggregator.FeedItem, warn func(error)) {
+	queued := deliveries.Queue(svc.name, items)
+	if quiet.holding(svc.name) {
+		return
+	}
+	batches := svc.packed(queued)
+	switch {
+	case svc.batch > 0:
+		if !deliveries.Due(svc.name, svc.batch) {
+			return
+		}
+		batches = svc.digests(queued)
+	case quiet.held(deliveries, svc.name):
+		batches = svc.digests(queued)
+	}
+
+	sent := 0
+	for _, batch := range batches {
+		if ctx.Err() != nil {
+			warn(fmt.Errorf("ran out of time notifying %s: %d items will be sent next run", svc.name, len(queued)-sent))
+			return
+		}
+		sent += len(batch)
+		send := func() error { return svc.digest(batch) }
+		if len(batch) == 1 {
+			send = func() error { return svc.send(batch[0]) }
+		}
+		if err := send(); err != nil {
+			warn(err)
+			continue
+		}
+		deliveries.Delivered(svc.name, batch...)
+	}
+}
+
+// packed splits items into the messages svc sends them in: as svc.pack
+// splits them, or one item each.
+func (svc service) packed(items []aggregator.FeedItem) [][]aggregator.FeedItem {
+	if svc.pack != nil {
+		return svc.pack(items)
+	}
+	batches := make([][]aggregator.FeedItem, len(items))
+	for i, item := range items {
+		batches[i] = []aggregator.FeedItem{item}
+	}
+	return batches
+}
+
+// digests splits items into the digests of svc, each further split as
+// svc.pack splits them.
+func (svc service) digests(items []aggregator.FeedItem) [][]aggregator.FeedItem {
+	batches := digests(items, svc.group)
+	if svc.pack == nil {
+		return batches
+	}
+	var packed [][]aggregator.FeedItem
+	for _, batch := range batches {
+		packed = append(packed, svc.pack(batch)...)
+	}
+	return packed
+}
+
+// digests splits items into digests of at most digestSize items, in order,
+// keeping apart the items group tells apart.
+func digests(items []aggregator.FeedItem, group func(aggregator.FeedItem) string) [][]aggregator.FeedItem {
+	var keys []string
+	grouped := make(map[string][]aggregator.FeedItem)
+	for _, item := range items {
+		var key string
+		if group != nil {
+			key = group(item)
+		}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], item)
+	}
+	var batches [][]aggregator.FeedItem
+	for _, key := range keys {
+		batches = append(batches, slices.Collect(slices.Chunk(grouped[key], digestSize))...)
+	}
+	return batches
+}
+
+// pushDiscovered sends a phone notification for each item the push settings
+// select, through every push service set up. Items selected by a keyword
+// get high priority, and so does a digest listing one.
+func pushDiscovered(ctx context.Context, push config.Push, styles notificationStyles, client *http.Client, deliveries *delivery.Store, quiet quietHours, items []aggregator.FeedItem, warn func(error)) {
+	var wanted []aggregator.FeedItem
+	for _, item := range items {
+		if ok, _ := pushWanted(push, item); ok {
+			wanted = append(wanted, item)
+		}
+	}
+	urgent := func(items ...aggregator.FeedItem) bool {
+		return slices.ContainsFunc(items, func(item aggregator.FeedItem) bool {
+			_, urgent := pushWanted(push, item)
+			return urgent
+		})
+	}
+	if push.NtfyTopic != "" {
+		topic := ntfy.NewClient(ntfy.WithHTTPClient(client), ntfy.WithToken(push.NtfyToken))
+		style := styles[deliverToNtfy]
+		publish := func(n ntfy.Notification, urgent bool) error {
+			n.Priority = ntfy.PriorityDefault
+			if urgent {
+				n.Priority = ntfy.PriorityHigh
+			}
+			return topic.Publish(ctx, push.NtfyTopic, n)
+		}
+		deliver(ctx, deliveries, quiet, service{
+			name:  deliverToNtfy,
+			batch: push.NtfyBatch,
+			send: func(item aggregator.FeedItem) error {
+				n := ntfy.Notification{Title: item.Title, Message: pushText(item, style), Click: item.URL, Tags: []string{string(item.Source)}}
+				if style.thumbnail {
+					n.Attach = item.Thumbnail
+				}
+				if err := publish(n, urgent(item)); err != nil {
+					return fmt.Errorf("failed to push %q to ntfy: %w", item.Title, err)
+				}
+				return nil
+			},
+			digest: func(items []aggregator.FeedItem) error {
+				n := ntfy.Notification{Title: fmt.Sprintf("%d new items", len(items)), Message: digestText(items)}
+				if err := publish(n, urgent(items...)); err != nil {
+					return fmt.Errorf("failed to push %d items to ntfy: %w", len(items), err)
+				}
+				return nil
+			},
+		}, wanted, warn)
+	}
+	if push.PushoverToken != "" {
+		devices := pushover.NewClient(push.PushoverToken, push.PushoverUser, pushover.WithHTTPClient(client))
+		send := func(m pushover.Message, urgent bool) error {
+			m.Priority = pushover.PriorityNormal
+			if urgent {
+				m.Priority = pushover.PriorityHigh
+			}
+			return devices.Send(ctx, m)
+		}
+		deliver(ctx, deliveries, quiet, service{
+			name:  deliverToPushover,
+			batch: push.PushoverBatch,
+			send: func(item aggregator.FeedItem) error {
+				m := pushover.Message{
+					Title: shorten(item.Title, pushoverTitleLength), Message: pushText(item, styles[deliverToPushover]), URL: item.URL,
+					URLTitle: "Open in " + string(item.Source),
+				}
+				if err := send(m, urgent(item)); err != nil {
+					return fmt.Errorf("failed to push %q to Pushover: %w", item.Title, err)
+				}
+				return nil
+			},
+			digest: func(items []aggregator.FeedItem) error {
+				m := pushover.Message{Title: fmt.Sprintf("%d new items", len(items)), Message: digestText(items)}
+				if err := send(m, urgent(items...)); err != nil {
+					return fmt.Errorf("failed to push %d items to Pushover: %w", len(items), err)
+				}
+				return nil
+			},
+		}, wanted, warn)
+	}
+}
+
+// digestText lists items in a digest notification, one line each.
+func digestText(items []aggregator.FeedItem) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = "• " + item.Title
+		if item.Author != "" {
+			lines[i] += " — " + item.Author
+		}
+	}
+	return shorten(strings.Join(lines, "\n"), templateTextLength)
+}
+
+// pushText is the body of a phone notification: the item's announcement
+// and the text style shows of it.
+func pushText(item aggregator.FeedItem, style notificationStyle) string {
+	text := announcement(item)
+	if body := style.text(item); body != "" {
+		text += "\n\n" + body
+	}
+	return text
+}
+
+// pushWanted reports whether item passes the push settings' source and
+// keyword filters, and whether a keyword selected it.
+func pushWanted(push config.Push, item aggregator.FeedItem) (wanted, urgent bool) {
+	if len(push.Sources) > 0 && !slices.Contains(push.Sources, string(item.Source)) {
+		return false, false
+	}
+	if len(push.Keywords) == 0 {
+		return true, false
+	}
+	text := strings.ToLower(item.Title + "\n" + item.Author)
+	for _, keyword := range push.Keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// slackMessage announces item with an attachment showing its title and
+// author, and the text and thumbnail style shows.
+func slackMessage(item aggregator.FeedItem, style notificationStyle) slack.Message {
+	attachment := slack.Attachment{
+		Fallback:   item.Title,
+		AuthorName: item.Author,
+		Title:      item.Title,
+		TitleLink:  item.URL,
+		Text:       style.text(item),
+		Footer:     string(item.Source),
+	}
+	if style.thumbnail {
+		attachment.ThumbURL = item.Thumbnail
+	}
+	if !item.PublishedAt.IsZero() {
+		attachment.Timestamp = item.PublishedAt.Unix()
+	}
+	return slack.Message{
+		Text:        announcement(item),
+		Attachments: []slack.Attachment{attachment},
+	}
+}
+
+// slackDigest announces items in one message, with an attachment per item.
+func slackDigest(items []aggregator.FeedItem, style notificationStyle) slack.Message {
+	msg := slack.Message{Text: fmt.Sprintf("%d new items", len(items))}
+	for _, item := range items {
+		msg.Attachments = append(msg.Attachments, slackMessage(item, style).Attachments...)
+	}
+	return msg
+}
+
+// discordBatches splits items into as few messages as Discord allows, one
+// embed per item, so a burst of new items doesn't flood the channel. A
+// message ends at MaxEmbeds embeds or before its embeds would exceed
+// MaxEmbedsLength characters.
+func discordBatches(items []aggregator.FeedItem, style notificationStyle, p *message.Printer) [][]aggregator.FeedItem {
+	var batches [][]aggregator.FeedItem
+	var batch []aggregator.FeedItem
+	length := 0
+	for _, item := range items {
+		n := discordEmbed(item, style, p).Length()
+		if len(batch) == discord.MaxEmbeds || length+n > discord.MaxEmbedsLength {
+			batches = append(batches, batch)
+			batch, length = nil, 0
+		}
+		batch = append(batch, item)
+		length += n
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// discordMessage shows each item of batch in an embed, introduced with the
+// item's announcement for a single item, else with how many there are.
+func discordMessage(batch []aggregator.FeedItem, style notificationStyle, p *message.Printer) discord.Message {
+	msg := discord.Message{Content: fmt.Sprintf("%d new items", len(batch))}
+	if len(batch) == 1 {
+		msg.Content = announcement(batch[0])
+	}
+	for _, item := range batch {
+		msg.Embeds = append(msg.Embeds, discordEmbed(item, style, p))
+	}
+	return msg
+}
+
+// discordEmbed shows item with its source's color, and the text, thumbnail
+// and engagement style shows.
+func discordEmbed(item aggregator.FeedItem, style notificationStyle, p *message.Printer) discord.Embed {
+	embed := discord.Embed{
+		Title:       shorten(item.Title, discordTitleLength),
+		URL:         item.URL,
+		Description: style.text(item),
+		Color:       sourceColors[item.Source],
+		Footer:      &discord.Footer{Text: string(item.Source)},
+	}
+	if item.Author != "" {
+		embed.Author = &discord.Author{Name: shorten(item.Author, discordAuthorLength)}
+	}
+	if item.Thumbnail != "" && style.thumbnail {
+		embed.Thumbnail = &discord.Image{URL: item.Thumbnail}
+	}
+	if !item.PublishedAt.IsZero() {
+		published := item.PublishedAt.UTC()
+		embed.Timestamp = &published
+	}
+	if !style.engagement {
+		return embed
+	}
+	for _, count := range []struct {
+		name string
+		n    int64
+	}{
+		{"Views", item.Engagement.Views},
+		{"Likes", item.Engagement.Likes},
+		{"Comments", item.Engagement.Comments},
+	} {
+		if count.n > 0 {
+			embed.Fields = append(embed.Fields, discord.Field{Name: count.name, Value: p.Sprintf("%d", count.n), Inline: true})
+		}
+	}
+	return embed
+}
+
+// announcement is the line that introduces item in a notification.
+func announcement(item aggregator.FeedItem) string {
+	from := item.Author
+	if from == "" {
+		from = string(item.Source)
+	}
+	return fmt.Sprintf("New %s from %s", item.Type, from)
+}
+
+// shorten cuts text to at most n runes, ending it with "…" when cut.
+func shorten(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return strings.TrimRight(string([]rune(text)[:n-1]), " ") + "…"
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/delivery"
+	"github.com/gauthierbraillon/feedmix/internal/discord"
+	"github.com/gauthierbraillon/feedmix/internal/ntfy"
+	"github.com/gauthierbraillon/feedmix/internal/slack"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// TestItemPipeline_CollectsItemsNotSeenBefore verifies that only items new
+// since an earlier run are collected, and none are announced on the first
+// run, when every item is new.
+func TestItemPipeline_CollectsItemsNotSeenBefore(t *testing.T) {
+	cfg := config.Config{Dir: t.TempDir(), CacheDir: t.TempDir()}
+	old := aggregator.FeedItem{ID: "a", Source: aggregator.SourceYouTube, Title: "Old", URL: "https://example.com/a"}
+	fresh := aggregator.FeedItem{ID: "b", Source: aggregator.SourceYouTube, Title: "Fresh", URL: "https://example.com/b"}
+	fail := func(err error) { t.Fatal(err) }
+
+	first := openItemPipeline(cfg, nil, clock.System, fail)
+	first.process(context.Background(), []aggregator.FeedItem{old})
+	first.save()
+	if len(first.discovered) != 1 || len(first.announced()) != 0 {
+		t.Errorf("a first run should discover the whole feed but not announce it, got %+v", first.announced())
+	}
+
+	second := openItemPipeline(cfg, nil, clock.System, fail)
+	second.process(context.Background(), []aggregator.FeedItem{old, fresh})
+	if announced := second.announced(); len(announced) != 1 || announced[0].ID != "b" {
+		t.Errorf("only the item new since the last run should be announced, got %+v", announced)
+	}
+}
+
+// TestNotifyDiscovered_PostsToSlack verifies that new items are posted
+// oldest first, each source's to its channel, with the item as attachment.
+func TestNotifyDiscovered_PostsToSlack(t *testing.T) {
+	var posted []slack.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posted = append(posted, msg)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{
+		{ID: "p1", Source: aggregator.SourceSubstack, Type: aggregator.ItemTypeArticle, Title: "Newer post", Author: "The Review", PublishedAt: now},
+		{
+			ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Older video", Author: "Tech Channel",
+			Description: "<p>Cobra &amp; friends</p>", URL: "https://www.youtube.com/watch?v=v1", Thumbnail: "https://i.ytimg.com/vi/v1/hqdefault.jpg",
+			PublishedAt: now.Add(-time.Hour),
+		},
+	}
+	cfg := config.Config{Dir: t.TempDir(), Slack: config.Slack{WebhookURL: server.URL, Channels: map[string]string{"youtube": "#videos"}}}
+	withoutSlackInterval(t)
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.System, items, func(err error) { t.Error(err) })
+
+	if len(posted) != 2 {
+		t.Fatalf("expected one message per item, got %+v", posted)
+	}
+	video := posted[0]
+	if video.Channel != "#videos" || video.Text != "New video from Tech Channel" || len(video.Attachments) != 1 {
+		t.Fatalf("the older video should go first, to its source's channel, got %+v", video)
+	}
+	if a := video.Attachments[0]; a.Title != "Older video" || a.TitleLink != items[1].URL || a.AuthorName != "Tech Channel" ||
+		a.Text != "Cobra & friends" || a.ThumbURL != items[1].Thumbnail || a.Timestamp != items[1].PublishedAt.Unix() {
+		t.Errorf("the attachment should show the video, got %+v", a)
+	}
+	if posted[1].Channel != "" {
+		t.Errorf("a source without a channel should post to the webhook's own, got %q", posted[1].Channel)
+	}
+}
+
+// TestNotifyDiscovered_PostsEachItemToSlackOnce verifies that an item Slack
+// refused is retried on the next run, and that repeating a run doesn't post
+// the items Slack accepted again.
+func TestNotifyDiscovered_PostsEachItemToSlackOnce(t *testing.T) {
+	var posted []string
+	refuse := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if title := msg.Attachments[0].Title; title == "Second" && refuse {
+			w.WriteHeader(http.StatusInternalServerError)
+		} else {
+			posted = append(posted, title)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceYouTube, Title: "First", PublishedAt: now.Add(-time.Hour)},
+		{ID: "b", Source: aggregator.SourceYouTube, Title: "Second", PublishedAt: now},
+	}
+	cfg := config.Config{Dir: t.TempDir(), Slack: config.Slack{WebhookURL: server.URL}}
+	withoutSlackInterval(t)
+	var warnings []error
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now), items, func(err error) { warnings = append(warnings, err) })
+	if len(posted) != 1 || len(warnings) != 1 {
+		t.Fatalf("expected the first item posted and the refused one reported, got %v and %v", posted, warnings)
+	}
+
+	refuse = false
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now.Add(time.Hour)), nil, func(err error) { t.Error(err) })
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now.Add(time.Hour)), items, func(err error) { t.Error(err) })
+	if len(posted) != 2 || posted[1] != "Second" {
+		t.Errorf("the refused item should be posted on the next run, and nothing twice, got %v", posted)
+	}
+}
+
+// TestNotifyDiscovered_SendsSlackDigestsAfterTheBatchWindow documents batching:
+//   - items wait until the first queued has waited the window
+//   - then they go out together, one digest per channel
+func TestNotifyDiscovered_SendsSlackDigestsAfterTheBatchWindow(t *testing.T) {
+	var posted []slack.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		posted = append(posted, msg)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	cfg := config.Config{Dir: t.TempDir(), Slack: config.Slack{WebhookURL: server.URL, Batch: 30 * time.Minute, Channels: map[string]string{"substack": "#reading"}}}
+	withoutSlackInterval(t)
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now), []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceYouTube, Title: "First", PublishedAt: now},
+	}, func(err error) { t.Error(err) })
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now.Add(20*time.Minute)), []aggregator.FeedItem{
+		{ID: "b", Source: aggregator.SourceYouTube, Title: "Second", PublishedAt: now},
+		{ID: "c", Source: aggregator.SourceSubstack, Title: "Post", PublishedAt: now},
+	}, func(err error) { t.Error(err) })
+	if len(posted) != 0 {
+		t.Fatalf("nothing should be posted within the batch window, got %+v", posted)
+	}
+
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(now.Add(30*time.Minute)), nil, func(err error) { t.Error(err) })
+	if len(posted) != 2 {
+		t.Fatalf("expected a digest and a single post, got %+v", posted)
+	}
+	if posted[0].Text != "2 new items" || len(posted[0].Attachments) != 2 {
+		t.Errorf("expected a digest of both videos, got %+v", posted[0])
+	}
+	if posted[1].Channel != "#reading" || posted[1].Attachments[0].Title != "Post" {
+		t.Errorf("expected the post in its own channel, got %+v", posted[1])
+	}
+}
+
+// TestNotifyDiscovered_ResendsOnlyDigestsThatFailed verifies that
+// re-running after a destination refused a digest sends it that digest
+// again, and leaves alone the destinations that took theirs.
+func TestNotifyDiscovered_ResendsOnlyDigestsThatFailed(t *testing.T) {
+	var slackPosts, discordPosts int
+	refuse := true
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackPosts++
+	}))
+	defer slackServer.Close()
+	discordServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if refuse {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		discordPosts++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer discordServer.Close()
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	cfg := config.Config{
+		Dir:     t.TempDir(),
+		Slack:   config.Slack{WebhookURL: slackServer.URL, Batch: time.Minute},
+		Discord: config.Discord{WebhookURL: discordServer.URL, Batch: time.Minute},
+		Locale:  language.English,
+	}
+	items := []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceYouTube, Title: "First", PublishedAt: now},
+		{ID: "b", Source: aggregator.SourceYouTube, Title: "Second", PublishedAt: now},
+	}
+	withoutSlackInterval(t)
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), http.DefaultClient, clock.Fixed(now), items, func(err error) { t.Error(err) })
+	var warnings []error
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), http.DefaultClient, clock.Fixed(now.Add(time.Minute)), items, func(err error) { warnings = append(warnings, err) })
+	if slackPosts != 1 || len(warnings) != 1 {
+		t.Fatalf("expected the Slack digest posted and the Discord one reported, got %d posts and %v", slackPosts, warnings)
+	}
+
+	refuse = false
+	for range 2 {
+		notifyDiscovered(context.Background(), cfg, defaultStyles(t), http.DefaultClient, clock.Fixed(now.Add(2*time.Minute)), items, func(err error) { t.Error(err) })
+	}
+	if slackPosts != 1 || discordPosts != 1 {
+		t.Errorf("only the refused digest should be sent again, and once, got %d Slack and %d Discord posts", slackPosts, discordPosts)
+	}
+}
+
+// defaultStyles returns the notification styles used without
+// FEEDMIX_*_TEMPLATE settings.
+func defaultStyles(t *testing.T) notificationStyles {
+	styles, err := parseNotificationStyles(config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return styles
+}
+
+// withoutSlackInterval lets a test post to Slack without waiting between posts.
+func withoutSlackInterval(t *testing.T) {
+	interval := slackPostInterval
+	slackPostInterval = 0
+	t.Cleanup(func() { slackPostInterval = interval })
+}
+
+// TestNotifyDiscovered_BatchesDiscordEmbeds verifies that a burst of new
+// items is posted to Discord as few messages, one embed per item with the
+// source's color and the item's engagement.
+func TestNotifyDiscovered_BatchesDiscordEmbeds(t *testing.T) {
+	var posted []discord.Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg discord.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posted = append(posted, msg)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	var items []aggregator.FeedItem
+	for i := range 23 {
+		items = append(items, aggregator.FeedItem{
+			ID: fmt.Sprint(i), Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: fmt.Sprintf("Video %d", i),
+			Author: "Tech Channel", Thumbnail: "https://i.ytimg.com/vi/v1/hqdefault.jpg", PublishedAt: now.Add(time.Duration(i) * time.Minute),
+			Engagement: aggregator.Engagement{Views: 15300, Likes: 820},
+		})
+	}
+	cfg := config.Config{Dir: t.TempDir(), Discord: config.Discord{WebhookURL: server.URL}, Locale: language.English}
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.System, items, func(err error) { t.Error(err) })
+
+	if len(posted) != 3 || len(posted[0].Embeds) != discord.MaxEmbeds || len(posted[2].Embeds) != 3 {
+		t.Fatalf("23 items should be posted as 3 messages of at most %d embeds, got %+v", discord.MaxEmbeds, posted)
+	}
+	if posted[2].Content != "3 new items" {
+		t.Errorf("a batch should say how many items it holds, got %q", posted[2].Content)
+	}
+	embed := posted[0].Embeds[0]
+	if embed.Title != "Video 0" || embed.Color != 0xFF0000 || embed.Thumbnail == nil || embed.Author == nil || embed.Author.Name != "Tech Channel" {
+		t.Errorf("the embed should show the oldest video with YouTube's color, got %+v", embed)
+	}
+	if len(embed.Fields) != 2 || embed.Fields[0].Value != "15,300" || embed.Fields[1].Value != "820" {
+		t.Errorf("the embed should show views and likes, got %+v", embed.Fields)
+	}
+}
+
+// TestNotifyDiscovered_RetriesDiscordMessagesThatFailed verifies that the
+// items of a message Discord refused are posted on the next run, and only
+// those.
+func TestNotifyDiscovered_RetriesDiscordMessagesThatFailed(t *testing.T) {
+	var posted []discord.Message
+	refuse := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if refuse {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var msg discord.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		posted = append(posted, msg)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	item := aggregator.FeedItem{ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Video", Author: "Tech Channel"}
+	cfg := config.Config{Dir: t.TempDir(), Discord: config.Discord{WebhookURL: server.URL}, Locale: language.English}
+	var warnings []error
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.System, []aggregator.FeedItem{item}, func(err error) { warnings = append(warnings, err) })
+	if len(warnings) != 1 {
+		t.Fatalf("the refused message should be reported, got %v", warnings)
+	}
+
+	refuse = false
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.System, nil, func(err error) { t.Error(err) })
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.System, []aggregator.FeedItem{item}, func(err error) { t.Error(err) })
+	if len(posted) != 1 || len(posted[0].Embeds) != 1 || posted[0].Embeds[0].Title != "Video" {
+		t.Errorf("the refused item should be posted once on the next run, got %+v", posted)
+	}
+}
+
+// TestDiscordMessages_KeepsEachMessageWithinDiscordLimits verifies that a
+// message ends before its embeds exceed Discord's 6000 characters, and that
+// names too long for an embed author are shortened.
+func TestDiscordMessages_KeepsEachMessageWithinDiscordLimits(t *testing.T) {
+	long := func(n int) string { return strings.Repeat("x", n) }
+	var items []aggregator.FeedItem
+	for i := range 10 {
+		items = append(items, aggregator.FeedItem{
+			ID: fmt.Sprint(i), Source: aggregator.SourceYouTube, Title: long(300), Description: long(400), Author: long(300),
+		})
+	}
+
+	style, p := defaultStyles(t)[deliverToDiscord], message.NewPrinter(language.English)
+	var messages []discord.Message
+	for _, batch := range discordBatches(items, style, p) {
+		messages = append(messages, discordMessage(batch, style, p))
+	}
+
+	if len(messages) != 2 || len(messages[0].Embeds) != 7 || len(messages[1].Embeds) != 3 {
+		t.Fatalf("10 long items should be split into messages of 7 and 3 embeds, got %d messages", len(messages))
+	}
+	for _, msg := range messages {
+		length := 0
+		for _, embed := range msg.Embeds {
+			length += embed.Length()
+		}
+		if length > discord.MaxEmbedsLength {
+			t.Errorf("a message's embeds should stay within %d characters, got %d", discord.MaxEmbedsLength, length)
+		}
+	}
+	if name := messages[0].Embeds[0].Author.Name; utf8.RuneCountInString(name) != discordAuthorLength {
+		t.Errorf("the author name should be shortened to %d characters, got %d", discordAuthorLength, utf8.RuneCountInString(name))
+	}
+}
+
+// TestNotifyDiscovered_PushesSelectedItems verifies that only items of the
+// chosen sources mentioning a keyword reach ntfy and Pushover, with high
+// priority.
+func TestNotifyDiscovered_PushesSelectedItems(t *testing.T) {
+	var topics []map[string]any
+	var messages []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/1/messages.json" {
+			_ = r.ParseForm()
+			messages = append(messages, r.PostForm)
+			fmt.Fprint(w, `{"status":1}`)
+			return
+		}
+		var n map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&n)
+		topics = append(topics, n)
+	}))
+	defer server.Close()
+
+	items := []aggregator.FeedItem{
+		{ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Go in 100 seconds", Author: "Fireship", URL: "https://www.youtube.com/watch?v=v1"},
+		{ID: "v2", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Cooking", Author: "Chef"},
+		{ID: "p1", Source: aggregator.SourceSubstack, Type: aggregator.ItemTypeArticle, Title: "Fireship newsletter", Author: "Jeff"},
+	}
+	push := config.Push{NtfyTopic: server.URL + "/alerts", PushoverToken: "app", PushoverUser: "me", Sources: []string{"youtube"}, Keywords: []string{"fireship"}}
+	// Pushover has a single endpoint; every request goes to the test server.
+	client := &http.Client{Transport: serverTransport{server.URL}}
+	deliveries, err := delivery.Open(filepath.Join(t.TempDir(), "deliveries.json"), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pushDiscovered(context.Background(), push, defaultStyles(t), client, deliveries, quietHours{}, items, func(err error) { t.Error(err) })
+
+	if len(topics) != 1 || topics[0]["title"] != "Go in 100 seconds" || topics[0]["topic"] != "alerts" || topics[0]["priority"] != float64(ntfy.PriorityHigh) {
+		t.Errorf("only the matching YouTube video should reach ntfy, with high priority, got %v", topics)
+	}
+	if len(messages) != 1 || messages[0].Get("url") != items[0].URL || messages[0].Get("priority") != "1" {
+		t.Errorf("only the matching YouTube video should reach Pushover, with high priority, got %v", messages)
+	}
+
+	pushDiscovered(context.Background(), push, defaultStyles(t), client, deliveries, quietHours{}, items, func(err error) { t.Error(err) })
+	if len(topics) != 1 || len(messages) != 1 {
+		t.Errorf("a repeated run shouldn't push the video again, got %d and %d notifications", len(topics), len(messages))
+	}
+}
+
+// serverTransport sends every request to the server at its URL, whatever
+// host the request is for.
+// TestNotifyDiscovered_HoldsNotificationsDuringQuietHours documents quiet hours:
+//   - items found during quiet hours wait, for the services they apply to
+//   - the first run after them pushes what waited as one digest
+func TestNotifyDiscovered_HoldsNotificationsDuringQuietHours(t *testing.T) {
+	var topics []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&n)
+		topics = append(topics, n)
+	}))
+	defer server.Close()
+
+	night := time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)
+	cfg := config.Config{
+		Dir:   t.TempDir(),
+		Push:  config.Push{NtfyTopic: server.URL + "/alerts"},
+		Quiet: config.Quiet{Start: 22 * time.Hour, End: 8 * time.Hour, Destinations: []string{"ntfy"}},
+	}
+	items := []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceYouTube, Title: "First", Author: "Fireship", PublishedAt: night},
+		{ID: "b", Source: aggregator.SourceYouTube, Title: "Second", Author: "Chef", PublishedAt: night},
+	}
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(night), items, func(err error) { t.Error(err) })
+	if len(topics) != 0 {
+		t.Fatalf("nothing should be pushed during quiet hours, got %v", topics)
+	}
+
+	notifyDiscovered(context.Background(), cfg, defaultStyles(t), server.Client(), clock.Fixed(night.Add(9*time.Hour)), nil, func(err error) { t.Error(err) })
+	if len(topics) != 1 || topics[0]["title"] != "2 new items" || topics[0]["message"] != "• First — Fireship\n• Second — Chef" {
+		t.Errorf("expected what waited pushed as one digest, got %v", topics)
+	}
+}
+
+type serverTransport struct {
+	url string
+}
+
+func (t serverTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	server, err := url.Parse(t.url)
+	if err != nil {
+		return nil, err
+	}
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = server.Scheme, server.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// TestNotificationStyles_ShapeEachServicesMessages verifies the presets
+// and templates FEEDMIX_*_TEMPLATE accepts: "title" leaves out the
+// description, thumbnail and engagement, "thumbnail" only the description,
+// and a template replaces the description.
+func TestNotificationStyles_ShapeEachServicesMessages(t *testing.T) {
+	item := aggregator.FeedItem{
+		ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Go in 100 seconds", Author: "Fireship",
+		Description: "<p>Learn Go</p>", Thumbnail: "https://i.ytimg.com/vi/v1/hqdefault.jpg", Engagement: aggregator.Engagement{Views: 1000},
+	}
+	styles, err := parseNotificationStyles(config.Config{
+		Slack:   config.Slack{Template: "title"},
+		Discord: config.Discord{Template: "thumbnail"},
+		Push:    config.Push{NtfyTemplate: "{{.Author}}: {{upper .Title}}"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := message.NewPrinter(language.English)
+
+	if a := slackMessage(item, styles[deliverToSlack]).Attachments[0]; a.Title != item.Title || a.Text != "" || a.ThumbURL != "" {
+		t.Errorf("the title style should show only the title, got %+v", a)
+	}
+	if e := discordEmbed(item, styles[deliverToDiscord], p); e.Description != "" || e.Thumbnail == nil || len(e.Fields) != 1 {
+		t.Errorf("the thumbnail style should leave out only the description, got %+v", e)
+	}
+	if text := pushText(item, styles[deliverToNtfy]); text != "New video from Fireship\n\nFireship: GO IN 100 SECONDS" {
+		t.Errorf("a template should replace the description, got %q", text)
+	}
+	if text := pushText(item, styles[deliverToPushover]); text != "New video from Fireship\n\nLearn Go" {
+		t.Errorf("a service without a template should show the description, got %q", text)
+	}
+
+	if _, err := parseNotificationStyles(config.Config{Discord: config.Discord{Template: "{{.Titel}}"}}); err == nil || !strings.Contains(err.Error(), "FEEDMIX_DISCORD_TEMPLATE") {
+		t.Errorf("a broken template should be reported with its setting, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+	"github.com/gauthierbraillon/feedmix/internal/history"
+	"github.com/gauthierbraillon/feedmix/pkg/browser"
+)
+
+func lastFeedPath(cfg config.Config) string {
+	return filepath.Join(cfg.CacheDir, "last_feed.json")
+}
+
+// saveLastFeed remembers the items just displayed, in display order, so
+// 'feedmix open N' can resolve N without fetching again.
+func saveLastFeed(cfg config.Config, items []aggregator.FeedItem) error {
+	data, err := json.Marshal(aggregator.NewEnvelope(items))
+	if err != nil {
+		return fmt.Errorf("failed to encode displayed feed: %w", err)
+	}
+	if err := os.MkdirAll(cfg.CacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(lastFeedPath(cfg), data, 0600); err != nil {
+		return fmt.Errorf("failed to save displayed feed: %w", err)
+	}
+	return nil
+}
+
+func loadLastFeed(cfg config.Config) ([]aggregator.FeedItem, error) {
+	data, err := os.ReadFile(lastFeedPath(cfg)) // #nosec G304 - path is the last displayed feed in the user's cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no feed to open items from: run 'feedmix feed' first")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read displayed feed: %w", err)
+	}
+	items, _, err := aggregator.UnmarshalItems[aggregator.FeedItem](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse displayed feed: %w", err)
+	}
+	return items, nil
+}
+
+// resolveItem resolves ref, a 1-based position in items (as numbered on
+// screen) or an item ID.
+func resolveItem(items []aggregator.FeedItem, ref string) (aggregator.FeedItem, error) {
+	for _, item := range items {
+		if item.ID == ref {
+			return item, nil
+		}
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || n > len(items) {
+		return aggregator.FeedItem{}, fmt.Errorf("invalid item %q: must be a number from 1 to %d or an item ID", ref, len(items))
+	}
+	return items[n-1], nil
+}
+
+// promptItem lists the last feed by number and reads the user's choice from stdin.
+func promptItem(cmd *cobra.Command, items []aggregator.FeedItem) (string, error) {
+	out := cmd.ErrOrStderr()
+	for i, item := range items {
+		fmt.Fprintf(out, "%3d. %s\n", i+1, item.Title)
+	}
+	fmt.Fprint(out, "Open item: ")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	choice := strings.TrimSpace(line)
+	if choice == "" && err != nil {
+		return "", fmt.Errorf("failed to read item number: %w", err)
+	}
+	return choice, nil
+}
+
+// openConfirmThreshold is how many tabs 'feedmix open' opens at once
+// without asking first.
+const openConfirmThreshold = 10
+
+// openFilter selects the items 'feedmix open' opens as a batch.
+type openFilter struct {
+	top     int
+	unread  bool
+	sources []aggregator.Source
+}
+
+// selectItems returns the items of the last feed matching f, in feed order.
+// opened reports whether the user already opened an item.
+func (f openFilter) selectItems(items []aggregator.FeedItem, opened func(aggregator.FeedItem) bool) []aggregator.FeedItem {
+	var selected []aggregator.FeedItem
+	for _, item := range items {
+		if f.top > 0 && len(selected) == f.top {
+			break
+		}
+		if item.URL == "" || (f.unread && opened(item)) {
+			continue
+		}
+		if len(f.sources) > 0 && !slices.Contains(f.sources, item.Source) {
+			continue
+		}
+		selected = append(selected, item)
+	}
+	return selected
+}
+
+// confirmOpen asks on stderr whether to open n tabs; anything but "y" or
+// "yes" declines.
+func confirmOpen(cmd *cobra.Command, n int) bool {
+	fmt.Fprintf(cmd.ErrOrStderr(), "Open %d tabs? [y/N] ", n)
+	line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// openHistory loads the item history, recording times on the command's clock.
+func openHistory(cmd *cobra.Command, cfg config.Config) (*history.Store, error) {
+	now, err := commandClock(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return history.Open(historyPath(cfg), now)
+}
+
+// markOpened remembers items as opened for --unread and logs them as event. It is best-effort: the items are open either way.
+func markOpened(cmd *cobra.Command, cfg config.Config, event eventlog.Type, items []aggregator.FeedItem) {
+	store, err := openHistory(cmd, cfg)
+	if err == nil {
+		for _, item := range items {
+			store.MarkOpened(item)
+		}
+		err = store.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+	recordEvents(cmd, cfg, event, items)
+}
+
+// recordEvents appends an event of type t for each of items to the event
+// log, if one is configured. A failure only warns.
+func recordEvents(cmd *cobra.Command, cfg config.Config, t eventlog.Type, items []aggregator.FeedItem) {
+	if cfg.EventLog == "" {
+		return
+	}
+	now, err := commandClock(cmd)
+	if err == nil {
+		err = eventlog.New(cfg.EventLog, eventlog.WithClock(now)).Record(t, items)
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+	}
+}
+
+func newOpenCmd() *cobra.Command {
+	var printURL, unread, yes bool
+	var top int
+	var sources []string
+
+	cmd := &cobra.Command{
+		Use:   "open [N]",
+		Short: "Open items from the last feed in the browser",
+		Long: "Opens the URL of item N, as numbered in the output of the last 'feedmix feed', in your default browser.\n" +
+			"Without N, lists the items and asks which one to open.\n\n" +
+			"With --top, --unread or --source, opens every matching item in its own tab instead, e.g. 'feedmix open --top 5' " +
+			"or 'feedmix open --unread --source youtube'. Opening more than 10 tabs at once asks for confirmation unless --yes is set.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			items, err := loadLastFeed(cfg)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("the last feed had no items")
+			}
+
+			if cmd.Flags().Changed("top") || unread || len(sources) > 0 {
+				if len(args) == 1 {
+					return fmt.Errorf("give an item number or --top, --unread and --source, not both")
+				}
+				filter := openFilter{top: top, unread: unread}
+				if cmd.Flags().Changed("top") && top < 1 {
+					return fmt.Errorf("invalid --top %d: must be a positive number", top)
+				}
+				for _, name := range sources {
+					source := aggregator.Source(strings.ToLower(strings.TrimSpace(name)))
+					switch source {
+					case aggregator.SourceYouTube, aggregator.SourceSubstack, aggregator.SourceReader, aggregator.SourceBridge, aggregator.SourcePodcast, aggregator.SourceTwitch, aggregator.SourceGitHub, aggregator.SourceMedium, aggregator.SourceLobsters, aggregator.SourceArxiv, aggregator.SourcePeerTube:
+					default:
+						return fmt.Errorf("invalid --source %q: must be %q, %q, %q, %q, %q, %q, %q, %q, %q, %q or %q", name, aggregator.SourceYouTube, aggregator.SourceSubstack, aggregator.SourceReader, aggregator.SourceBridge, aggregator.SourcePodcast, aggregator.SourceTwitch, aggregator.SourceGitHub, aggregator.SourceMedium, aggregator.SourceLobsters, aggregator.SourceArxiv, aggregator.SourcePeerTube)
+					}
+					filter.sources = append(filter.sources, source)
+				}
+				return openBatch(cmd, cfg, items, filter, printURL, yes)
+			}
+
+			var choice string
+			if len(args) == 1 {
+				choice = args[0]
+			} else if choice, err = promptItem(cmd, items); err != nil {
+				return err
+			}
+			item, err := resolveItem(items, choice)
+			if err != nil {
+				return err
+			}
+			if item.URL == "" {
+				return fmt.Errorf("item %s (%s) has no URL", choice, item.Title)
+			}
+			if printURL {
+				fmt.Fprintln(cmd.OutOrStdout(), item.URL)
+				return nil
+			}
+			if err := browser.Open(item.URL); err != nil {
+				return err
+			}
+			markOpened(cmd, cfg, eventlog.Opened, []aggregator.FeedItem{item})
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&printURL, "print", false, "Print the URLs instead of opening them")
+	cmd.Flags().IntVar(&top, "top", 0, "Open the first N items of the last feed")
+	cmd.Flags().BoolVar(&unread, "unread", false, "Only open items not opened with feedmix before")
+	cmd.Flags().StringSliceVar(&sources, "source", nil, "Only open items from these sources: youtube, substack, reader, bridge")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Open more than 10 tabs without asking")
+	return cmd
+}
+
+// openBatch opens the items of the last feed matching filter as browser
+// tabs, or prints their URLs.
+func openBatch(cmd *cobra.Command, cfg config.Config, items []aggregator.FeedItem, filter openFilter, printURL, yes bool) error {
+	opened := func(aggregator.FeedItem) bool { return false }
+	if filter.unread {
+		store, err := openHistory(cmd, cfg)
+		if err != nil {
+			return err
+		}
+		opened = store.Opened
+	}
+	selected := filter.selectItems(items, opened)
+	if len(selected) == 0 {
+		fmt.Fprintln(cmd.ErrOrStderr(), "No items to open.")
+		return nil
+	}
+	if printURL {
+		for _, item := range selected {
+			fmt.Fprintln(cmd.OutOrStdout(), item.URL)
+		}
+		return nil
+	}
+	if len(selected) > openConfirmThreshold && !yes && !confirmOpen(cmd, len(selected)) {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Nothing opened.")
+		return nil
+	}
+	return openItems(cmd, cfg, selected, browser.Open)
+}
+
+// openItems opens each of items in its own browser tab with open and
+// remembers them as opened, reporting how many could be.
+func openItems(cmd *cobra.Command, cfg config.Config, items []aggregator.FeedItem, open func(string) error) error {
+	var done []aggregator.FeedItem
+	var errs []error
+	for _, item := range items {
+		if err := open(item.URL); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", item.Title, err))
+			continue
+		}
+		done = append(done, item)
+	}
+	markOpened(cmd, cfg, eventlog.Opened, done)
+	fmt.Fprintf(cmd.ErrOrStderr(), "Opened %d of %d items.\n", len(done), len(items))
+	return errors.Join(errs...)
+}
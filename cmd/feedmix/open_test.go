@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/history"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// TestOpenFilter_SelectsTopUnreadItemsOfASource verifies that --top counts
+// only the items that pass the other filters.
+func TestOpenFilter_SelectsTopUnreadItemsOfASource(t *testing.T) {
+	var items []aggregator.FeedItem
+	for i := 1; i <= 6; i++ {
+		source := aggregator.SourceYouTube
+		if i%2 == 0 {
+			source = aggregator.SourceSubstack
+		}
+		items = append(items, aggregator.FeedItem{ID: fmt.Sprint(i), Source: source, URL: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	items[2].URL = ""
+	opened := func(item aggregator.FeedItem) bool { return item.ID == "1" }
+
+	filter := openFilter{top: 2, unread: true, sources: []aggregator.Source{aggregator.SourceYouTube}}
+	selected := filter.selectItems(items, opened)
+	if len(selected) != 1 || selected[0].ID != "5" {
+		t.Errorf("expected only item 5 (1 is opened, 3 has no URL), got %+v", selected)
+	}
+
+	if selected := (openFilter{top: 3}).selectItems(items, opened); len(selected) != 3 || selected[2].ID != "4" {
+		t.Errorf("--top alone should take the first items with a URL, got %+v", selected)
+	}
+}
+
+// TestConfirmOpen_DefaultsToNo verifies that a tab bomb needs an explicit yes.
+func TestConfirmOpen_DefaultsToNo(t *testing.T) {
+	for input, want := range map[string]bool{"y\n": true, "YES\n": true, "\n": false, "": false, "n\n": false} {
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetErr(&strings.Builder{})
+		if got := confirmOpen(cmd, 12); got != want {
+			t.Errorf("answer %q: expected %v, got %v", input, want, got)
+		}
+	}
+}
+
+// TestOpenItems_RemembersOnlyOpenedItems verifies that items are handed to
+// the opener and that only those it opened stop counting as unread.
+func TestOpenItems_RemembersOnlyOpenedItems(t *testing.T) {
+	cfg := config.Config{Dir: t.TempDir()}
+	items := []aggregator.FeedItem{
+		{ID: "1", Source: aggregator.SourceYouTube, Title: "Opens", URL: "https://example.com/1"},
+		{ID: "2", Source: aggregator.SourceSubstack, Title: "Fails", URL: "https://example.com/2"},
+	}
+	var urls []string
+	open := func(url string) error {
+		urls = append(urls, url)
+		if url == items[1].URL {
+			return errors.New("no browser")
+		}
+		return nil
+	}
+
+	cmd := &cobra.Command{}
+	var stderr strings.Builder
+	cmd.SetErr(&stderr)
+	if err := openItems(cmd, cfg, items, open); err == nil {
+		t.Error("a failed open should be reported")
+	}
+	if !slices.Equal(urls, []string{items[0].URL, items[1].URL}) {
+		t.Errorf("expected every item's URL to be opened, got %v", urls)
+	}
+	if !strings.Contains(stderr.String(), "Opened 1 of 2 items") {
+		t.Errorf("expected a count of opened items, got %q", stderr.String())
+	}
+
+	store, err := history.Open(historyPath(cfg), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.Opened(items[0]) || store.Opened(items[1]) {
+		t.Errorf("only the opened item should be remembered, got opened=%v,%v", store.Opened(items[0]), store.Opened(items[1]))
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// writePaged writes output to cmd's standard output, through pager when it
+// is a terminal the output doesn't fit on, unless --no-pager is set. Like
+// git, output is written directly if the pager can't be found.
+func writePaged(cmd *cobra.Command, pager, output string) error {
+	out := cmd.OutOrStdout()
+	if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager || !needsPager(pager, output, out, os.Getenv) {
+		_, err := io.WriteString(out, output)
+		return err
+	}
+	args := strings.Fields(pager)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		_, err := io.WriteString(out, output)
+		return err
+	}
+	return runPager(args, output, out, cmd.ErrOrStderr(), os.Getenv)
+}
+
+// needsPager reports whether output written to w should go through pager:
+// w is a capable terminal and output is taller than it, or its height is
+// unknown, in which case the pager decides.
+func needsPager(pager, output string, w io.Writer, getenv func(string) string) bool {
+	if pager == "" || !isTerminal(w) || getenv("TERM") == "dumb" {
+		return false
+	}
+	rows := outputHeight(w, getenv)
+	return rows == 0 || strings.Count(output, "\n") >= rows
+}
+
+// runPager shows output through the pager command args. As git does, less
+// is told to keep colors and quit if the output fits on one screen, unless
+// the user configured it with $LESS.
+func runPager(args []string, output string, stdout, stderr io.Writer, getenv func(string) string) error {
+	pager := exec.Command(args[0], args[1:]...) // #nosec G204 -- the pager is chosen by the user, like $PAGER for git
+	pager.Stdin = strings.NewReader(output)
+	pager.Stdout = stdout
+	pager.Stderr = stderr
+	pager.Env = os.Environ()
+	if getenv("LESS") == "" {
+		pager.Env = append(pager.Env, "LESS=FRX")
+	}
+	if getenv("LV") == "" {
+		pager.Env = append(pager.Env, "LV=-c")
+	}
+	if err := pager.Run(); err != nil {
+		return fmt.Errorf("pager %q failed: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
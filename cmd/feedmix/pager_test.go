@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestNeedsPager_OnlyForTerminals verifies that output to files and pipes,
+// such as 'feedmix feed | grep', is never paged.
+func TestNeedsPager_OnlyForTerminals(t *testing.T) {
+	getenv := func(string) string { return "" }
+	long := strings.Repeat("line\n", 500)
+
+	if needsPager("less -R", long, &bytes.Buffer{}, getenv) {
+		t.Error("output to a pipe or file should not be paged")
+	}
+	if needsPager("", long, &bytes.Buffer{}, getenv) {
+		t.Error("an empty pager should turn paging off")
+	}
+}
+
+// TestRunPager_PipesOutputAndDefaultsLess verifies that the pager reads the
+// output and that less keeps colors unless $LESS says otherwise.
+func TestRunPager_PipesOutputAndDefaultsLess(t *testing.T) {
+	var out bytes.Buffer
+	if err := runPager([]string{"tr", "a-z", "A-Z"}, "feed\n", &out, &out, func(string) string { return "" }); err != nil || out.String() != "FEED\n" {
+		t.Errorf("the pager should receive the output, got %q (err %v)", out.String(), err)
+	}
+
+	out.Reset()
+	if err := runPager([]string{"sh", "-c", `echo "$LESS"`}, "", &out, &out, func(string) string { return "" }); err != nil || out.String() != "FRX\n" {
+		t.Errorf("less should default to -FRX, got %q (err %v)", out.String(), err)
+	}
+
+	if err := runPager([]string{"false"}, "", &out, &out, func(string) string { return "" }); err == nil {
+		t.Error("a failing pager should be reported")
+	}
+}
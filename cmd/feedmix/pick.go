@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/picker"
+	"github.com/gauthierbraillon/feedmix/pkg/browser"
+)
+
+// Actions 'feedmix pick' can take on the picked items.
+const (
+	pickOpen = "open"
+	pickSave = "save"
+	pickRead = "read"
+)
+
+// pickLabel is how each item is listed in the finder.
+const pickLabel = `{{ago .PublishedAt}} · {{.Source}} · {{with .Author}}{{.}} · {{end}}{{.Title}}`
+
+func newPickCmd() *cobra.Command {
+	var action, finder string
+
+	cmd := &cobra.Command{
+		Use:   "pick [query]",
+		Short: "Fuzzy-find items in the last feed and open, save or read them",
+		Long: "Lists the items of the last 'feedmix feed' in a fuzzy finder and acts on the ones you pick: opens them in the browser, " +
+			"saves them, or shows them in full with --action read.\n\n" +
+			"fzf is used when it is installed, with Tab to pick several items; otherwise feedmix asks for words to filter by and " +
+			"the numbers of the items to pick. Choose another fzf-compatible finder, or 'builtin', with --finder or FEEDMIX_FINDER.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch action {
+			case pickOpen, pickSave, pickRead:
+			default:
+				return fmt.Errorf("invalid --action %q: must be %q, %q or %q", action, pickOpen, pickSave, pickRead)
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("finder") {
+				cfg.Finder = strings.TrimSpace(finder)
+			}
+			items, err := loadLastFeed(cfg)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("the last feed had no items")
+			}
+
+			tmpl, err := display.ParseTemplate(pickLabel)
+			if err != nil {
+				return err
+			}
+			labels := strings.Split(strings.TrimSuffix(display.NewTerminalFormatter(display.WithTemplate(tmpl), display.WithClock(now), display.WithLocale(cfg.Locale)).FormatFeed(items), "\n"), "\n")
+			query := strings.Join(args, " ")
+			var picked []int
+			if command := finderCommand(cfg.Finder, cmd); command != nil {
+				picked, err = runFinder(cmd, command, labels, query)
+			} else {
+				picked, err = picker.Prompt(cmd.InOrStdin(), cmd.ErrOrStderr(), labels, query)
+			}
+			if err != nil {
+				return err
+			}
+			if len(picked) == 0 {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Nothing picked.")
+				return nil
+			}
+			selected := make([]aggregator.FeedItem, len(picked))
+			for i, index := range picked {
+				selected[i] = items[index]
+			}
+
+			switch action {
+			case pickSave:
+				return saveItems(cmd, cfg, selected)
+			case pickRead:
+				opts, err := formatterOptions(cfg, cmd.OutOrStdout(), now)
+				if err != nil {
+					return err
+				}
+				formatter := display.NewTerminalFormatter(opts...)
+				articles := make([]string, len(selected))
+				for i, item := range selected {
+					articles[i] = formatter.FormatArticle(item)
+				}
+				return writePaged(cmd, cfg.Pager, strings.Join(articles, "\n"))
+			default:
+				return openItems(cmd, cfg, selected, browser.Open)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&action, "action", "a", pickOpen, "What to do with the picked items: open, save or read")
+	cmd.Flags().StringVar(&finder, "finder", "", "Fuzzy finder: an fzf-compatible command, or builtin (FEEDMIX_FINDER; default: fzf when installed)")
+	return cmd
+}
+
+// finderCommand returns the external finder to run, or nil for the
+// built-in one. By default fzf is used when it is installed and the user is
+// at a terminal to drive it.
+func finderCommand(finder string, cmd *cobra.Command) []string {
+	switch finder {
+	case config.FinderBuiltin:
+		return nil
+	case "":
+		stdin, ok := cmd.InOrStdin().(*os.File)
+		if _, err := exec.LookPath("fzf"); err != nil || !ok || !isTerminal(stdin) {
+			return nil
+		}
+		return []string{"fzf", "--multi", "--prompt", "feedmix> "}
+	default:
+		return strings.Fields(finder)
+	}
+}
+
+// runFinder runs an fzf-compatible finder: the numbered labels go to its
+// standard input and the lines picked come back on its standard output. As
+// with fzf, exit code 1 (no match) or 130 (canceled) means nothing was picked.
+func runFinder(cmd *cobra.Command, command, labels []string, query string) ([]int, error) {
+	if query != "" && command[0] == "fzf" {
+		command = append(command, "--query", query)
+	}
+	var input strings.Builder
+	for i, label := range labels {
+		fmt.Fprintf(&input, "%d. %s\n", i+1, label)
+	}
+	var output bytes.Buffer
+	finder := exec.Command(command[0], command[1:]...) // #nosec G204 -- the finder is chosen by the user, like the pager
+	finder.Stdin = strings.NewReader(input.String())
+	finder.Stdout = &output
+	finder.Stderr = cmd.ErrOrStderr()
+	if err := finder.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("finder %q failed: %w", strings.Join(command, " "), err)
+	}
+
+	var picked []int
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		number, _, _ := strings.Cut(line, ".")
+		n, err := strconv.Atoi(strings.TrimSpace(number))
+		if err != nil || n < 1 || n > len(labels) {
+			return nil, fmt.Errorf("finder %q returned an unknown item: %q", strings.Join(command, " "), line)
+		}
+		picked = append(picked, n-1)
+	}
+	return picked, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/canonical"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/history"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// itemPipeline prepares fetched items for display: canonical URLs, one item
+// per page, and edit detection against the item history. It may process a
+// run's items in several batches but is not safe for concurrent use.
+type itemPipeline struct {
+	cfg      config.Config
+	resolver *canonical.Resolver
+	history  *history.Store
+	warn     func(error)
+	// firstRun is set when the history was empty, so that every item would
+	// count as new.
+	firstRun bool
+	// discovered collects the processed items no earlier run had seen.
+	discovered []aggregator.FeedItem
+}
+
+func historyPath(cfg config.Config) string {
+	return filepath.Join(cfg.Dir, "history.json")
+}
+
+// openItemPipeline opens the URL cache and item history. A stage whose state
+// can't be loaded is skipped with a warning rather than failing the run.
+func openItemPipeline(cfg config.Config, client *http.Client, now clock.Clock, warn func(error)) *itemPipeline {
+	p := &itemPipeline{cfg: cfg, warn: warn}
+	var err error
+	if p.resolver, err = canonical.Open(client, filepath.Join(cfg.CacheDir, "urls.json")); err != nil {
+		warn(err)
+	}
+	if p.history, err = history.Open(historyPath(cfg), now); err != nil {
+		warn(err)
+	} else {
+		p.firstRun = p.history.Empty()
+	}
+	return p
+}
+
+// process canonicalizes URLs, drops items linking to the same page, marks
+// items whose content changed since an earlier run, and collects those no
+// earlier run had seen. If configured, edited items move to the top of the
+// feed by their update time.
+func (p *itemPipeline) process(ctx context.Context, items []aggregator.FeedItem) []aggregator.FeedItem {
+	if p.resolver != nil {
+		items = canonical.Dedupe(p.resolver.Items(ctx, items, p.warn))
+	}
+	if p.history == nil {
+		return items
+	}
+
+	known := make([]bool, len(items))
+	for i, item := range items {
+		known[i] = p.history.Known(item)
+	}
+	items = p.history.Observe(items)
+	for i, item := range items {
+		if !known[i] {
+			p.discovered = append(p.discovered, item)
+		}
+	}
+	if p.cfg.ResurfaceUpdated {
+		for i := range items {
+			if items[i].UpdatedAt.After(items[i].PublishedAt) {
+				items[i].PublishedAt = items[i].UpdatedAt
+			}
+		}
+	}
+	return items
+}
+
+// save persists the URL cache and item history.
+func (p *itemPipeline) save() {
+	if p.resolver != nil {
+		if err := p.resolver.Save(); err != nil {
+			p.warn(err)
+		}
+	}
+	if p.history != nil {
+		if err := p.history.Save(); err != nil {
+			p.warn(err)
+		}
+	}
+}
+
+// announced returns the discovered items worth notifying about: none on the
+// first run, which would announce the whole feed.
+func (p *itemPipeline) announced() []aggregator.FeedItem {
+	if p.firstRun {
+		return nil
+	}
+	return p.discovered
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/spf13/cobra"
+)
+
+// profiler writes a pprof or execution trace profile covering a single command run.
+type profiler struct {
+	mode string
+	path string
+	file *os.File
+}
+
+func addProfileFlags(cmd *cobra.Command, p *profiler) {
+	cmd.PersistentFlags().StringVar(&p.mode, "profile", "", "write a profile for this run: cpu, mem or trace")
+	cmd.PersistentFlags().StringVar(&p.path, "profile-out", "", "profile output path (default feedmix.<mode>.prof)")
+	_ = cmd.PersistentFlags().MarkHidden("profile")
+	_ = cmd.PersistentFlags().MarkHidden("profile-out")
+}
+
+func (p *profiler) start() error {
+	if p.mode == "" {
+		return nil
+	}
+	if p.mode != "cpu" && p.mode != "mem" && p.mode != "trace" {
+		return fmt.Errorf("unsupported profile %q (use cpu, mem or trace)", p.mode)
+	}
+	if p.path == "" {
+		p.path = fmt.Sprintf("feedmix.%s.prof", p.mode)
+	}
+
+	f, err := os.Create(p.path) // #nosec G304 -- path is chosen by the user running the command
+	if err != nil {
+		return fmt.Errorf("failed to create profile file: %w", err)
+	}
+	p.file = f
+
+	switch p.mode {
+	case "cpu":
+		err = pprof.StartCPUProfile(f)
+	case "trace":
+		err = trace.Start(f)
+	}
+	if err != nil {
+		_ = f.Close()
+		p.file = nil
+		return fmt.Errorf("failed to start %s profile: %w", p.mode, err)
+	}
+	return nil
+}
+
+func (p *profiler) stop() error {
+	if p.file == nil {
+		return nil
+	}
+	defer func() { p.file = nil }()
+
+	var err error
+	switch p.mode {
+	case "cpu":
+		pprof.StopCPUProfile()
+	case "trace":
+		trace.Stop()
+	case "mem":
+		runtime.GC()
+		err = pprof.WriteHeapProfile(p.file)
+	}
+	if closeErr := p.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", p.mode, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+)
+
+func quotaPath(cfg config.Config) string {
+	return filepath.Join(cfg.Dir, "quota.json")
+}
+
+// keyPoolPath is where API keys whose quota ran out are remembered.
+func keyPoolPath(cfg config.Config) string {
+	return filepath.Join(cfg.CacheDir, "youtube_keys.json")
+}
+
+// warnIfOverBudget warns before a run whose estimated cost (the previous run's)
+// would push today's usage past the budget.
+func warnIfOverBudget(usage youtube.QuotaUsage, budget int, warn func(error)) {
+	if usage.LastRun == 0 || usage.Units+usage.LastRun <= budget {
+		return
+	}
+	warn(fmt.Errorf("this run is estimated at %d YouTube quota units but only %d of today's %d remain; requests may fail with quotaExceeded",
+		usage.LastRun, max(budget-usage.Units, 0), budget))
+}
+
+func printQuotaSummary(out io.Writer, run int, usage youtube.QuotaUsage, budget int) {
+	fmt.Fprintf(out, "YouTube quota: %d units this run, %d/%d used today\n", run, usage.Units, budget)
+}
+
+func newQuotaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "quota",
+		Short: "Show today's estimated YouTube API quota usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			usage, err := youtube.LoadQuotaUsage(quotaPath(cfg), now())
+			if err != nil {
+				return err
+			}
+
+			budget := cfg.YouTube.QuotaBudget
+			remaining := max(budget-usage.Units, 0)
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "YouTube quota for %s (resets at midnight Pacific time)\n", usage.Day)
+			fmt.Fprintf(out, "  Used today  %d / %d units\n", usage.Units, budget)
+			fmt.Fprintf(out, "  Remaining   %d units\n", remaining)
+			if usage.LastRun > 0 {
+				fmt.Fprintf(out, "  Last run    %d units (about %d more runs today)\n", usage.LastRun, remaining/usage.LastRun)
+			}
+			if n := len(cfg.YouTube.APIKeys); n > 0 {
+				keys, err := youtube.OpenKeyPool(keyPoolPath(cfg), cfg.YouTube.APIKeys, now)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "  API keys    %d of %d with quota left\n", keys.Available(), n)
+			}
+			fmt.Fprint(out, "\n  Estimates count 100 units per channel search and 1 per list call.\n")
+			fmt.Fprint(out, "  Set FEEDMIX_YOUTUBE_QUOTA_BUDGET to match your project's daily quota.\n")
+			return nil
+		},
+	}
+}
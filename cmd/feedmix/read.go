@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+)
+
+func newReadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read <N|id>",
+		Short: "Read an item from the last feed in the terminal",
+		Long: "Prints the whole of item N, as numbered in the output of the last 'feedmix feed', or the item with the given ID, " +
+			"paged when it doesn't fit on screen. Substack posts show their full text when the feed carries it; paywalled " +
+			"posts and other items show their description.\n\n" +
+			"The item counts as opened for 'feedmix open --unread'.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			items, err := loadLastFeed(cfg)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return fmt.Errorf("the last feed had no items")
+			}
+			item, err := resolveItem(items, args[0])
+			if err != nil {
+				return err
+			}
+			if store, err := openSaved(cfg, now); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+			} else if saved, ok := store.Lookup(item); ok {
+				item.Gone = saved.Gone
+			}
+
+			opts, err := formatterOptions(cfg, cmd.OutOrStdout(), now)
+			if err != nil {
+				return err
+			}
+			if err := writePaged(cmd, cfg.Pager, display.NewTerminalFormatter(opts...).FormatArticle(item)); err != nil {
+				return err
+			}
+			markOpened(cmd, cfg, eventlog.Read, []aggregator.FeedItem{item})
+			return nil
+		},
+	}
+}
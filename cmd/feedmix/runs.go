@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/runs"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func runsDir(cfg config.Config) string {
+	return filepath.Join(cfg.Dir, "runs")
+}
+
+func newRunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect past feed runs",
+		Long:  "Every 'feedmix feed' records a manifest of its sources, API requests, warnings and items. Use these commands to find out why an item was missing from a run.\n\nRuns are referred to by ID or by their number in 'feedmix runs list', 1 being the most recent.",
+	}
+	cmd.AddCommand(newRunsListCmd())
+	cmd.AddCommand(newRunsShowCmd())
+	cmd.AddCommand(newRunsDiffCmd())
+	cmd.AddCommand(newRunsPruneCmd())
+	return cmd
+}
+
+// resolveRun returns the ID of the run ref refers to: a run ID, or its
+// 1-based position in 'feedmix runs list'.
+func resolveRun(dir, ref string) (string, error) {
+	ids, err := runs.IDs(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("no runs recorded yet: run 'feedmix feed' first")
+	}
+	for _, id := range ids {
+		if id == ref {
+			return id, nil
+		}
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || n > len(ids) {
+		return "", fmt.Errorf("invalid run %q: must be a number from 1 to %d or a run ID", ref, len(ids))
+	}
+	return ids[n-1], nil
+}
+
+func newRunsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recorded feed runs, most recent first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			manifests, err := runs.List(runsDir(cfg))
+			if err != nil {
+				return err
+			}
+			if len(manifests) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No runs recorded yet: run 'feedmix feed' first.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "#\tID\tSTARTED\tDURATION\tFETCHED\tSHOWN\tWARNINGS\tSTATUS")
+			for i, m := range manifests {
+				status := "ok"
+				switch {
+				case m.Error != "":
+					status = "failed"
+				case len(m.Unhealthy) > 0:
+					status = "unhealthy"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+					i+1, m.ID, m.StartedAt.Local().Format("2006-01-02 15:04"), roundDuration(m.Duration),
+					m.Fetched(), m.Displayed(), len(m.Warnings), status)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newRunsShowCmd() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "show [run]",
+		Short: "Show the manifest of a feed run (default: the latest)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			ref := "1"
+			if len(args) == 1 {
+				ref = args[0]
+			}
+			m, err := loadRun(runsDir(cfg), ref)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(m)
+			}
+			printManifest(cmd.OutOrStdout(), m)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw manifest as JSON")
+	return cmd
+}
+
+func loadRun(dir, ref string) (runs.Manifest, error) {
+	id, err := resolveRun(dir, ref)
+	if err != nil {
+		return runs.Manifest{}, err
+	}
+	return runs.Load(dir, id)
+}
+
+func newRunsDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Show what changed between two feed runs",
+		Long:  "Lists the items run b fetched that run a didn't and the other way around, items that only one of them displayed, and how each source fared. 'feedmix runs diff 2 1' compares the last two runs.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			a, err := loadRun(runsDir(cfg), args[0])
+			if err != nil {
+				return err
+			}
+			b, err := loadRun(runsDir(cfg), args[1])
+			if err != nil {
+				return err
+			}
+			printDiff(cmd.OutOrStdout(), a, b)
+			return nil
+		},
+	}
+}
+
+func printDiff(out io.Writer, a, b runs.Manifest) {
+	fmt.Fprintf(out, "From %s to %s\n", a.ID, b.ID)
+	fmt.Fprintf(out, "  Fetched:   %d → %d\n", a.Fetched(), b.Fetched())
+	fmt.Fprintf(out, "  Shown:     %d → %d\n", a.Displayed(), b.Displayed())
+	fmt.Fprintf(out, "  Requests:  %d → %d\n", len(a.Requests), len(b.Requests))
+	fmt.Fprintf(out, "  Warnings:  %d → %d\n", len(a.Warnings), len(b.Warnings))
+
+	fmt.Fprintln(out, "\nSources")
+	for _, line := range sourceChanges(a.Sources, b.Sources) {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	d := runs.Compare(a, b)
+	sections := []struct {
+		title string
+		mark  string
+		items []runs.Item
+	}{
+		{"Newly fetched", "+", d.Added},
+		{"No longer fetched", "-", d.Removed},
+		{"Now shown", "+", d.Shown},
+		{"No longer shown", "-", d.Hidden},
+	}
+	for _, s := range sections {
+		if len(s.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s (%d)\n", s.title, len(s.items))
+		for _, item := range s.items {
+			fmt.Fprintf(out, "  %s [%s] %s — %s\n", s.mark, strings.ToUpper(string(item.Source)), item.Author, item.Title)
+		}
+	}
+}
+
+// sourceChanges describes each source's item count and error in both runs.
+func sourceChanges(a, b []runs.Source) []string {
+	outcome := func(sources []runs.Source, name string) string {
+		items, found, failed := 0, false, ""
+		for _, s := range sources {
+			if s.Name == name {
+				items += s.Items
+				found = true
+				if s.Error != "" {
+					failed = s.Error
+				}
+			}
+		}
+		switch {
+		case !found:
+			return "not fetched"
+		case failed != "":
+			return "error: " + failed
+		default:
+			return fmt.Sprintf("%d items", items)
+		}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, s := range append(append([]runs.Source(nil), a...), b...) {
+		if !seen[s.Name] {
+			seen[s.Name] = true
+			names = append(names, s.Name)
+		}
+	}
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%-10s %s → %s", name, outcome(a, name), outcome(b, name)))
+	}
+	return lines
+}
+
+func newRunsPruneCmd() *cobra.Command {
+	var keep, olderThan string
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old feed runs",
+		Long:  "Removes recorded runs beyond FEEDMIX_RUNS_KEEP or older than FEEDMIX_RUNS_MAX_AGE. Runs are also pruned after every 'feedmix feed'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			retention := cfg.Runs
+			if cmd.Flags().Changed("keep") {
+				if retention.Keep, err = config.ParseKeep("--keep", keep, 0); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("older-than") {
+				if retention.MaxAge, err = config.ParseAge("--older-than", olderThan, 0); err != nil {
+					return err
+				}
+			}
+
+			removed, err := runs.Prune(runsDir(cfg), retention.Keep, retention.MaxAge, now())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %d runs\n", len(removed))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keep, "keep", "", "Keep this many of the most recent runs, 0 for all (FEEDMIX_RUNS_KEEP)")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Remove runs older than this, e.g. 7d or 12h (FEEDMIX_RUNS_MAX_AGE)")
+	return cmd
+}
+
+func printManifest(out io.Writer, m runs.Manifest) {
+	fmt.Fprintf(out, "Run %s\n", m.ID)
+	fmt.Fprintf(out, "  Started:   %s\n", m.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "  Duration:  %s\n", roundDuration(m.Duration))
+	fmt.Fprintf(out, "  Version:   %s (%s)\n", m.Version, m.GoVersion)
+	fmt.Fprintf(out, "  Command:   feedmix %s\n", strings.Join(m.Args, " "))
+	fmt.Fprintf(out, "  Quota:     %d units\n", m.QuotaUnits)
+	if m.Error != "" {
+		fmt.Fprintf(out, "  Error:     %s\n", m.Error)
+	}
+
+	fmt.Fprintf(out, "\nSources (%d)\n", len(m.Sources))
+	for _, s := range m.Sources {
+		line := fmt.Sprintf("  %-10s %4d items  %s", s.Name, s.Items, roundDuration(s.Duration))
+		if s.Error != "" {
+			line += "  error: " + s.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintf(out, "\nRequests (%d)\n", len(m.Requests))
+	for _, r := range m.Requests {
+		status := fmt.Sprint(r.Status)
+		if r.Error != "" {
+			status = "error: " + r.Error
+		}
+		fmt.Fprintf(out, "  %s %s  %s  %s\n", r.Method, status, roundDuration(r.Duration), r.URL)
+	}
+
+	if len(m.Unhealthy) > 0 {
+		fmt.Fprintf(out, "\nUnhealthy feeds (%d)\n", len(m.Unhealthy))
+		for _, f := range m.Unhealthy {
+			fmt.Fprintf(out, "  %s  %s\n", f.Key, f.Error)
+		}
+	}
+
+	if len(m.Warnings) > 0 {
+		fmt.Fprintf(out, "\nWarnings (%d)\n", len(m.Warnings))
+		for _, w := range m.Warnings {
+			fmt.Fprintf(out, "  %s\n", w)
+		}
+	}
+
+	fmt.Fprintf(out, "\nItems (%d fetched, %d shown; * = shown)\n", m.Fetched(), m.Displayed())
+	for _, item := range m.Items {
+		mark := " "
+		if item.Displayed {
+			mark = "*"
+		}
+		fmt.Fprintf(out, "  %s [%s] %s — %s\n", mark, strings.ToUpper(string(item.Source)), item.Author, item.Title)
+	}
+}
+
+func roundDuration(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// saveManifest stores the manifest of a feed run and prunes runs that are
+// old at now. Failing to record a run doesn't fail it.
+func saveManifest(cfg config.Config, m runs.Manifest, now clock.Clock, warn func(error)) {
+	if err := runs.Save(runsDir(cfg), m); err != nil {
+		warn(err)
+		return
+	}
+	if _, err := runs.Prune(runsDir(cfg), cfg.Runs.Keep, cfg.Runs.MaxAge, now()); err != nil {
+		warn(err)
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+	"github.com/gauthierbraillon/feedmix/internal/linkcheck"
+	"github.com/gauthierbraillon/feedmix/internal/saved"
+	"github.com/gauthierbraillon/feedmix/internal/wayback"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+func openSaved(cfg config.Config, now clock.Clock) (*saved.Store, error) {
+	return saved.Open(filepath.Join(cfg.Dir, "saved.json"), now)
+}
+
+func newSaveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "save <N|id>...",
+		Short: "Save items from the last feed to revisit later",
+		Long:  "Saves items by their number in the output of the last 'feedmix feed', or by item ID. List them with 'feedmix saved'.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			items, err := loadLastFeed(cfg)
+			if err != nil {
+				return err
+			}
+			var selected []aggregator.FeedItem
+			for _, ref := range args {
+				item, err := resolveItem(items, ref)
+				if err != nil {
+					return err
+				}
+				selected = append(selected, item)
+			}
+			return saveItems(cmd, cfg, selected)
+		},
+	}
+}
+
+// saveItems adds items to the saved list, reporting each one.
+func saveItems(cmd *cobra.Command, cfg config.Config, items []aggregator.FeedItem) error {
+	now, err := commandClock(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := openSaved(cfg, now)
+	if err != nil {
+		return err
+	}
+	var added []aggregator.FeedItem
+	for _, item := range items {
+		if store.Add(item) {
+			added = append(added, item)
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved: %s\n", item.Title)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Already saved: %s\n", item.Title)
+		}
+	}
+	if cfg.ArchiveSaved {
+		archiveSaved(cmd.Context(), cmd.OutOrStdout(), store, wayback.NewClient(wayback.WithHTTPClient(httpx.NewClient())), added, func(err error) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		})
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+
+	if cfg.EventLog != "" {
+		if err := eventlog.New(cfg.EventLog, eventlog.WithClock(now)).Record(eventlog.Saved, added); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// archiveSaved has the Wayback Machine archive the pages of items and
+// records the snapshots in store. A page it fails to archive is reported
+// via warn; the item stays saved.
+func archiveSaved(ctx context.Context, out io.Writer, store *saved.Store, archive *wayback.Client, items []aggregator.FeedItem, warn func(error)) {
+	for _, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		snapshot, err := archive.Save(ctx, item.URL)
+		if err != nil {
+			warn(fmt.Errorf("failed to archive %q: %w", item.Title, err))
+			continue
+		}
+		store.SetArchivedURL(item, snapshot)
+		fmt.Fprintf(out, "Archived: %s\n", snapshot)
+	}
+}
+
+func newSavedCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "saved",
+		Short: "List saved items",
+		Long:  "Lists saved items, most recently saved first. Use --format json or markdown to export the list.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			store, err := openSaved(cfg, now)
+			if err != nil {
+				return err
+			}
+			items := store.Items()
+
+			out := cmd.OutOrStdout()
+			switch format {
+			case "json":
+				return saved.WriteJSON(out, items)
+			case "markdown", "md":
+				return saved.WriteMarkdown(out, items)
+			case formatCSV:
+				return display.WriteCSV(out, feedItems(items))
+			case formatJSONFeed:
+				return display.WriteJSONFeed(out, "feedmix saved items", feedItems(items))
+			case formatText:
+				if len(items) == 0 {
+					fmt.Fprintln(out, "No saved items. Save one with 'feedmix save N' after 'feedmix feed'.")
+					return nil
+				}
+				layoutOpts, err := formatterOptions(cfg, out, now)
+				if err != nil {
+					return err
+				}
+				layoutOpts = append(layoutOpts, display.WithDayHeaders(false), display.WithGroupBy(aggregator.GroupByNone))
+				return writePaged(cmd, cfg.Pager, display.NewTerminalFormatter(layoutOpts...).FormatFeed(feedItems(items)))
+			default:
+				return fmt.Errorf("invalid --format %q: must be text, json, markdown, csv or jsonfeed", format)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, markdown, csv or jsonfeed")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <N|id>...",
+		Short: "Remove items from the saved list by their number in 'feedmix saved' or their ID",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			store, err := openSaved(cfg, now)
+			if err != nil {
+				return err
+			}
+			listed := make([]aggregator.FeedItem, 0)
+			for _, item := range store.Items() {
+				listed = append(listed, item.FeedItem)
+			}
+			var remove []aggregator.FeedItem
+			for _, ref := range args {
+				item, err := resolveItem(listed, ref)
+				if err != nil {
+					return err
+				}
+				remove = append(remove, item)
+			}
+			for _, item := range remove {
+				if store.Remove(item.ID) {
+					fmt.Fprintf(cmd.OutOrStdout(), "Removed: %s\n", item.Title)
+				}
+			}
+			return store.Save()
+		},
+	})
+	cmd.AddCommand(newSavedCheckCmd())
+	return cmd
+}
+
+func newSavedCheckCmd() *cobra.Command {
+	var archived bool
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check that saved items are still there",
+		Long: "Requests the headers of each saved item's page, a few seconds apart per site, and marks the items that are gone " +
+			"(the page is gone or the site no longer exists) in 'feedmix saved', listing them with those that couldn't be checked. " +
+			"With YouTube credentials, videos are looked up on YouTube instead, and deleted, private and unlisted ones are marked " +
+			"gone (1 quota unit per 50 videos). With --wayback, items found gone are looked up on the Internet Archive's Wayback " +
+			"Machine, and the archived copy is recorded with the item ('feedmix saved --format json').",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			store, err := openSaved(cfg, now)
+			if err != nil {
+				return err
+			}
+			videos, err := videoChecker(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+			httpClient := httpx.NewClient()
+			var archive *wayback.Client
+			if archived {
+				archive = wayback.NewClient(wayback.WithHTTPClient(httpClient))
+			}
+			warn := func(err error) { fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err) }
+			checkSaved(cmd.Context(), cmd.OutOrStdout(), warn, store, linkcheck.NewChecker(linkcheck.WithHTTPClient(httpClient)), videos, archive)
+			return store.Save()
+		},
+	}
+	cmd.Flags().BoolVar(&archived, "wayback", false, "Look items found gone up on the Wayback Machine and record their archived copy")
+	return cmd
+}
+
+// videoChecker returns a YouTube client to look saved videos up with, or
+// nil without credentials for the default account.
+func videoChecker(ctx context.Context, cfg config.Config) (*youtube.Client, error) {
+	token, _, err := youtubeToken(cfg, "")
+	if err != nil || token == nil {
+		return nil, err
+	}
+	tokens, err := youtubeTokenSource(ctx, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+	opts := []youtube.ClientOption{youtube.WithTokenSource(tokens)}
+	if cfg.YouTube.APIURL != "" {
+		opts = append(opts, youtube.WithBaseURL(cfg.YouTube.APIURL))
+	}
+	return youtube.NewClient(nil, opts...), nil
+}
+
+// checkSaved checks that every saved item is still there, tombstoning
+// those that are gone and reporting them with those that couldn't be
+// checked. YouTube videos are looked up with videos when set, other items
+// by their link; when the lookup fails, warn is told and the videos are
+// checked by their link too. With archive set, gone items' archived copies
+// are recorded in store.
+func checkSaved(ctx context.Context, out io.Writer, warn func(error), store *saved.Store, checker *linkcheck.Checker, videos *youtube.Client, archive *wayback.Client) {
+	items := store.Items()
+	var availability map[string]string
+	if videos != nil {
+		var ids []string
+		for _, item := range items {
+			if item.Source == aggregator.SourceYouTube {
+				ids = append(ids, item.ID)
+			}
+		}
+		if len(ids) > 0 {
+			var err error
+			if availability, err = videos.FetchAvailability(ctx, ids); err != nil {
+				warn(fmt.Errorf("failed to look videos up on YouTube, checking their links: %w", err))
+			}
+		}
+	}
+
+	var checked, gone, unknown int
+	for _, item := range items {
+		var reason string
+		if status, ok := availability[item.ID]; ok && item.Source == aggregator.SourceYouTube {
+			checked++
+			if status != youtube.VideoPublic {
+				reason = status
+			}
+		} else {
+			if item.URL == "" {
+				continue
+			}
+			checked++
+			result := checker.Check(ctx, item.URL)
+			switch result.Status {
+			case linkcheck.Dead:
+				reason = result.Err.Error()
+			case linkcheck.Unknown:
+				unknown++
+				fmt.Fprintf(out, "Couldn't check: %s\n  %s (%v)\n", item.Title, item.URL, result.Err)
+				continue
+			}
+		}
+		store.Tombstone(item.FeedItem, reason)
+		if reason == "" {
+			continue
+		}
+		gone++
+		fmt.Fprintf(out, "Gone: %s\n  %s (%s)\n", item.Title, item.URL, reason)
+		if archive == nil || item.URL == "" {
+			continue
+		}
+		copyURL, err := archive.Closest(ctx, item.URL)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "  failed to look it up on the Wayback Machine: %v\n", err)
+		case copyURL == "":
+			fmt.Fprintln(out, "  not on the Wayback Machine")
+		default:
+			store.SetArchivedURL(item.FeedItem, copyURL)
+			fmt.Fprintf(out, "  archived: %s\n", copyURL)
+		}
+	}
+	fmt.Fprintf(out, "Checked %d items: %d gone, %d couldn't be checked\n", checked, gone, unknown)
+}
+
+// feedItems returns the feed items saved as items.
+func feedItems(items []saved.Item) []aggregator.FeedItem {
+	feed := make([]aggregator.FeedItem, len(items))
+	for i, item := range items {
+		feed[i] = item.FeedItem
+	}
+	return feed
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/linkcheck"
+	"github.com/gauthierbraillon/feedmix/internal/saved"
+	"github.com/gauthierbraillon/feedmix/internal/wayback"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func TestCheckSaved_ReportsDeadLinksAndRecordsArchivedCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wayback/available":
+			fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"url":"https://web.archive.org/web/2024/%s"}}}`, r.URL.Query().Get("url"))
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store, err := saved.Open(filepath.Join(t.TempDir(), "saved.json"), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(aggregator.FeedItem{ID: "a", Source: aggregator.SourceSubstack, Title: "Still there", URL: server.URL + "/post"})
+	store.Add(aggregator.FeedItem{ID: "b", Source: aggregator.SourceSubstack, Title: "Retracted", URL: server.URL + "/gone"})
+
+	var out bytes.Buffer
+	checker := linkcheck.NewChecker(linkcheck.WithHTTPClient(server.Client()), linkcheck.WithHostInterval(0), linkcheck.WithInterval(0))
+	checkSaved(context.Background(), &out, func(err error) { t.Error(err) }, store, checker, nil, wayback.NewClient(wayback.WithHTTPClient(server.Client()), wayback.WithBaseURL(server.URL)))
+
+	if got := out.String(); strings.Contains(got, "Still there") || !strings.Contains(got, "Gone: Retracted") || !strings.Contains(got, "Checked 2 items: 1 gone") {
+		t.Errorf("expected only the retracted post reported, got:\n%s", got)
+	}
+	for _, item := range store.Items() {
+		if want := map[string]string{"a": "", "b": "https://web.archive.org/web/2024/" + server.URL + "/gone"}[item.ID]; item.ArchivedURL != want {
+			t.Errorf("%s: expected archived copy %q, got %q", item.ID, want, item.ArchivedURL)
+		}
+		if gone := item.Gone != ""; gone != (item.ID == "b") {
+			t.Errorf("%s: only the retracted post should be tombstoned, got %q", item.ID, item.Gone)
+		}
+	}
+}
+
+func TestArchiveSaved_RecordsSnapshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/save/https://example.com/post" {
+			w.Header().Set("Content-Location", "/web/20240115120000/https://example.com/post")
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	store, err := saved.Open(filepath.Join(t.TempDir(), "saved.json"), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceSubstack, Title: "Post", URL: "https://example.com/post"},
+		{ID: "b", Source: aggregator.SourceSubstack, Title: "Busy", URL: "https://example.com/busy"},
+	}
+	for _, item := range items {
+		store.Add(item)
+	}
+
+	var out bytes.Buffer
+	var warnings []error
+	archiveSaved(context.Background(), &out, store, wayback.NewClient(wayback.WithHTTPClient(server.Client()), wayback.WithSaveURL(server.URL)), items, func(err error) { warnings = append(warnings, err) })
+	if got, _ := store.Lookup(items[0]); got.ArchivedURL != server.URL+"/web/20240115120000/https://example.com/post" {
+		t.Errorf("expected the snapshot recorded, got %q", got.ArchivedURL)
+	}
+	if got, ok := store.Lookup(items[1]); !ok || got.ArchivedURL != "" || len(warnings) != 1 {
+		t.Errorf("a page that failed to archive should stay saved and be reported, got %+v and %v", got, warnings)
+	}
+}
+
+func TestCheckSaved_LooksVideosUpOnYouTube(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/youtube/v3/videos" {
+			t.Errorf("videos shouldn't be checked by their link, got %s", r.URL)
+		}
+		fmt.Fprint(w, `{"items":[{"id":"v1","status":{"privacyStatus":"public"}},{"id":"v2","status":{"privacyStatus":"private"}}]}`)
+	}))
+	defer server.Close()
+
+	store, err := saved.Open(filepath.Join(t.TempDir(), "saved.json"), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"v1", "v2", "v3"} {
+		store.Add(aggregator.FeedItem{ID: id, Source: aggregator.SourceYouTube, Title: "Video " + id, URL: "https://www.youtube.com/watch?v=" + id})
+	}
+
+	var out bytes.Buffer
+	videos := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(server.URL))
+	checkSaved(context.Background(), &out, func(err error) { t.Error(err) }, store, linkcheck.NewChecker(), videos, nil)
+	for _, item := range store.Items() {
+		if want := map[string]string{"v1": "", "v2": "private", "v3": "removed"}[item.ID]; item.Gone != want {
+			t.Errorf("%s: expected tombstone %q, got %q", item.ID, want, item.Gone)
+		}
+	}
+}
+
+func TestCheckSaved_WarnsAndChecksLinksWhenYouTubeLookupFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/youtube/v3/videos" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	store, err := saved.Open(filepath.Join(t.TempDir(), "saved.json"), clock.System)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(aggregator.FeedItem{ID: "v1", Source: aggregator.SourceYouTube, Title: "Video", URL: server.URL + "/watch"})
+
+	var out bytes.Buffer
+	var warnings []error
+	checker := linkcheck.NewChecker(linkcheck.WithHTTPClient(server.Client()), linkcheck.WithHostInterval(0), linkcheck.WithInterval(0))
+	videos := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(server.URL))
+	checkSaved(context.Background(), &out, func(err error) { warnings = append(warnings, err) }, store, checker, videos, nil)
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "YouTube") {
+		t.Errorf("the failed lookup should be reported as a warning, got %v", warnings)
+	}
+	if got := out.String(); strings.Contains(got, "Warning") || !strings.Contains(got, "Checked 1 items: 0 gone") {
+		t.Errorf("the report should check the video's link and hold no warning, got:\n%s", got)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+	"github.com/gauthierbraillon/feedmix/internal/jsonschema"
+	"github.com/gauthierbraillon/feedmix/internal/runs"
+	"github.com/gauthierbraillon/feedmix/internal/saved"
+)
+
+// outputSchema is the JSON Schema of one of feedmix's JSON outputs.
+type outputSchema struct {
+	name   string
+	output string
+	schema func() *jsonschema.Schema
+}
+
+// schemaEnums lists the values of the string types the outputs share.
+var schemaEnums = []jsonschema.Option{
+	jsonschema.WithEnum(aggregator.SourceYouTube, aggregator.SourceSubstack, aggregator.SourceReader, aggregator.SourceBridge, aggregator.SourcePodcast, aggregator.SourceTwitch, aggregator.SourceGitHub, aggregator.SourceMedium, aggregator.SourceLobsters, aggregator.SourceArxiv, aggregator.SourcePeerTube),
+	jsonschema.WithEnum(aggregator.ItemTypeVideo, aggregator.ItemTypeLike, aggregator.ItemTypeArticle, aggregator.ItemTypeLive, aggregator.ItemTypePodcast, aggregator.ItemTypePost, aggregator.ItemTypeRelease, aggregator.ItemTypeLink, aggregator.ItemTypePaper),
+	jsonschema.WithEnum(eventlog.Discovered, eventlog.Displayed, eventlog.Opened, eventlog.Read, eventlog.Saved),
+}
+
+var outputSchemas = []outputSchema{
+	{"items", "feedmix saved --format json", func() *jsonschema.Schema {
+		return jsonschema.For(aggregator.NewEnvelope[saved.Item](nil), "feedmix items",
+			fmt.Sprintf("A list of feed items tagged with the version of their layout, currently %d.", aggregator.SchemaVersion), schemaEnums...)
+	}},
+	{"jsonfeed", "feedmix feed --format jsonfeed, feedmix saved --format jsonfeed", func() *jsonschema.Schema {
+		return display.JSONFeedSchema(schemaEnums...)
+	}},
+	{"events", "each line of the FEEDMIX_EVENT_LOG file (NDJSON)", func() *jsonschema.Schema {
+		return jsonschema.For(eventlog.Event{}, "feedmix event", "One line of the event log: something that happened to an item.", schemaEnums...)
+	}},
+	{"manifest", "feedmix runs show --json", func() *jsonschema.Schema {
+		return jsonschema.For(runs.Manifest{}, "feedmix run manifest", "What a feed run requested, fetched and displayed.", schemaEnums...)
+	}},
+}
+
+func newSchemaCmd() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "schema [name]",
+		Short: "Print the JSON Schema of a JSON output",
+		Long: "Prints the JSON Schema (draft 2020-12) of one of feedmix's JSON outputs, to validate it or generate code from it. " +
+			"Without a name, lists the schemas and the outputs they describe; with --dir, writes every schema there as <name>.schema.json.\n\n" +
+			"The schemas are derived from the types feedmix encodes, so they always match the version that prints them.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if dir != "" {
+				if len(args) == 1 {
+					return fmt.Errorf("give a schema name or --dir, not both")
+				}
+				if err := os.MkdirAll(dir, 0750); err != nil {
+					return fmt.Errorf("failed to create schema directory: %w", err)
+				}
+				for _, s := range outputSchemas {
+					data, err := json.MarshalIndent(s.schema(), "", "  ")
+					if err != nil {
+						return err
+					}
+					path := filepath.Join(dir, s.name+".schema.json")
+					if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+						return fmt.Errorf("failed to write schema: %w", err)
+					}
+					fmt.Fprintln(out, path)
+				}
+				return nil
+			}
+
+			if len(args) == 0 {
+				for _, s := range outputSchemas {
+					fmt.Fprintf(out, "%-10s %s\n", s.name, s.output)
+				}
+				return nil
+			}
+			var names []string
+			for _, s := range outputSchemas {
+				if s.name == args[0] {
+					enc := json.NewEncoder(out)
+					enc.SetIndent("", "  ")
+					return enc.Encode(s.schema())
+				}
+				names = append(names, s.name)
+			}
+			return fmt.Errorf("unknown schema %q: must be one of %s", args[0], strings.Join(names, ", "))
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Write every schema to this directory instead")
+	return cmd
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/eventlog"
+	"github.com/gauthierbraillon/feedmix/internal/greader"
+	"github.com/gauthierbraillon/feedmix/internal/history"
+	"github.com/gauthierbraillon/feedmix/internal/readsync"
+	"github.com/gauthierbraillon/feedmix/internal/source"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+func newSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Sync read and starred items with your reader",
+		Long: "Keeps the reading list of FEEDMIX_READER_URL in step with feedmix both ways: items opened in feedmix are marked read " +
+			"on the server and saved items are starred, while items read or starred in the reader's web interface are marked opened " +
+			"(for 'feedmix open --unread') and saved in feedmix. Unreading and unstarring sync too.\n\n" +
+			"When both sides changed an item, the most recent change wins. The server doesn't say when an item was read, so " +
+			"changes made there count from the sync that first sees them: sync often, e.g. from cron, to keep them accurate.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if cfg.Reader.URL == "" {
+				return errors.New("no reader to sync with: set FEEDMIX_READER_URL, FEEDMIX_READER_USER and FEEDMIX_READER_PASSWORD")
+			}
+			now, err := commandClock(cmd)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			client := greader.NewClient(cfg.Reader.URL, cfg.Reader.User, cfg.Reader.Password, greader.WithHTTPClient(httpx.NewClient()))
+
+			entries, err := client.FetchItems(ctx, cfg.Limits.Reader)
+			if err != nil {
+				return err
+			}
+			starred, err := client.FetchStarred(ctx, cfg.Limits.Reader)
+			if err != nil {
+				return err
+			}
+
+			opened, err := history.Open(historyPath(cfg), now)
+			if err != nil {
+				return err
+			}
+			store, err := openSaved(cfg, now)
+			if err != nil {
+				return err
+			}
+			state, err := readsync.Open(filepath.Join(cfg.Dir, "reader-sync.json"), now)
+			if err != nil {
+				return err
+			}
+
+			items := make(map[string]aggregator.FeedItem)
+			var synced []readsync.Item
+			for _, entry := range append(entries, starred...) {
+				if _, dup := items[entry.ID]; dup {
+					continue
+				}
+				item := source.ReaderItem(entry)
+				items[entry.ID] = item
+				synced = append(synced, readsync.Item{
+					ID:        entry.ID,
+					Published: entry.PublishedAt,
+					Local: readsync.State{
+						Read:      opened.Opened(item),
+						ReadAt:    opened.OpenedAt(item),
+						Starred:   !store.SavedAt(item).IsZero(),
+						StarredAt: store.SavedAt(item),
+					},
+					Remote: readsync.State{Read: entry.Read, Starred: entry.Starred},
+				})
+			}
+			plan := state.Reconcile(synced)
+
+			for _, edit := range []struct {
+				ids []string
+				fn  func(context.Context, []string, bool) error
+				on  bool
+			}{
+				{plan.Remote.Read, client.MarkRead, true},
+				{plan.Remote.Unread, client.MarkRead, false},
+				{plan.Remote.Star, client.Star, true},
+				{plan.Remote.Unstar, client.Star, false},
+			} {
+				if err := edit.fn(ctx, edit.ids, edit.on); err != nil {
+					return err
+				}
+			}
+
+			var read, saved []aggregator.FeedItem
+			for _, id := range plan.Local.Read {
+				opened.MarkOpened(items[id])
+				read = append(read, items[id])
+			}
+			for _, id := range plan.Local.Unread {
+				opened.ClearOpened(items[id])
+			}
+			for _, id := range plan.Local.Star {
+				store.Add(items[id])
+				saved = append(saved, items[id])
+			}
+			for _, id := range plan.Local.Unstar {
+				store.Remove(id)
+			}
+			if err := opened.Save(); err != nil {
+				return err
+			}
+			if err := store.Save(); err != nil {
+				return err
+			}
+			if err := state.Save(); err != nil {
+				return err
+			}
+			recordEvents(cmd, cfg, eventlog.Read, read)
+			recordEvents(cmd, cfg, eventlog.Saved, saved)
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Synced %d items with %s: %s.\n", len(synced), cfg.Reader.URL, syncSummary(plan))
+			return nil
+		},
+	}
+}
+
+// syncSummary describes what a sync changed on each side.
+func syncSummary(plan readsync.Plan) string {
+	if plan.Local.Empty() && plan.Remote.Empty() {
+		return "already in step"
+	}
+	describe := func(changes readsync.Changes, read, starred, unstarred string) string {
+		var parts []string
+		for _, part := range []struct {
+			ids  []string
+			verb string
+		}{
+			{changes.Read, read},
+			{changes.Unread, "marked unread"},
+			{changes.Star, starred},
+			{changes.Unstar, unstarred},
+		} {
+			if len(part.ids) > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", len(part.ids), part.verb))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	var sides []string
+	if !plan.Remote.Empty() {
+		sides = append(sides, describe(plan.Remote, "marked read", "starred", "unstarred")+" on the server")
+	}
+	if !plan.Local.Empty() {
+		sides = append(sides, describe(plan.Local, "marked opened", "saved", "unsaved")+" in feedmix")
+	}
+	return strings.Join(sides, "; ")
+}
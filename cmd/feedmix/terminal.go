@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+)
+
+// isTerminal reports whether w is a terminal rather than a file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// outputWidth returns the number of columns to fit output written to w to:
+// the configured width, else the terminal's, else 0 (unbounded) for files
+// and pipes. $COLUMNS is used when the terminal can't be asked.
+func outputWidth(configured int, w io.Writer, getenv func(string) string) int {
+	if configured > 0 {
+		return configured
+	}
+	if !isTerminal(w) {
+		return 0
+	}
+	if cols, _, ok := terminalSize(w.(*os.File)); ok {
+		return cols
+	}
+	return envSize(getenv("COLUMNS"))
+}
+
+// outputHeight returns the number of rows of the terminal w is, or 0 if w
+// isn't a terminal or its size is unknown. $LINES is used when the terminal
+// can't be asked.
+func outputHeight(w io.Writer, getenv func(string) string) int {
+	if !isTerminal(w) {
+		return 0
+	}
+	if _, rows, ok := terminalSize(w.(*os.File)); ok {
+		return rows
+	}
+	return envSize(getenv("LINES"))
+}
+
+// envSize parses a $COLUMNS or $LINES value, returning 0 when unusable.
+func envSize(raw string) int {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+// imageProtocol resolves an inline thumbnail mode for output written to w
+// to the protocol images are drawn with, or "" to show thumbnail URLs. In
+// auto mode only terminals known to support a protocol get images.
+func imageProtocol(mode string, w io.Writer, getenv func(string) string) display.ImageProtocol {
+	switch mode {
+	case "":
+		return ""
+	case config.ImageProtocolKitty, config.ImageProtocolITerm, config.ImageProtocolSixel:
+		return display.ImageProtocol(mode)
+	}
+	if !isTerminal(w) || getenv("TERM") == "dumb" {
+		return ""
+	}
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || strings.HasPrefix(getenv("TERM"), "xterm-kitty") || getenv("TERM_PROGRAM") == "ghostty":
+		return display.ImageKitty
+	case getenv("TERM_PROGRAM") == "iTerm.app" || getenv("TERM_PROGRAM") == "WezTerm":
+		return display.ImageITerm
+	case strings.HasPrefix(getenv("TERM"), "foot") || strings.HasPrefix(getenv("TERM"), "mlterm"):
+		return display.ImageSixel
+	}
+	return ""
+}
+
+// hyperlinksEnabled resolves a hyperlink mode for output written to w. In
+// auto mode they are only enabled on a terminal known to support OSC 8, as
+// there is no way to ask the terminal itself.
+func hyperlinksEnabled(mode string, w io.Writer, getenv func(string) string) bool {
+	switch mode {
+	case config.HyperlinksAlways:
+		return true
+	case config.HyperlinksNever:
+		return false
+	}
+	if !isTerminal(w) || getenv("TERM") == "dumb" {
+		return false
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+	if getenv("KITTY_WINDOW_ID") != "" || getenv("WT_SESSION") != "" || strings.HasPrefix(getenv("TERM"), "xterm-kitty") {
+		return true
+	}
+	vte, err := strconv.Atoi(getenv("VTE_VERSION"))
+	return err == nil && vte >= 5000
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+
+package main
+
+import "os"
+
+// terminalSize reports the size as unknown on platforms where feedmix
+// doesn't query the terminal; $COLUMNS and $LINES are used instead.
+func terminalSize(*os.File) (cols, rows int, ok bool) {
+	return 0, 0, false
+}
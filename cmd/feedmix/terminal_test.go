@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// TestHyperlinksEnabled_AutoNeedsATerminal verifies that auto mode never
+// sends escape sequences into files or pipes, even from a capable terminal.
+func TestHyperlinksEnabled_AutoNeedsATerminal(t *testing.T) {
+	env := map[string]string{"TERM_PROGRAM": "iTerm.app"}
+	getenv := func(key string) string { return env[key] }
+	var buf bytes.Buffer
+
+	if hyperlinksEnabled(config.HyperlinksAuto, &buf, getenv) {
+		t.Error("auto mode should not emit hyperlinks into a pipe or file")
+	}
+	if !hyperlinksEnabled(config.HyperlinksAlways, &buf, getenv) {
+		t.Error("always mode should emit hyperlinks regardless of the output")
+	}
+	if hyperlinksEnabled(config.HyperlinksNever, &buf, getenv) {
+		t.Error("never mode should not emit hyperlinks")
+	}
+}
+
+// TestFormatterOptions_RejectsUnknownTheme verifies that a typo in
+// FEEDMIX_THEME or --theme is reported instead of silently ignored.
+func TestFormatterOptions_RejectsUnknownTheme(t *testing.T) {
+	cfg := config.Config{Display: config.Display{Color: true, Theme: "neon"}}
+	if _, err := formatterOptions(cfg, &bytes.Buffer{}, clock.System); err == nil {
+		t.Error("an unknown theme should be rejected")
+	}
+
+	cfg.Display.Theme = ""
+	if _, err := formatterOptions(cfg, &bytes.Buffer{}, clock.System); err != nil {
+		t.Errorf("an empty theme should select the default one, got: %v", err)
+	}
+}
+
+// TestOutputWidth_ConfiguredWidthWinsAndPipesAreUnbounded verifies that
+// output to a file or pipe is only wrapped when a width is set.
+func TestOutputWidth_ConfiguredWidthWinsAndPipesAreUnbounded(t *testing.T) {
+	getenv := func(key string) string { return map[string]string{"COLUMNS": "120"}[key] }
+	var buf bytes.Buffer
+
+	if n := outputWidth(0, &buf, getenv); n != 0 {
+		t.Errorf("a pipe should not be wrapped, got width %d", n)
+	}
+	if n := outputWidth(72, &buf, getenv); n != 72 {
+		t.Errorf("the configured width should be used, got %d", n)
+	}
+}
+
+// TestImageProtocol_AutoNeedsATerminal verifies that auto mode never sends
+// image escape sequences into files or pipes, while a forced protocol does.
+func TestImageProtocol_AutoNeedsATerminal(t *testing.T) {
+	getenv := func(key string) string { return map[string]string{"TERM": "xterm-kitty"}[key] }
+	var buf bytes.Buffer
+
+	if p := imageProtocol(config.InlineThumbnailsAuto, &buf, getenv); p != "" {
+		t.Errorf("auto mode should not draw images into a pipe or file, got %q", p)
+	}
+	if p := imageProtocol(config.ImageProtocolSixel, &buf, getenv); p != display.ImageSixel {
+		t.Errorf("a forced protocol should be used regardless of the output, got %q", p)
+	}
+	if p := imageProtocol("", &buf, getenv); p != "" {
+		t.Errorf("inline thumbnails should be off by default, got %q", p)
+	}
+}
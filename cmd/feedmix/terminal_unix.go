@@ -0,0 +1,19 @@
+//go:build darwin || linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalSize asks the terminal behind f for its number of columns and rows.
+func terminalSize(f *os.File) (cols, rows int, ok bool) {
+	var size struct{ rows, cols, x, y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size))) // #nosec G103 -- TIOCGWINSZ fills the winsize struct
+	if errno != 0 || size.cols == 0 || size.rows == 0 {
+		return 0, 0, false
+	}
+	return int(size.cols), int(size.rows), true
+}
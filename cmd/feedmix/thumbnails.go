@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// Thumbnails rarely change, so downloaded ones are reused for a week, as
+// long as all of them fit in thumbnailCacheSize bytes.
+const (
+	thumbnailCacheTTL  = 7 * 24 * time.Hour
+	thumbnailCacheSize = 100 << 20
+	maxThumbnailSize   = 5 << 20
+)
+
+// thumbnailLoader downloads thumbnails for inline display, each at most once.
+type thumbnailLoader struct {
+	ctx    context.Context
+	client *http.Client
+
+	mu     sync.Mutex
+	images map[string][]byte
+	errs   map[string]error
+}
+
+func newThumbnailLoader(ctx context.Context, client *http.Client) *thumbnailLoader {
+	return &thumbnailLoader{ctx: ctx, client: client, images: make(map[string][]byte), errs: make(map[string]error)}
+}
+
+// prefetch downloads the thumbnails of items with up to concurrency
+// requests at a time, so formatting the feed doesn't wait on each in turn.
+func (l *thumbnailLoader) prefetch(items []aggregator.FeedItem, concurrency int) {
+	urls := make(chan string)
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range urls {
+				_, _ = l.load(url)
+			}
+		}()
+	}
+	for _, item := range items {
+		if item.Thumbnail != "" {
+			urls <- item.Thumbnail
+		}
+	}
+	close(urls)
+	wg.Wait()
+}
+
+// load returns the image at url.
+func (l *thumbnailLoader) load(url string) ([]byte, error) {
+	l.mu.Lock()
+	data, done := l.images[url]
+	err, failed := l.errs[url]
+	l.mu.Unlock()
+	if done || failed {
+		return data, err
+	}
+
+	data, err = l.download(url)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		l.errs[url] = err
+		return nil, err
+	}
+	l.images[url] = data
+	return data, nil
+}
+
+func (l *thumbnailLoader) download(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(l.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid thumbnail URL: %w", err)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download thumbnail: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download thumbnail: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download thumbnail: %w", err)
+	}
+	if len(data) > maxThumbnailSize {
+		return nil, fmt.Errorf("thumbnail larger than %d bytes", maxThumbnailSize)
+	}
+	return data, nil
+}
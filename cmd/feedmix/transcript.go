@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/display"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+)
+
+func newTranscriptCmd() *cobra.Command {
+	var lang string
+	var timestamps bool
+
+	cmd := &cobra.Command{
+		Use:   "transcript <video-id|url>",
+		Short: "Print the transcript of a YouTube video",
+		Long: "Prints the captions of a YouTube video as running text, to read it like an article or pass it on to another tool. " +
+			"English captions are preferred, then the video's default language; choose another with --lang.\n\n" +
+			"Captions need no credentials and cost no YouTube quota.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			videoID, err := youtube.ParseVideoID(args[0])
+			if err != nil {
+				return err
+			}
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			var opts []youtube.ClientOption
+			if cfg.YouTube.APIURL != "" {
+				opts = append(opts, youtube.WithTimedTextURL(strings.TrimRight(cfg.YouTube.APIURL, "/")+"/api/timedtext"))
+			}
+			transcript, err := youtube.NewClient(nil, opts...).FetchTranscript(context.Background(), videoID, strings.TrimSpace(lang))
+			if err != nil {
+				return err
+			}
+
+			if !timestamps {
+				return writePaged(cmd, cfg.Pager, transcript.Text()+"\n")
+			}
+			var b strings.Builder
+			for _, cue := range transcript.Cues {
+				fmt.Fprintf(&b, "[%s] %s\n", display.VideoTime(cue.Start), strings.Join(strings.Fields(cue.Text), " "))
+			}
+			return writePaged(cmd, cfg.Pager, b.String())
+		},
+	}
+
+	cmd.Flags().StringVar(&lang, "lang", "", "Language of the captions, e.g. en or fr (default: English, else the video's language)")
+	cmd.Flags().BoolVar(&timestamps, "timestamps", false, "Print each caption on its own line after its time in the video")
+	return cmd
+}
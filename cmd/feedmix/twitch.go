@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+const twitchProvider = "twitch"
+
+// twitchTokenSource returns refreshing tokens for Twitch, starting from
+// FEEDMIX_TWITCH_REFRESH_TOKEN when set, otherwise from the token saved by
+// 'feedmix auth twitch', which refreshed tokens are written back to.
+func twitchTokenSource(ctx context.Context, cfg config.Config) (oauth.TokenSource, error) {
+	startToken := &oauth.Token{RefreshToken: cfg.Twitch.RefreshToken}
+	var tokenOpts []oauth.TokenSourceOption
+	if cfg.Twitch.RefreshToken == "" {
+		stored, err := tokenStore(cfg).Load(twitchProvider)
+		if errors.Is(err, oauth.ErrTokenNotFound) || (err == nil && stored.RefreshToken == "") {
+			return nil, fmt.Errorf("missing Twitch credentials: run 'feedmix auth twitch' or set FEEDMIX_TWITCH_REFRESH_TOKEN")
+		}
+		if err != nil {
+			return nil, err
+		}
+		startToken = stored
+		tokenOpts = append(tokenOpts, oauth.WithTokenStore(tokenStore(cfg), twitchProvider))
+	}
+
+	tokens := oauth.NewRefreshingTokenSource(oauth.NewFlow(twitchOAuthConfig(cfg)), startToken, tokenOpts...)
+	if _, err := tokens.Token(ctx); err != nil {
+		return nil, fmt.Errorf("failed to refresh Twitch token: %w", err)
+	}
+	return tokens, nil
+}
+
+func twitchOAuthConfig(cfg config.Config) oauth.Config {
+	return oauth.TwitchOAuthConfig(cfg.Twitch.ClientID, cfg.Twitch.ClientSecret)
+}
+
+func newAuthTwitchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "twitch",
+		Short: "Authorize Twitch access and store the token",
+		Long: "Prints a code and URL to open on any device, where you approve feedmix reading the channels you follow " +
+			"on Twitch, then saves the token to the token store selected by FEEDMIX_TOKEN_STORE (file or keyring).\n\n" +
+			"It needs the client ID of a Twitch application (https://dev.twitch.tv/console/apps) in FEEDMIX_TWITCH_CLIENT_ID; " +
+			"create it as a public client, or also set FEEDMIX_TWITCH_CLIENT_SECRET for a confidential one.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if cfg.Twitch.ClientID == "" {
+				return fmt.Errorf("FEEDMIX_TWITCH_CLIENT_ID is not set: register an application at https://dev.twitch.tv/console/apps")
+			}
+			token, err := authorizeDevice(cmd, oauth.NewFlow(twitchOAuthConfig(cfg)))
+			if err != nil {
+				return err
+			}
+			if err := tokenStore(cfg).Save(twitchProvider, token); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Twitch token saved to %s\n", tokenStoreLocation(cfg, twitchProvider))
+			return nil
+		},
+	}
+}
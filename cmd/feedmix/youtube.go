@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gauthierbraillon/feedmix/internal/config"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+)
+
+func newYouTubeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "youtube",
+		Short: "YouTube helpers",
+	}
+	cmd.AddCommand(newYouTubeResolveCmd())
+	return cmd
+}
+
+func newYouTubeResolveCmd() *cobra.Command {
+	var account string
+
+	cmd := &cobra.Command{
+		Use:   "resolve <@handle|url>...",
+		Short: "Print the channel ID of a channel handle or URL",
+		Long: "Looks up the channel ID (UC…) of each channel given as an @handle, a channel URL such as " +
+			"https://www.youtube.com/@handle, /channel/, /user/ or /c/, or the URL of one of its videos, and prints one ID per line.\n\n" +
+			"Each lookup costs 1 YouTube quota unit, except legacy /c/ URLs, which can only be found by searching (100 units).",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(os.Getenv)
+			if err != nil {
+				return err
+			}
+			if account != "" {
+				if _, err := youtubeAccounts(cfg, []string{account}); err != nil {
+					return err
+				}
+			}
+			ctx := context.Background()
+			tokens, err := youtubeTokenSource(ctx, cfg, account)
+			if err != nil {
+				return err
+			}
+			opts := []youtube.ClientOption{youtube.WithTokenSource(tokens)}
+			if cfg.YouTube.APIURL != "" {
+				opts = append(opts, youtube.WithBaseURL(cfg.YouTube.APIURL))
+			}
+			client := youtube.NewClient(nil, opts...)
+
+			for _, ref := range args {
+				id, err := client.ResolveChannel(ctx, ref)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), id)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&account, "account", "", "YouTube account from FEEDMIX_YOUTUBE_ACCOUNTS to look channels up with")
+	return cmd
+}
@@ -7,6 +7,7 @@ toolchain go1.24.13
 require (
 	github.com/joho/godotenv v1.5.1
 	github.com/spf13/cobra v1.10.2
+	golang.org/x/text v0.30.0
 )
 
 require (
@@ -36,6 +36,14 @@ func (a *Aggregator) GetFeed(opts FeedOptions) []FeedItem {
 			continue
 		}
 
+		if len(opts.Groups) > 0 && !containsString(opts.Groups, item.Group) {
+			continue
+		}
+
+		if opts.HidePaywalled && item.Paywalled {
+			continue
+		}
+
 		// Apply date filters
 		if !opts.Since.IsZero() && item.PublishedAt.Before(opts.Since) {
 			continue
@@ -49,15 +57,130 @@ func (a *Aggregator) GetFeed(opts FeedOptions) []FeedItem {
 
 	// Sort by PublishedAt descending (newest first)
 	sort.Slice(result, func(i, j int) bool {
+		if opts.Sort == SortGrowth {
+			if gi, gj := result[i].Growth(), result[j].Growth(); gi != gj {
+				return gi > gj
+			}
+		} else if li, lj := result[i].LiveNow(), result[j].LiveNow(); li != lj {
+			return li
+		}
 		return result[i].PublishedAt.After(result[j].PublishedAt)
 	})
 
 	// Apply limit
+	result = newCaps(opts).filter(result)
 	if opts.Limit > 0 && len(result) > opts.Limit {
 		result = result[:opts.Limit]
 	}
 
-	return result
+	return group(result, opts.GroupBy)
+}
+
+// group gathers sorted items into sections, ordered by their most recent
+// item, keeping the order of items within each section.
+func group(items []FeedItem, by GroupBy) []FeedItem {
+	if by == GroupByNone {
+		return items
+	}
+	var keys []string
+	sections := make(map[string][]FeedItem)
+	for _, item := range items {
+		key := by.Section(item)
+		if _, ok := sections[key]; !ok {
+			keys = append(keys, key)
+		}
+		sections[key] = append(sections[key], item)
+	}
+	grouped := make([]FeedItem, 0, len(items))
+	for _, key := range keys {
+		grouped = append(grouped, sections[key]...)
+	}
+	return grouped
+}
+
+// Stream adds each batch received on batches and emits the items that pass
+// the filters in opts as soon as their batch arrives, in opts.Sort order
+// within a batch, until opts.Limit items have been emitted. Items whose
+// source ID or URL was already emitted are skipped. The returned channel is
+// closed once batches is closed; it must be drained. GetFeed then returns
+// the complete, sorted feed.
+func (a *Aggregator) Stream(batches <-chan []FeedItem, opts FeedOptions) <-chan FeedItem {
+	out := make(chan FeedItem)
+	filters := opts
+	filters.Limit = 0
+	filters.SourceLimits, filters.GroupLimits = nil, nil
+	caps := newCaps(opts)
+
+	go func() {
+		defer close(out)
+		seen := make(map[string]bool)
+		emitted := 0
+		for batch := range batches {
+			a.AddItems(batch)
+			for _, item := range (&Aggregator{items: batch}).GetFeed(filters) {
+				if opts.Limit > 0 && emitted >= opts.Limit {
+					break
+				}
+				key := string(item.Source) + ":" + item.ID
+				if seen[key] || (item.URL != "" && seen[item.URL]) || !caps.allow(item) {
+					continue
+				}
+				seen[key] = true
+				if item.URL != "" {
+					seen[item.URL] = true
+				}
+				out <- item
+				emitted++
+			}
+		}
+	}()
+	return out
+}
+
+// caps enforces FeedOptions.SourceLimits and GroupLimits, counting the
+// items allowed so far.
+type caps struct {
+	sourceLimits map[Source]int
+	groupLimits  map[string]int
+	sources      map[Source]int
+	groups       map[string]int
+}
+
+func newCaps(opts FeedOptions) *caps {
+	return &caps{
+		sourceLimits: opts.SourceLimits,
+		groupLimits:  opts.GroupLimits,
+		sources:      make(map[Source]int),
+		groups:       make(map[string]int),
+	}
+}
+
+// allow reports whether item is within its source's and group's limits,
+// counting it if so.
+func (c *caps) allow(item FeedItem) bool {
+	if limit, ok := c.sourceLimits[item.Source]; ok && c.sources[item.Source] >= limit {
+		return false
+	}
+	if limit, ok := c.groupLimits[item.Group]; ok && item.Group != "" && c.groups[item.Group] >= limit {
+		return false
+	}
+	c.sources[item.Source]++
+	c.groups[item.Group]++
+	return true
+}
+
+// filter returns the items within their limits, in order.
+func (c *caps) filter(items []FeedItem) []FeedItem {
+	if len(c.sourceLimits) == 0 && len(c.groupLimits) == 0 {
+		return items
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if c.allow(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
 }
 
 func containsSource(sources []Source, source Source) bool {
@@ -77,3 +200,12 @@ func containsType(types []ItemType, itemType ItemType) bool {
 	}
 	return false
 }
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package aggregator
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -96,6 +99,21 @@ func TestAC202_Feed_ShowsOnlyItemsFromSelectedSource(t *testing.T) {
 	}
 }
 
+func TestAC202_Feed_ShowsOnlyItemsFromSelectedGroups(t *testing.T) {
+	now := time.Now()
+	agg := New()
+	agg.AddItems([]FeedItem{
+		{ID: "1", Group: "tech", PublishedAt: now},
+		{ID: "2", Group: "gaming", PublishedAt: now},
+		{ID: "3", PublishedAt: now},
+	})
+
+	feed := agg.GetFeed(FeedOptions{Groups: []string{"tech"}})
+	if len(feed) != 1 || feed[0].ID != "1" {
+		t.Errorf("user filtering by group should only see that group's items, got %+v", feed)
+	}
+}
+
 func TestAC203_Feed_ShowsOnlySelectedContentTypes(t *testing.T) {
 	now := time.Now()
 	items := []FeedItem{
@@ -168,3 +186,229 @@ func TestAC205_Feed_HandlesEmptyFeedGracefully(t *testing.T) {
 		t.Errorf("user with no subscriptions should see empty feed, got %d items", len(feed))
 	}
 }
+
+func TestAC206_Feed_StreamsItemsAsSourcesComplete(t *testing.T) {
+	now := time.Now()
+	batches := make(chan []FeedItem)
+	agg := New()
+	stream := agg.Stream(batches, FeedOptions{Limit: 3})
+
+	batches <- []FeedItem{
+		{ID: "fast-old", Source: SourceYouTube, PublishedAt: now.Add(-2 * time.Hour)},
+		{ID: "fast-new", Source: SourceYouTube, PublishedAt: now.Add(-1 * time.Hour)},
+	}
+	if first := <-stream; first.ID != "fast-new" {
+		t.Errorf("user should see the first batch, newest first, before later sources finish; got %s", first.ID)
+	}
+	if second := <-stream; second.ID != "fast-old" {
+		t.Errorf("user should see the rest of the first batch next, got %s", second.ID)
+	}
+
+	go func() {
+		batches <- []FeedItem{{ID: "fast-new", Source: SourceYouTube, PublishedAt: now.Add(-1 * time.Hour)}}
+		batches <- []FeedItem{
+			{ID: "slow-1", Source: SourceSubstack, PublishedAt: now},
+			{ID: "slow-2", Source: SourceSubstack, PublishedAt: now.Add(-time.Minute)},
+		}
+		close(batches)
+	}()
+	var rest []string
+	for item := range stream {
+		rest = append(rest, item.ID)
+	}
+	if len(rest) != 1 || rest[0] != "slow-1" {
+		t.Errorf("user should not see duplicates and streaming should stop at the limit, got %v", rest)
+	}
+	if feed := agg.GetFeed(FeedOptions{}); len(feed) == 0 || feed[0].ID != "slow-1" {
+		t.Error("the complete feed should be available, sorted, once the stream ends")
+	}
+}
+
+// BenchmarkGetFeed measures filtering, sorting and limiting a feed the size of
+// a heavy user's run (300 channels × 5 videos plus newsletters).
+// Run with: go test -bench=. -benchmem ./internal/aggregator
+func BenchmarkGetFeed(b *testing.B) {
+	now := time.Now()
+	items := make([]FeedItem, 0, 1600)
+	for i := 0; i < cap(items); i++ {
+		source := SourceYouTube
+		if i%16 == 0 {
+			source = SourceSubstack
+		}
+		items = append(items, FeedItem{
+			ID:          fmt.Sprintf("item-%d", i),
+			Source:      source,
+			PublishedAt: now.Add(-time.Duration(i*7919%1600) * time.Minute),
+		})
+	}
+
+	agg := New()
+	agg.AddItems(items)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		agg.GetFeed(FeedOptions{Limit: 20})
+	}
+}
+
+func TestAC207_Feed_GroupsItemsByAuthorWithMostRecentSectionFirst(t *testing.T) {
+	now := time.Now()
+	agg := New()
+	agg.AddItems([]FeedItem{
+		{ID: "a1", Author: "Alice", AuthorID: "UC_A", PublishedAt: now.Add(-3 * time.Hour)},
+		{ID: "b1", Author: "Bob", AuthorID: "UC_B", PublishedAt: now.Add(-1 * time.Hour)},
+		{ID: "a2", Author: "Alice", AuthorID: "UC_A", PublishedAt: now.Add(-2 * time.Hour)},
+		{ID: "b2", Author: "Bob", AuthorID: "UC_B", PublishedAt: now.Add(-4 * time.Hour)},
+		{ID: "c1", Author: "Carol", Source: SourceSubstack, PublishedAt: now.Add(-5 * time.Hour)},
+	})
+
+	feed := agg.GetFeed(FeedOptions{GroupBy: GroupByAuthor, Limit: 4})
+	var ids []string
+	for _, item := range feed {
+		ids = append(ids, item.ID)
+	}
+	if got := strings.Join(ids, ","); got != "b1,b2,a2,a1" {
+		t.Errorf("items should be grouped per author, newest section first, within the limit, got %s", got)
+	}
+}
+
+func TestAC208_Items_DecodeAcrossSchemaVersions(t *testing.T) {
+	if len(migrations) != SchemaVersion {
+		t.Fatalf("every schema version needs a migration from the previous one: %d migrations for version %d", len(migrations), SchemaVersion)
+	}
+
+	items := []FeedItem{{ID: "a", Source: SourceYouTube, Title: "Current"}}
+	data, err := json.Marshal(NewEnvelope(items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, version, err := UnmarshalItems[FeedItem](data)
+	if err != nil || version != SchemaVersion || len(decoded) != 1 || decoded[0].Title != "Current" {
+		t.Errorf("items should round-trip with the current version, got %+v, version %d (err %v)", decoded, version, err)
+	}
+
+	decoded, version, err = UnmarshalItems[FeedItem]([]byte(`[{"id":"b","source":"substack","title":"Unversioned"}]`))
+	if err != nil || version != 0 || len(decoded) != 1 || decoded[0].Title != "Unversioned" {
+		t.Errorf("a bare array from before versioning should decode as version 0, got %+v, version %d (err %v)", decoded, version, err)
+	}
+
+	decoded, version, err = UnmarshalItems[FeedItem]([]byte(`{"schema_version":7,"items":[{"id":"c","title":"Newer","rating":5}]}`))
+	if err != nil || version != 7 || len(decoded) != 1 || decoded[0].Title != "Newer" {
+		t.Errorf("items from a newer version should decode, ignoring unknown fields, got %+v, version %d (err %v)", decoded, version, err)
+	}
+
+	if _, _, err := UnmarshalItems[FeedItem]([]byte(`{"schema_version":1,"items":"oops"}`)); err == nil {
+		t.Error("malformed items should be rejected")
+	}
+}
+
+func TestAC209_Feed_CapsNoisySourcesAndGroups(t *testing.T) {
+	now := time.Now()
+	var items []FeedItem
+	for i := range 6 {
+		items = append(items, FeedItem{ID: fmt.Sprintf("news%d", i), Source: SourceYouTube, Group: "news", PublishedAt: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	items = append(items,
+		FeedItem{ID: "tech1", Source: SourceYouTube, Group: "tech", PublishedAt: now.Add(-10 * time.Minute)},
+		FeedItem{ID: "tech2", Source: SourceYouTube, Group: "tech", PublishedAt: now.Add(-11 * time.Minute)},
+		FeedItem{ID: "post1", Source: SourceSubstack, PublishedAt: now.Add(-12 * time.Minute)},
+		FeedItem{ID: "post2", Source: SourceSubstack, PublishedAt: now.Add(-13 * time.Minute)},
+	)
+	agg := New()
+	agg.AddItems(items)
+
+	ids := func(feed []FeedItem) string {
+		var ids []string
+		for _, item := range feed {
+			ids = append(ids, item.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+	opts := FeedOptions{GroupLimits: map[string]int{"news": 2}, SourceLimits: map[Source]int{SourceSubstack: 1}}
+	if got := ids(agg.GetFeed(opts)); got != "news0,news1,tech1,tech2,post1" {
+		t.Errorf("the newest items of capped groups and sources should be kept, others uncapped, got %s", got)
+	}
+	opts.Limit = 4
+	if got := ids(agg.GetFeed(opts)); got != "news0,news1,tech1,tech2" {
+		t.Errorf("the overall limit should apply after the caps, got %s", got)
+	}
+
+	batches := make(chan []FeedItem, 2)
+	batches <- items[:4]
+	batches <- items[4:]
+	close(batches)
+	var streamed []FeedItem
+	for item := range New().Stream(batches, FeedOptions{GroupLimits: map[string]int{"news": 3}}) {
+		streamed = append(streamed, item)
+	}
+	if got := ids(streamed); got != "news0,news1,news2,tech1,tech2,post1,post2" {
+		t.Errorf("caps should count items across streamed batches, got %s", got)
+	}
+}
+
+func TestAC210_Feed_SortsByGrowth(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	trend := func(views ...int64) []Sample {
+		var samples []Sample
+		for i, v := range views {
+			samples = append(samples, Sample{At: now.Add(time.Duration(i-len(views)+1) * time.Hour), Views: v})
+		}
+		return samples
+	}
+	agg := New()
+	agg.AddItems([]FeedItem{
+		{ID: "new", PublishedAt: now},
+		{ID: "slow", PublishedAt: now.Add(-time.Hour), Trend: trend(100, 200, 300)},
+		{ID: "fast", PublishedAt: now.Add(-2 * time.Hour), Trend: trend(100, 5000)},
+		{ID: "post", Source: SourceSubstack, PublishedAt: now.Add(-3 * time.Hour), Trend: []Sample{{At: now.Add(-time.Hour), Likes: 10}, {At: now, Likes: 130}}},
+	})
+
+	if got := agg.GetFeed(FeedOptions{})[0].ID; got != "new" {
+		t.Errorf("the feed should be newest first by default, got %s first", got)
+	}
+	var ids []string
+	for _, item := range agg.GetFeed(FeedOptions{Sort: SortGrowth}) {
+		ids = append(ids, item.ID)
+	}
+	if got := strings.Join(ids, ","); got != "fast,post,slow,new" {
+		t.Errorf("items gaining views (or likes) fastest should come first, then the newest, got %s", got)
+	}
+	if got := (FeedItem{Trend: trend(100, 200, 400)}).Growth(); got != 150 {
+		t.Errorf("growth should be the gain per hour over the trend, got %v", got)
+	}
+}
+
+func TestAC211_Feed_HidesPaywalledPosts(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	agg := New()
+	agg.AddItems([]FeedItem{
+		{ID: "free", Source: SourceSubstack, PublishedAt: now},
+		{ID: "paid", Source: SourceSubstack, PublishedAt: now.Add(-time.Hour), Paywalled: true},
+	})
+
+	if got := len(agg.GetFeed(FeedOptions{})); got != 2 {
+		t.Errorf("paywalled posts should be shown by default, got %d items", got)
+	}
+	if got := agg.GetFeed(FeedOptions{HidePaywalled: true}); len(got) != 1 || got[0].ID != "free" {
+		t.Errorf("only the free post should be left, got %+v", got)
+	}
+}
+
+func TestAC212_Feed_ListsLiveStreamsFirst(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	agg := New()
+	agg.AddItems([]FeedItem{
+		{ID: "video", PublishedAt: now},
+		{ID: "live", Source: SourceTwitch, PublishedAt: now.Add(-2 * time.Hour), Broadcast: &Broadcast{Status: BroadcastLive}},
+		{ID: "upcoming", PublishedAt: now.Add(-time.Hour), Broadcast: &Broadcast{Status: BroadcastUpcoming}},
+	})
+
+	var ids []string
+	for _, item := range agg.GetFeed(FeedOptions{}) {
+		ids = append(ids, item.ID)
+	}
+	if got := strings.Join(ids, ","); got != "live,video,upcoming" {
+		t.Errorf("streams on air should come first, then the newest, got %s", got)
+	}
+}
@@ -0,0 +1,81 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the FeedItem JSON layout that feedmix
+// writes to disk and exports. Bump it, and add the migration from the
+// previous version to migrations, when a change to FeedItem would make
+// items written by older versions decode wrongly.
+const SchemaVersion = 1
+
+// migrations[v] upgrades one serialized item from schema v to v+1, in place.
+var migrations = []func(item map[string]json.RawMessage) error{
+	// Version 1 only wrapped items in an Envelope; the layout is unchanged.
+	0: func(map[string]json.RawMessage) error { return nil },
+}
+
+// Envelope is how lists of items are serialized: the items, tagged with the
+// schema version they were written with.
+type Envelope[T any] struct {
+	SchemaVersion int `json:"schema_version"`
+	Items         []T `json:"items"`
+}
+
+// NewEnvelope wraps items with the current schema version.
+func NewEnvelope[T any](items []T) Envelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Envelope[T]{SchemaVersion: SchemaVersion, Items: items}
+}
+
+// UnmarshalItems decodes items serialized as an Envelope of any schema
+// version, or as the bare JSON array written before versioning (version 0).
+// Items from older versions are migrated to the current layout; fields added
+// by newer versions are ignored. It returns the version data was written
+// with, so callers can avoid overwriting data from a newer feedmix.
+func UnmarshalItems[T any](data []byte) ([]T, int, error) {
+	var envelope Envelope[json.RawMessage]
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &envelope.Items); err != nil {
+			return nil, 0, err
+		}
+	} else if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]T, 0, len(envelope.Items))
+	for i, raw := range envelope.Items {
+		raw, err := migrate(raw, envelope.SchemaVersion)
+		if err != nil {
+			return nil, envelope.SchemaVersion, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, envelope.SchemaVersion, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		items = append(items, item)
+	}
+	return items, envelope.SchemaVersion, nil
+}
+
+// migrate upgrades a serialized item from schema version to the current one.
+func migrate(raw json.RawMessage, version int) (json.RawMessage, error) {
+	if version >= SchemaVersion {
+		return raw, nil
+	}
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	for v := max(version, 0); v < SchemaVersion; v++ {
+		if err := migrations[v](item); err != nil {
+			return nil, fmt.Errorf("failed to migrate from schema %d: %w", v, err)
+		}
+	}
+	return json.Marshal(item)
+}
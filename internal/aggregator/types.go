@@ -8,26 +8,130 @@ type Source string
 const SourceYouTube Source = "youtube"
 const SourceSubstack Source = "substack"
 
+// SourceReader items come from a Google Reader API server such as FreshRSS
+// or Miniflux, and SourceBridge items from RSS-Bridge feeds.
+const SourceReader Source = "reader"
+const SourceBridge Source = "bridge"
+
+// SourcePodcast items are episodes from podcast feeds.
+const SourcePodcast Source = "podcast"
+
+// SourceTwitch items are live streams and past broadcasts of followed
+// Twitch channels.
+const SourceTwitch Source = "twitch"
+
+// SourceGitHub items are releases of GitHub repositories.
+const SourceGitHub Source = "github"
+
+// SourceMedium items are stories of Medium writers and publications.
+const SourceMedium Source = "medium"
+
+// SourceLobsters items are stories from Lobsters and similar link
+// aggregators.
+const SourceLobsters Source = "lobsters"
+
+// SourceArxiv items are papers from arXiv.
+const SourceArxiv Source = "arxiv"
+
+// SourcePeerTube items are videos of channels on PeerTube instances.
+const SourcePeerTube Source = "peertube"
+
 type ItemType string
 
 const (
 	ItemTypeVideo   ItemType = "video"
 	ItemTypeLike    ItemType = "like"
 	ItemTypeArticle ItemType = "article"
+	// ItemTypeLive is a YouTube premiere or live stream that hasn't ended.
+	ItemTypeLive ItemType = "live"
+	// ItemTypePodcast is a post whose main content is an audio episode.
+	ItemTypePodcast ItemType = "podcast"
+	// ItemTypePost is a short-form post, such as a Substack Note.
+	ItemTypePost ItemType = "post"
+	// ItemTypeRelease is a new version of a project, such as a GitHub release.
+	ItemTypeRelease ItemType = "release"
+	// ItemTypeLink is a link shared on an aggregator such as Lobsters.
+	ItemTypeLink ItemType = "link"
+	// ItemTypePaper is a research paper, such as an arXiv preprint.
+	ItemTypePaper ItemType = "paper"
 )
 
 type FeedItem struct {
-	ID          string     `json:"id"`
-	Source      Source     `json:"source"`
-	Type        ItemType   `json:"type"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Author      string     `json:"author"`
-	AuthorID    string     `json:"author_id"`
-	URL         string     `json:"url"`
-	Thumbnail   string     `json:"thumbnail,omitempty"`
-	PublishedAt time.Time  `json:"published_at"`
-	Engagement  Engagement `json:"engagement"`
+	ID           string     `json:"id"`
+	Source       Source     `json:"source"`
+	Type         ItemType   `json:"type"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Author       string     `json:"author"`
+	AuthorID     string     `json:"author_id"`
+	AuthorHandle string     `json:"author_handle,omitempty"`
+	Group        string     `json:"group,omitempty"`
+	URL          string     `json:"url"`
+	Thumbnail    string     `json:"thumbnail,omitempty"`
+	PublishedAt  time.Time  `json:"published_at"`
+	UpdatedAt    time.Time  `json:"updated_at,omitempty"`
+	Engagement   Engagement `json:"engagement"`
+	// Trend is the item's engagement as fetched by recent runs, oldest
+	// first; empty until it has changed between runs.
+	Trend []Sample `json:"trend,omitempty"`
+	// Restriction says why the item may not play for the user, e.g.
+	// "age-restricted" or "not available in FR"; empty when it plays.
+	Restriction string `json:"restriction,omitempty"`
+	// Broadcast is set on YouTube premieres and live streams that haven't
+	// ended.
+	Broadcast *Broadcast `json:"broadcast,omitempty"`
+	// Chapters are the sections of a YouTube video, if its description
+	// lists them.
+	Chapters []Chapter `json:"chapters,omitempty"`
+	// Content is the full text of an article as HTML, when its feed
+	// carries it; Description is only an excerpt.
+	Content string `json:"content,omitempty"`
+	// Paywalled is set on posts only paid subscribers can read in full.
+	Paywalled bool `json:"paywalled,omitempty"`
+	// Audio is the episode of a podcast item.
+	Audio *Audio `json:"audio,omitempty"`
+	// Gone says why the item is no longer there, such as "removed" or
+	// "private", once 'feedmix saved check' found so.
+	Gone string `json:"gone,omitempty"`
+}
+
+// Audio is a podcast episode's audio file.
+type Audio struct {
+	URL string `json:"url"`
+	// Type is the MIME type, e.g. audio/mpeg.
+	Type string `json:"type,omitempty"`
+	// Length is the size of the file in bytes; 0 when unknown.
+	Length int64 `json:"length,omitempty"`
+	// Duration is 0 when unknown.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Season and Episode number the episode; 0 when the feed doesn't.
+	Season  int `json:"season,omitempty"`
+	Episode int `json:"episode,omitempty"`
+}
+
+// Chapter is a section of a video, starting Start into it.
+type Chapter struct {
+	Start time.Duration `json:"start"`
+	Title string        `json:"title"`
+}
+
+// Broadcast states.
+const (
+	BroadcastUpcoming = "upcoming"
+	BroadcastLive     = "live"
+)
+
+// Broadcast is when a premiere or live stream airs.
+type Broadcast struct {
+	// Status is BroadcastUpcoming or BroadcastLive.
+	Status string `json:"status"`
+	// Start is the scheduled start, or the actual one once on air; zero
+	// when unknown.
+	Start time.Time `json:"start"`
+	// End is the scheduled end, often unknown.
+	End time.Time `json:"end,omitempty"`
+	// Premiere is set when an uploaded video airs, rather than a live stream.
+	Premiere bool `json:"premiere,omitempty"`
 }
 
 type Engagement struct {
@@ -36,10 +140,90 @@ type Engagement struct {
 	Views    int64 `json:"views,omitempty"`
 }
 
+// Sample is an item's engagement when a run fetched it.
+type Sample struct {
+	At    time.Time `json:"at"`
+	Views int64     `json:"views,omitempty"`
+	Likes int64     `json:"likes"`
+}
+
+// Count is what growth is measured in: views, or likes for items without
+// view counts such as Substack posts.
+func (s Sample) Count() int64 {
+	if s.Views > 0 {
+		return s.Views
+	}
+	return s.Likes
+}
+
+// Growth is how many views (likes, for items without view counts) the item
+// gained per hour over its Trend, or 0 with fewer than two samples.
+func (i FeedItem) Growth() float64 {
+	if len(i.Trend) < 2 {
+		return 0
+	}
+	first, last := i.Trend[0], i.Trend[len(i.Trend)-1]
+	hours := last.At.Sub(first.At).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return float64(last.Count()-first.Count()) / hours
+}
+
+// LiveNow reports whether the item is a stream on air.
+func (i FeedItem) LiveNow() bool {
+	return i.Broadcast != nil && i.Broadcast.Status == BroadcastLive
+}
+
 type FeedOptions struct {
 	Limit   int
 	Since   time.Time
 	Until   time.Time
 	Sources []Source
 	Types   []ItemType
+	Groups  []string
+	GroupBy GroupBy
+	Sort    Sort
+	// SourceLimits and GroupLimits cap how many items of a source or group
+	// the feed shows, newest first, so a noisy one doesn't crowd out the
+	// rest. Sources and groups not listed are only held to Limit.
+	SourceLimits map[Source]int
+	GroupLimits  map[string]int
+	// HidePaywalled drops posts only paid subscribers can read in full.
+	HidePaywalled bool
+}
+
+// Sort orders a feed.
+type Sort string
+
+const (
+	// SortNewest puts the most recently published items first.
+	SortNewest Sort = ""
+	// SortGrowth puts the items gaining engagement fastest first.
+	SortGrowth Sort = "growth"
+)
+
+// GroupBy organizes a feed into sections, such as one per channel.
+type GroupBy string
+
+const (
+	GroupByNone   GroupBy = ""
+	GroupBySource GroupBy = "source"
+	GroupByAuthor GroupBy = "author"
+)
+
+// Section returns the key of the section item belongs to; items with the
+// same key are shown together.
+func (g GroupBy) Section(item FeedItem) string {
+	switch g {
+	case GroupBySource:
+		return string(item.Source)
+	case GroupByAuthor:
+		if item.AuthorID != "" {
+			return string(item.Source) + ":" + item.AuthorID
+		}
+		return string(item.Source) + ":" + item.Author
+	default:
+		return ""
+	}
 }
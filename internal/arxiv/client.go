@@ -0,0 +1,223 @@
+// Package arxiv provides a client for the arXiv API, which answers searches
+// of papers by category, author or keyword with an Atom feed.
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
+)
+
+// DefaultBaseURL is the arXiv API's query endpoint.
+const DefaultBaseURL = "https://export.arxiv.org/api/query"
+
+// Paper is an arXiv paper, in its latest version.
+type Paper struct {
+	// ID is the paper's arXiv identifier without its version, e.g.
+	// 2401.01234 or hep-th/9901001.
+	ID       string
+	Version  int
+	Title    string
+	Abstract string
+	Authors  []string
+	// URL is the paper's abstract page; PDFURL its latest PDF.
+	URL    string
+	PDFURL string
+	// PrimaryCategory is the category the paper was submitted to, e.g.
+	// cs.LG; Categories also lists those it was cross-listed in.
+	PrimaryCategory string
+	Categories      []string
+	PublishedAt     time.Time
+	UpdatedAt       time.Time
+}
+
+// Query is a search of papers: those in any of Categories, by any of
+// Authors, or mentioning any of Keywords.
+type Query struct {
+	Categories []string
+	Authors    []string
+	Keywords   []string
+}
+
+// String returns the query in the API's search_query syntax.
+func (q Query) String() string {
+	var terms []string
+	for _, category := range q.Categories {
+		terms = append(terms, "cat:"+category)
+	}
+	for _, author := range q.Authors {
+		terms = append(terms, "au:"+quote(author))
+	}
+	for _, keyword := range q.Keywords {
+		terms = append(terms, "all:"+quote(keyword))
+	}
+	return strings.Join(terms, " OR ")
+}
+
+// quote wraps phrases in the double quotes the API expects.
+func quote(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the API endpoint (useful for testing).
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// Client searches arXiv.
+type Client struct {
+	httpClient HTTPClient
+	baseURL    string
+}
+
+// NewClient creates a new arXiv client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Search returns the papers matching query, most recently submitted first,
+// up to limit. arXiv asks clients to send at most one request every three
+// seconds, so a query covers everything the user follows at once.
+func (c *Client) Search(ctx context.Context, query Query, limit int) ([]Paper, error) {
+	params := url.Values{
+		"search_query": {query.String()},
+		"sortBy":       {"submittedDate"},
+		"sortOrder":    {"descending"},
+		"max_results":  {strconv.Itoa(max(limit, 1))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("arXiv API returned HTTP %d", resp.StatusCode)
+	}
+	body, err := rss.ReadFeed(resp, c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(body)
+}
+
+func parseFeed(data []byte) ([]Paper, error) {
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse arXiv response: %w", err)
+	}
+	papers := make([]Paper, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		if entry.Title == "Error" && strings.Contains(entry.ID, "/api/errors") {
+			return nil, fmt.Errorf("arXiv rejected the query: %s", clean(entry.Summary))
+		}
+		papers = append(papers, entry.paper())
+	}
+	return papers, nil
+}
+
+func (e atomEntry) paper() Paper {
+	_, absID, found := strings.Cut(strings.TrimSpace(e.ID), "/abs/")
+	if !found {
+		absID = strings.TrimSpace(e.ID)
+	}
+	id, version := splitVersion(absID)
+	paper := Paper{
+		ID:              id,
+		Version:         version,
+		Title:           clean(e.Title),
+		Abstract:        clean(e.Summary),
+		URL:             "https://arxiv.org/abs/" + id,
+		PrimaryCategory: e.PrimaryCategory.Term,
+	}
+	paper.PublishedAt, _ = time.Parse(time.RFC3339, strings.TrimSpace(e.Published))
+	paper.UpdatedAt, _ = time.Parse(time.RFC3339, strings.TrimSpace(e.Updated))
+	for _, author := range e.Authors {
+		paper.Authors = append(paper.Authors, clean(author.Name))
+	}
+	for _, link := range e.Links {
+		if link.Title == "pdf" {
+			paper.PDFURL = link.Href
+		}
+	}
+	for _, category := range e.Categories {
+		paper.Categories = append(paper.Categories, category.Term)
+	}
+	return paper
+}
+
+// splitVersion splits "2401.01234v2" into its ID and version, 1 when none
+// is given.
+func splitVersion(s string) (string, int) {
+	if i := strings.LastIndexByte(s, 'v'); i > 0 {
+		if version, err := strconv.Atoi(s[i+1:]); err == nil {
+			return s[:i], version
+		}
+	}
+	return s, 1
+}
+
+// clean joins the lines the API wraps titles and abstracts on.
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// atomFeed and atomEntry are private XML parsing structs.
+type atomFeed struct {
+	Entries []atomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type atomEntry struct {
+	ID        string `xml:"http://www.w3.org/2005/Atom id"`
+	Title     string `xml:"http://www.w3.org/2005/Atom title"`
+	Summary   string `xml:"http://www.w3.org/2005/Atom summary"`
+	Published string `xml:"http://www.w3.org/2005/Atom published"`
+	Updated   string `xml:"http://www.w3.org/2005/Atom updated"`
+	Authors   []struct {
+		Name string `xml:"http://www.w3.org/2005/Atom name"`
+	} `xml:"http://www.w3.org/2005/Atom author"`
+	Links []struct {
+		Href  string `xml:"href,attr"`
+		Title string `xml:"title,attr"`
+	} `xml:"http://www.w3.org/2005/Atom link"`
+	PrimaryCategory struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://arxiv.org/schemas/atom primary_category"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://www.w3.org/2005/Atom category"`
+}
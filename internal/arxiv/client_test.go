@@ -0,0 +1,77 @@
+package arxiv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const arxivFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2401.01234v2</id>
+    <updated>2024-01-20T18:00:00Z</updated>
+    <published>2024-01-15T18:00:00Z</published>
+    <title>Attention Is Still
+      All You Need</title>
+    <summary>  We revisit
+  transformers.
+</summary>
+    <author><name>Jane Doe</name></author>
+    <author><name>John Roe</name></author>
+    <link href="http://arxiv.org/abs/2401.01234v2" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/2401.01234v2" rel="related" type="application/pdf"/>
+    <arxiv:primary_category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+// TestClient_Search documents searching arXiv:
+//   - categories, authors and keywords make one query, newest first
+//   - titles and abstracts are unwrapped; the version is split off the ID
+func TestClient_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("search_query") != `cat:cs.LG OR au:"Jane Doe" OR all:transformers` || q.Get("sortBy") != "submittedDate" || q.Get("max_results") != "10" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, arxivFeed)
+	}))
+	defer server.Close()
+
+	query := Query{Categories: []string{"cs.LG"}, Authors: []string{"Jane Doe"}, Keywords: []string{"transformers"}}
+	papers, err := NewClient(WithBaseURL(server.URL)).Search(context.Background(), query, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(papers) != 1 {
+		t.Fatalf("expected 1 paper, got %+v", papers)
+	}
+	paper := papers[0]
+	if paper.ID != "2401.01234" || paper.Version != 2 || paper.URL != "https://arxiv.org/abs/2401.01234" {
+		t.Errorf("expected the paper's ID, version and abstract page, got %+v", paper)
+	}
+	if paper.Title != "Attention Is Still All You Need" || paper.Abstract != "We revisit transformers." {
+		t.Errorf("expected the title and abstract on one line, got %q and %q", paper.Title, paper.Abstract)
+	}
+	if len(paper.Authors) != 2 || paper.PrimaryCategory != "cs.LG" || len(paper.Categories) != 2 || paper.PDFURL == "" {
+		t.Errorf("expected the authors, categories and PDF, got %+v", paper)
+	}
+}
+
+func TestClient_SearchReportsRejectedQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>http://arxiv.org/api/errors#incorrect_id_format</id>`+
+			`<title>Error</title><summary>incorrect id format</summary></entry></feed>`)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(WithBaseURL(server.URL)).Search(context.Background(), Query{Categories: []string{"cs.XX"}}, 5)
+	if err == nil || err.Error() != "arXiv rejected the query: incorrect id format" {
+		t.Errorf("expected arXiv's error, got %v", err)
+	}
+}
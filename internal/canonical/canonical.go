@@ -0,0 +1,212 @@
+// Package canonical rewrites item URLs to one canonical form, so the same
+// page reached through a redirector or with tracking parameters is stored and
+// de-duplicated as a single item.
+package canonical
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// redirectors are hosts that only forward to the real page. Other URLs are
+// normalized locally without a network request.
+var redirectors = map[string]bool{
+	"feedproxy.google.com": true,
+	"feeds.feedburner.com": true,
+	"t.co":                 true,
+	"bit.ly":               true,
+	"buff.ly":              true,
+	"ow.ly":                true,
+	"lnkd.in":              true,
+}
+
+// trackingParams are query parameters that identify a campaign or a click,
+// never the page itself. Parameters starting with "utm_" are dropped too.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"yclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"igshid":  true,
+	"ref_src": true,
+	"si":      true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+}
+
+// Normalize lowercases the scheme and host, drops default ports and removes
+// tracking parameters. Remaining query parameters are sorted so equivalent
+// URLs compare equal. Values that don't parse as absolute URLs are returned
+// unchanged.
+func Normalize(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[key] || strings.HasPrefix(key, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+	return u.String()
+}
+
+// Resolver canonicalizes URLs, following redirector links once and
+// remembering where they led in a file so later runs skip the request.
+type Resolver struct {
+	client      *http.Client
+	path        string
+	redirectors map[string]bool
+
+	mu       sync.Mutex
+	resolved map[string]string
+	dirty    bool
+}
+
+// Open loads the resolver cache at path; a missing file yields an empty cache.
+// client follows redirects; nil uses http.DefaultClient.
+func Open(client *http.Client, path string) (*Resolver, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r := &Resolver{client: client, path: path, redirectors: redirectors, resolved: make(map[string]string)}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the URL cache in the user's cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read URL cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.resolved); err != nil {
+		return nil, fmt.Errorf("failed to parse URL cache: %w", err)
+	}
+	return r, nil
+}
+
+// Canonicalize returns the canonical form of raw. A redirector link that
+// can't be followed is normalized as is and reported as an error.
+func (r *Resolver) Canonicalize(ctx context.Context, raw string) (string, error) {
+	if !r.isRedirector(raw) {
+		return Normalize(raw), nil
+	}
+
+	r.mu.Lock()
+	target, ok := r.resolved[raw]
+	r.mu.Unlock()
+	if ok {
+		return target, nil
+	}
+
+	target, err := r.follow(ctx, raw)
+	if err != nil {
+		return Normalize(raw), fmt.Errorf("failed to resolve %s: %w", raw, err)
+	}
+	target = Normalize(target)
+
+	r.mu.Lock()
+	r.resolved[raw] = target
+	r.dirty = true
+	r.mu.Unlock()
+	return target, nil
+}
+
+// Items canonicalizes the URL of every item, reporting unresolvable links to warn.
+func (r *Resolver) Items(ctx context.Context, items []aggregator.FeedItem, warn func(error)) []aggregator.FeedItem {
+	out := make([]aggregator.FeedItem, len(items))
+	for i, item := range items {
+		if item.URL != "" {
+			var err error
+			if item.URL, err = r.Canonicalize(ctx, item.URL); err != nil {
+				warn(err)
+			}
+		}
+		out[i] = item
+	}
+	return out
+}
+
+// Save writes newly resolved redirects to the cache file.
+func (r *Resolver) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return fmt.Errorf("failed to create URL cache directory: %w", err)
+	}
+	data, err := json.Marshal(r.resolved)
+	if err != nil {
+		return fmt.Errorf("failed to encode URL cache: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write URL cache: %w", err)
+	}
+	r.dirty = false
+	return nil
+}
+
+func (r *Resolver) isRedirector(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return r.redirectors[strings.ToLower(u.Hostname())]
+}
+
+// follow issues a HEAD request and returns the URL the redirects ended at.
+func (r *Resolver) follow(ctx context.Context, raw string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, raw, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return resp.Request.URL.String(), nil
+}
+
+// Dedupe keeps the first item for each URL. Items without a URL are kept.
+func Dedupe(items []aggregator.FeedItem) []aggregator.FeedItem {
+	seen := make(map[string]bool, len(items))
+	out := make([]aggregator.FeedItem, 0, len(items))
+	for _, item := range items {
+		if item.URL != "" {
+			if seen[item.URL] {
+				continue
+			}
+			seen[item.URL] = true
+		}
+		out = append(out, item)
+	}
+	return out
+}
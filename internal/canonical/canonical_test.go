@@ -0,0 +1,94 @@
+package canonical
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"HTTPS://Example.COM/Post", "https://example.com/Post"},
+		{"https://example.com:443/p", "https://example.com/p"},
+		{"http://example.com:8080/p", "http://example.com:8080/p"},
+		{"https://example.com/p?utm_source=rss&utm_medium=feed", "https://example.com/p"},
+		{"https://example.com/p?b=2&fbclid=x&a=1", "https://example.com/p?a=1&b=2"},
+		{"https://www.youtube.com/watch?v=abc&si=share", "https://www.youtube.com/watch?v=abc"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.raw); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestResolver_FollowsRedirectorsOnceAcrossRuns(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feedproxy/abc" {
+			requests++
+			http.Redirect(w, r, server.URL+"/post?utm_campaign=feed", http.StatusMovedPermanently)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "urls.json")
+	link := server.URL + "/feedproxy/abc"
+	for run := 0; run < 2; run++ {
+		r, err := Open(server.Client(), path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r.redirectors = map[string]bool{"127.0.0.1": true}
+
+		got, err := r.Canonicalize(context.Background(), link)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != server.URL+"/post" {
+			t.Errorf("redirector link should resolve to the clean target, got %q", got)
+		}
+		if err := r.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("a redirector link should be resolved once and then cached, got %d requests", requests)
+	}
+}
+
+func TestResolver_UnresolvableLinkIsReportedAndKept(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	r, _ := Open(server.Client(), filepath.Join(t.TempDir(), "urls.json"))
+	r.redirectors = map[string]bool{"127.0.0.1": true}
+
+	var warnings []error
+	items := r.Items(context.Background(), []aggregator.FeedItem{{ID: "1", URL: server.URL + "/gone"}}, func(err error) { warnings = append(warnings, err) })
+	if items[0].URL != server.URL+"/gone" || len(warnings) != 1 {
+		t.Errorf("a dead redirector link should be kept and warned about, got %q with %d warnings", items[0].URL, len(warnings))
+	}
+}
+
+func TestDedupe_KeepsFirstItemPerURL(t *testing.T) {
+	items := Dedupe([]aggregator.FeedItem{
+		{ID: "a", URL: "https://example.com/p"},
+		{ID: "b", URL: "https://example.com/p"},
+		{ID: "c"},
+		{ID: "d"},
+	})
+	if len(items) != 3 || items[0].ID != "a" {
+		t.Errorf("items sharing a URL should collapse to the first one, got %+v", items)
+	}
+}
@@ -0,0 +1,1465 @@
+// Package config resolves feedmix settings from the environment.
+package config
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultFetchLimit is the number of recent items requested per channel or publication.
+const DefaultFetchLimit = 5
+
+// DefaultReaderFetchLimit is the number of recent items requested from a
+// reader API's reading list, which merges all of its feeds.
+const DefaultReaderFetchLimit = 50
+
+// MaxReaderFetchLimit is the most items a reader API returns at once.
+const MaxReaderFetchLimit = 1000
+
+// DefaultYouTubeRateLimit is the default ceiling on YouTube API requests per second.
+const DefaultYouTubeRateLimit = 10
+
+// DefaultYouTubeQuotaBudget matches the daily quota of a new Google Cloud project.
+const DefaultYouTubeQuotaBudget = 10000
+
+// DefaultConcurrency is how many channels or publications are fetched at once.
+const DefaultConcurrency = 8
+
+// MaxConcurrency keeps a typo from opening hundreds of connections.
+const MaxConcurrency = 64
+
+// DefaultPager shows long output when neither FEEDMIX_PAGER nor PAGER is set.
+const DefaultPager = "less -R"
+
+// FinderBuiltin selects the fuzzy finder built into 'feedmix pick' over fzf.
+const FinderBuiltin = "builtin"
+
+// DefaultDescriptionLength is how many characters of a description are shown
+// when descriptions are enabled.
+const DefaultDescriptionLength = 200
+
+// DefaultCacheTTL is how long API responses are reused between runs.
+const DefaultCacheTTL = 5 * time.Minute
+
+// Default retention of run manifests: the most recent runs of the last month.
+const (
+	DefaultRunsKeep   = 200
+	DefaultRunsMaxAge = 30 * 24 * time.Hour
+)
+
+// MaxYouTubeFetchLimit is the largest page size accepted by the YouTube search endpoint.
+const MaxYouTubeFetchLimit = 50
+
+// MaxTwitchFetchLimit is the largest page size accepted by the Twitch videos endpoint.
+const MaxTwitchFetchLimit = 100
+
+// MaxGitHubFetchLimit is the largest page size accepted by the GitHub releases endpoint.
+const MaxGitHubFetchLimit = 100
+
+// MaxLobstersFetchLimit is the number of stories on a page of a Lobsters site.
+const MaxLobstersFetchLimit = 25
+
+// DefaultArxivFetchLimit is the number of recent papers requested from
+// arXiv, across everything followed there.
+const DefaultArxivFetchLimit = 25
+
+// MaxArxivFetchLimit keeps arXiv searches to a size its API serves quickly.
+const MaxArxivFetchLimit = 200
+
+// MaxPeerTubeFetchLimit is the largest page size accepted by the PeerTube videos endpoint.
+const MaxPeerTubeFetchLimit = 100
+
+// Config holds the fully resolved feedmix configuration.
+type Config struct {
+	Dir      string
+	CacheDir string
+	YouTube  YouTube
+	Substack Substack
+	Reader   Reader
+	Bridge   Bridge
+	Podcast  Podcast
+	Twitch   Twitch
+	GitHub   GitHub `dump:"github"`
+	Medium   Medium
+	Lobsters Lobsters
+	Arxiv    Arxiv
+	PeerTube PeerTube `dump:"peertube"`
+	Limits   FetchLimits
+	Caps     FeedCaps
+	Cache    CacheTTL
+	Runs     RunRetention
+	Slack    Slack
+	Discord  Discord
+	Push     Push
+	Quiet    Quiet
+	Miniflux Miniflux
+	// Concurrency caps simultaneous channel or publication fetches per source.
+	Concurrency int
+	// EventLog is the JSON Lines file receiving item lifecycle events; empty disables it.
+	EventLog string
+	// Pager is the command output longer than the terminal is shown
+	// through; empty writes it directly.
+	Pager string
+	// Finder is the fzf-compatible command 'feedmix pick' runs, FinderBuiltin,
+	// or empty to use fzf when it is installed.
+	Finder string
+	// ResurfaceUpdated moves edited items back to the top of the feed.
+	ResurfaceUpdated bool
+	// HidePaywalled leaves out posts only paid subscribers can read in full.
+	HidePaywalled bool
+	// ArchiveSaved has the Wayback Machine archive the page of each item
+	// saved.
+	ArchiveSaved bool
+	// TokenStore selects where OAuth tokens are kept: TokenStoreFile or TokenStoreKeyring.
+	TokenStore string
+	// Locale controls how numbers such as view counts are written.
+	Locale  language.Tag
+	Display Display
+	// Strict makes feedmix refuse to run with FEEDMIX_ variables it doesn't
+	// read; see CheckEnvironment.
+	Strict bool
+
+	// read records the variables Load read.
+	read map[string]bool
+}
+
+// Display controls the layout of each item in the terminal feed.
+type Display struct {
+	// Description shows up to DescriptionLength characters of each description.
+	Description       bool
+	DescriptionLength int
+	// TitleLength truncates titles; 0 shows them in full.
+	TitleLength int
+	// Width is the number of columns titles and descriptions are wrapped
+	// to; 0 uses the terminal's width.
+	Width      int
+	Engagement bool
+	Thumbnails bool
+	// Details lists the chapters of videos under them.
+	Details bool
+	// InlineThumbnails draws thumbnails in the terminal with an image
+	// protocol: InlineThumbnailsAuto, one of the ImageProtocol* values, or
+	// empty to leave them out.
+	InlineThumbnails string
+	// CalmTitles tones down clickbait titles.
+	CalmTitles bool
+	// Hyperlinks makes titles clickable: HyperlinksAuto, HyperlinksAlways or HyperlinksNever.
+	Hyperlinks string
+	// Color enables colors on terminals, styled with the named Theme
+	// (empty for the default theme).
+	Color bool
+	Theme string
+	// Compact shows each item on a single line.
+	Compact bool
+	// DayHeaders inserts a header before the first item of each day.
+	DayHeaders bool
+	// GroupBy organizes the feed into per-source or per-author sections:
+	// GroupBySource, GroupByAuthor, or empty for a chronological feed.
+	GroupBy string
+}
+
+// Sections accepted by FEEDMIX_GROUP_BY.
+const (
+	GroupBySource = "source"
+	GroupByAuthor = "author"
+)
+
+// Values accepted by FEEDMIX_INLINE_THUMBNAILS besides "off". Auto picks
+// the protocol the terminal is known to support.
+const (
+	InlineThumbnailsAuto = "auto"
+	ImageProtocolKitty   = "kitty"
+	ImageProtocolITerm   = "iterm"
+	ImageProtocolSixel   = "sixel"
+)
+
+// Hyperlink modes accepted by FEEDMIX_HYPERLINKS. Auto enables them on
+// terminals known to support OSC 8 hyperlinks.
+const (
+	HyperlinksAuto   = "auto"
+	HyperlinksAlways = "always"
+	HyperlinksNever  = "never"
+)
+
+// Token store backends accepted by FEEDMIX_TOKEN_STORE.
+const (
+	TokenStoreFile    = "file"
+	TokenStoreKeyring = "keyring"
+)
+
+// YouTube holds YouTube Data API credentials and endpoints.
+type YouTube struct {
+	ClientID     string
+	ClientSecret string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	RefreshToken string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	TokenURL     string
+	DeviceURL    string
+	APIURL       string `dump:"api_url"`
+	// APIKeys are Data API keys from other Google Cloud projects, used in
+	// turn for public data as each one's daily quota runs out.
+	APIKeys []string `dump:"api_keys,secret"`
+	// Accounts names the YouTube accounts whose subscriptions are merged; empty
+	// means a single unnamed account.
+	Accounts []string
+	// Channels lists channel IDs to follow through their public RSS feeds
+	// instead of the Data API, without credentials or quota.
+	Channels []string
+	// Groups assigns channels (by channel ID) to a group, overriding the
+	// group derived from the channel's topics.
+	Groups map[string]string
+	// Region is the ISO 3166-1 alpha-2 code of the user's country, used to
+	// flag videos that don't play there; empty when unknown.
+	Region string
+	// RateLimit caps API requests per second across all channels; 0 disables the limiter.
+	RateLimit float64
+	// QuotaBudget is the daily number of API quota units feedmix may spend.
+	QuotaBudget int
+}
+
+// Substack holds the configured Substack publications.
+// Authors restricts multi-author publications and Headers adds request
+// headers (e.g. cookies for paid posts), both keyed by publication URL;
+// Cookie is the session of a Substack account, sent to every publication
+// on substack.com.
+type Substack struct {
+	URLs    []string `dump:"urls"`
+	Authors map[string][]string
+	Headers map[string]http.Header `dump:",secret"`
+	// Notes are the handles of writers whose Substack Notes join the feed.
+	Notes []string `dump:"notes"`
+	// Cookie is a Cookie header value, such as substack.sid=..., or empty.
+	Cookie string `dump:",secret"`
+	// Sections restricts publications to some of their sections, by slug
+	// ("podcast" for /s/podcast), keyed by publication URL.
+	Sections map[string][]string
+}
+
+// SectionsFor returns the sections of a publication to fetch, or nil for
+// the whole publication.
+func (s Substack) SectionsFor(publicationURL string) []string {
+	return s.Sections[strings.TrimRight(publicationURL, "/")]
+}
+
+// AuthorsFor returns the authors to keep for a publication, or nil for all authors.
+func (s Substack) AuthorsFor(publicationURL string) []string {
+	return s.Authors[strings.TrimRight(publicationURL, "/")]
+}
+
+// HeadersFor returns the extra request headers for a publication, or nil.
+// Publications on substack.com get the session cookie unless their own
+// headers set one; those on custom domains, which keep a session of their
+// own, never do.
+func (s Substack) HeadersFor(publicationURL string) http.Header {
+	headers := s.Headers[strings.TrimRight(publicationURL, "/")]
+	if s.Cookie == "" || headers.Get("Cookie") != "" || !onSubstack(publicationURL) {
+		return headers
+	}
+	headers = headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set("Cookie", s.Cookie)
+	return headers
+}
+
+// onSubstack reports whether rawURL is served from substack.com or one of
+// its subdomains.
+func onSubstack(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "substack.com" || strings.HasSuffix(host, ".substack.com")
+}
+
+// Reader is a Google Reader API server, such as FreshRSS or Miniflux, whose
+// reading list joins the feed; it is off while URL is empty.
+type Reader struct {
+	URL      string `dump:"url"`
+	User     string
+	Password string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+}
+
+// Bridge holds the RSS-Bridge feeds to fetch.
+type Bridge struct {
+	URLs []string `dump:"urls"`
+}
+
+// Twitch holds the Twitch application whose user's followed channels join
+// the feed; it is off while ClientID is empty. ClientSecret is only needed
+// by confidential applications.
+type Twitch struct {
+	ClientID     string
+	ClientSecret string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	RefreshToken string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+}
+
+// GitHub holds the repositories whose releases join the feed: Repos, as
+// "owner/name", and those Starred by a user when set. Token is an optional
+// personal access token, for the API's higher rate limit.
+type GitHub struct {
+	Repos   []string `dump:"repos"`
+	Starred string
+	Token   string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+}
+
+// Medium holds the Medium writers and publications to fetch, by page URL.
+type Medium struct {
+	URLs []string `dump:"urls"`
+}
+
+// Lobsters holds the link aggregators running Lobsters to fetch, by site
+// URL, and the tags to read on each.
+type Lobsters struct {
+	URLs []string `dump:"urls"`
+	// Tags restricts sites to stories with some tags, or leaves out those
+	// given as "-tag"; sites not listed show their front page.
+	Tags map[string][]string
+}
+
+// TagsFor returns the tags to read on a site, or nil for its front page.
+func (l Lobsters) TagsFor(siteURL string) []string {
+	return l.Tags[strings.TrimRight(siteURL, "/")]
+}
+
+// Arxiv holds what the user follows on arXiv: papers in any of Categories
+// (e.g. cs.LG), by any of Authors, or mentioning any of Keywords.
+type Arxiv struct {
+	Categories []string `dump:"categories"`
+	Authors    []string `dump:"authors"`
+	Keywords   []string `dump:"keywords"`
+}
+
+// Enabled reports whether the user follows anything on arXiv.
+func (a Arxiv) Enabled() bool {
+	return len(a.Categories)+len(a.Authors)+len(a.Keywords) > 0
+}
+
+// PeerTube holds the PeerTube channels to fetch, as name@host.
+type PeerTube struct {
+	Channels []string `dump:"channels"`
+}
+
+// Slack holds the incoming webhook that new items are posted to, and the
+// channel each source's items go to.
+type Slack struct {
+	WebhookURL string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	// Channels maps sources, such as "youtube", to a channel such as
+	// "#videos"; other sources post to the webhook's own channel.
+	Channels map[string]string
+	// Template is what a message shows of each item; see Discord.Template.
+	Template string `dump:"template"`
+	// Batch is how long new items collect before they are posted as one
+	// digest; see Discord.Batch.
+	Batch time.Duration `dump:"batch"`
+}
+
+// ChannelFor returns the channel a source's items are posted to, or "" for
+// the webhook's own channel.
+func (s Slack) ChannelFor(source string) string {
+	return s.Channels[source]
+}
+
+// Discord holds the webhook that new items are posted to.
+type Discord struct {
+	WebhookURL string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	// Template is what a notification shows of each item: "summary" (the
+	// default), "thumbnail", "title", or a Go template for the text under
+	// the item's title, as for feed --template.
+	Template string `dump:"template"`
+	// Batch is how long new items collect, from the first, before they are
+	// sent together; zero sends each run's items at once.
+	Batch time.Duration `dump:"batch"`
+}
+
+// Push holds where phone notifications of new items go, through an ntfy
+// topic or Pushover, and which items deserve one.
+type Push struct {
+	// NtfyTopic is a topic of ntfy.sh, or the URL of a topic on any server.
+	// Anyone who knows a public topic can read it, so it is kept secret.
+	NtfyTopic     string `dump:",secret"`
+	NtfyToken     string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	PushoverToken string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+	PushoverUser  string `dump:",secret"`
+	// Sources restricts notifications to items of some sources; empty
+	// allows all.
+	Sources []string `dump:"sources"`
+	// Keywords restricts notifications to items whose title or author
+	// mentions one of them, case-insensitively; empty allows all.
+	Keywords []string `dump:"keywords"`
+	// NtfyTemplate and PushoverTemplate are what a notification shows of
+	// each item; see Discord.Template.
+	NtfyTemplate     string `dump:"ntfy_template"`
+	PushoverTemplate string `dump:"pushover_template"`
+	// NtfyBatch and PushoverBatch are how long new items collect before
+	// they are pushed as one digest; see Discord.Batch.
+	NtfyBatch     time.Duration `dump:"ntfy_batch"`
+	PushoverBatch time.Duration `dump:"pushover_batch"`
+}
+
+// Enabled reports whether a push service is set up.
+func (p Push) Enabled() bool {
+	return p.NtfyTopic != "" || p.PushoverToken != ""
+}
+
+// Quiet holds the quiet hours during which notifications wait, to be sent
+// as digests once they end.
+type Quiet struct {
+	// Start and End are times of day, as time since midnight; hours from
+	// 22:00 to 08:00 span midnight. Equal ones set no hours.
+	Start time.Duration `dump:"start"`
+	End   time.Duration `dump:"end"`
+	// Weekends makes Saturdays and Sundays quiet all day.
+	Weekends bool `dump:"weekends"`
+	// Destinations are the notification services, such as "ntfy", quiet
+	// hours apply to; empty applies them to all.
+	Destinations []string `dump:"destinations"`
+}
+
+// AppliesTo reports whether quiet hours are set and hold back the
+// notifications of destination.
+func (q Quiet) AppliesTo(destination string) bool {
+	if q.Start == q.End && !q.Weekends {
+		return false
+	}
+	return len(q.Destinations) == 0 || slices.Contains(q.Destinations, destination)
+}
+
+// Contains reports whether t, read in its own time zone, falls within the
+// quiet hours.
+func (q Quiet) Contains(t time.Time) bool {
+	if q.Weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true
+	}
+	hour, minute, _ := t.Clock()
+	at := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	if q.Start <= q.End {
+		return q.Start <= at && at < q.End
+	}
+	return at >= q.Start || at < q.End
+}
+
+// Miniflux holds the Miniflux instance 'feedmix export miniflux' subscribes
+// to the feeds feedmix follows, and its API key.
+type Miniflux struct {
+	URL   string `dump:"url"`
+	Token string `dump:",secret"` // #nosec G117 - holds a user-supplied value, not an embedded secret
+}
+
+// Podcast holds the podcast feeds to fetch.
+type Podcast struct {
+	URLs []string `dump:"urls"`
+}
+
+// CacheTTL controls how long each source's API responses are reused; 0 disables caching.
+type CacheTTL struct {
+	YouTube  time.Duration
+	Substack time.Duration
+	Podcast  time.Duration
+	GitHub   time.Duration `dump:"github"`
+	Medium   time.Duration
+	Lobsters time.Duration
+	Arxiv    time.Duration
+	PeerTube time.Duration `dump:"peertube"`
+}
+
+// RunRetention controls which run manifests are kept: at most Keep runs,
+// none older than MaxAge. A zero field doesn't limit.
+type RunRetention struct {
+	Keep   int
+	MaxAge time.Duration
+}
+
+// FetchLimits controls how many recent items are requested from each source.
+// Overrides are keyed by YouTube channel ID, Substack publication URL,
+// bridge feed URL, podcast feed URL, GitHub repository, Medium page URL,
+// Lobsters site URL or PeerTube channel (name@host).
+type FetchLimits struct {
+	YouTube   int
+	Substack  int
+	Reader    int
+	Bridge    int
+	Podcast   int
+	Twitch    int
+	GitHub    int `dump:"github"`
+	Medium    int
+	Lobsters  int
+	Arxiv     int
+	PeerTube  int `dump:"peertube"`
+	Overrides map[string]int
+}
+
+// FeedCaps caps how many items of each source ("youtube", "substack", ...) or
+// group the feed shows, whatever the overall --limit.
+type FeedCaps struct {
+	Sources map[string]int
+	Groups  map[string]int
+}
+
+// limit returns the override for key, a feed, channel or repository, or
+// else fallback, the limit of its source. Trailing slashes of URLs don't
+// matter.
+func (l FetchLimits) limit(key string, fallback int) int {
+	if n, ok := l.Overrides[strings.TrimRight(key, "/")]; ok {
+		return n
+	}
+	return fallback
+}
+
+// YouTubeChannel returns the fetch limit for a YouTube channel.
+func (l FetchLimits) YouTubeChannel(channelID string) int {
+	return l.limit(channelID, l.YouTube)
+}
+
+// SubstackPublication returns the fetch limit for a Substack publication.
+func (l FetchLimits) SubstackPublication(publicationURL string) int {
+	return l.limit(publicationURL, l.Substack)
+}
+
+// BridgeFeed returns the fetch limit for an RSS-Bridge feed.
+func (l FetchLimits) BridgeFeed(feedURL string) int {
+	return l.limit(feedURL, l.Bridge)
+}
+
+// GitHubRepo returns the fetch limit for a GitHub repository ("owner/name").
+func (l FetchLimits) GitHubRepo(repo string) int {
+	return l.limit(repo, l.GitHub)
+}
+
+// MediumPage returns the fetch limit for a Medium writer or publication.
+func (l FetchLimits) MediumPage(pageURL string) int {
+	return l.limit(pageURL, l.Medium)
+}
+
+// LobstersSite returns the fetch limit for a Lobsters site.
+func (l FetchLimits) LobstersSite(siteURL string) int {
+	return l.limit(siteURL, l.Lobsters)
+}
+
+// PeerTubeChannel returns the fetch limit for a PeerTube channel (name@host).
+func (l FetchLimits) PeerTubeChannel(handle string) int {
+	return l.limit(handle, l.PeerTube)
+}
+
+// PodcastFeed returns the fetch limit for a podcast feed.
+func (l FetchLimits) PodcastFeed(feedURL string) int {
+	return l.limit(feedURL, l.Podcast)
+}
+
+// Load reads configuration using getenv (typically os.Getenv).
+func Load(getenv func(string) string) (Config, error) {
+	read := make(map[string]bool)
+	getenv = recordReads(getenv, read)
+	cfg := readSettings(getenv)
+	for _, load := range []func(*Config, func(string) string) error{
+		loadTokenStore,
+		loadYouTube,
+		loadSubstack,
+		loadFeeds,
+		loadNotifications,
+		loadPreferences,
+		loadFetchLimits,
+		loadCacheTTLs,
+		loadRuns,
+	} {
+		if err := load(&cfg, getenv); err != nil {
+			return Config{}, err
+		}
+	}
+	cfg.read = read
+	return cfg, nil
+}
+
+// readSettings reads the settings taken as they are set.
+func readSettings(getenv func(string) string) Config {
+	return Config{
+		Dir:      configDir(getenv("FEEDMIX_CONFIG_DIR")),
+		CacheDir: cacheDir(getenv("FEEDMIX_CACHE_DIR")),
+		YouTube: YouTube{
+			ClientID:     getenv("FEEDMIX_YOUTUBE_CLIENT_ID"),
+			ClientSecret: getenv("FEEDMIX_YOUTUBE_CLIENT_SECRET"),
+			RefreshToken: getenv("FEEDMIX_YOUTUBE_REFRESH_TOKEN"),
+			TokenURL:     getenv("FEEDMIX_OAUTH_TOKEN_URL"),
+			DeviceURL:    getenv("FEEDMIX_OAUTH_DEVICE_URL"),
+			APIURL:       getenv("FEEDMIX_API_URL"),
+			APIKeys:      SplitList(getenv("FEEDMIX_YOUTUBE_API_KEYS")),
+		},
+		Substack: Substack{
+			URLs: SplitList(getenv("FEEDMIX_SUBSTACK_URLS")),
+		},
+		Reader: Reader{
+			URL:      strings.TrimSpace(getenv("FEEDMIX_READER_URL")),
+			User:     getenv("FEEDMIX_READER_USER"),
+			Password: getenv("FEEDMIX_READER_PASSWORD"),
+		},
+		Bridge: Bridge{
+			URLs: SplitList(getenv("FEEDMIX_BRIDGE_URLS")),
+		},
+		Podcast: Podcast{
+			URLs: SplitList(getenv("FEEDMIX_PODCAST_URLS")),
+		},
+		Twitch: Twitch{
+			ClientID:     strings.TrimSpace(getenv("FEEDMIX_TWITCH_CLIENT_ID")),
+			ClientSecret: getenv("FEEDMIX_TWITCH_CLIENT_SECRET"),
+			RefreshToken: getenv("FEEDMIX_TWITCH_REFRESH_TOKEN"),
+		},
+		Medium: Medium{
+			URLs: SplitList(getenv("FEEDMIX_MEDIUM_URLS")),
+		},
+		Arxiv: Arxiv{
+			Categories: SplitList(getenv("FEEDMIX_ARXIV_CATEGORIES")),
+			Authors:    SplitList(getenv("FEEDMIX_ARXIV_AUTHORS")),
+			Keywords:   SplitList(getenv("FEEDMIX_ARXIV_KEYWORDS")),
+		},
+		Lobsters: Lobsters{
+			URLs: SplitList(getenv("FEEDMIX_LOBSTERS_URLS")),
+		},
+		GitHub: GitHub{
+			Token: strings.TrimSpace(getenv("FEEDMIX_GITHUB_TOKEN")),
+		},
+		Slack: Slack{
+			WebhookURL: strings.TrimSpace(getenv("FEEDMIX_SLACK_WEBHOOK_URL")),
+			Template:   strings.TrimSpace(getenv("FEEDMIX_SLACK_TEMPLATE")),
+		},
+		Discord: Discord{
+			WebhookURL: strings.TrimSpace(getenv("FEEDMIX_DISCORD_WEBHOOK_URL")),
+			Template:   strings.TrimSpace(getenv("FEEDMIX_DISCORD_TEMPLATE")),
+		},
+		Push: Push{
+			NtfyTopic:        strings.TrimSpace(getenv("FEEDMIX_NTFY_TOPIC")),
+			NtfyToken:        strings.TrimSpace(getenv("FEEDMIX_NTFY_TOKEN")),
+			PushoverToken:    strings.TrimSpace(getenv("FEEDMIX_PUSHOVER_TOKEN")),
+			PushoverUser:     strings.TrimSpace(getenv("FEEDMIX_PUSHOVER_USER")),
+			Keywords:         SplitList(getenv("FEEDMIX_PUSH_KEYWORDS")),
+			NtfyTemplate:     strings.TrimSpace(getenv("FEEDMIX_NTFY_TEMPLATE")),
+			PushoverTemplate: strings.TrimSpace(getenv("FEEDMIX_PUSHOVER_TEMPLATE")),
+		},
+		Miniflux: Miniflux{
+			URL:   strings.TrimSpace(getenv("FEEDMIX_MINIFLUX_URL")),
+			Token: strings.TrimSpace(getenv("FEEDMIX_MINIFLUX_TOKEN")),
+		},
+		EventLog: getenv("FEEDMIX_EVENT_LOG"),
+		Pager:    parsePager(getenv),
+		Finder:   strings.TrimSpace(getenv("FEEDMIX_FINDER")),
+	}
+}
+
+// loadTokenStore reads FEEDMIX_TOKEN_STORE, defaulting to the file store.
+func loadTokenStore(cfg *Config, getenv func(string) string) error {
+	cfg.TokenStore = strings.ToLower(strings.TrimSpace(getenv("FEEDMIX_TOKEN_STORE")))
+	switch cfg.TokenStore {
+	case "":
+		cfg.TokenStore = TokenStoreFile
+	case TokenStoreFile, TokenStoreKeyring:
+	default:
+		return fmt.Errorf("invalid FEEDMIX_TOKEN_STORE %q: must be %q or %q", cfg.TokenStore, TokenStoreFile, TokenStoreKeyring)
+	}
+	return nil
+}
+
+// loadYouTube reads the YouTube accounts, channels and groups, and how
+// fast and how much of the API quota to use.
+func loadYouTube(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.YouTube.Accounts, err = parseAccounts(getenv("FEEDMIX_YOUTUBE_ACCOUNTS")); err != nil {
+		return err
+	}
+	if cfg.YouTube.Channels, err = parseChannels(getenv("FEEDMIX_YOUTUBE_CHANNELS")); err != nil {
+		return err
+	}
+	if cfg.YouTube.Groups, err = parseGroups(getenv("FEEDMIX_YOUTUBE_GROUPS")); err != nil {
+		return err
+	}
+	if cfg.YouTube.RateLimit, err = parseRate("FEEDMIX_YOUTUBE_RATE_LIMIT", getenv("FEEDMIX_YOUTUBE_RATE_LIMIT"), DefaultYouTubeRateLimit); err != nil {
+		return err
+	}
+	cfg.YouTube.QuotaBudget, err = parsePositive("FEEDMIX_YOUTUBE_QUOTA_BUDGET", getenv("FEEDMIX_YOUTUBE_QUOTA_BUDGET"), DefaultYouTubeQuotaBudget*(1+len(cfg.YouTube.APIKeys)), 0)
+	return err
+}
+
+// loadSubstack reads which Substack authors, sections and notes to follow,
+// and what to send Substack with each request.
+func loadSubstack(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.Substack.Authors, err = parseAuthors(getenv("FEEDMIX_SUBSTACK_AUTHORS")); err != nil {
+		return err
+	}
+	if cfg.Substack.Sections, err = parseSections(getenv("FEEDMIX_SUBSTACK_SECTIONS")); err != nil {
+		return err
+	}
+	if cfg.Substack.Notes, err = parseNoteHandles(getenv("FEEDMIX_SUBSTACK_NOTES")); err != nil {
+		return err
+	}
+	if cfg.Substack.Cookie, err = parseSubstackCookie(getenv("FEEDMIX_SUBSTACK_COOKIE")); err != nil {
+		return err
+	}
+	cfg.Substack.Headers, err = parseHeaders("FEEDMIX_SUBSTACK_HEADERS", getenv("FEEDMIX_SUBSTACK_HEADERS"), getenv)
+	return err
+}
+
+// loadFeeds reads what to follow on Lobsters, PeerTube and GitHub.
+func loadFeeds(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.Lobsters.Tags, err = parseLobstersTags(getenv("FEEDMIX_LOBSTERS_TAGS")); err != nil {
+		return err
+	}
+	if cfg.PeerTube.Channels, err = parsePeerTubeChannels(getenv("FEEDMIX_PEERTUBE_CHANNELS")); err != nil {
+		return err
+	}
+	if cfg.GitHub.Repos, err = parseGitHubRepos(getenv("FEEDMIX_GITHUB_REPOS")); err != nil {
+		return err
+	}
+	cfg.GitHub.Starred, err = parseGitHubUser(getenv("FEEDMIX_GITHUB_STARRED"))
+	return err
+}
+
+// loadNotifications reads where new items are announced, which of them,
+// how they are batched and when they are held back.
+func loadNotifications(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.Slack.Channels, err = parseSlackChannels(getenv("FEEDMIX_SLACK_CHANNELS")); err != nil {
+		return err
+	}
+	for _, name := range SplitList(getenv("FEEDMIX_PUSH_SOURCES")) {
+		source, err := parseSource("FEEDMIX_PUSH_SOURCES", name)
+		if err != nil {
+			return err
+		}
+		cfg.Push.Sources = append(cfg.Push.Sources, source)
+	}
+	for _, batch := range []struct {
+		name   string
+		window *time.Duration
+	}{
+		{"FEEDMIX_SLACK_BATCH", &cfg.Slack.Batch},
+		{"FEEDMIX_DISCORD_BATCH", &cfg.Discord.Batch},
+		{"FEEDMIX_NTFY_BATCH", &cfg.Push.NtfyBatch},
+		{"FEEDMIX_PUSHOVER_BATCH", &cfg.Push.PushoverBatch},
+	} {
+		if *batch.window, err = parseBatch(batch.name, getenv(batch.name)); err != nil {
+			return err
+		}
+	}
+	if cfg.Quiet, err = parseQuiet(getenv("FEEDMIX_QUIET_HOURS"), getenv("FEEDMIX_QUIET_DESTINATIONS")); err != nil {
+		return err
+	}
+	if (cfg.Push.PushoverToken == "") != (cfg.Push.PushoverUser == "") {
+		return fmt.Errorf("set both FEEDMIX_PUSHOVER_TOKEN and FEEDMIX_PUSHOVER_USER to send Pushover notifications")
+	}
+	return nil
+}
+
+// loadPreferences reads the locale and region, the layout, and the
+// switches that change what the feed keeps and how feedmix runs.
+func loadPreferences(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.Locale, err = parseLocale(getenv); err != nil {
+		return err
+	}
+	if cfg.YouTube.Region, err = parseRegion(getenv("FEEDMIX_REGION"), cfg.Locale); err != nil {
+		return err
+	}
+	if cfg.ResurfaceUpdated, err = parseBool("FEEDMIX_RESURFACE_UPDATED", getenv("FEEDMIX_RESURFACE_UPDATED"), false); err != nil {
+		return err
+	}
+	if cfg.HidePaywalled, err = parseBool("FEEDMIX_HIDE_PAYWALLED", getenv("FEEDMIX_HIDE_PAYWALLED"), false); err != nil {
+		return err
+	}
+	if cfg.ArchiveSaved, err = parseBool("FEEDMIX_ARCHIVE_SAVED", getenv("FEEDMIX_ARCHIVE_SAVED"), false); err != nil {
+		return err
+	}
+	if cfg.Display, err = parseDisplay(getenv); err != nil {
+		return err
+	}
+	if cfg.Concurrency, err = parsePositive("FEEDMIX_CONCURRENCY", getenv("FEEDMIX_CONCURRENCY"), DefaultConcurrency, MaxConcurrency); err != nil {
+		return err
+	}
+	cfg.Strict, err = parseBool("FEEDMIX_STRICT", getenv("FEEDMIX_STRICT"), false)
+	return err
+}
+
+// loadFetchLimits reads how many items to fetch from each source and to
+// show of each source and group.
+func loadFetchLimits(cfg *Config, getenv func(string) string) error {
+	var err error
+	for _, limit := range []struct {
+		name string
+		n    *int
+		max  int
+	}{
+		{"FEEDMIX_YOUTUBE_FETCH_LIMIT", &cfg.Limits.YouTube, MaxYouTubeFetchLimit},
+		{"FEEDMIX_SUBSTACK_FETCH_LIMIT", &cfg.Limits.Substack, 0},
+		{"FEEDMIX_BRIDGE_FETCH_LIMIT", &cfg.Limits.Bridge, 0},
+		{"FEEDMIX_PODCAST_FETCH_LIMIT", &cfg.Limits.Podcast, 0},
+		{"FEEDMIX_TWITCH_FETCH_LIMIT", &cfg.Limits.Twitch, MaxTwitchFetchLimit},
+		{"FEEDMIX_GITHUB_FETCH_LIMIT", &cfg.Limits.GitHub, MaxGitHubFetchLimit},
+		{"FEEDMIX_MEDIUM_FETCH_LIMIT", &cfg.Limits.Medium, 0},
+		{"FEEDMIX_LOBSTERS_FETCH_LIMIT", &cfg.Limits.Lobsters, MaxLobstersFetchLimit},
+		{"FEEDMIX_PEERTUBE_FETCH_LIMIT", &cfg.Limits.PeerTube, MaxPeerTubeFetchLimit},
+	} {
+		if *limit.n, err = parseLimit(limit.name, getenv(limit.name), limit.max); err != nil {
+			return err
+		}
+	}
+	if cfg.Limits.Reader, err = parsePositive("FEEDMIX_READER_FETCH_LIMIT", getenv("FEEDMIX_READER_FETCH_LIMIT"), DefaultReaderFetchLimit, MaxReaderFetchLimit); err != nil {
+		return err
+	}
+	if cfg.Limits.Arxiv, err = parsePositive("FEEDMIX_ARXIV_FETCH_LIMIT", getenv("FEEDMIX_ARXIV_FETCH_LIMIT"), DefaultArxivFetchLimit, MaxArxivFetchLimit); err != nil {
+		return err
+	}
+	if cfg.Limits.Overrides, err = parseOverrides(getenv("FEEDMIX_FETCH_LIMITS")); err != nil {
+		return err
+	}
+	if cfg.Caps.Sources, err = parseSourceCaps(getenv("FEEDMIX_SOURCE_LIMITS")); err != nil {
+		return err
+	}
+	cfg.Caps.Groups, err = parseGroupCaps(getenv("FEEDMIX_GROUP_LIMITS"))
+	return err
+}
+
+// loadCacheTTLs reads how long each source's cached responses stay fresh.
+func loadCacheTTLs(cfg *Config, getenv func(string) string) error {
+	for _, ttl := range []struct {
+		name string
+		ttl  *time.Duration
+	}{
+		{"FEEDMIX_YOUTUBE_CACHE_TTL", &cfg.Cache.YouTube},
+		{"FEEDMIX_SUBSTACK_CACHE_TTL", &cfg.Cache.Substack},
+		{"FEEDMIX_PODCAST_CACHE_TTL", &cfg.Cache.Podcast},
+		{"FEEDMIX_GITHUB_CACHE_TTL", &cfg.Cache.GitHub},
+		{"FEEDMIX_MEDIUM_CACHE_TTL", &cfg.Cache.Medium},
+		{"FEEDMIX_LOBSTERS_CACHE_TTL", &cfg.Cache.Lobsters},
+		{"FEEDMIX_ARXIV_CACHE_TTL", &cfg.Cache.Arxiv},
+		{"FEEDMIX_PEERTUBE_CACHE_TTL", &cfg.Cache.PeerTube},
+	} {
+		var err error
+		if *ttl.ttl, err = parseTTL(ttl.name, getenv(ttl.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRuns reads how many run manifests to keep, and for how long.
+func loadRuns(cfg *Config, getenv func(string) string) error {
+	var err error
+	if cfg.Runs.Keep, err = ParseKeep("FEEDMIX_RUNS_KEEP", getenv("FEEDMIX_RUNS_KEEP"), DefaultRunsKeep); err != nil {
+		return err
+	}
+	cfg.Runs.MaxAge, err = ParseAge("FEEDMIX_RUNS_MAX_AGE", getenv("FEEDMIX_RUNS_MAX_AGE"), DefaultRunsMaxAge)
+	return err
+}
+
+// SplitList splits a comma-separated value, trimming whitespace and dropping empty entries.
+func SplitList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	values := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			values = append(values, p)
+		}
+	}
+	return values
+}
+
+func configDir(override string) string {
+	if override != "" {
+		return override
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "feedmix")
+}
+
+func cacheDir(override string) string {
+	if override != "" {
+		return override
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "feedmix")
+}
+
+func parseLimit(name, raw string, max int) (int, error) {
+	return parsePositive(name, raw, DefaultFetchLimit, max)
+}
+
+func parsePositive(name, raw string, def, max int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive integer", name, raw)
+	}
+	if max > 0 && n > max {
+		return 0, fmt.Errorf("invalid %s %d: must be at most %d", name, n, max)
+	}
+	return n, nil
+}
+
+func parseRate(name, raw string, def float64) (float64, error) {
+	if raw == "" {
+		return def, nil
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative number of requests per second", name, raw)
+	}
+	return rate, nil
+}
+
+func parseAccounts(raw string) ([]string, error) {
+	accounts := SplitList(raw)
+	for _, account := range accounts {
+		if !ValidAccountName(account) {
+			return nil, fmt.Errorf("invalid FEEDMIX_YOUTUBE_ACCOUNTS entry %q: use letters, digits, '-' and '_'", account)
+		}
+	}
+	return accounts, nil
+}
+
+// ValidAccountName reports whether name can label an account (and its token entry).
+func ValidAccountName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDisplay(getenv func(string) string) (Display, error) {
+	var d Display
+	var err error
+	if d.Description, err = parseBool("FEEDMIX_SHOW_DESCRIPTION", getenv("FEEDMIX_SHOW_DESCRIPTION"), false); err != nil {
+		return Display{}, err
+	}
+	if d.DescriptionLength, err = parsePositive("FEEDMIX_DESCRIPTION_LENGTH", getenv("FEEDMIX_DESCRIPTION_LENGTH"), DefaultDescriptionLength, 0); err != nil {
+		return Display{}, err
+	}
+	if d.TitleLength, err = parsePositive("FEEDMIX_TITLE_LENGTH", getenv("FEEDMIX_TITLE_LENGTH"), 0, 0); err != nil {
+		return Display{}, err
+	}
+	if d.Width, err = parsePositive("FEEDMIX_WIDTH", getenv("FEEDMIX_WIDTH"), 0, 0); err != nil {
+		return Display{}, err
+	}
+	if d.Engagement, err = parseBool("FEEDMIX_SHOW_ENGAGEMENT", getenv("FEEDMIX_SHOW_ENGAGEMENT"), true); err != nil {
+		return Display{}, err
+	}
+	if d.Thumbnails, err = parseBool("FEEDMIX_SHOW_THUMBNAILS", getenv("FEEDMIX_SHOW_THUMBNAILS"), false); err != nil {
+		return Display{}, err
+	}
+	if d.Details, err = parseBool("FEEDMIX_SHOW_DETAILS", getenv("FEEDMIX_SHOW_DETAILS"), false); err != nil {
+		return Display{}, err
+	}
+	if d.InlineThumbnails, err = ParseInlineThumbnails("FEEDMIX_INLINE_THUMBNAILS", getenv("FEEDMIX_INLINE_THUMBNAILS")); err != nil {
+		return Display{}, err
+	}
+	if d.CalmTitles, err = parseBool("FEEDMIX_CALM_TITLES", getenv("FEEDMIX_CALM_TITLES"), false); err != nil {
+		return Display{}, err
+	}
+	if d.Hyperlinks, err = ParseHyperlinks("FEEDMIX_HYPERLINKS", getenv("FEEDMIX_HYPERLINKS")); err != nil {
+		return Display{}, err
+	}
+	if d.Compact, err = parseBool("FEEDMIX_COMPACT", getenv("FEEDMIX_COMPACT"), false); err != nil {
+		return Display{}, err
+	}
+	if d.DayHeaders, err = parseBool("FEEDMIX_DAY_HEADERS", getenv("FEEDMIX_DAY_HEADERS"), false); err != nil {
+		return Display{}, err
+	}
+	if d.GroupBy, err = ParseGroupBy("FEEDMIX_GROUP_BY", getenv("FEEDMIX_GROUP_BY")); err != nil {
+		return Display{}, err
+	}
+	d.Color = getenv("NO_COLOR") == ""
+	d.Theme = strings.ToLower(strings.TrimSpace(getenv("FEEDMIX_THEME")))
+	return d, nil
+}
+
+// ParseHyperlinks validates a hyperlink mode; empty means HyperlinksAuto.
+// name is the setting reported in errors.
+func ParseHyperlinks(name, raw string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(raw)); mode {
+	case "":
+		return HyperlinksAuto, nil
+	case HyperlinksAuto, HyperlinksAlways, HyperlinksNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q: must be %q, %q or %q", name, raw, HyperlinksAuto, HyperlinksAlways, HyperlinksNever)
+	}
+}
+
+// ParseGroupBy validates a feed grouping; "none" or an empty value means a
+// chronological feed and returns "".
+func ParseGroupBy(name, raw string) (string, error) {
+	switch by := strings.ToLower(strings.TrimSpace(raw)); by {
+	case "", "none":
+		return "", nil
+	case GroupBySource, GroupByAuthor:
+		return by, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q: must be %q, %q or \"none\"", name, raw, GroupBySource, GroupByAuthor)
+	}
+}
+
+// ParseInlineThumbnails validates an inline thumbnail mode; "off" or an
+// empty value returns "".
+func ParseInlineThumbnails(name, raw string) (string, error) {
+	switch mode := strings.ToLower(strings.TrimSpace(raw)); mode {
+	case "", "off":
+		return "", nil
+	case InlineThumbnailsAuto, ImageProtocolKitty, ImageProtocolITerm, ImageProtocolSixel:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q: must be %q, %q, %q, %q or \"off\"", name, raw, InlineThumbnailsAuto, ImageProtocolKitty, ImageProtocolITerm, ImageProtocolSixel)
+	}
+}
+
+// parsePager reads FEEDMIX_PAGER, falling back to PAGER and then "less -R"
+// like git does. "cat" turns paging off.
+func parsePager(getenv func(string) string) string {
+	pager := strings.TrimSpace(getenv("FEEDMIX_PAGER"))
+	if pager == "" {
+		pager = strings.TrimSpace(getenv("PAGER"))
+	}
+	switch pager {
+	case "":
+		return DefaultPager
+	case "cat":
+		return ""
+	default:
+		return pager
+	}
+}
+
+// parseLocale reads FEEDMIX_LOCALE, falling back to the POSIX locale
+// variables (LC_ALL, LC_MESSAGES, LANG) and then English. Only an invalid
+// FEEDMIX_LOCALE is an error; an unusable system locale falls back silently.
+func parseLocale(getenv func(string) string) (language.Tag, error) {
+	if raw := strings.TrimSpace(getenv("FEEDMIX_LOCALE")); raw != "" {
+		tag, err := language.Parse(posixLocale(raw))
+		if err != nil {
+			return language.Und, fmt.Errorf("invalid FEEDMIX_LOCALE %q: must be a language tag such as en-US or fr", raw)
+		}
+		return tag, nil
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		raw := getenv(name)
+		if raw == "" {
+			continue
+		}
+		if tag, err := language.Parse(posixLocale(raw)); err == nil {
+			return tag, nil
+		}
+		break
+	}
+	return language.English, nil
+}
+
+// parseRegion reads FEEDMIX_REGION, falling back to the region of locale
+// when it names one ("fr-FR", not "fr").
+func parseRegion(raw string, locale language.Tag) (string, error) {
+	if raw = strings.TrimSpace(raw); raw != "" {
+		region, err := language.ParseRegion(raw)
+		if err != nil || !region.IsCountry() {
+			return "", fmt.Errorf("invalid FEEDMIX_REGION %q: must be a two-letter country code such as US or FR", raw)
+		}
+		return region.String(), nil
+	}
+	if region, confidence := locale.Region(); confidence == language.Exact && region.IsCountry() {
+		return region.String(), nil
+	}
+	return "", nil
+}
+
+// posixLocale turns "fr_FR.UTF-8@euro" into "fr-FR"; "C" and "POSIX" mean English.
+func posixLocale(raw string) string {
+	if i := strings.IndexAny(raw, ".@"); i >= 0 {
+		raw = raw[:i]
+	}
+	if raw == "C" || raw == "POSIX" {
+		return "en"
+	}
+	return strings.ReplaceAll(raw, "_", "-")
+}
+
+func parseBool(name, raw string, def bool) (bool, error) {
+	if raw == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(raw))
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: must be true or false", name, raw)
+	}
+	return b, nil
+}
+
+func parseTTL(name, raw string) (time.Duration, error) {
+	if raw == "" {
+		return DefaultCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration such as 5m or 0 to disable", name, raw)
+	}
+	return ttl, nil
+}
+
+// ParseKeep parses a number of items to keep, where 0 keeps everything.
+func ParseKeep(name, raw string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a number, or 0 to keep everything", name, raw)
+	}
+	return n, nil
+}
+
+// MaxBatch is the longest notifications can be held back to send together.
+const MaxBatch = 24 * time.Hour
+
+// parseBatch parses a notification batching window such as 30m, where 0
+// or nothing sends at once.
+func parseBatch(name, raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil || window < 0 || window > MaxBatch {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration up to %s such as 30m, or 0 to send at once", name, raw, MaxBatch)
+	}
+	return window, nil
+}
+
+// parseQuiet parses quiet hours such as "22:00-08:00,weekends", and the
+// notification services they apply to.
+func parseQuiet(hours, destinations string) (Quiet, error) {
+	var q Quiet
+	for _, entry := range SplitList(hours) {
+		if strings.EqualFold(entry, "weekends") {
+			q.Weekends = true
+			continue
+		}
+		from, to, ok := strings.Cut(entry, "-")
+		start, startErr := parseTimeOfDay(from)
+		end, endErr := parseTimeOfDay(to)
+		if !ok || startErr != nil || endErr != nil || q.Start != q.End {
+			return Quiet{}, fmt.Errorf("invalid FEEDMIX_QUIET_HOURS %q: must be hours such as 22:00-08:00, weekends, or both", hours)
+		}
+		q.Start, q.End = start, end
+	}
+	for _, name := range SplitList(destinations) {
+		switch destination := strings.ToLower(name); destination {
+		case "slack", "discord", "ntfy", "pushover":
+			q.Destinations = append(q.Destinations, destination)
+		default:
+			return Quiet{}, fmt.Errorf("invalid FEEDMIX_QUIET_DESTINATIONS entry %q: must be slack, discord, ntfy or pushover", name)
+		}
+	}
+	return q, nil
+}
+
+// parseTimeOfDay parses a time of day such as 08:00 as the time since
+// midnight.
+func parseTimeOfDay(raw string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ParseAge parses a maximum age such as 30d or 12h, where 0 keeps everything.
+// Unlike time.ParseDuration, it accepts whole days.
+func ParseAge(name, raw string, def time.Duration) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return def, nil
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	age, err := time.ParseDuration(raw)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration such as 30d or 12h, or 0 to keep everything", name, raw)
+	}
+	return age, nil
+}
+
+func parseOverrides(raw string) (map[string]int, error) {
+	overrides := make(map[string]int)
+	err := forEachPair("FEEDMIX_FETCH_LIMITS", "<channel-id or url>=<limit>", raw, func(key, value string) error {
+		n, err := parseLimit("FEEDMIX_FETCH_LIMITS limit for "+key, value, MaxYouTubeFetchLimit)
+		overrides[key] = n
+		return err
+	})
+	return overrides, err
+}
+
+func parseSourceCaps(raw string) (map[string]int, error) {
+	caps := make(map[string]int)
+	err := forEachPair("FEEDMIX_SOURCE_LIMITS", "<source>=<limit>", raw, func(key, value string) error {
+		source, err := parseSource("FEEDMIX_SOURCE_LIMITS", key)
+		if err != nil {
+			return err
+		}
+		n, err := parsePositive("FEEDMIX_SOURCE_LIMITS limit for "+key, value, 0, 0)
+		caps[source] = n
+		return err
+	})
+	return caps, err
+}
+
+// parseSource reads the name of a source, in any case.
+func parseSource(name, raw string) (string, error) {
+	source := strings.ToLower(raw)
+	switch source {
+	case "youtube", "substack", "reader", "bridge", "podcast", "twitch", "github", "medium", "lobsters", "arxiv", "peertube":
+		return source, nil
+	}
+	return "", fmt.Errorf("invalid %s source %q: must be youtube, substack, reader, bridge, podcast, twitch, github, medium, lobsters, arxiv or peertube", name, raw)
+}
+
+func parseGroupCaps(raw string) (map[string]int, error) {
+	caps := make(map[string]int)
+	err := forEachPair("FEEDMIX_GROUP_LIMITS", "<group>=<limit>", raw, func(key, value string) error {
+		n, err := parsePositive("FEEDMIX_GROUP_LIMITS limit for "+key, value, 0, 0)
+		caps[strings.ToLower(key)] = n
+		return err
+	})
+	return caps, err
+}
+
+// parseChannels reads a list of channel IDs, which are "UC" and 22 more
+// characters.
+func parseChannels(raw string) ([]string, error) {
+	channels := SplitList(raw)
+	for _, id := range channels {
+		if len(id) != 24 || !strings.HasPrefix(id, "UC") {
+			return nil, fmt.Errorf("invalid FEEDMIX_YOUTUBE_CHANNELS entry %q: expected a channel ID such as UCxYz123ABC... (24 characters starting with UC)", id)
+		}
+	}
+	return channels, nil
+}
+
+func parseGroups(raw string) (map[string]string, error) {
+	groups := make(map[string]string)
+	err := forEachPair("FEEDMIX_YOUTUBE_GROUPS", "<channel id>=<group>", raw, func(key, value string) error {
+		groups[key] = strings.ToLower(value)
+		return nil
+	})
+	return groups, err
+}
+
+func parseAuthors(raw string) (map[string][]string, error) {
+	authors := make(map[string][]string)
+	err := forEachPair("FEEDMIX_SUBSTACK_AUTHORS", "<publication url>=<author>", raw, func(key, value string) error {
+		authors[key] = append(authors[key], value)
+		return nil
+	})
+	return authors, err
+}
+
+// parseSections reads "<publication url>=<section>" entries, where the
+// section is a slug or its path, such as /s/podcast.
+func parseSections(raw string) (map[string][]string, error) {
+	sections := make(map[string][]string)
+	err := forEachPair("FEEDMIX_SUBSTACK_SECTIONS", "<publication url>=<section>", raw, func(key, value string) error {
+		section := strings.Trim(strings.TrimPrefix(strings.TrimPrefix(value, "/"), "s/"), "/")
+		if section == "" || strings.Contains(section, "/") {
+			return fmt.Errorf("invalid FEEDMIX_SUBSTACK_SECTIONS entry for %s: %q is not a section such as podcast or /s/podcast", key, value)
+		}
+		sections[key] = append(sections[key], section)
+		return nil
+	})
+	return sections, err
+}
+
+// parseLobstersTags reads "<site url>=<tag>" entries, where "-tag" leaves
+// out stories with the tag.
+func parseLobstersTags(raw string) (map[string][]string, error) {
+	tags := make(map[string][]string)
+	err := forEachPair("FEEDMIX_LOBSTERS_TAGS", "<site url>=<tag>", raw, func(key, value string) error {
+		if name := strings.TrimPrefix(value, "-"); name == "" || strings.ContainsAny(name, "/, ") {
+			return fmt.Errorf("invalid FEEDMIX_LOBSTERS_TAGS entry for %s: %q is not a tag such as go or -culture", key, value)
+		}
+		site := strings.TrimRight(key, "/")
+		tags[site] = append(tags[site], value)
+		return nil
+	})
+	return tags, err
+}
+
+// parseSlackChannels reads "<source>=<channel>" entries. Channels may be
+// given without their "#".
+func parseSlackChannels(raw string) (map[string]string, error) {
+	channels := make(map[string]string)
+	err := forEachPair("FEEDMIX_SLACK_CHANNELS", "<source>=<channel>", raw, func(key, value string) error {
+		source, err := parseSource("FEEDMIX_SLACK_CHANNELS", key)
+		if err != nil {
+			return err
+		}
+		if strings.ContainsAny(value, ", ") {
+			return fmt.Errorf("invalid FEEDMIX_SLACK_CHANNELS entry for %s: %q is not a channel such as #videos", key, value)
+		}
+		if !strings.HasPrefix(value, "#") && !strings.HasPrefix(value, "@") {
+			value = "#" + value
+		}
+		channels[source] = value
+		return nil
+	})
+	return channels, err
+}
+
+// parseNoteHandles reads writers' handles, given as jane, @jane or
+// https://substack.com/@jane.
+func parseNoteHandles(raw string) ([]string, error) {
+	var handles []string
+	for _, entry := range SplitList(raw) {
+		handle := strings.TrimRight(strings.TrimPrefix(entry, "https://substack.com/"), "/")
+		handle = strings.TrimPrefix(handle, "@")
+		if handle == "" || strings.ContainsAny(handle, "/@ \t") {
+			return nil, fmt.Errorf("invalid FEEDMIX_SUBSTACK_NOTES entry %q: expected a handle such as @jane or https://substack.com/@jane", entry)
+		}
+		handles = append(handles, handle)
+	}
+	return handles, nil
+}
+
+// parsePeerTubeChannels reads channels given as name@host, @name@host, or
+// by the URL of their page, such as https://framatube.org/c/framasoft or
+// https://framatube.org/video-channels/framasoft, and returns them as
+// name@host.
+func parsePeerTubeChannels(raw string) ([]string, error) {
+	var channels []string
+	for _, entry := range SplitList(raw) {
+		handle := strings.TrimPrefix(entry, "@")
+		if rest, ok := strings.CutPrefix(entry, "https://"); ok {
+			host, path, _ := strings.Cut(strings.TrimRight(rest, "/"), "/")
+			for _, prefix := range []string{"c/", "video-channels/"} {
+				if name, ok := strings.CutPrefix(path, prefix); ok {
+					name, _, _ = strings.Cut(name, "/")
+					handle = name + "@" + host
+					break
+				}
+			}
+		}
+		name, host, ok := strings.Cut(handle, "@")
+		if !ok || name == "" || host == "" || strings.ContainsAny(handle, "/ \t") || strings.Contains(host, "@") {
+			return nil, fmt.Errorf("invalid FEEDMIX_PEERTUBE_CHANNELS entry %q: expected name@host or a channel URL such as https://framatube.org/c/framasoft", entry)
+		}
+		channels = append(channels, handle)
+	}
+	return channels, nil
+}
+
+// parseGitHubRepos reads repositories given as owner/name or by their URL,
+// such as https://github.com/owner/name/releases.
+func parseGitHubRepos(raw string) ([]string, error) {
+	var repos []string
+	for _, entry := range SplitList(raw) {
+		repo := strings.TrimPrefix(strings.TrimPrefix(entry, "https://"), "github.com/")
+		parts := strings.Split(strings.TrimSuffix(strings.TrimRight(repo, "/"), ".git"), "/")
+		if len(parts) == 3 && parts[2] == "releases" {
+			parts = parts[:2]
+		}
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(repo, " \t?#") {
+			return nil, fmt.Errorf("invalid FEEDMIX_GITHUB_REPOS entry %q: expected owner/name or https://github.com/owner/name", entry)
+		}
+		repos = append(repos, parts[0]+"/"+parts[1])
+	}
+	return repos, nil
+}
+
+// parseGitHubUser reads a user given as octocat, @octocat or
+// https://github.com/octocat.
+func parseGitHubUser(raw string) (string, error) {
+	user := strings.TrimSpace(raw)
+	user = strings.TrimRight(strings.TrimPrefix(user, "https://github.com/"), "/")
+	user = strings.TrimPrefix(user, "@")
+	if strings.ContainsAny(user, "/@ \t") {
+		return "", fmt.Errorf("invalid FEEDMIX_GITHUB_STARRED %q: expected a user such as octocat or https://github.com/octocat", raw)
+	}
+	return user, nil
+}
+
+// parseSubstackCookie reads a Cookie header value, or the bare value of a
+// substack.sid cookie as copied from the browser.
+func parseSubstackCookie(raw string) (string, error) {
+	cookie := strings.TrimSpace(raw)
+	if strings.ContainsAny(cookie, "\r\n") {
+		return "", fmt.Errorf("invalid FEEDMIX_SUBSTACK_COOKIE: must be on one line")
+	}
+	if cookie != "" && !strings.Contains(cookie, "=") {
+		cookie = "substack.sid=" + cookie
+	}
+	return cookie, nil
+}
+
+// parseHeaders reads "<url> <Header-Name>: <value>" entries. Values may
+// reference environment variables as $NAME or ${NAME}, so secrets such as
+// session cookies can live outside the list itself.
+func parseHeaders(name, raw string, getenv func(string) string) (map[string]http.Header, error) {
+	headers := make(map[string]http.Header)
+	for _, entry := range SplitList(raw) {
+		key, header, _ := strings.Cut(entry, " ")
+		headerName, value, ok := strings.Cut(header, ":")
+		key = strings.TrimRight(key, "/")
+		headerName = strings.TrimSpace(headerName)
+		if !ok || key == "" || headerName == "" || strings.ContainsAny(headerName, " \t") {
+			return nil, fmt.Errorf("invalid %s entry %q: expected <url> <Header-Name>: <value>", name, entry)
+		}
+
+		value = strings.TrimSpace(os.Expand(strings.TrimSpace(value), getenv))
+		if value == "" {
+			return nil, fmt.Errorf("invalid %s entry for %s: header %s has an empty value (is the referenced variable set?)", name, key, headerName)
+		}
+		if headers[key] == nil {
+			headers[key] = http.Header{}
+		}
+		headers[key].Add(headerName, value)
+	}
+	return headers, nil
+}
+
+// forEachPair walks a comma-separated list of key=value entries. Keys are
+// channel IDs or URLs (trailing slashes removed); the last "=" separates
+// the value.
+func forEachPair(name, format, raw string, fn func(key, value string) error) error {
+	for _, entry := range SplitList(raw) {
+		i := strings.LastIndex(entry, "=")
+		key := strings.TrimRight(strings.TrimSpace(entry[:max(i, 0)]), "/")
+		value := strings.TrimSpace(entry[i+1:])
+		if i <= 0 || key == "" || value == "" {
+			return fmt.Errorf("invalid %s entry %q: expected %s", name, entry, format)
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
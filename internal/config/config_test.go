@@ -0,0 +1,765 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func envMap(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestLoad_DefaultsFetchLimitsToFive(t *testing.T) {
+	cfg, err := Load(envMap(nil))
+	if err != nil {
+		t.Fatalf("empty environment should load, got: %v", err)
+	}
+	if got := cfg.Limits.YouTubeChannel("UC123"); got != DefaultFetchLimit {
+		t.Errorf("channel limit should default to %d, got %d", DefaultFetchLimit, got)
+	}
+	if got := cfg.Limits.SubstackPublication("https://example.substack.com"); got != DefaultFetchLimit {
+		t.Errorf("publication limit should default to %d, got %d", DefaultFetchLimit, got)
+	}
+}
+
+func TestLoad_AppliesPerSourceFetchLimits(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_YOUTUBE_FETCH_LIMIT":  "8",
+		"FEEDMIX_SUBSTACK_FETCH_LIMIT": "2",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Limits.YouTubeChannel("UC123"); got != 8 {
+		t.Errorf("user-configured YouTube limit should apply to every channel, got %d", got)
+	}
+	if got := cfg.Limits.SubstackPublication("https://example.substack.com"); got != 2 {
+		t.Errorf("user-configured Substack limit should apply to every publication, got %d", got)
+	}
+}
+
+func TestLoad_AppliesPerChannelAndPublicationOverrides(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_FETCH_LIMITS": "UCchannelX=10, https://newsletter-y.substack.com/=3",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Limits.YouTubeChannel("UCchannelX"); got != 10 {
+		t.Errorf("channel X should fetch 10 videos, got %d", got)
+	}
+	if got := cfg.Limits.YouTubeChannel("UCother"); got != DefaultFetchLimit {
+		t.Errorf("channels without override should keep the default, got %d", got)
+	}
+	if got := cfg.Limits.SubstackPublication("https://newsletter-y.substack.com"); got != 3 {
+		t.Errorf("newsletter Y should fetch 3 posts regardless of trailing slash, got %d", got)
+	}
+}
+
+func TestLoad_RejectsInvalidFetchLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"non-numeric", map[string]string{"FEEDMIX_YOUTUBE_FETCH_LIMIT": "lots"}, "FEEDMIX_YOUTUBE_FETCH_LIMIT"},
+		{"zero", map[string]string{"FEEDMIX_SUBSTACK_FETCH_LIMIT": "0"}, "FEEDMIX_SUBSTACK_FETCH_LIMIT"},
+		{"above YouTube page size", map[string]string{"FEEDMIX_YOUTUBE_FETCH_LIMIT": "51"}, "at most 50"},
+		{"override without limit", map[string]string{"FEEDMIX_FETCH_LIMITS": "UCchannelX"}, "FEEDMIX_FETCH_LIMITS"},
+		{"override with bad limit", map[string]string{"FEEDMIX_FETCH_LIMITS": "UCchannelX=-1"}, "UCchannelX"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(envMap(tc.env))
+			if err == nil {
+				t.Fatal("invalid limit should be rejected")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error should mention %q, got: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_SplitsSubstackURLs(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_URLS": " https://a.substack.com ,,https://b.substack.com",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Substack.URLs) != 2 || cfg.Substack.URLs[0] != "https://a.substack.com" || cfg.Substack.URLs[1] != "https://b.substack.com" {
+		t.Errorf("should trim and drop empty entries, got %q", cfg.Substack.URLs)
+	}
+}
+
+func TestLoad_ConfigDirHonorsOverride(t *testing.T) {
+	cfg, _ := Load(envMap(map[string]string{"FEEDMIX_CONFIG_DIR": "/tmp/feedmix-test"}))
+	if cfg.Dir != "/tmp/feedmix-test" {
+		t.Errorf("FEEDMIX_CONFIG_DIR should override the config directory, got %q", cfg.Dir)
+	}
+}
+
+func TestLoad_CacheDirHonorsOverride(t *testing.T) {
+	cfg, _ := Load(envMap(map[string]string{"FEEDMIX_CACHE_DIR": "/tmp/feedmix-cache"}))
+	if cfg.CacheDir != "/tmp/feedmix-cache" {
+		t.Errorf("FEEDMIX_CACHE_DIR should override the cache directory, got %q", cfg.CacheDir)
+	}
+}
+
+func TestLoad_ParsesSubstackAuthorFilters(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_AUTHORS": "https://collective.substack.com=Jane Doe,https://collective.substack.com/=John Roe",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	authors := cfg.Substack.AuthorsFor("https://collective.substack.com/")
+	if len(authors) != 2 || authors[0] != "Jane Doe" || authors[1] != "John Roe" {
+		t.Errorf("both authors should be kept for the publication, got %q", authors)
+	}
+	if got := cfg.Substack.AuthorsFor("https://other.substack.com"); got != nil {
+		t.Errorf("publications without a filter should keep every author, got %q", got)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_SUBSTACK_AUTHORS": "https://collective.substack.com="})); err == nil {
+		t.Error("an entry without an author should be rejected")
+	}
+}
+
+func TestLoad_SendsSubstackSessionCookieToSubstackPublications(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_COOKIE":  " s%3Asession ",
+		"FEEDMIX_SUBSTACK_HEADERS": "https://other.substack.com Cookie: substack.sid=other, https://paid.substack.com X-Region: eu",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, u := range []string{"https://paid.substack.com/", "https://substack.com/@jane", "https://free.substack.com"} {
+		if got := cfg.Substack.HeadersFor(u).Get("Cookie"); got != "substack.sid=s%3Asession" {
+			t.Errorf("%s should get the session cookie, got %q", u, got)
+		}
+	}
+	if got := cfg.Substack.HeadersFor("https://paid.substack.com").Get("X-Region"); got != "eu" {
+		t.Errorf("the publication's own headers should be kept, got %q", got)
+	}
+	if got := cfg.Substack.HeadersFor("https://other.substack.com").Get("Cookie"); got != "substack.sid=other" {
+		t.Errorf("a publication's own cookie should win, got %q", got)
+	}
+	if got := cfg.Substack.HeadersFor("https://stratechery.com"); got != nil {
+		t.Errorf("a custom domain should never get the substack.com session, got %v", got)
+	}
+
+	cfg, _ = Load(envMap(map[string]string{"FEEDMIX_SUBSTACK_COOKIE": "substack.sid=abc; substack.lli=1"}))
+	if got := cfg.Substack.HeadersFor("https://paid.substack.com").Get("Cookie"); got != "substack.sid=abc; substack.lli=1" {
+		t.Errorf("a whole Cookie header should be sent as is, got %q", got)
+	}
+}
+
+func TestLoad_ParsesSubstackSections(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_SECTIONS": "https://big.substack.com/=tech,https://big.substack.com=/s/podcast/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.Substack.SectionsFor("https://big.substack.com"), ","); got != "tech,podcast" {
+		t.Errorf("sections should be read as slugs, got %q", got)
+	}
+	if got := cfg.Substack.SectionsFor("https://other.substack.com"); got != nil {
+		t.Errorf("publications without sections should be read whole, got %q", got)
+	}
+
+	for _, bad := range []string{"https://big.substack.com=", "https://big.substack.com=/s/", "https://big.substack.com=tech/feed"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_SUBSTACK_SECTIONS": bad})); err == nil {
+			t.Errorf("entry %q should be rejected", bad)
+		}
+	}
+}
+
+func TestLoad_ParsesSubstackNoteHandles(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_NOTES": "jane,@john,https://substack.com/@ann/",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.Substack.Notes, ","); got != "jane,john,ann" {
+		t.Errorf("every form of handle should be read as the bare handle, got %q", got)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_SUBSTACK_NOTES": "https://jane.substack.com"})); err == nil {
+		t.Error("a publication URL should be rejected as a handle")
+	}
+}
+
+func TestLoad_ParsesGitHubRepos(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_GITHUB_REPOS":   "cli/cli,https://github.com/junegunn/fzf/releases,github.com/golang/go.git",
+		"FEEDMIX_GITHUB_STARRED": "https://github.com/octocat",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.GitHub.Repos, ","); got != "cli/cli,junegunn/fzf,golang/go" {
+		t.Errorf("every form of repository should be read as owner/name, got %q", got)
+	}
+	if cfg.GitHub.Starred != "octocat" {
+		t.Errorf("the profile URL should be read as the user, got %q", cfg.GitHub.Starred)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_GITHUB_REPOS": "https://github.com/octocat"})); err == nil {
+		t.Error("a user should be rejected as a repository")
+	}
+}
+
+func TestLoad_ParsesLobstersTags(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_LOBSTERS_URLS": "https://lobste.rs/",
+		"FEEDMIX_LOBSTERS_TAGS": "https://lobste.rs/=go,https://lobste.rs=-culture",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.Lobsters.TagsFor(cfg.Lobsters.URLs[0]), ","); got != "go,-culture" {
+		t.Errorf("tags should be looked up whatever the trailing slash, got %q", got)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_LOBSTERS_TAGS": "https://lobste.rs=-"})); err == nil {
+		t.Error("an empty tag should be rejected")
+	}
+}
+
+func TestLoad_ParsesPeerTubeChannels(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_PEERTUBE_CHANNELS": "framasoft@framatube.org,@blender@video.blender.org,https://tilvids.com/c/tech/videos,https://peertube.tv/video-channels/news",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "framasoft@framatube.org,blender@video.blender.org,tech@tilvids.com,news@peertube.tv"
+	if got := strings.Join(cfg.PeerTube.Channels, ","); got != want {
+		t.Errorf("every form of channel should be read as name@host, got %q", got)
+	}
+
+	for _, bad := range []string{"framasoft", "https://framatube.org/w/abc", "a@b@c"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_PEERTUBE_CHANNELS": bad})); err == nil {
+			t.Errorf("channel %q should be rejected", bad)
+		}
+	}
+}
+
+func TestLoad_ParsesSlackChannels(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_SLACK_CHANNELS": "YouTube=#videos,substack=reading"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Slack.ChannelFor("youtube") != "#videos" || cfg.Slack.ChannelFor("substack") != "#reading" || cfg.Slack.ChannelFor("reader") != "" {
+		t.Errorf("channels should be read per source, with their #, got %v", cfg.Slack.Channels)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_SLACK_CHANNELS": "mastodon=#toots"})); err == nil {
+		t.Error("an unknown source should be rejected")
+	}
+}
+
+func TestLoad_ParsesPushSettings(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_NTFY_TOPIC":    "feedmix-alerts",
+		"FEEDMIX_PUSH_SOURCES":  "YouTube,twitch",
+		"FEEDMIX_PUSH_KEYWORDS": "Fireship,release",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Push.Enabled() || strings.Join(cfg.Push.Sources, ",") != "youtube,twitch" || len(cfg.Push.Keywords) != 2 {
+		t.Errorf("push settings should be read, got %+v", cfg.Push)
+	}
+
+	for name, env := range map[string]map[string]string{
+		"an unknown source":         {"FEEDMIX_PUSH_SOURCES": "mastodon"},
+		"a Pushover token alone":    {"FEEDMIX_PUSHOVER_TOKEN": "app"},
+		"a Pushover user key alone": {"FEEDMIX_PUSHOVER_USER": "me"},
+	} {
+		if _, err := Load(envMap(env)); err == nil {
+			t.Errorf("%s should be rejected", name)
+		}
+	}
+}
+
+func TestLoad_ParsesNotificationBatches(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_SLACK_BATCH": "30m", "FEEDMIX_NTFY_BATCH": "1h"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Slack.Batch != 30*time.Minute || cfg.Push.NtfyBatch != time.Hour || cfg.Discord.Batch != 0 {
+		t.Errorf("batching windows should be read per service, got %s, %s and %s", cfg.Slack.Batch, cfg.Push.NtfyBatch, cfg.Discord.Batch)
+	}
+
+	for _, bad := range []string{"soon", "-5m", "48h"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_DISCORD_BATCH": bad})); err == nil {
+			t.Errorf("batching window %q should be rejected", bad)
+		}
+	}
+}
+
+// TestLoad_ParsesQuietHours documents quiet hours:
+//   - hours may span midnight, and weekends are quiet all day
+//   - they apply to every notification service unless some are listed
+func TestLoad_ParsesQuietHours(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_QUIET_HOURS": "22:00-08:00, weekends", "FEEDMIX_QUIET_DESTINATIONS": "ntfy,Pushover"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	for at, quiet := range map[time.Time]bool{
+		monday.Add(23 * time.Hour):                true,
+		monday.Add(7*time.Hour + 59*time.Minute):  true,
+		monday.Add(8 * time.Hour):                 false,
+		monday.Add(5*24*time.Hour + 12*time.Hour): true,
+	} {
+		if got := cfg.Quiet.Contains(at); got != quiet {
+			t.Errorf("quiet at %s: expected %v, got %v", at.Format("Mon 15:04"), quiet, got)
+		}
+	}
+	if !cfg.Quiet.AppliesTo("pushover") || cfg.Quiet.AppliesTo("slack") {
+		t.Errorf("quiet hours should apply to the listed services only, got %v", cfg.Quiet.Destinations)
+	}
+
+	cfg, _ = Load(envMap(nil))
+	if cfg.Quiet.AppliesTo("slack") {
+		t.Error("without quiet hours nothing should be held back")
+	}
+
+	for _, bad := range []string{"22:00", "late", "25:00-08:00", "22:00-08:00,12:00-13:00"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_QUIET_HOURS": bad})); err == nil {
+			t.Errorf("quiet hours %q should be rejected", bad)
+		}
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_QUIET_DESTINATIONS": "email"})); err == nil {
+		t.Error("an unknown notification service should be rejected")
+	}
+}
+
+func TestLoad_YouTubeRateLimit(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.YouTube.RateLimit != DefaultYouTubeRateLimit {
+		t.Errorf("rate limit should default to %d req/s, got %v", DefaultYouTubeRateLimit, cfg.YouTube.RateLimit)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_RATE_LIMIT": "2.5"}))
+	if err != nil || cfg.YouTube.RateLimit != 2.5 {
+		t.Errorf("user-configured rate should be honored, got %v (err %v)", cfg.YouTube.RateLimit, err)
+	}
+
+	for _, bad := range []string{"fast", "-1", "Inf"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_RATE_LIMIT": bad})); err == nil {
+			t.Errorf("rate %q should be rejected", bad)
+		}
+	}
+}
+
+func TestLoad_YouTubeQuotaBudget(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.YouTube.QuotaBudget != DefaultYouTubeQuotaBudget {
+		t.Errorf("quota budget should default to %d units, got %d", DefaultYouTubeQuotaBudget, cfg.YouTube.QuotaBudget)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_QUOTA_BUDGET": "5000"}))
+	if err != nil || cfg.YouTube.QuotaBudget != 5000 {
+		t.Errorf("user-configured budget should be honored, got %d (err %v)", cfg.YouTube.QuotaBudget, err)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_QUOTA_BUDGET": "0"})); err == nil {
+		t.Error("a zero budget should be rejected")
+	}
+
+	cfg, _ = Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_API_KEYS": "key-a, key-b"}))
+	if len(cfg.YouTube.APIKeys) != 2 || cfg.YouTube.QuotaBudget != 3*DefaultYouTubeQuotaBudget {
+		t.Errorf("each API key should add a project's quota to the default budget, got %d keys and %d units", len(cfg.YouTube.APIKeys), cfg.YouTube.QuotaBudget)
+	}
+}
+
+func TestLoad_Concurrency(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.Concurrency != DefaultConcurrency {
+		t.Errorf("concurrency should default to %d, got %d", DefaultConcurrency, cfg.Concurrency)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_CONCURRENCY": "2"}))
+	if err != nil || cfg.Concurrency != 2 {
+		t.Errorf("user-configured concurrency should be honored, got %d (err %v)", cfg.Concurrency, err)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_CONCURRENCY": "500"})); err == nil {
+		t.Errorf("concurrency above %d should be rejected", MaxConcurrency)
+	}
+}
+
+func TestLoad_Locale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want language.Tag
+	}{
+		{nil, language.English},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, language.MustParse("fr-FR")},
+		{map[string]string{"LANG": "C.UTF-8"}, language.English},
+		{map[string]string{"LC_ALL": "de_DE", "LANG": "fr_FR.UTF-8"}, language.MustParse("de-DE")},
+		{map[string]string{"FEEDMIX_LOCALE": "es", "LANG": "fr_FR.UTF-8"}, language.Spanish},
+	}
+	for _, tt := range tests {
+		cfg, err := Load(envMap(tt.env))
+		if err != nil || cfg.Locale != tt.want {
+			t.Errorf("env %v: locale should be %s, got %s (err %v)", tt.env, tt.want, cfg.Locale, err)
+		}
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_LOCALE": "not a locale"})); err == nil {
+		t.Error("an invalid FEEDMIX_LOCALE should be rejected")
+	}
+}
+
+func TestLoad_Region(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{nil, ""},
+		{map[string]string{"LANG": "fr"}, ""},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, "FR"},
+		{map[string]string{"FEEDMIX_REGION": "de", "LANG": "fr_FR.UTF-8"}, "DE"},
+	}
+	for _, tt := range tests {
+		cfg, err := Load(envMap(tt.env))
+		if err != nil || cfg.YouTube.Region != tt.want {
+			t.Errorf("env %v: region should be %q, got %q (err %v)", tt.env, tt.want, cfg.YouTube.Region, err)
+		}
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_REGION": "France"})); err == nil {
+		t.Error("an invalid FEEDMIX_REGION should be rejected")
+	}
+}
+
+func TestLoad_Display(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	want := Display{DescriptionLength: DefaultDescriptionLength, Engagement: true, Hyperlinks: HyperlinksAuto, Color: true}
+	if cfg.Display != want {
+		t.Errorf("display should default to %+v, got %+v", want, cfg.Display)
+	}
+
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SHOW_DESCRIPTION":   "true",
+		"FEEDMIX_DESCRIPTION_LENGTH": "80",
+		"FEEDMIX_TITLE_LENGTH":       "60",
+		"FEEDMIX_WIDTH":              "100",
+		"FEEDMIX_SHOW_ENGAGEMENT":    "false",
+		"FEEDMIX_SHOW_THUMBNAILS":    "1",
+		"FEEDMIX_INLINE_THUMBNAILS":  "Kitty",
+		"FEEDMIX_CALM_TITLES":        "true",
+		"FEEDMIX_HYPERLINKS":         "Never",
+		"FEEDMIX_THEME":              "Vivid",
+		"NO_COLOR":                   "1",
+		"FEEDMIX_COMPACT":            "true",
+		"FEEDMIX_DAY_HEADERS":        "true",
+		"FEEDMIX_GROUP_BY":           "Author",
+	}))
+	want = Display{Description: true, DescriptionLength: 80, TitleLength: 60, Width: 100, Thumbnails: true, InlineThumbnails: ImageProtocolKitty, CalmTitles: true, Hyperlinks: HyperlinksNever, Theme: "vivid", Compact: true, DayHeaders: true, GroupBy: GroupByAuthor}
+	if err != nil || cfg.Display != want {
+		t.Errorf("configured layout should be honored, got %+v (err %v)", cfg.Display, err)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_SHOW_THUMBNAILS": "sometimes"})); err == nil {
+		t.Error("an invalid boolean should be rejected")
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_HYPERLINKS": "sometimes"})); err == nil {
+		t.Error("an invalid hyperlink mode should be rejected")
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_GROUP_BY": "topic"})); err == nil {
+		t.Error("an invalid grouping should be rejected")
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_INLINE_THUMBNAILS": "ascii"})); err == nil {
+		t.Error("an invalid image protocol should be rejected")
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_WIDTH": "wide"})); err == nil {
+		t.Error("an invalid width should be rejected")
+	}
+}
+
+func TestLoad_Pager(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want string
+	}{
+		{nil, DefaultPager},
+		{map[string]string{"PAGER": "more"}, "more"},
+		{map[string]string{"PAGER": "more", "FEEDMIX_PAGER": "less -S"}, "less -S"},
+		{map[string]string{"FEEDMIX_PAGER": "cat"}, ""},
+	} {
+		if cfg, err := Load(envMap(tc.env)); err != nil || cfg.Pager != tc.want {
+			t.Errorf("%v: expected pager %q, got %q (err %v)", tc.env, tc.want, cfg.Pager, err)
+		}
+	}
+}
+
+func TestLoad_FeedCaps(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_SOURCE_LIMITS": "YouTube=30,substack=10", "FEEDMIX_GROUP_LIMITS": "News=10"}))
+	if err != nil || cfg.Caps.Sources["youtube"] != 30 || cfg.Caps.Sources["substack"] != 10 || cfg.Caps.Groups["news"] != 10 {
+		t.Errorf("source and group limits should be parsed, got %+v (err %v)", cfg.Caps, err)
+	}
+
+	for _, env := range []map[string]string{
+		{"FEEDMIX_SOURCE_LIMITS": "twitter=5"},
+		{"FEEDMIX_SOURCE_LIMITS": "youtube=0"},
+		{"FEEDMIX_GROUP_LIMITS": "news"},
+	} {
+		if _, err := Load(envMap(env)); err == nil {
+			t.Errorf("%v: expected an error", env)
+		}
+	}
+}
+
+func TestLoad_CacheTTLs(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.Cache.YouTube != DefaultCacheTTL || cfg.Cache.Substack != DefaultCacheTTL {
+		t.Errorf("cache TTLs should default to %v, got %+v", DefaultCacheTTL, cfg.Cache)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_CACHE_TTL": "30m", "FEEDMIX_SUBSTACK_CACHE_TTL": "0"}))
+	if err != nil || cfg.Cache.YouTube != 30*time.Minute || cfg.Cache.Substack != 0 {
+		t.Errorf("per-source TTLs should be honored, got %+v (err %v)", cfg.Cache, err)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_CACHE_TTL": "soon"})); err == nil {
+		t.Error("an invalid TTL should be rejected")
+	}
+}
+
+func TestLoad_RunRetention(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.Runs != (RunRetention{Keep: DefaultRunsKeep, MaxAge: DefaultRunsMaxAge}) {
+		t.Errorf("run retention should default to %d runs over %v, got %+v", DefaultRunsKeep, DefaultRunsMaxAge, cfg.Runs)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_RUNS_KEEP": "0", "FEEDMIX_RUNS_MAX_AGE": "7d"}))
+	if err != nil || cfg.Runs != (RunRetention{MaxAge: 7 * 24 * time.Hour}) {
+		t.Errorf("configured retention should be honored, with days accepted, got %+v (err %v)", cfg.Runs, err)
+	}
+
+	for _, env := range []map[string]string{{"FEEDMIX_RUNS_KEEP": "-1"}, {"FEEDMIX_RUNS_MAX_AGE": "a month"}} {
+		if _, err := Load(envMap(env)); err == nil {
+			t.Errorf("invalid retention %v should be rejected", env)
+		}
+	}
+}
+
+func TestLoad_TokenStore(t *testing.T) {
+	cfg, _ := Load(envMap(nil))
+	if cfg.TokenStore != TokenStoreFile {
+		t.Errorf("tokens should be stored in files by default, got %q", cfg.TokenStore)
+	}
+
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_TOKEN_STORE": "Keyring"}))
+	if err != nil || cfg.TokenStore != TokenStoreKeyring {
+		t.Errorf("keyring should be selectable, got %q (err %v)", cfg.TokenStore, err)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_TOKEN_STORE": "vault"})); err == nil {
+		t.Error("an unknown token store should be rejected")
+	}
+}
+
+func TestLoad_ParsesSubstackHeadersWithEnvReferences(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_SUBSTACK_HEADERS": "https://paid.substack.com/ Cookie: substack.sid=${SUBSTACK_SID}, https://paid.substack.com X-Region: eu",
+		"SUBSTACK_SID":             "s%3Asecret",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	headers := cfg.Substack.HeadersFor("https://paid.substack.com")
+	if got := headers.Get("Cookie"); got != "substack.sid=s%3Asecret" {
+		t.Errorf("cookie should be expanded from the environment, got %q", got)
+	}
+	if got := headers.Get("X-Region"); got != "eu" {
+		t.Errorf("every header for the publication should be kept, got %q", got)
+	}
+	if cfg.Substack.HeadersFor("https://free.substack.com") != nil {
+		t.Error("publications without headers should get none")
+	}
+
+	for _, bad := range []string{"https://paid.substack.com", "https://paid.substack.com Cookie", "https://paid.substack.com Cookie: $UNSET"} {
+		if _, err := Load(envMap(map[string]string{"FEEDMIX_SUBSTACK_HEADERS": bad})); err == nil {
+			t.Errorf("entry %q should be rejected", bad)
+		}
+	}
+}
+
+func TestLoad_ResurfaceUpdated(t *testing.T) {
+	if cfg, _ := Load(envMap(nil)); cfg.ResurfaceUpdated {
+		t.Error("edited items should not be resurfaced by default")
+	}
+	if cfg, err := Load(envMap(map[string]string{"FEEDMIX_RESURFACE_UPDATED": "true"})); err != nil || !cfg.ResurfaceUpdated {
+		t.Errorf("resurfacing should be opt-in, got %v (err %v)", cfg.ResurfaceUpdated, err)
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_RESURFACE_UPDATED": "sometimes"})); err == nil {
+		t.Error("a non-boolean value should be rejected")
+	}
+}
+
+func TestLoad_ArchiveSaved(t *testing.T) {
+	if cfg, _ := Load(envMap(nil)); cfg.ArchiveSaved {
+		t.Error("saved items should not be archived by default")
+	}
+	if cfg, err := Load(envMap(map[string]string{"FEEDMIX_ARCHIVE_SAVED": "true"})); err != nil || !cfg.ArchiveSaved {
+		t.Errorf("archiving should be opt-in, got %v (err %v)", cfg.ArchiveSaved, err)
+	}
+}
+
+func TestLoad_YouTubeGroups(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_GROUPS": "UC_A=Tech, UC_B=chill"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.YouTube.Groups["UC_A"] != "tech" || cfg.YouTube.Groups["UC_B"] != "chill" {
+		t.Errorf("channel groups should be parsed and lowercased, got %v", cfg.YouTube.Groups)
+	}
+
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_GROUPS": "UC_A"})); err == nil {
+		t.Error("an entry without a group should be rejected")
+	}
+}
+
+func TestLoad_YouTubeAccounts(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_ACCOUNTS": "personal, work"}))
+	if err != nil || len(cfg.YouTube.Accounts) != 2 || cfg.YouTube.Accounts[1] != "work" {
+		t.Errorf("named accounts should be listed in order, got %q (err %v)", cfg.YouTube.Accounts, err)
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_ACCOUNTS": "../evil"})); err == nil {
+		t.Error("account names that could escape the token directory should be rejected")
+	}
+}
+
+func TestLoad_YouTubeChannels(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_CHANNELS": "UCsBjURrPoezykLs9EqgamOA, UCHnyfMqiRRG1u-2MsSQLbXA"}))
+	if err != nil || len(cfg.YouTube.Channels) != 2 || cfg.YouTube.Channels[1] != "UCHnyfMqiRRG1u-2MsSQLbXA" {
+		t.Errorf("channel IDs should be listed in order, got %q (err %v)", cfg.YouTube.Channels, err)
+	}
+	if _, err := Load(envMap(map[string]string{"FEEDMIX_YOUTUBE_CHANNELS": "@fireship"})); err == nil {
+		t.Error("a handle should be rejected: feeds need channel IDs")
+	}
+}
+
+func TestDump_RedactsSecretsAndNamesFieldsInSnakeCase(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_YOUTUBE_CLIENT_ID":     "client-id",
+		"FEEDMIX_YOUTUBE_CLIENT_SECRET": "client-secret",
+		"FEEDMIX_SUBSTACK_URLS":         "https://paid.substack.com",
+		"FEEDMIX_SUBSTACK_HEADERS":      "https://paid.substack.com Cookie: substack.sid=top-secret",
+		"FEEDMIX_YOUTUBE_CACHE_TTL":     "1h",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dump := Dump(cfg)
+
+	youtube := dump["youtube"].(map[string]any)
+	if youtube["client_id"] != "client-id" || youtube["client_secret"] != Redacted || youtube["refresh_token"] != "" {
+		t.Errorf("secrets should be redacted when set and other values kept, got %v", youtube)
+	}
+	headers := dump["substack"].(map[string]any)["headers"].(map[string]any)["https://paid.substack.com"].(map[string]any)
+	if got := headers["Cookie"].([]any); len(got) != 1 || got[0] != Redacted {
+		t.Errorf("header names should be kept and their values redacted, got %v", headers)
+	}
+	if got := dump["cache"].(map[string]any)["youtube"]; got != "1h0m0s" {
+		t.Errorf("durations should be written as text, got %v", got)
+	}
+	if got := dump["display"].(map[string]any)["description_length"]; got != int64(DefaultDescriptionLength) {
+		t.Errorf("defaults should be included, got %v", got)
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata with the current output")
+
+// TestDump_MatchesGolden snapshots the dump of a configuration with the
+// sources whose names are easily mangled set, so a renamed or missing key
+// shows up as a diff. After an intended
+// change, rewrite the snapshot with:
+//
+//	go test ./internal/config -run Golden -update
+func TestDump_MatchesGolden(t *testing.T) {
+	cfg, err := Load(envMap(map[string]string{
+		"FEEDMIX_CONFIG_DIR":           "/home/me/.config/feedmix",
+		"FEEDMIX_CACHE_DIR":            "/home/me/.cache/feedmix",
+		"FEEDMIX_GITHUB_REPOS":         "golang/go",
+		"FEEDMIX_GITHUB_STARRED":       "octocat",
+		"FEEDMIX_GITHUB_TOKEN":         "ghp_secret",
+		"FEEDMIX_GITHUB_FETCH_LIMIT":   "3",
+		"FEEDMIX_GITHUB_CACHE_TTL":     "1h",
+		"FEEDMIX_PEERTUBE_CHANNELS":    "blender@video.blender.org",
+		"FEEDMIX_PEERTUBE_FETCH_LIMIT": "10",
+		"FEEDMIX_PEERTUBE_CACHE_TTL":   "30m",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := json.MarshalIndent(Dump(cfg), "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := string(data) + "\n"
+
+	path := filepath.Join("testdata", "dump.json.golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path) // #nosec G304 - path is a golden file of this package
+	if err != nil {
+		t.Fatalf("%v: run 'go test ./internal/config -run Golden -update' to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("dump differs from %s (rerun with -update if intended):\n%s", path, got)
+	}
+}
+
+func TestCheckEnvironment_ReportsUnknownSettings(t *testing.T) {
+	env := map[string]string{
+		"FEEDMIX_SUBSTACK_URL":     "https://example.substack.com",
+		"FEEDMIX_SUBSTACK_HEADERS": "https://paid.substack.com Cookie: $FEEDMIX_PAID_COOKIE",
+		"FEEDMIX_PAID_COOKIE":      "sid=abc",
+		"FEEDMIX_COMPACT":          "true",
+	}
+	cfg, err := Load(envMap(env))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var environ []string
+	for name, value := range env {
+		environ = append(environ, name+"="+value)
+	}
+	environ = append(environ, "HOME=/home/me")
+
+	err = cfg.CheckEnvironment(environ)
+	if err == nil || !strings.Contains(err.Error(), "FEEDMIX_SUBSTACK_URL (did you mean FEEDMIX_SUBSTACK_URLS?)") {
+		t.Errorf("a misspelled setting should be reported with the closest name, got %v", err)
+	}
+	if err != nil && (strings.Contains(err.Error(), "PAID_COOKIE") || strings.Contains(err.Error(), "COMPACT") || strings.Contains(err.Error(), "HOME")) {
+		t.Errorf("settings, variables referenced by headers and other variables should not be reported, got %v", err)
+	}
+	if err := cfg.CheckEnvironment([]string{"FEEDMIX_COMPACT=true"}); err != nil {
+		t.Errorf("known settings should pass, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// Redacted replaces the value of secret settings in Dump.
+const Redacted = "<redacted>"
+
+// Dump returns cfg as nested maps keyed by snake_case field names, for
+// printing as JSON or YAML. Durations and locales are written as text, and
+// the values of fields tagged `dump:",secret"` (credentials, request
+// headers) are replaced by Redacted when set. A `dump:"name"` tag renames
+// a field.
+func Dump(cfg Config) map[string]any {
+	return dumpValue(reflect.ValueOf(cfg), false).(map[string]any)
+}
+
+func dumpValue(v reflect.Value, secret bool) any {
+	switch x := v.Interface().(type) {
+	case time.Duration:
+		return x.String()
+	case language.Tag:
+		return x.String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("dump"), ",")
+			if name == "" {
+				name = snakeCase(field.Name)
+			}
+			fields[name] = dumpValue(v.Field(i), secret || opts == "secret")
+		}
+		return fields
+	case reflect.Map:
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[fmt.Sprint(iter.Key().Interface())] = dumpValue(iter.Value(), secret)
+		}
+		return entries
+	case reflect.Slice:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = dumpValue(v.Index(i), secret)
+		}
+		return items
+	case reflect.String:
+		if secret && v.String() != "" {
+			return Redacted
+		}
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// snakeCase turns a Go field name into snake_case, keeping acronyms
+// together: ClientID becomes client_id and YouTube youtube.
+func snakeCase(name string) string {
+	name = strings.ReplaceAll(name, "YouTube", "Youtube")
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
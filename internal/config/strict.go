@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// envPrefix starts the name of every feedmix environment variable.
+const envPrefix = "FEEDMIX_"
+
+// recordReads wraps getenv to note each variable read in read.
+func recordReads(getenv func(string) string, read map[string]bool) func(string) string {
+	return func(name string) string {
+		read[name] = true
+		return getenv(name)
+	}
+}
+
+// CheckEnvironment reports the FEEDMIX_ variables set in environ (as
+// returned by os.Environ) that Load didn't read, such as a misspelled
+// FEEDMIX_SUBSTACK_URL, with the closest setting's name when one is
+// similar. Variables referenced from FEEDMIX_SUBSTACK_HEADERS count as read.
+func (c Config) CheckEnvironment(environ []string) error {
+	var unknown []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(name, envPrefix) && !c.read[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	known := make([]string, 0, len(c.read))
+	for name := range c.read {
+		if strings.HasPrefix(name, envPrefix) {
+			known = append(known, name)
+		}
+	}
+	sort.Strings(known)
+	for i, name := range unknown {
+		if closest := closestName(name, known); closest != "" {
+			unknown[i] += " (did you mean " + closest + "?)"
+		}
+	}
+	if len(unknown) == 1 {
+		return fmt.Errorf("unknown setting %s: feedmix doesn't read it", unknown[0])
+	}
+	return fmt.Errorf("unknown settings %s: feedmix doesn't read them", strings.Join(unknown, ", "))
+}
+
+// closestName returns the name in names within a few edits of name, or
+// "" if none is.
+func closestName(name string, names []string) string {
+	best, bestDistance := "", 4
+	for _, candidate := range names {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,161 @@
+// Package delivery remembers which new items each notification destination
+// has received, so an item reaches a destination once even when a run is
+// repeated, and an item a destination failed to take is retried next run.
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// retention is how long a delivery is remembered, as long as the item
+// history remembers the item itself.
+const retention = 90 * 24 * time.Hour
+
+// retryFor is how long an item a destination keeps refusing is retried.
+const retryFor = 7 * 24 * time.Hour
+
+// Store maps each destination, such as "slack", to what it was sent.
+type Store struct {
+	path         string
+	destinations map[string]*destination
+	now          clock.Clock
+}
+
+type destination struct {
+	// Delivered maps the key of each item the destination accepted to when.
+	Delivered map[string]time.Time `json:"delivered"`
+	// Pending are the items still to send, oldest first.
+	Pending []pending `json:"pending,omitempty"`
+}
+
+type pending struct {
+	Item     aggregator.FeedItem `json:"item"`
+	QueuedAt time.Time           `json:"queued_at"`
+}
+
+// Open loads the store at path, recording times read from now; a missing
+// file yields an empty store.
+func Open(path string, now clock.Clock) (*Store, error) {
+	s := &Store{path: path, destinations: make(map[string]*destination), now: now}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the delivery file in the user's config directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification deliveries: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.destinations); err != nil {
+		return nil, fmt.Errorf("failed to parse notification deliveries: %w", err)
+	}
+	return s, nil
+}
+
+// Queue adds the items the destination to hasn't received and isn't due to
+// receive, and returns every item pending for it, oldest first.
+func (s *Store) Queue(to string, items []aggregator.FeedItem) []aggregator.FeedItem {
+	d := s.destination(to)
+	now := s.now().UTC()
+	for _, item := range items {
+		key := itemKey(item)
+		if _, ok := d.Delivered[key]; ok {
+			continue
+		}
+		if slices.ContainsFunc(d.Pending, func(p pending) bool { return itemKey(p.Item) == key }) {
+			continue
+		}
+		d.Pending = append(d.Pending, pending{Item: item, QueuedAt: now})
+	}
+	slices.SortStableFunc(d.Pending, func(a, b pending) int { return a.Item.PublishedAt.Compare(b.Item.PublishedAt) })
+
+	queued := make([]aggregator.FeedItem, len(d.Pending))
+	for i, p := range d.Pending {
+		queued[i] = p.Item
+	}
+	return queued
+}
+
+// Due reports whether the items pending for to have waited window since
+// the first of them was queued, so a batch of them can be sent.
+func (s *Store) Due(to string, window time.Duration) bool {
+	d := s.destination(to)
+	if len(d.Pending) == 0 {
+		return false
+	}
+	first := d.Pending[0].QueuedAt
+	for _, p := range d.Pending[1:] {
+		if p.QueuedAt.Before(first) {
+			first = p.QueuedAt
+		}
+	}
+	return !s.now().Before(first.Add(window))
+}
+
+// QueuedDuring reports whether an item pending for to was queued at a time
+// during reports as within, such as quiet hours.
+func (s *Store) QueuedDuring(to string, during func(time.Time) bool) bool {
+	return slices.ContainsFunc(s.destination(to).Pending, func(p pending) bool { return during(p.QueuedAt.In(s.now().Location())) })
+}
+
+// Delivered records that to accepted the items, so they are neither queued
+// nor sent to it again.
+func (s *Store) Delivered(to string, items ...aggregator.FeedItem) {
+	d := s.destination(to)
+	now := s.now().UTC()
+	for _, item := range items {
+		key := itemKey(item)
+		d.Delivered[key] = now
+		d.Pending = slices.DeleteFunc(d.Pending, func(p pending) bool { return itemKey(p.Item) == key })
+	}
+}
+
+func (s *Store) destination(name string) *destination {
+	d, ok := s.destinations[name]
+	if !ok {
+		d = &destination{}
+		s.destinations[name] = d
+	}
+	if d.Delivered == nil {
+		d.Delivered = make(map[string]time.Time)
+	}
+	return d
+}
+
+func itemKey(item aggregator.FeedItem) string {
+	return string(item.Source) + ":" + item.ID
+}
+
+// Save writes the store back to disk, giving up on items pending for 7
+// days and forgetting deliveries older than 90 days.
+func (s *Store) Save() error {
+	now := s.now()
+	for _, d := range s.destinations {
+		for key, at := range d.Delivered {
+			if at.Before(now.Add(-retention)) {
+				delete(d.Delivered, key)
+			}
+		}
+		d.Pending = slices.DeleteFunc(d.Pending, func(p pending) bool { return p.QueuedAt.Before(now.Add(-retryFor)) })
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create deliveries directory: %w", err)
+	}
+	data, err := json.Marshal(s.destinations)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification deliveries: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write notification deliveries: %w", err)
+	}
+	return nil
+}
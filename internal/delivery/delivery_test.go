@@ -0,0 +1,96 @@
+package delivery
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func video(id string, published time.Time) aggregator.FeedItem {
+	return aggregator.FeedItem{ID: id, Source: aggregator.SourceYouTube, Title: "Video " + id, PublishedAt: published}
+}
+
+// TestStore_SendsEachItemOncePerDestination documents delivery tracking:
+//   - queued items are returned oldest first
+//   - an item a destination accepted is never queued for it again
+//   - an item it didn't accept stays pending across runs
+//   - destinations are tracked separately
+func TestStore_SendsEachItemOncePerDestination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	older, newer := video("a", now.Add(-time.Hour)), video("b", now)
+
+	store, err := Open(path, clock.Fixed(now))
+	if err != nil {
+		t.Fatalf("missing deliveries should open empty, got: %v", err)
+	}
+	queued := store.Queue("slack", []aggregator.FeedItem{newer, older})
+	if len(queued) != 2 || queued[0].ID != "a" {
+		t.Fatalf("expected both items queued oldest first, got %+v", queued)
+	}
+	store.Delivered("slack", older)
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ = Open(path, clock.Fixed(now.Add(time.Hour)))
+	queued = store.Queue("slack", []aggregator.FeedItem{older})
+	if len(queued) != 1 || queued[0].ID != "b" {
+		t.Errorf("only the undelivered item should be retried, got %+v", queued)
+	}
+	if queued := store.Queue("discord", []aggregator.FeedItem{older}); len(queued) != 1 || queued[0].ID != "a" {
+		t.Errorf("another destination should still get the item, got %+v", queued)
+	}
+}
+
+func TestStore_GivesUpOnItemsPendingForAWeek(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	store, _ := Open(path, clock.Fixed(now))
+	store.Queue("slack", []aggregator.FeedItem{video("a", now)})
+	store.now = clock.Fixed(now.Add(retryFor + time.Hour))
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ = Open(path, clock.Fixed(now.Add(retryFor+time.Hour)))
+	if queued := store.Queue("slack", nil); len(queued) != 0 {
+		t.Errorf("an item pending for more than a week should be dropped, got %+v", queued)
+	}
+}
+
+func TestStore_QueuedDuringReportsItemsHeldBack(t *testing.T) {
+	night := time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)
+	store, _ := Open(filepath.Join(t.TempDir(), "deliveries.json"), clock.Fixed(night))
+	atNight := func(t time.Time) bool { return t.Hour() >= 22 }
+	if store.QueuedDuring("ntfy", atNight) {
+		t.Error("nothing was queued yet")
+	}
+	store.Queue("ntfy", []aggregator.FeedItem{video("a", night)})
+	if !store.QueuedDuring("ntfy", atNight) || store.QueuedDuring("ntfy", func(time.Time) bool { return false }) {
+		t.Error("expected the item reported as queued at night only")
+	}
+}
+
+func TestStore_DueOnceTheFirstPendingItemWaitedTheWindow(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	store, _ := Open(filepath.Join(t.TempDir(), "deliveries.json"), clock.Fixed(now))
+	if store.Due("slack", 0) {
+		t.Error("nothing is due without pending items")
+	}
+	store.Queue("slack", []aggregator.FeedItem{video("a", now)})
+
+	store.now = clock.Fixed(now.Add(20 * time.Minute))
+	store.Queue("slack", []aggregator.FeedItem{video("b", now)})
+	if store.Due("slack", 30*time.Minute) {
+		t.Error("items shouldn't be due before the first waited the window")
+	}
+	store.now = clock.Fixed(now.Add(30 * time.Minute))
+	if !store.Due("slack", 30*time.Minute) {
+		t.Error("items should be due once the first waited the window, whatever came after")
+	}
+}
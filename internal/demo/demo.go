@@ -0,0 +1,50 @@
+// Package demo provides a sample feed, bundled with feedmix, for trying it
+// without credentials or network access.
+package demo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+//go:embed items.json
+var fixture []byte
+
+// feed is the sample feed as it was when recorded.
+type feed struct {
+	RecordedAt time.Time             `json:"recorded_at"`
+	Items      []aggregator.FeedItem `json:"items"`
+}
+
+// Items returns the sample feed as if it had been fetched at now: every
+// date is moved by the time since it was recorded, so items keep their
+// ages, trends and broadcast countdowns.
+func Items(now time.Time) ([]aggregator.FeedItem, error) {
+	var f feed
+	if err := json.Unmarshal(fixture, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse the sample feed: %w", err)
+	}
+	shift := now.Sub(f.RecordedAt)
+	move := func(t time.Time) time.Time {
+		if t.IsZero() {
+			return t
+		}
+		return t.Add(shift)
+	}
+	for i := range f.Items {
+		item := &f.Items[i]
+		item.PublishedAt = move(item.PublishedAt)
+		item.UpdatedAt = move(item.UpdatedAt)
+		for j := range item.Trend {
+			item.Trend[j].At = move(item.Trend[j].At)
+		}
+		if b := item.Broadcast; b != nil {
+			b.Start, b.End = move(b.Start), move(b.End)
+		}
+	}
+	return f.Items, nil
+}
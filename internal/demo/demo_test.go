@@ -0,0 +1,30 @@
+package demo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItems_AreDatedRelativeToNow(t *testing.T) {
+	now := time.Date(2030, 6, 1, 9, 0, 0, 0, time.UTC)
+	items, err := Items(now)
+	if err != nil {
+		t.Fatalf("the bundled sample feed should parse: %v", err)
+	}
+	if len(items) == 0 {
+		t.Fatal("the sample feed should have items")
+	}
+	for _, item := range items {
+		if age := now.Sub(item.PublishedAt); age < 0 || age > 7*24*time.Hour {
+			t.Errorf("%s should be published within the last week, got %v ago", item.ID, age)
+		}
+		for _, sample := range item.Trend {
+			if sample.At.After(now) || sample.At.Before(item.PublishedAt) {
+				t.Errorf("%s: trend sample at %v should be between its publication and now", item.ID, sample.At)
+			}
+		}
+		if b := item.Broadcast; b != nil && b.Start.Year() != now.Year() {
+			t.Errorf("%s: broadcast start %v should move with the feed", item.ID, b.Start)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package discord provides a client for Discord webhooks, posting messages
+// with embeds to a server's channel.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxEmbeds is the most embeds Discord accepts in one message.
+const MaxEmbeds = 10
+
+// MaxEmbedsLength is the most characters Discord accepts across the embeds
+// of one message, as counted by Embed.Length.
+const MaxEmbedsLength = 6000
+
+// Message is the payload of a webhook.
+type Message struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+}
+
+// Embed is a rich card in a message.
+type Embed struct {
+	Title       string  `json:"title"`
+	URL         string  `json:"url,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Color       int     `json:"color,omitempty"`
+	Author      *Author `json:"author,omitempty"`
+	Thumbnail   *Image  `json:"thumbnail,omitempty"`
+	Fields      []Field `json:"fields,omitempty"`
+	Footer      *Footer `json:"footer,omitempty"`
+	// Timestamp is shown next to the footer; zero leaves it out.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// Length returns the characters of e that count toward MaxEmbedsLength:
+// its title, description, author, field names and values, and footer.
+func (e Embed) Length() int {
+	n := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	if e.Author != nil {
+		n += utf8.RuneCountInString(e.Author.Name)
+	}
+	for _, f := range e.Fields {
+		n += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if e.Footer != nil {
+		n += utf8.RuneCountInString(e.Footer.Text)
+	}
+	return n
+}
+
+// Author is the name heading an embed.
+type Author struct {
+	Name string `json:"name"`
+}
+
+// Image is an image shown in an embed.
+type Image struct {
+	URL string `json:"url"`
+}
+
+// Field is a name and value shown in an embed; inline fields share a row.
+type Field struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Footer is the small text at the bottom of an embed.
+type Footer struct {
+	Text string `json:"text"`
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Client posts to webhooks.
+type Client struct {
+	httpClient HTTPClient
+}
+
+// NewClient creates a new Discord client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Post sends msg to the webhook at webhookURL. Messages with more than
+// MaxEmbeds embeds are rejected by Discord.
+func (c *Client) Post(ctx context.Context, webhookURL string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode Discord message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		var apiErr struct {
+			Message    string  `json:"message"`
+			RetryAfter float64 `json:"retry_after"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0:
+			return fmt.Errorf("discord webhook is rate limited, retry in %.1fs", apiErr.RetryAfter)
+		case apiErr.Message != "":
+			return fmt.Errorf("discord webhook returned HTTP %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("discord webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
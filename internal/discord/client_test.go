@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestClient_Post documents posting to a webhook:
+//   - the message goes as JSON, with its embeds
+//   - empty parts of an embed are left out
+//   - Discord's 204 No Content counts as success
+func TestClient_Post(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	published := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	msg := Message{Content: "2 new items", Embeds: []Embed{{
+		Title: "Go", URL: "https://example.com", Color: 0xFF0000, Timestamp: &published,
+		Fields: []Field{{Name: "Views", Value: "1,200", Inline: true}},
+	}}}
+	if err := NewClient().Post(context.Background(), server.URL, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	embed := got["embeds"].([]any)[0].(map[string]any)
+	if embed["color"] != float64(0xFF0000) || embed["timestamp"] != "2024-01-15T12:00:00Z" || len(embed["fields"].([]any)) != 1 {
+		t.Errorf("unexpected embed: %v", embed)
+	}
+	if _, ok := embed["thumbnail"]; ok {
+		t.Errorf("an embed without a thumbnail shouldn't send one: %v", embed)
+	}
+}
+
+func TestClient_Post_ReportsWebhookErrors(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		body   string
+		want   string
+	}{
+		{http.StatusNotFound, `{"message":"Unknown Webhook","code":10015}`, "Unknown Webhook"},
+		{http.StatusTooManyRequests, `{"message":"You are being rate limited.","retry_after":1.5}`, "retry in 1.5s"},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			fmt.Fprint(w, tc.body)
+		}))
+		err := NewClient().Post(context.Background(), server.URL, Message{Content: "hi"})
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("HTTP %d: expected an error mentioning %q, got %v", tc.status, tc.want, err)
+		}
+	}
+}
+
+func TestEmbed_LengthCountsTheTextDiscordLimits(t *testing.T) {
+	embed := Embed{
+		Title: "Café", Description: "New video", URL: "https://example.com/not-counted",
+		Author: &Author{Name: "Tech"}, Fields: []Field{{Name: "Views", Value: "1,000"}}, Footer: &Footer{Text: "youtube"},
+	}
+	if got := embed.Length(); got != 4+9+4+5+5+7 {
+		t.Errorf("expected the title, description, author, field and footer characters, got %d", got)
+	}
+}
@@ -0,0 +1,276 @@
+package display
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// FormatArticle formats the full text of item for reading in the terminal:
+// its header as in the feed, then its content, or its description when the
+// feed carries no content. Paragraphs, headings, lists, quotes and code
+// blocks keep their shape and wrap to the formatter's width; links are
+// numbered and listed at the end.
+func (f *TerminalFormatter) FormatArticle(item aggregator.FeedItem) string {
+	header := *f
+	header.descriptionLength = 0
+	header.showThumbnails = false
+	header.showDetails = false
+
+	body := item.Content
+	if strings.TrimSpace(body) == "" {
+		body = item.Description
+		if !htmlTag.MatchString(body) {
+			body = strings.ReplaceAll(html.EscapeString(body), "\n", "<br>")
+		}
+	}
+	r := &articleRenderer{width: f.width, theme: f.theme}
+	r.render(body)
+
+	var b strings.Builder
+	b.WriteString(header.FormatItem(item))
+	for i, block := range r.blocks {
+		if i == 0 || !block.tight || !r.blocks[i-1].tight {
+			b.WriteString("\n")
+		}
+		b.WriteString(block.text + "\n")
+	}
+	if len(r.links) > 0 {
+		b.WriteString("\n")
+		for i, link := range r.links {
+			b.WriteString(paint(f.theme.URL, fmt.Sprintf("[%d] %s", i+1, link)) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// articleBlock is a rendered paragraph, heading or list item. Consecutive
+// tight blocks, the items of a list, aren't separated by a blank line.
+type articleBlock struct {
+	text  string
+	tight bool
+}
+
+type articleList struct {
+	ordered bool
+	n       int
+}
+
+// articleRenderer turns article HTML into text blocks. It is not a full
+// HTML parser, but newsletters stick to simple, well-formed markup.
+type articleRenderer struct {
+	width int
+	theme Theme
+
+	blocks  []articleBlock
+	links   []string
+	text    strings.Builder
+	lists   []articleList
+	marker  string
+	heading int
+	quote   int
+	pre     int
+	skip    int
+	href    string
+}
+
+var htmlTag = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*[\s/>]`)
+
+var tagAttr = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// skippedTags hold no text worth reading: scripts, styles, and the
+// subscribe buttons and forms newsletters embed.
+var skippedTags = map[string]bool{"script": true, "style": true, "button": true, "form": true, "svg": true}
+
+func (r *articleRenderer) render(s string) {
+	for s != "" {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			r.write(s)
+			break
+		}
+		r.write(s[:start])
+		s = s[start:]
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+3:]
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			r.write(s)
+			break
+		}
+		r.tag(s[1:end])
+		s = s[end+1:]
+	}
+	r.flush()
+}
+
+// write adds text from between tags to the pending block.
+func (r *articleRenderer) write(s string) {
+	if r.skip > 0 || s == "" {
+		return
+	}
+	s = html.UnescapeString(s)
+	if r.pre == 0 {
+		s = strings.ReplaceAll(s, "\n", " ")
+	}
+	r.text.WriteString(s)
+}
+
+// tag handles the tag between < and >, such as `a href="..."` or `/p`.
+func (r *articleRenderer) tag(s string) {
+	closing := strings.HasPrefix(s, "/")
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "/"), "/")
+	name, attrs := s, ""
+	if i := strings.IndexAny(s, " \t\r\n"); i >= 0 {
+		name, attrs = s[:i], s[i:]
+	}
+	name = strings.ToLower(name)
+	attr := func(key string) string {
+		for _, m := range tagAttr.FindAllStringSubmatch(attrs, -1) {
+			if strings.EqualFold(m[1], key) {
+				return html.UnescapeString(m[2] + m[3] + m[4])
+			}
+		}
+		return ""
+	}
+
+	if skippedTags[name] {
+		if closing {
+			r.skip = max(r.skip-1, 0)
+		} else {
+			r.skip++
+		}
+		return
+	}
+	if r.skip > 0 {
+		return
+	}
+
+	switch name {
+	case "p", "div", "section", "article", "header", "footer", "figure", "figcaption", "table", "tr":
+		r.flush()
+	case "br":
+		r.text.WriteString("\n")
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.flush()
+		if closing {
+			r.heading = 0
+		} else {
+			r.heading = int(name[1] - '0')
+		}
+	case "ul", "ol":
+		r.flush()
+		if closing {
+			if len(r.lists) > 0 {
+				r.lists = r.lists[:len(r.lists)-1]
+			}
+		} else {
+			r.lists = append(r.lists, articleList{ordered: name == "ol"})
+		}
+	case "li":
+		r.flush()
+		if !closing && len(r.lists) > 0 {
+			list := &r.lists[len(r.lists)-1]
+			list.n++
+			r.marker = "• "
+			if list.ordered {
+				r.marker = fmt.Sprintf("%d. ", list.n)
+			}
+		}
+	case "blockquote":
+		r.flush()
+		if closing {
+			r.quote = max(r.quote-1, 0)
+		} else {
+			r.quote++
+		}
+	case "pre":
+		r.flush()
+		if closing {
+			r.pre = max(r.pre-1, 0)
+		} else {
+			r.pre++
+		}
+	case "hr":
+		r.flush()
+		r.blocks = append(r.blocks, articleBlock{text: "* * *"})
+	case "a":
+		if !closing {
+			r.href = attr("href")
+		} else if r.href != "" {
+			if strings.HasPrefix(r.href, "http://") || strings.HasPrefix(r.href, "https://") {
+				r.links = append(r.links, r.href)
+				fmt.Fprintf(&r.text, " [%d]", len(r.links))
+			}
+			r.href = ""
+		}
+	case "img":
+		if alt := strings.TrimSpace(attr("alt")); alt != "" {
+			fmt.Fprintf(&r.text, " [image: %s] ", alt)
+		} else {
+			r.text.WriteString(" [image] ")
+		}
+	}
+}
+
+// flush ends the pending block, wrapping it to the width after its quote
+// bars and list indentation.
+func (r *articleRenderer) flush() {
+	text := r.text.String()
+	r.text.Reset()
+
+	var lines []string
+	if r.pre > 0 {
+		lines = strings.Split(strings.Trim(text, "\n"), "\n")
+	} else {
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	if len(lines) == 0 || (len(lines) == 1 && strings.TrimSpace(lines[0]) == "") {
+		return
+	}
+	marker := r.marker
+	r.marker = ""
+
+	prefix := strings.Repeat("│ ", r.quote)
+	if len(r.lists) > 1 {
+		prefix += strings.Repeat("  ", len(r.lists)-1)
+	}
+	if r.heading > 0 {
+		marker = strings.Repeat("#", r.heading) + " "
+	}
+	indent := strings.Repeat(" ", columns(marker))
+
+	var out []string
+	for i, line := range lines {
+		wrapped := []string{line}
+		if r.width > 0 && r.pre == 0 {
+			room := r.width - columns(prefix) - columns(marker)
+			wrapped = wrap(line, room, room)
+		}
+		for j, w := range wrapped {
+			lead := indent
+			if i == 0 && j == 0 {
+				lead = marker
+			}
+			out = append(out, prefix+lead+w)
+		}
+	}
+	text = strings.Join(out, "\n")
+	if r.heading > 0 {
+		text = paint(r.theme.Title, text)
+	}
+	r.blocks = append(r.blocks, articleBlock{text: text, tight: len(r.lists) > 0})
+}
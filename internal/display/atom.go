@@ -0,0 +1,130 @@
+package display
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// atomFeed is an Atom document (RFC 4287).
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// WriteAtom writes items to w as an Atom feed titled title, for publishing
+// as a static file. Each entry is categorized by its source (and group),
+// and the feed is as recent as its newest item, or now when it has none.
+func WriteAtom(w io.Writer, title string, items []aggregator.FeedItem, now time.Time) error {
+	feed := atomFeed{ID: "urn:feedmix:feed", Title: title, Author: atomPerson{Name: "feedmix"}}
+	var newest time.Time
+	for _, item := range items {
+		if updated := itemUpdated(item); updated.After(newest) {
+			newest = updated
+		}
+	}
+	if newest.IsZero() {
+		newest = now
+	}
+	feed.Updated = atomTime(newest)
+
+	for _, item := range items {
+		entry := atomEntry{
+			ID:         "urn:feedmix:" + string(item.Source) + ":" + item.ID,
+			Title:      item.Title,
+			Updated:    feed.Updated,
+			Categories: []atomCategory{{Term: string(item.Source)}},
+		}
+		if updated := itemUpdated(item); !updated.IsZero() {
+			entry.Updated = atomTime(updated)
+		}
+		if !item.PublishedAt.IsZero() {
+			entry.Published = atomTime(item.PublishedAt)
+		}
+		if item.URL != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "alternate", Href: item.URL})
+		}
+		if audio := item.Audio; audio != nil {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Href: audio.URL, Type: audio.Type, Length: audio.Length})
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		if item.Group != "" {
+			entry.Categories = append(entry.Categories, atomCategory{Term: item.Group})
+		}
+		if item.Description != "" {
+			entry.Summary = &atomText{Type: "text", Body: item.Description}
+			if htmlDescription(item.Source) {
+				entry.Summary.Type = "html"
+			}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// htmlDescription reports whether source's descriptions are HTML, as feed
+// entries are, rather than plain text like YouTube's.
+func htmlDescription(source aggregator.Source) bool {
+	return source != aggregator.SourceYouTube
+}
+
+// itemUpdated is when item last changed: its edit time, else its publication.
+func itemUpdated(item aggregator.FeedItem) time.Time {
+	if item.UpdatedAt.After(item.PublishedAt) {
+		return item.UpdatedAt
+	}
+	return item.PublishedAt
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,58 @@
+package display
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// paywallLock marks the titles of posts only paid subscribers can read in full.
+const paywallLock = "🔒 "
+
+// badge labels an item whose page is gone, else its broadcast.
+func (f *TerminalFormatter) badge(item aggregator.FeedItem) string {
+	if item.Gone != "" {
+		return "Gone: " + item.Gone
+	}
+	return f.broadcastBadge(item)
+}
+
+// broadcastBadge labels a premiere or live stream: "LIVE" while on air,
+// "Premieres in 2h" or "Live in 2h" until it starts. Other items have none.
+func (f *TerminalFormatter) broadcastBadge(item aggregator.FeedItem) string {
+	b := item.Broadcast
+	switch {
+	case b == nil:
+		return ""
+	case b.Status == aggregator.BroadcastLive:
+		return "LIVE"
+	case b.Start.IsZero() && b.Premiere:
+		return "Upcoming premiere"
+	case b.Start.IsZero():
+		return "Upcoming live stream"
+	}
+	wait := b.Start.Sub(f.now())
+	switch {
+	case wait <= 0 && b.Premiere:
+		return "Premiering soon"
+	case wait <= 0:
+		return "Going live soon"
+	case b.Premiere:
+		return "Premieres in " + countdown(wait)
+	default:
+		return "Live in " + countdown(wait)
+	}
+}
+
+// countdown is a short duration until an event: "15m", "2h", "3d".
+func countdown(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(int(d.Minutes()), 1))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
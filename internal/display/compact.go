@@ -0,0 +1,130 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// Column widths of the compact layout, in characters.
+const (
+	compactTimeWidth   = 6
+	compactAuthorWidth = 20
+	compactTitleWidth  = 70
+)
+
+// sourceIcons mark each source in the compact layout; unlisted sources use
+// the first letter of their name.
+var sourceIcons = map[aggregator.Source]string{
+	aggregator.SourceYouTube:  "▶",
+	aggregator.SourceSubstack: "✉",
+	aggregator.SourceReader:   "◉",
+	aggregator.SourceBridge:   "⇄",
+	aggregator.SourcePodcast:  "♪",
+	aggregator.SourceTwitch:   "◆",
+	aggregator.SourceGitHub:   "⚑",
+	aggregator.SourceMedium:   "M",
+	aggregator.SourceLobsters: "▲",
+	aggregator.SourceArxiv:    "χ",
+	aggregator.SourcePeerTube: "▷",
+}
+
+// WithCompact renders each item on one aligned line (age, source icon,
+// author, title) instead of the multi-line layout.
+func WithCompact(compact bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.compact = compact
+	}
+}
+
+// compactLine formats item as line n of the compact layout. Broadcasts
+// have their badge before the title.
+func (f *TerminalFormatter) compactLine(n int, item aggregator.FeedItem) string {
+	titleLength := f.titleLength
+	if titleLength <= 0 {
+		titleLength = compactTitleWidth
+	}
+	title := item.Title
+	if f.calmTitles {
+		title = calmTitle(title)
+	}
+	title = f.TruncateText(title, titleLength)
+	if item.Paywalled {
+		title = paywallLock + title
+	}
+	badge := f.badge(item)
+	if f.width > 0 {
+		column := compactTitleColumn(n)
+		if badge != "" {
+			column += columns(badge) + 1
+		}
+		title = fitColumns(title, max(f.width-column, minWrapWidth))
+	}
+	if f.hyperlinks && item.URL != "" {
+		title = hyperlink(item.URL, title)
+	}
+	title = paint(f.theme.Title, title)
+	if badge != "" {
+		title = paint(f.theme.Badge, badge) + " " + title
+	}
+
+	line := fmt.Sprintf("%3d. %s %s %s %s",
+		n,
+		paint(f.theme.Meta, pad(compactTimestamp(item.PublishedAt, f.now()), compactTimeWidth)),
+		paint(f.theme.source(item.Source), sourceIcon(item.Source)),
+		paint(f.theme.Meta, pad(f.TruncateText(item.Author, compactAuthorWidth), compactAuthorWidth)),
+		title,
+	)
+	if item.Audio != nil && item.Audio.Duration > 0 {
+		line += " " + paint(f.theme.Meta, "("+VideoTime(item.Audio.Duration)+")")
+	}
+	if item.Restriction != "" {
+		line += " " + paint(f.theme.Meta, "("+item.Restriction+")")
+	}
+	return line + "\n"
+}
+
+// compactTitleColumn is the column line n's title starts at: after the
+// number, age, source icon and author, each followed by a space.
+func compactTitleColumn(n int) int {
+	return len(fmt.Sprintf("%3d. ", n)) + compactTimeWidth + 1 + 2 + compactAuthorWidth + 1
+}
+
+func sourceIcon(source aggregator.Source) string {
+	if icon, ok := sourceIcons[source]; ok {
+		return icon
+	}
+	if source == "" {
+		return "?"
+	}
+	r, _ := utf8.DecodeRuneInString(string(source))
+	return strings.ToUpper(string(r))
+}
+
+// compactTimestamp is a short relative age at now: "now", "5m", "3h", "2d", then the date.
+func compactTimestamp(t, now time.Time) string {
+	diff := now.Sub(t)
+	switch {
+	case diff < time.Minute:
+		return "now"
+	case diff < time.Hour:
+		return fmt.Sprintf("%dm", int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(diff.Hours()))
+	case diff < 7*24*time.Hour:
+		return fmt.Sprintf("%dd", int(diff.Hours()/24))
+	default:
+		return t.Format("Jan 2")
+	}
+}
+
+// pad right-pads s with spaces to width characters.
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
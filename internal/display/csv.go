@@ -0,0 +1,63 @@
+package display
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// csvHeader names the columns of CSV output.
+var csvHeader = []string{"id", "source", "type", "title", "author", "url", "published_at", "views", "likes", "comments"}
+
+// WriteCSV writes items to w as CSV, one row per item under a header row,
+// for spreadsheets and data pipelines. Dates are RFC 3339 in UTC and
+// engagement counts are plain numbers.
+func WriteCSV(w io.Writer, items []aggregator.FeedItem) error {
+	out := csv.NewWriter(w)
+	_ = out.Write(csvHeader)
+	for _, item := range items {
+		_ = out.Write(csvRow(item))
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// StreamCSV writes each item to w as a CSV row as it arrives on items, in
+// the layout of WriteCSV, and returns the items written once items is
+// closed. A write error stops output but not the stream.
+func StreamCSV(w io.Writer, items <-chan aggregator.FeedItem) ([]aggregator.FeedItem, error) {
+	out := csv.NewWriter(w)
+	_ = out.Write(csvHeader)
+	out.Flush()
+	var written []aggregator.FeedItem
+	for item := range items {
+		written = append(written, item)
+		if out.Error() == nil {
+			_ = out.Write(csvRow(item))
+			out.Flush()
+		}
+	}
+	return written, out.Error()
+}
+
+func csvRow(item aggregator.FeedItem) []string {
+	var published string
+	if !item.PublishedAt.IsZero() {
+		published = item.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		item.ID,
+		string(item.Source),
+		string(item.Type),
+		item.Title,
+		item.Author,
+		item.URL,
+		published,
+		strconv.FormatInt(item.Engagement.Views, 10),
+		strconv.FormatInt(item.Engagement.Likes, 10),
+		strconv.FormatInt(item.Engagement.Comments, 10),
+	}
+}
@@ -0,0 +1,177 @@
+package display
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// Golden tests snapshot the complete output of each format for a fixed set
+// of items, so a change anywhere in a layout shows up as a line diff rather
+// than slipping past tests that only check for a substring. After an
+// intended change, review the diff and rewrite the snapshots with:
+//
+//	go test ./internal/display -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata with the current output")
+
+var goldenNow = time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// goldenItems covers what the layouts treat differently: sources, engagement,
+// descriptions, chapters, broadcasts, restrictions, edits, paywalls and
+// podcast episodes.
+var goldenItems = []aggregator.FeedItem{
+	{
+		ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Building CLI tools in Go",
+		Description: "A walk through <b>cobra</b> &amp; friends.", Author: "Tech Channel", AuthorHandle: "@tech",
+		URL: "https://www.youtube.com/watch?v=v1", Thumbnail: "https://i.ytimg.com/vi/v1/hqdefault.jpg",
+		PublishedAt: goldenNow.Add(-2 * time.Hour), Engagement: aggregator.Engagement{Views: 15300, Likes: 820, Comments: 41},
+		Chapters: []aggregator.Chapter{{Start: 0, Title: "Intro"}, {Start: 95 * time.Second, Title: "Flags"}},
+	},
+	{
+		ID: "live1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeLive, Title: "Q&A stream", Author: "Tech Channel",
+		URL: "https://www.youtube.com/watch?v=live1", PublishedAt: goldenNow.Add(-3 * time.Hour),
+		Broadcast: &aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Start: goldenNow.Add(5 * time.Hour)},
+	},
+	{
+		ID: "p1", Source: aggregator.SourceSubstack, Type: aggregator.ItemTypeArticle, Title: "On slow reading",
+		Description: "Why long articles deserve a calm place to be read.", Author: "The Review — Jane Doe",
+		URL: "https://review.substack.com/p/slow-reading", PublishedAt: goldenNow.Add(-26 * time.Hour),
+		UpdatedAt: goldenNow.Add(-time.Hour), Engagement: aggregator.Engagement{Likes: 120, Comments: 8},
+		Content: `<h2>Slow down</h2><p>Reading <a href="https://example.com/essay">one essay</a> a day is enough.</p><ul><li>Turn off alerts</li><li>Read on paper</li></ul>`,
+	},
+	{
+		ID: "p2", Source: aggregator.SourceSubstack, Type: aggregator.ItemTypeArticle, Title: "Members only: the full archive",
+		Description: "A preview.", Author: "The Review — Jane Doe", URL: "https://review.substack.com/p/archive",
+		PublishedAt: goldenNow.Add(-50 * time.Hour), Paywalled: true,
+	},
+	{
+		ID: "pod1", Source: aggregator.SourceSubstack, Type: aggregator.ItemTypePodcast, Title: "Episode 12: Interviews",
+		Author: "The Review — Jane Doe", URL: "https://review.substack.com/p/episode-12", PublishedAt: goldenNow.Add(-4 * 24 * time.Hour),
+		Audio: &aggregator.Audio{URL: "https://api.substack.com/feed/podcast/12.mp3", Type: "audio/mpeg", Length: 40960000, Duration: 42*time.Minute + 10*time.Second},
+	},
+	{
+		ID: "ep7", Source: aggregator.SourcePodcast, Type: aggregator.ItemTypePodcast, Title: "Generics, one year on",
+		Author: "Go Time — Jane Doe", URL: "https://example.com/episodes/7", Thumbnail: "https://example.com/show.jpg",
+		PublishedAt: goldenNow.Add(-6 * 24 * time.Hour),
+		Audio:       &aggregator.Audio{URL: "https://cdn.example.com/7.mp3", Type: "audio/mpeg", Duration: time.Hour + 2*time.Minute + 3*time.Second, Season: 2, Episode: 7},
+	},
+	{
+		ID: "r1", Source: aggregator.SourceReader, Type: aggregator.ItemTypeArticle, Title: "Release notes 2.0",
+		Author: "Project Blog", URL: "https://blog.example.com/2.0", PublishedAt: goldenNow.Add(-9 * 24 * time.Hour),
+		Restriction: "not available in FR",
+	},
+}
+
+func TestGolden_TerminalLayouts(t *testing.T) {
+	base := []FormatterOption{WithClock(clock.Fixed(goldenNow))}
+	for _, tc := range []struct {
+		name string
+		opts []FormatterOption
+	}{
+		{"terminal", nil},
+		{"terminal_details", []FormatterOption{WithDescription(200), WithDetails(true), WithThumbnails(true)}},
+		{"terminal_narrow", []FormatterOption{WithWidth(32)}},
+		{"terminal_theme", []FormatterOption{WithTheme(themes[DefaultTheme])}},
+		{"compact", []FormatterOption{WithCompact(true)}},
+		{"sections", []FormatterOption{WithDayHeaders(true), WithGroupBy(aggregator.GroupBySource)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertGolden(t, tc.name+".txt", NewTerminalFormatter(append(base, tc.opts...)...).FormatFeed(goldenItems))
+		})
+	}
+}
+
+func TestGolden_Article(t *testing.T) {
+	formatter := NewTerminalFormatter(WithClock(clock.Fixed(goldenNow)), WithWidth(40))
+	assertGolden(t, "article.txt", formatter.FormatArticle(goldenItems[2]))
+}
+
+func TestGolden_Exports(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		write func(*bytes.Buffer) error
+	}{
+		{"feed.csv", func(b *bytes.Buffer) error { return WriteCSV(b, goldenItems) }},
+		{"feed.json", func(b *bytes.Buffer) error { return WriteJSONFeed(b, "feedmix", goldenItems) }},
+		{"feed.atom", func(b *bytes.Buffer) error { return WriteAtom(b, "feedmix", goldenItems, goldenNow) }},
+		{"feed.ics", func(b *bytes.Buffer) error { return WriteICS(b, goldenItems, goldenNow) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := tc.write(&b); err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, tc.name, b.String())
+		})
+	}
+}
+
+// assertGolden compares got with testdata/name.golden, or rewrites the file
+// with -update. Escape characters are stored as \e, so colored output
+// stays readable in the file and in diffs.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	got = strings.ReplaceAll(got, "\x1b", `\e`)
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path) // #nosec G304 - path is a golden file of this package
+	if err != nil {
+		t.Fatalf("%v: run 'go test ./internal/display -run Golden -update' to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (- want, + got; rerun with -update if intended):\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+// lineDiff lists the lines of want and got, marking those only in want
+// with "-" and those only in got with "+".
+func lineDiff(want, got string) string {
+	a, b := strings.Split(want, "\n"), strings.Split(got, "\n")
+	// common[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&out, "  %s\n", a[i])
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || common[i][j+1] >= common[i+1][j]):
+			fmt.Fprintf(&out, "+ %s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(&out, "- %s\n", a[i])
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,89 @@
+package display
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// broadcastLength is how long an event lasts when its broadcast has no
+// scheduled end.
+const broadcastLength = time.Hour
+
+// icsEscaper escapes TEXT values (RFC 5545, section 3.3.11).
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// WriteICS writes the upcoming premieres and live streams among items to w
+// as an iCalendar document (RFC 5545), one event per broadcast, for
+// calendar apps. Other items, and broadcasts of unknown start, are left
+// out. Events are stamped with now.
+func WriteICS(w io.Writer, items []aggregator.FeedItem, now time.Time) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICS(name + ":" + value))
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//feedmix//feedmix//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "feedmix")
+	for _, item := range items {
+		if item.Broadcast == nil || item.Broadcast.Start.IsZero() {
+			continue
+		}
+		end := item.Broadcast.End
+		if !end.After(item.Broadcast.Start) {
+			end = item.Broadcast.Start.Add(broadcastLength)
+		}
+		description := strings.TrimSpace(item.URL + "\n\n" + strings.TrimSpace(item.Description))
+		line("BEGIN", "VEVENT")
+		line("UID", string(item.Source)+"-"+item.ID+"@feedmix")
+		line("DTSTAMP", icsTime(now))
+		line("DTSTART", icsTime(item.Broadcast.Start))
+		line("DTEND", icsTime(end))
+		line("SUMMARY", icsEscaper.Replace(icsSummary(item)))
+		if description != "" {
+			line("DESCRIPTION", icsEscaper.Replace(description))
+		}
+		if item.URL != "" {
+			line("URL", item.URL)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// icsSummary names the event after the item and who airs it.
+func icsSummary(item aggregator.FeedItem) string {
+	if item.Author == "" {
+		return item.Title
+	}
+	return item.Author + ": " + item.Title
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// foldICS ends a content line with CRLF, folding it into lines of at most
+// 75 octets without splitting a UTF-8 character.
+func foldICS(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package display
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	// Decoders for the formats thumbnails come in.
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// ImageProtocol is a terminal graphics protocol images can be drawn with.
+type ImageProtocol string
+
+const (
+	ImageKitty ImageProtocol = "kitty"
+	ImageITerm ImageProtocol = "iterm"
+	ImageSixel ImageProtocol = "sixel"
+)
+
+// Size of inline thumbnails. Terminal cells are about twice as tall as they
+// are wide; sixel images are drawn at thumbnailPixels wide.
+const (
+	thumbnailColumns = 24
+	thumbnailPixels  = 240
+	kittyChunkSize   = 4096
+)
+
+// WithInlineThumbnails draws each item's thumbnail in the terminal with
+// protocol, loading the image through load. Thumbnails that fail to load or
+// decode fall back to their URL, as with WithThumbnails. The compact layout
+// has no room for them.
+func WithInlineThumbnails(protocol ImageProtocol, load func(url string) ([]byte, error)) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.showThumbnails = true
+		f.imageProtocol = protocol
+		f.loadImage = load
+	}
+}
+
+// thumbnail returns the lines showing item's thumbnail: the image itself
+// when it can be drawn, else its URL.
+func (f *TerminalFormatter) thumbnail(url string) string {
+	if f.imageProtocol != "" && f.loadImage != nil {
+		if data, err := f.loadImage(url); err == nil {
+			if img, err := inlineImage(f.imageProtocol, data); err == nil {
+				return "  " + img
+			}
+		}
+	}
+	return "  " + paint(f.theme.Meta, "thumbnail: "+url)
+}
+
+// inlineImage encodes an image file as the escape sequence drawing it with
+// protocol, thumbnailColumns wide.
+func inlineImage(protocol ImageProtocol, data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode thumbnail: %w", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return "", fmt.Errorf("empty thumbnail")
+	}
+	rows := max(1, (thumbnailColumns*bounds.Dy()+bounds.Dx())/(2*bounds.Dx()))
+
+	switch protocol {
+	case ImageKitty:
+		return kittyImage(img, rows)
+	case ImageITerm:
+		return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+			len(data), thumbnailColumns, rows, base64.StdEncoding.EncodeToString(data)), nil
+	case ImageSixel:
+		return sixelImage(img), nil
+	default:
+		return "", fmt.Errorf("unknown image protocol %q", protocol)
+	}
+}
+
+// kittyImage sends img as PNG, split into chunks as the kitty graphics
+// protocol requires, and asks the terminal not to reply.
+func kittyImage(img image.Image, rows int) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scale(img, thumbnailPixels)); err != nil {
+		return "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	payload := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var b strings.Builder
+	for i := 0; i < len(payload); i += kittyChunkSize {
+		chunk := payload[i:min(i+kittyChunkSize, len(payload))]
+		more := 0
+		if i+kittyChunkSize < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", thumbnailColumns, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String(), nil
+}
+
+// sixelImage draws img in sixels: bands six pixels high, painted one
+// palette color at a time.
+func sixelImage(img image.Image) string {
+	scaled := scale(img, thumbnailPixels)
+	bounds := scaled.Bounds()
+	paletted := image.NewPaletted(bounds, palette.WebSafe)
+	draw.FloydSteinberg.Draw(paletted, bounds, scaled, bounds.Min)
+	w, h := bounds.Dx(), bounds.Dy()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", w, h)
+	used := make(map[uint8]bool)
+	for _, index := range paletted.Pix {
+		used[index] = true
+	}
+	for i, c := range paletted.Palette {
+		if used[uint8(i)] {
+			r, g, bl, _ := c.RGBA()
+			fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+		}
+	}
+
+	row := make([]byte, w)
+	for top := 0; top < h; top += 6 {
+		for i := range paletted.Palette {
+			index := uint8(i)
+			painted := false
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < h; dy++ {
+					if paletted.ColorIndexAt(x, top+dy) == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+				painted = painted || bits != 0
+			}
+			if painted {
+				fmt.Fprintf(&b, "#%d%s$", i, runLength(row))
+			}
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// runLength compresses runs of a repeated sixel as !<count><sixel>.
+func runLength(row []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(&b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// scale shrinks img to at most width pixels wide, keeping its aspect ratio,
+// with nearest-neighbor sampling.
+func scale(img image.Image, width int) image.Image {
+	src := img.Bounds()
+	if src.Dx() <= width {
+		return img
+	}
+	height := max(1, src.Dy()*width/src.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dst.Set(x, y, color.RGBAModel.Convert(img.At(src.Min.X+x*src.Dx()/width, src.Min.Y+y*src.Dy()/height)))
+		}
+	}
+	return dst
+}
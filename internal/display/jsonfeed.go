@@ -0,0 +1,111 @@
+package display
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/jsonschema"
+)
+
+// jsonFeedVersion identifies the JSON Feed spec WriteJSONFeed follows.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// jsonFeed is a JSON Feed document (https://www.jsonfeed.org/version/1.1/).
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url,omitempty"`
+	Title         string               `json:"title,omitempty"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   *string              `json:"content_text,omitempty"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published,omitempty"`
+	DateModified  string               `json:"date_modified,omitempty"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+	// Feedmix carries what JSON Feed has no field for, as an extension.
+	Feedmix jsonFeedExtension `json:"_feedmix"`
+}
+
+// jsonFeedAttachment is a podcast episode's audio file.
+type jsonFeedAttachment struct {
+	URL      string `json:"url"`
+	MIMEType string `json:"mime_type"`
+	Size     int64  `json:"size_in_bytes,omitempty"`
+	Duration int64  `json:"duration_in_seconds,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedExtension struct {
+	Source      aggregator.Source     `json:"source"`
+	Type        aggregator.ItemType   `json:"type,omitempty"`
+	Engagement  aggregator.Engagement `json:"engagement"`
+	Restriction string                `json:"restriction,omitempty"`
+}
+
+// WriteJSONFeed writes items to w as a JSON Feed 1.1 document titled title,
+// for feed readers. YouTube descriptions are plain text and the others
+// HTML; the source, engagement and playback restriction of each item go in
+// a "_feedmix" extension.
+func WriteJSONFeed(w io.Writer, title string, items []aggregator.FeedItem) error {
+	feed := jsonFeed{Version: jsonFeedVersion, Title: title, Items: make([]jsonFeedItem, 0, len(items))}
+	for _, item := range items {
+		feed.Items = append(feed.Items, jsonFeedEntry(item))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(feed)
+}
+
+// JSONFeedSchema describes the documents WriteJSONFeed writes.
+func JSONFeedSchema(opts ...jsonschema.Option) *jsonschema.Schema {
+	return jsonschema.For(jsonFeed{}, "feedmix JSON Feed",
+		`A JSON Feed 1.1 document as written by feedmix, with what JSON Feed has no field for in each item's "_feedmix" extension.`, opts...)
+}
+
+func jsonFeedEntry(item aggregator.FeedItem) jsonFeedItem {
+	entry := jsonFeedItem{
+		ID:      string(item.Source) + ":" + item.ID,
+		URL:     item.URL,
+		Title:   item.Title,
+		Image:   item.Thumbnail,
+		Feedmix: jsonFeedExtension{Source: item.Source, Type: item.Type, Engagement: item.Engagement, Restriction: item.Restriction},
+	}
+	if htmlDescription(item.Source) && item.Description != "" {
+		entry.ContentHTML = item.Description
+	} else {
+		entry.ContentText = &item.Description
+	}
+	if !item.PublishedAt.IsZero() {
+		entry.DatePublished = item.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	if item.UpdatedAt.After(item.PublishedAt) {
+		entry.DateModified = item.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if item.Author != "" {
+		entry.Authors = []jsonFeedAuthor{{Name: item.Author}}
+	}
+	if audio := item.Audio; audio != nil {
+		mimeType := audio.Type
+		if mimeType == "" {
+			mimeType = "audio/mpeg"
+		}
+		entry.Attachments = []jsonFeedAttachment{{URL: audio.URL, MIMEType: mimeType, Size: audio.Length, Duration: int64(audio.Duration.Seconds())}}
+	}
+	entry.Tags = append(entry.Tags, string(item.Source))
+	if item.Group != "" {
+		entry.Tags = append(entry.Tags, item.Group)
+	}
+	return entry
+}
@@ -0,0 +1,58 @@
+package display
+
+import (
+	"math"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// localeWords are the compact-number suffixes and engagement labels of a language.
+type localeWords struct {
+	thousand, million, billion string
+	views, likes, comments     string
+}
+
+// engagementWords is keyed by base language; other languages use English
+// words with their own decimal separator.
+var engagementWords = map[string]localeWords{
+	"en": {thousand: "K", million: "M", billion: "B", views: "views", likes: "likes", comments: "comments"},
+	"fr": {thousand: " k", million: " M", billion: " Md", views: "vues", likes: "j’aime", comments: "commentaires"},
+	"de": {thousand: " Tsd.", million: " Mio.", billion: " Mrd.", views: "Aufrufe", likes: "Likes", comments: "Kommentare"},
+	"es": {thousand: " mil", million: " M", billion: " mil M", views: "visualizaciones", likes: "me gusta", comments: "comentarios"},
+}
+
+func wordsFor(tag language.Tag) localeWords {
+	base, _ := tag.Base()
+	if words, ok := engagementWords[base.String()]; ok {
+		return words
+	}
+	return engagementWords["en"]
+}
+
+// compactNumber abbreviates n the way YouTube does: 950, 1.2K, 12K, 1.2M.
+// Values are truncated, not rounded, so 1,290 shows as 1.2K rather than 1.3K.
+func compactNumber(p *message.Printer, words localeWords, n int64) string {
+	var div float64
+	var suffix string
+	switch {
+	case n < 1e3:
+		return p.Sprintf("%d", n)
+	case n < 1e6:
+		div, suffix = 1e3, words.thousand
+	case n < 1e9:
+		div, suffix = 1e6, words.million
+	default:
+		div, suffix = 1e9, words.billion
+	}
+
+	v := float64(n) / div
+	digits := 0
+	if v < 10 {
+		digits = 1
+	}
+	scale := math.Pow10(digits)
+	v = math.Floor(v*scale) / scale
+	return p.Sprintf("%v", number.Decimal(v, number.MaxFractionDigits(digits))) + suffix
+}
@@ -0,0 +1,99 @@
+package display
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// WithDayHeaders inserts a "Today", "Yesterday" or dated header before the
+// first item of each day, in local time. Items must be sorted by date, so
+// headers are left out of streamed feeds.
+func WithDayHeaders(show bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.dayHeaders = show
+	}
+}
+
+// WithGroupBy puts a header naming the source or author before each section
+// of a feed grouped by aggregator.FeedOptions.GroupBy. It takes precedence
+// over day headers.
+func WithGroupBy(by aggregator.GroupBy) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.groupBy = by
+	}
+}
+
+// sectionLabel names the section of a grouped feed that item starts.
+func (f *TerminalFormatter) sectionLabel(item aggregator.FeedItem) string {
+	if f.groupBy == aggregator.GroupBySource {
+		return strings.ToUpper(string(item.Source))
+	}
+	label := item.Author
+	if item.AuthorHandle != "" {
+		label += " (" + item.AuthorHandle + ")"
+	}
+	return label + " [" + strings.ToUpper(string(item.Source)) + "]"
+}
+
+// dayLabel names the local day of t as seen from now: "Today", "Yesterday",
+// the weekday within the last week, then the date.
+func dayLabel(t, now time.Time) string {
+	t, now = t.Local(), now.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case day.Before(today) && day.After(today.AddDate(0, 0, -7)):
+		return t.Format("Monday")
+	case t.Year() == now.Year():
+		return t.Format("Monday, Jan 2")
+	default:
+		return t.Format("Monday, Jan 2, 2006")
+	}
+}
+
+// sectionHeader formats the header starting a section or day, first being
+// the header at the top of the feed.
+func (f *TerminalFormatter) sectionHeader(label string, first bool) string {
+	header := paint(f.theme.Title, "── "+label+" ──") + "\n"
+	if !f.compact {
+		header += "\n"
+	}
+	if first {
+		return header
+	}
+	return "\n" + header
+}
+
+// separators returns what goes before each of items: nothing before the
+// first, then a divider, or a header where a new section or day starts.
+func (f *TerminalFormatter) separators(items []aggregator.FeedItem) []string {
+	seps := make([]string, len(items))
+	now := f.now()
+	previous := ""
+	for i, item := range items {
+		if i > 0 {
+			seps[i] = f.divider()
+		}
+		switch {
+		case f.template != nil:
+		case f.groupBy != aggregator.GroupByNone:
+			if key := f.groupBy.Section(item); i == 0 || key != previous {
+				seps[i] = f.sectionHeader(f.sectionLabel(item), i == 0)
+				previous = key
+			}
+		case f.dayHeaders:
+			if label := dayLabel(item.PublishedAt, now); label != previous {
+				seps[i] = f.sectionHeader(label, i == 0)
+				previous = label
+			}
+		}
+	}
+	return seps
+}
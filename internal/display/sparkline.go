@@ -0,0 +1,47 @@
+package display
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// sparkBars draw a sparkline from lowest to highest value.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as bars scaled between their minimum and maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := slices.Min(values), slices.Max(values)
+	var b strings.Builder
+	for _, v := range values {
+		bar := len(sparkBars) - 1
+		if hi > lo {
+			bar = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBars)-1)))
+		}
+		b.WriteRune(sparkBars[bar])
+	}
+	return b.String()
+}
+
+// formatGrowth shows how item gained engagement between runs: a sparkline
+// of the hourly gain between each of its samples, and its overall gain per
+// hour, e.g. "▁▃█ +1.2K/h". It is empty when the item didn't grow.
+func (f *TerminalFormatter) formatGrowth(item aggregator.FeedItem) string {
+	growth := item.Growth()
+	if growth <= 0 {
+		return ""
+	}
+	var rates []float64
+	for i := 1; i < len(item.Trend); i++ {
+		prev, cur := item.Trend[i-1], item.Trend[i]
+		if hours := cur.At.Sub(prev.At).Hours(); hours > 0 {
+			rates = append(rates, max(float64(cur.Count()-prev.Count())/hours, 0))
+		}
+	}
+	return sparkline(rates) + " +" + compactNumber(f.printer, f.words, int64(growth)) + "/h"
+}
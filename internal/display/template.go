@@ -0,0 +1,94 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// templateItem is what a template is executed with: the item's fields, and
+// N, its number in the feed.
+type templateItem struct {
+	N int
+	aggregator.FeedItem
+}
+
+// ParseTemplate parses a text/template applied to each item of the feed,
+// such as '{{.N}}. {{.Title}} ({{ago .PublishedAt}})'. Besides the item's
+// fields and N, templates can use these functions:
+//
+//	ago .PublishedAt           relative age: "2 hours ago"
+//	date "2006-01-02" .PublishedAt
+//	truncate 40 .Title         at most 40 characters, ending in "..."
+//	count .Engagement.Views    abbreviated number: "1.2M"
+//	engagement .Engagement     "1.2M views • 45K likes"
+//	plain .Description         HTML stripped, on one line
+//	upper, lower, json
+//
+// The template is tried on an empty item, so misspelled fields are reported
+// here rather than halfway through the feed.
+func ParseTemplate(text string) (*template.Template, error) {
+	f := NewTerminalFormatter()
+	t, err := template.New("item").Funcs(f.templateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if err := t.Execute(io.Discard, templateItem{}); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// WithTemplate formats each item with t, from ParseTemplate, on its own
+// line, with no dividers or section headers.
+func WithTemplate(t *template.Template) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.template = t
+	}
+}
+
+// ExecuteTemplate formats item with t, from ParseTemplate, as the single
+// item of a list.
+func ExecuteTemplate(t *template.Template, item aggregator.FeedItem) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, templateItem{N: 1, FeedItem: item}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// templateFuncs are the functions templates can use, formatting with f's
+// locale and clock.
+func (f *TerminalFormatter) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"ago":        f.FormatTimestamp,
+		"date":       func(layout string, t time.Time) string { return t.Format(layout) },
+		"truncate":   func(n int, s string) string { return f.TruncateText(s, n) },
+		"count":      func(n int64) string { return compactNumber(f.printer, f.words, n) },
+		"engagement": f.formatEngagement,
+		"plain":      PlainText,
+		"upper":      func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"lower":      func(v any) string { return strings.ToLower(fmt.Sprint(v)) },
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}
+}
+
+// templateLine formats item as entry n with the template.
+func (f *TerminalFormatter) templateLine(n int, item aggregator.FeedItem) string {
+	var b strings.Builder
+	if err := f.template.Execute(&b, templateItem{N: n, FeedItem: item}); err != nil {
+		return fmt.Sprintf("template error on item %d: %v\n", n, err)
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -3,43 +3,238 @@ package display
 
 import (
 	"fmt"
+	"html"
+	"io"
 	"strings"
+	"text/template"
 	"time"
 	"unicode/utf8"
 
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
 	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
 )
 
 const separator = " • "
 
 // TerminalFormatter formats feed items for terminal display.
-type TerminalFormatter struct{}
+type TerminalFormatter struct {
+	printer *message.Printer
+	words   localeWords
+	now     func() time.Time
+
+	titleLength       int
+	descriptionLength int
+	width             int
+	hideEngagement    bool
+	showThumbnails    bool
+	showDetails       bool
+	imageProtocol     ImageProtocol
+	loadImage         func(url string) ([]byte, error)
+	calmTitles        bool
+	hyperlinks        bool
+	theme             Theme
+	compact           bool
+	dayHeaders        bool
+	groupBy           aggregator.GroupBy
+	template          *template.Template
+}
+
+// FormatterOption configures a TerminalFormatter.
+type FormatterOption func(*TerminalFormatter)
+
+// WithLocale formats engagement counts for locale: "1.2M views" in English,
+// "1,2 M vues" in French. The default is English.
+func WithLocale(tag language.Tag) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.printer = message.NewPrinter(tag)
+		f.words = wordsFor(tag)
+	}
+}
+
+// WithTitleLength truncates titles to n characters; 0 shows them in full.
+func WithTitleLength(n int) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.titleLength = n
+	}
+}
+
+// WithDescription shows up to n characters of each item's description as
+// plain text; 0 hides descriptions, which is the default.
+func WithDescription(n int) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.descriptionLength = n
+	}
+}
+
+// WithEngagement shows or hides the views/likes/comments line (shown by default).
+func WithEngagement(show bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.hideEngagement = !show
+	}
+}
+
+// WithThumbnails adds each item's thumbnail URL (hidden by default).
+func WithThumbnails(show bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.showThumbnails = show
+	}
+}
+
+// WithDetails lists the chapters of each video under it (hidden by default).
+func WithDetails(show bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.showDetails = show
+	}
+}
+
+// WithCalmTitles tones down clickbait titles: emoji, [TAGS] and "!!!" are
+// dropped and SHOUTED words are lowercased. Off by default.
+func WithCalmTitles(calm bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.calmTitles = calm
+	}
+}
+
+// WithHyperlinks makes titles clickable with OSC 8 escape sequences instead
+// of printing each URL on its own line. Only enable it for terminals that
+// support OSC 8; others may print the escape sequences as garbage.
+func WithHyperlinks(enabled bool) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.hyperlinks = enabled
+	}
+}
+
+// WithClock measures item ages and days against now instead of the system clock.
+func WithClock(now clock.Clock) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.now = now
+	}
+}
 
 // NewTerminalFormatter creates a new terminal formatter.
-func NewTerminalFormatter() *TerminalFormatter {
-	return &TerminalFormatter{}
+func NewTerminalFormatter(opts ...FormatterOption) *TerminalFormatter {
+	f := &TerminalFormatter{now: time.Now}
+	WithLocale(language.English)(f)
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.template != nil {
+		f.template = template.Must(f.template.Clone()).Funcs(f.templateFuncs())
+	}
+	return f
 }
 
 // FormatItem formats a single feed item for display.
 func (f *TerminalFormatter) FormatItem(item aggregator.FeedItem) string {
+	return f.formatItem(item, 0)
+}
+
+// formatItem formats item for display after indent columns of the first
+// line are already taken, such as by the entry number.
+func (f *TerminalFormatter) formatItem(item aggregator.FeedItem, indent int) string {
 	var lines []string
 
 	// Header: [SOURCE] Title
-	header := fmt.Sprintf("[%s] %s", strings.ToUpper(string(item.Source)), item.Title)
-	lines = append(lines, header)
+	title := item.Title
+	if f.calmTitles {
+		title = calmTitle(title)
+	}
+	if f.titleLength > 0 {
+		title = f.TruncateText(title, f.titleLength)
+	}
+	if item.Paywalled {
+		title = paywallLock + title
+	}
+	tag := "[" + strings.ToUpper(string(item.Source)) + "]"
+	header := paint(f.theme.source(item.Source), tag)
+	if badge := f.badge(item); badge != "" {
+		tag += " " + badge
+		header += " " + paint(f.theme.Badge, badge)
+	}
+	titleLines := []string{title}
+	if f.width > 0 {
+		titleLines = wrap(title, f.width-indent-columns(tag)-1, f.width-2)
+	}
+	for i, line := range titleLines {
+		if f.hyperlinks && item.URL != "" {
+			line = hyperlink(item.URL, line)
+		}
+		if i == 0 {
+			lines = append(lines, header+" "+paint(f.theme.Title, line))
+		} else {
+			lines = append(lines, "  "+paint(f.theme.Title, line))
+		}
+	}
 
 	// Author and timestamp
-	meta := fmt.Sprintf("  by %s%s%s", item.Author, separator, f.FormatTimestamp(item.PublishedAt))
-	lines = append(lines, meta)
+	author := item.Author
+	if item.AuthorHandle != "" {
+		author += " (" + item.AuthorHandle + ")"
+	}
+	meta := fmt.Sprintf("by %s%s%s", author, separator, f.FormatTimestamp(item.PublishedAt))
+	if !item.UpdatedAt.IsZero() {
+		meta += separator + "updated " + f.FormatTimestamp(item.UpdatedAt)
+	}
+	if item.Restriction != "" {
+		meta += separator + item.Restriction
+	}
+	lines = append(lines, "  "+paint(f.theme.Meta, meta))
+
+	if f.descriptionLength > 0 {
+		if description := PlainText(item.Description); description != "" {
+			description = f.TruncateText(description, f.descriptionLength)
+			if f.width > 0 {
+				for _, line := range wrap(description, f.width-2, f.width-2) {
+					lines = append(lines, "  "+line)
+				}
+			} else {
+				lines = append(lines, "  "+description)
+			}
+		}
+	}
 
 	// Engagement stats (if any)
-	if engagement := f.formatEngagement(item.Engagement); engagement != "" {
-		lines = append(lines, "  "+engagement)
+	if engagement := f.formatEngagement(item.Engagement); engagement != "" && !f.hideEngagement {
+		if growth := f.formatGrowth(item); growth != "" {
+			engagement += separator + growth
+		}
+		lines = append(lines, "  "+paint(f.theme.Engagement, engagement))
+	}
+
+	if f.showDetails {
+		for _, chapter := range item.Chapters {
+			lines = append(lines, "  "+paint(f.theme.Meta, VideoTime(chapter.Start))+" "+chapter.Title)
+		}
+	}
+
+	if audio := item.Audio; audio != nil {
+		label := "Audio"
+		switch {
+		case audio.Season > 0 && audio.Episode > 0:
+			label += fmt.Sprintf(" S%d E%d", audio.Season, audio.Episode)
+		case audio.Episode > 0:
+			label += fmt.Sprintf(" E%d", audio.Episode)
+		}
+		if audio.Duration > 0 {
+			label += " " + VideoTime(audio.Duration)
+		}
+		if f.hyperlinks {
+			lines = append(lines, "  "+paint(f.theme.Meta, hyperlink(audio.URL, label)))
+		} else {
+			lines = append(lines, "  "+paint(f.theme.Meta, label)+" "+paint(f.theme.URL, audio.URL))
+		}
 	}
 
 	// URL
-	if item.URL != "" {
-		lines = append(lines, "  "+item.URL)
+	if item.URL != "" && !f.hyperlinks {
+		lines = append(lines, "  "+paint(f.theme.URL, item.URL))
+	}
+
+	if f.showThumbnails && item.Thumbnail != "" {
+		lines = append(lines, f.thumbnail(item.Thumbnail))
 	}
 
 	return strings.Join(lines, "\n") + "\n"
@@ -50,35 +245,73 @@ func (f *TerminalFormatter) formatEngagement(e aggregator.Engagement) string {
 	var parts []string
 
 	if e.Views > 0 {
-		parts = append(parts, fmt.Sprintf("%d views", e.Views))
+		parts = append(parts, compactNumber(f.printer, f.words, e.Views)+" "+f.words.views)
 	}
 	if e.Likes > 0 {
-		parts = append(parts, fmt.Sprintf("%d likes", e.Likes))
+		parts = append(parts, compactNumber(f.printer, f.words, e.Likes)+" "+f.words.likes)
 	}
 	if e.Comments > 0 {
-		parts = append(parts, fmt.Sprintf("%d comments", e.Comments))
+		parts = append(parts, compactNumber(f.printer, f.words, e.Comments)+" "+f.words.comments)
 	}
 
 	return strings.Join(parts, separator)
 }
 
-// FormatFeed formats multiple feed items for display.
+// FormatFeed formats multiple feed items for display, numbered from 1.
 func (f *TerminalFormatter) FormatFeed(items []aggregator.FeedItem) string {
-	if len(items) == 0 {
+	if len(items) == 0 && f.template == nil {
 		return "No items to display.\n"
 	}
 
-	var formatted []string
-	for _, item := range items {
-		formatted = append(formatted, f.FormatItem(item))
+	var b strings.Builder
+	for i, sep := range f.separators(items) {
+		b.WriteString(sep)
+		b.WriteString(f.entry(i+1, items[i]))
 	}
+	return b.String()
+}
 
-	return strings.Join(formatted, "\n---\n\n")
+// StreamFeed writes each item to w as it arrives on items, in the same layout
+// as FormatFeed, and returns the items written once items is closed.
+func (f *TerminalFormatter) StreamFeed(w io.Writer, items <-chan aggregator.FeedItem) []aggregator.FeedItem {
+	var written []aggregator.FeedItem
+	for item := range items {
+		if len(written) > 0 {
+			fmt.Fprint(w, f.divider())
+		}
+		written = append(written, item)
+		fmt.Fprint(w, f.entry(len(written), item))
+	}
+	if len(written) == 0 {
+		fmt.Fprint(w, f.FormatFeed(nil))
+	}
+	return written
+}
+
+// entry formats item as the nth entry of the feed. Entries are numbered
+// because 'feedmix open N' refers to them.
+func (f *TerminalFormatter) entry(n int, item aggregator.FeedItem) string {
+	if f.template != nil {
+		return f.templateLine(n, item)
+	}
+	if f.compact {
+		return f.compactLine(n, item)
+	}
+	number := fmt.Sprintf("%d. ", n)
+	return number + f.formatItem(item, len(number))
+}
+
+// divider separates consecutive entries.
+func (f *TerminalFormatter) divider() string {
+	if f.compact || f.template != nil {
+		return ""
+	}
+	return "\n---\n\n"
 }
 
 // FormatTimestamp formats a timestamp as relative time.
 func (f *TerminalFormatter) FormatTimestamp(t time.Time) string {
-	diff := time.Since(t)
+	diff := f.now().Sub(t)
 
 	switch {
 	case diff < time.Minute:
@@ -102,6 +335,25 @@ func pluralize(n int, unit string) string {
 	return fmt.Sprintf("%d %ss ago", n, unit)
 }
 
+// PlainText strips HTML tags and entities from s and collapses whitespace,
+// so RSS descriptions fit on one line.
+func PlainText(s string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+			b.WriteRune(' ')
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(b.String())), " ")
+}
+
 // TruncateText truncates text to maxLen runes, adding "..." if truncated.
 func (f *TerminalFormatter) TruncateText(text string, maxLen int) string {
 	if utf8.RuneCountInString(text) <= maxLen {
@@ -113,3 +365,18 @@ func (f *TerminalFormatter) TruncateText(text string, maxLen int) string {
 	runes := []rune(text)
 	return string(runes[:maxLen-3]) + "..."
 }
+
+// hyperlink wraps text in an OSC 8 hyperlink to url.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// VideoTime formats a time into a video as YouTube does: m:ss, or h:mm:ss
+// from an hour in.
+func VideoTime(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -1,12 +1,22 @@
 package display
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
 	"strings"
 	"testing"
 	"time"
 	"unicode/utf8"
 
+	"golang.org/x/text/language"
+
 	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
 )
 
 func TestAC300_TerminalFeed_ShowsVideoTitle(t *testing.T) {
@@ -42,6 +52,22 @@ func TestAC300_TerminalFeed_ShowsAuthorName(t *testing.T) {
 	}
 }
 
+func TestAC300_TerminalFeed_ShowsChannelHandle(t *testing.T) {
+	item := aggregator.FeedItem{
+		Title:        "Test Video",
+		Author:       "Code",
+		AuthorHandle: "@codemaster",
+		Source:       aggregator.SourceYouTube,
+		PublishedAt:  time.Now(),
+	}
+
+	output := NewTerminalFormatter().FormatItem(item)
+
+	if !strings.Contains(output, "by Code (@codemaster)") {
+		t.Errorf("user should see the channel handle next to its title, got:\n%s", output)
+	}
+}
+
 func TestAC300_TerminalFeed_ShowsSourceIndicator(t *testing.T) {
 	item := aggregator.FeedItem{
 		Title:       "Test Video",
@@ -132,6 +158,9 @@ func TestAC304_TerminalFeed_ShowsMultipleItems(t *testing.T) {
 	if !strings.Contains(output, "Second Video") {
 		t.Error("user should see second video in feed")
 	}
+	if !strings.HasPrefix(output, "1. [YOUTUBE] First Video") || !strings.Contains(output, "2. [YOUTUBE] Second Video") {
+		t.Errorf("items should be numbered so 'feedmix open N' can refer to them, got:\n%s", output)
+	}
 }
 
 func TestAC303_TerminalFeed_TruncatesUTF8Safely(t *testing.T) {
@@ -159,3 +188,668 @@ func TestAC305_TerminalFeed_ShowsEmptyFeedMessage(t *testing.T) {
 		t.Error("user should see message indicating no content available")
 	}
 }
+
+func TestAC306_TerminalFeed_MarksEditedItems(t *testing.T) {
+	item := aggregator.FeedItem{
+		Title:       "Corrected post",
+		Source:      aggregator.SourceSubstack,
+		PublishedAt: time.Now().Add(-48 * time.Hour),
+		UpdatedAt:   time.Now().Add(-2 * time.Hour),
+	}
+
+	output := NewTerminalFormatter().FormatItem(item)
+
+	if !strings.Contains(output, "updated 2 hours ago") {
+		t.Errorf("user should see that the post was edited and when, got: %s", output)
+	}
+	if strings.Contains(NewTerminalFormatter().FormatItem(aggregator.FeedItem{PublishedAt: time.Now()}), "updated") {
+		t.Error("unedited items should not be marked updated")
+	}
+}
+
+func TestAC307_TerminalFeed_StreamsItemsInFeedLayout(t *testing.T) {
+	formatter := NewTerminalFormatter()
+	items := []aggregator.FeedItem{
+		{Source: aggregator.SourceYouTube, Title: "First", PublishedAt: time.Now()},
+		{Source: aggregator.SourceSubstack, Title: "Second", PublishedAt: time.Now()},
+	}
+
+	stream := make(chan aggregator.FeedItem, len(items))
+	for _, item := range items {
+		stream <- item
+	}
+	close(stream)
+
+	var out strings.Builder
+	written := formatter.StreamFeed(&out, stream)
+	if out.String() != formatter.FormatFeed(items) {
+		t.Errorf("streamed output should match the regular feed layout, got:\n%s", out.String())
+	}
+	if len(written) != 2 {
+		t.Errorf("StreamFeed should report the items it wrote, got %d", len(written))
+	}
+
+	empty := make(chan aggregator.FeedItem)
+	close(empty)
+	out.Reset()
+	formatter.StreamFeed(&out, empty)
+	if !strings.Contains(out.String(), "No items") {
+		t.Errorf("an empty stream should show the empty-feed message, got: %q", out.String())
+	}
+}
+
+func TestAC308_TerminalFeed_ShowsCompactLocalizedCounts(t *testing.T) {
+	tests := []struct {
+		locale language.Tag
+		views  int64
+		want   string
+	}{
+		{language.English, 950, "950 views"},
+		{language.English, 1290, "1.2K views"},
+		{language.English, 12_345, "12K views"},
+		{language.English, 1_234_567, "1.2M views"},
+		{language.English, 2_000_000_000, "2B views"},
+		{language.French, 1_234_567, "1,2 M vues"},
+		{language.German, 45_600, "45 Tsd. Aufrufe"},
+		{language.Japanese, 1_234_567, "1.2M views"},
+	}
+	for _, tt := range tests {
+		formatter := NewTerminalFormatter(WithLocale(tt.locale))
+		output := formatter.FormatItem(aggregator.FeedItem{Title: "Video", Engagement: aggregator.Engagement{Views: tt.views}})
+		if !strings.Contains(output, tt.want) {
+			t.Errorf("%s: %d views should read %q, got:\n%s", tt.locale, tt.views, tt.want, output)
+		}
+	}
+}
+
+func TestAC309_TerminalFeed_HonorsLayoutSettings(t *testing.T) {
+	item := aggregator.FeedItem{
+		Source:      aggregator.SourceSubstack,
+		Title:       "A rather long article title",
+		Description: "<p>First paragraph &amp; more.</p>\n<p>Second   paragraph.</p>",
+		Thumbnail:   "https://example.com/thumb.jpg",
+		Engagement:  aggregator.Engagement{Likes: 42},
+	}
+
+	defaults := NewTerminalFormatter().FormatItem(item)
+	if strings.Contains(defaults, "First paragraph") || strings.Contains(defaults, "thumb.jpg") || !strings.Contains(defaults, "42 likes") {
+		t.Errorf("by default descriptions and thumbnails should be hidden and engagement shown, got:\n%s", defaults)
+	}
+
+	custom := NewTerminalFormatter(WithTitleLength(10), WithDescription(30), WithEngagement(false), WithThumbnails(true)).FormatItem(item)
+	for _, want := range []string{"A rathe...", "First paragraph & more. Sec...", "thumbnail: https://example.com/thumb.jpg"} {
+		if !strings.Contains(custom, want) {
+			t.Errorf("custom layout should contain %q, got:\n%s", want, custom)
+		}
+	}
+	if strings.Contains(custom, "likes") {
+		t.Errorf("engagement should be hidden when disabled, got:\n%s", custom)
+	}
+}
+
+func TestAC310_TerminalFeed_CalmsClickbaitTitles(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"I Tried The New GPU 🔥🔥 (INSANE RESULTS!!!) [4K]", "I Tried The New GPU"},
+		{"INSANE GPU Deal!!!", "Insane GPU Deal!"},
+		{"THIS CHANGES EVERYTHING?!?", "This changes everything?"},
+		{"How AI Works (Part 2)", "How AI Works (Part 2)"},
+		{"🔥🔥🔥", "🔥🔥🔥"},
+	}
+	for _, tt := range tests {
+		output := NewTerminalFormatter(WithCalmTitles(true)).FormatItem(aggregator.FeedItem{Title: tt.title, Source: aggregator.SourceYouTube})
+		if !strings.Contains(output, "] "+tt.want+"\n") {
+			t.Errorf("calm title of %q should be %q, got:\n%s", tt.title, tt.want, output)
+		}
+	}
+
+	output := NewTerminalFormatter().FormatItem(aggregator.FeedItem{Title: "WOW!!!", Source: aggregator.SourceYouTube})
+	if !strings.Contains(output, "WOW!!!") {
+		t.Errorf("titles should be left alone unless calm titles are enabled, got:\n%s", output)
+	}
+}
+
+func TestAC311_TerminalFeed_LinksTitlesWithOSC8(t *testing.T) {
+	item := aggregator.FeedItem{Title: "Video", URL: "https://www.youtube.com/watch?v=abc", Source: aggregator.SourceYouTube}
+
+	output := NewTerminalFormatter(WithHyperlinks(true)).FormatItem(item)
+	if !strings.Contains(output, "\x1b]8;;https://www.youtube.com/watch?v=abc\x1b\\Video\x1b]8;;\x1b\\") {
+		t.Errorf("title should be an OSC 8 hyperlink, got: %q", output)
+	}
+	if strings.Contains(output, "  https://") {
+		t.Errorf("URL line should be dropped when the title links to it, got: %q", output)
+	}
+
+	if output := NewTerminalFormatter().FormatItem(item); strings.Contains(output, "\x1b") || !strings.Contains(output, "  https://www.youtube.com/watch?v=abc") {
+		t.Errorf("without hyperlinks the URL should be printed on its own line, got: %q", output)
+	}
+}
+
+func TestAC312_TerminalFeed_FlagsUnplayableItems(t *testing.T) {
+	item := aggregator.FeedItem{Title: "Video", Source: aggregator.SourceYouTube, PublishedAt: time.Now(), Restriction: "not available in FR"}
+
+	output := NewTerminalFormatter().FormatItem(item)
+	if !strings.Contains(output, separator+"not available in FR") {
+		t.Errorf("user should see why a video won't play, got:\n%s", output)
+	}
+}
+
+func TestAC313_TerminalFeed_ColorsWithTheme(t *testing.T) {
+	item := aggregator.FeedItem{Title: "Video", Author: "Channel", URL: "https://example.com", Source: aggregator.SourceYouTube, PublishedAt: time.Now()}
+	theme, ok := LookupTheme(DefaultTheme)
+	if !ok {
+		t.Fatal("the default theme should exist")
+	}
+
+	output := NewTerminalFormatter(WithTheme(theme)).FormatItem(item)
+	if !strings.Contains(output, "\x1b[31m[YOUTUBE]\x1b[0m \x1b[1mVideo\x1b[0m") {
+		t.Errorf("source tag and title should be styled by the theme, got: %q", output)
+	}
+	if !strings.Contains(output, "  \x1b[2mby Channel") {
+		t.Errorf("metadata should be dimmed, got: %q", output)
+	}
+
+	if output := NewTerminalFormatter().FormatItem(item); strings.Contains(output, "\x1b[") {
+		t.Errorf("output should be monochrome without a theme, got: %q", output)
+	}
+}
+
+func TestAC314_TerminalFeed_CompactShowsOneAlignedLinePerItem(t *testing.T) {
+	now := time.Now()
+	items := []aggregator.FeedItem{
+		{Title: "First", Author: "Short", Source: aggregator.SourceYouTube, PublishedAt: now.Add(-3 * time.Hour)},
+		{Title: "Second", Author: "A much longer channel name", Source: aggregator.SourceSubstack, PublishedAt: now.Add(-2 * 24 * time.Hour)},
+	}
+
+	output := NewTerminalFormatter(WithCompact(true)).FormatFeed(items)
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("compact feed should use one line per item, got:\n%s", output)
+	}
+	if lines[0] != "  1. 3h     ▶ Short                First" {
+		t.Errorf("unexpected first line %q", lines[0])
+	}
+	if lines[1] != "  2. 2d     ✉ A much longer cha... Second" {
+		t.Errorf("authors should be truncated to keep titles aligned, got %q", lines[1])
+	}
+}
+
+func TestAC315_TerminalFeed_DayHeadersSeparateEachDay(t *testing.T) {
+	now := time.Now()
+	items := []aggregator.FeedItem{
+		{Title: "Fresh", Author: "A", Source: aggregator.SourceYouTube, PublishedAt: now},
+		{Title: "Also fresh", Author: "A", Source: aggregator.SourceYouTube, PublishedAt: now},
+		{Title: "Older", Author: "B", Source: aggregator.SourceSubstack, PublishedAt: now.AddDate(0, 0, -1)},
+		{Title: "Oldest", Author: "C", Source: aggregator.SourceSubstack, PublishedAt: now.AddDate(0, 0, -10)},
+	}
+
+	output := NewTerminalFormatter(WithCompact(true), WithDayHeaders(true)).FormatFeed(items)
+	oldest := "── " + items[3].PublishedAt.Format("Monday, Jan 2") + " ──"
+	if items[3].PublishedAt.Year() != now.Year() {
+		oldest = "── " + items[3].PublishedAt.Format("Monday, Jan 2, 2006") + " ──"
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	want := []string{"── Today ──", "  1.", "  2.", "", "── Yesterday ──", "  3.", "", oldest, "  4."}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), output)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) || (prefix == "" && lines[i] != "") {
+			t.Errorf("line %d should start with %q, got %q", i+1, prefix, lines[i])
+		}
+	}
+
+	if output := NewTerminalFormatter().FormatFeed(items); strings.Contains(output, "Today") {
+		t.Errorf("day headers should be off by default, got:\n%s", output)
+	}
+}
+
+func TestAC315_TerminalFeed_DayHeadersReplaceDividerBetweenDays(t *testing.T) {
+	now := time.Now()
+	items := []aggregator.FeedItem{
+		{Title: "Fresh", Source: aggregator.SourceYouTube, PublishedAt: now},
+		{Title: "Older", Source: aggregator.SourceYouTube, PublishedAt: now.AddDate(0, 0, -1)},
+	}
+
+	output := NewTerminalFormatter(WithDayHeaders(true)).FormatFeed(items)
+	if !strings.HasPrefix(output, "── Today ──\n\n1. ") {
+		t.Errorf("feed should open with the first day's header, got:\n%s", output)
+	}
+	if strings.Contains(output, "---") || !strings.Contains(output, "\n\n── Yesterday ──\n\n2. ") {
+		t.Errorf("a new day should start with its header instead of a divider, got:\n%s", output)
+	}
+}
+
+func TestAC316_TerminalFeed_GroupByPutsHeaderBeforeEachSection(t *testing.T) {
+	now := time.Now()
+	items := []aggregator.FeedItem{
+		{Title: "First", Author: "Fireship", AuthorHandle: "@Fireship", AuthorID: "UC_F", Source: aggregator.SourceYouTube, PublishedAt: now},
+		{Title: "Second", Author: "Fireship", AuthorHandle: "@Fireship", AuthorID: "UC_F", Source: aggregator.SourceYouTube, PublishedAt: now.AddDate(0, 0, -1)},
+		{Title: "Third", Author: "Simon", Source: aggregator.SourceSubstack, PublishedAt: now},
+	}
+
+	output := NewTerminalFormatter(WithCompact(true), WithGroupBy(aggregator.GroupByAuthor), WithDayHeaders(true)).FormatFeed(items)
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	want := []string{"── Fireship (@Fireship) [YOUTUBE] ──", "  1.", "  2.", "", "── Simon [SUBSTACK] ──", "  3."}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), output)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d should start with %q, got %q", i+1, prefix, lines[i])
+		}
+	}
+
+	output = NewTerminalFormatter(WithGroupBy(aggregator.GroupBySource)).FormatFeed(items[:2])
+	if !strings.HasPrefix(output, "── YOUTUBE ──\n\n1. ") || !strings.Contains(output, "---") {
+		t.Errorf("a source section should open with its header and keep dividers inside, got:\n%s", output)
+	}
+}
+
+func TestAC317_TerminalFeed_AgesItemsAgainstTheClock(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{{Title: "Pinned", Source: aggregator.SourceYouTube, PublishedAt: now.Add(-2 * time.Hour)}}
+
+	if output := NewTerminalFormatter(WithClock(clock.Fixed(now))).FormatFeed(items); !strings.Contains(output, "2 hours ago") {
+		t.Errorf("expected the age measured against the fixed clock, got:\n%s", output)
+	}
+	if output := NewTerminalFormatter(WithCompact(true), WithClock(clock.Fixed(now))).FormatFeed(items); !strings.Contains(output, "2h") {
+		t.Errorf("expected the compact age measured against the fixed clock, got:\n%s", output)
+	}
+}
+
+func TestAC318_TerminalFeed_WrapsTitlesAndDescriptionsToTheWidth(t *testing.T) {
+	item := aggregator.FeedItem{
+		Title:       "A rather long article title that cannot possibly fit on one forty column line",
+		Description: "<p>" + strings.Repeat("Long Substack descriptions used to run far past the edge of the terminal. ", 3) + "</p>",
+		Author:      "Simon",
+		Source:      aggregator.SourceSubstack,
+		PublishedAt: time.Now(),
+	}
+
+	output := NewTerminalFormatter(WithWidth(40), WithDescription(500)).FormatFeed([]aggregator.FeedItem{item})
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > 40 {
+			t.Errorf("line exceeds 40 columns: %q", line)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "1. [SUBSTACK] A rather long") || !strings.HasPrefix(lines[1], "  ") {
+		t.Errorf("the title should wrap onto indented lines, got:\n%s", output)
+	}
+	if words := strings.Join(strings.Fields(output), " "); !strings.Contains(words, PlainText(item.Description)) {
+		t.Errorf("wrapping should keep the whole description, got:\n%s", output)
+	}
+
+	unbounded := NewTerminalFormatter(WithDescription(500)).FormatFeed([]aggregator.FeedItem{item})
+	if !strings.HasPrefix(unbounded, "1. [SUBSTACK] "+item.Title+"\n") {
+		t.Errorf("without a width the title should stay on one line, got:\n%s", unbounded)
+	}
+}
+
+func TestAC319_CompactFeed_FitsTitlesToTheWidth(t *testing.T) {
+	items := []aggregator.FeedItem{
+		{Title: strings.Repeat("word ", 30), Author: "Fireship", Source: aggregator.SourceYouTube, PublishedAt: time.Now()},
+		{Title: strings.Repeat("日本語", 20), Author: "NHK", Source: aggregator.SourceYouTube, PublishedAt: time.Now()},
+	}
+
+	output := NewTerminalFormatter(WithCompact(true), WithWidth(80)).FormatFeed(items)
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if n := columns(line); n > 80 {
+			t.Errorf("line takes %d columns, more than 80: %q", n, line)
+		}
+		if !strings.HasSuffix(line, "...") {
+			t.Errorf("a cut title should end with an ellipsis, got %q", line)
+		}
+	}
+}
+
+func TestAC320_TerminalFeed_DrawsInlineThumbnailsOrFallsBackToURLs(t *testing.T) {
+	var thumbnail bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 480, 270))
+	for x := 0; x < 480; x++ {
+		img.Set(x, x%270, color.RGBA{R: 255, A: 255})
+	}
+	if err := png.Encode(&thumbnail, img); err != nil {
+		t.Fatal(err)
+	}
+	load := func(url string) ([]byte, error) {
+		if url == "https://i.ytimg.com/vi/a/mqdefault.jpg" {
+			return thumbnail.Bytes(), nil
+		}
+		return nil, errors.New("not found")
+	}
+	item := aggregator.FeedItem{Title: "Pictured", Source: aggregator.SourceYouTube, PublishedAt: time.Now(), Thumbnail: "https://i.ytimg.com/vi/a/mqdefault.jpg"}
+
+	for protocol, want := range map[ImageProtocol]string{
+		ImageKitty: "\x1b_Ga=T,f=100,q=2,c=24,r=7,",
+		ImageITerm: "\x1b]1337;File=inline=1;",
+		ImageSixel: "\x1bPq\"1;1;240;135#",
+	} {
+		output := NewTerminalFormatter(WithInlineThumbnails(protocol, load)).FormatItem(item)
+		if !strings.Contains(output, "\n  "+want) || strings.Contains(output, "thumbnail:") {
+			t.Errorf("%s: expected the image drawn under the item, got %q", protocol, output)
+		}
+	}
+
+	item.Thumbnail = "https://i.ytimg.com/vi/missing/mqdefault.jpg"
+	output := NewTerminalFormatter(WithInlineThumbnails(ImageKitty, load)).FormatItem(item)
+	if !strings.Contains(output, "thumbnail: "+item.Thumbnail) {
+		t.Errorf("a thumbnail that fails to load should fall back to its URL, got %q", output)
+	}
+}
+
+func TestAC321_TerminalFeed_FormatsEachItemWithATemplate(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	tmpl, err := ParseTemplate(`{{.N}}|{{upper .Source}}|{{truncate 10 .Title}}|{{ago .PublishedAt}}|{{date "2006-01-02" .PublishedAt}}|{{count .Engagement.Views}}|{{engagement .Engagement}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []aggregator.FeedItem{
+		{Title: "A rather long video title", Source: aggregator.SourceYouTube, PublishedAt: now.Add(-2 * time.Hour), Engagement: aggregator.Engagement{Views: 1500000, Likes: 45000}},
+		{Title: "Short", Source: aggregator.SourceSubstack, PublishedAt: now.Add(-26 * time.Hour), Author: "Writer"},
+	}
+
+	output := NewTerminalFormatter(WithTemplate(tmpl), WithClock(clock.Fixed(now)), WithDayHeaders(true)).FormatFeed(items)
+	want := "1|YOUTUBE|A rathe...|2 hours ago|2024-01-02|1.5M|1.5M views • 45K likes\n" +
+		"2|SUBSTACK|Short|1 day ago|2024-01-01|0|\n"
+	if output != want {
+		t.Errorf("expected one templated line per item and nothing else:\ngot  %q\nwant %q", output, want)
+	}
+
+	if output := NewTerminalFormatter(WithTemplate(tmpl)).FormatFeed(nil); output != "" {
+		t.Errorf("an empty feed should print nothing with a template, got %q", output)
+	}
+
+	for _, bad := range []string{"{{.Title", "{{.Titel}}", "{{nope .Title}}"} {
+		if _, err := ParseTemplate(bad); err == nil {
+			t.Errorf("ParseTemplate(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestAC322_CSV_WritesOneRowPerItemUnderAHeader(t *testing.T) {
+	published := time.Date(2024, 1, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	items := []aggregator.FeedItem{
+		{ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: `Say "hi", world`, Author: "Tech", URL: "https://www.youtube.com/watch?v=v1", PublishedAt: published, Engagement: aggregator.Engagement{Views: 1500, Likes: 20, Comments: 3}},
+		{ID: "p1", Source: aggregator.SourceSubstack, Title: "Line\nbreak", URL: "https://example.substack.com/p/post"},
+	}
+	want := "id,source,type,title,author,url,published_at,views,likes,comments\n" +
+		`v1,youtube,video,"Say ""hi"", world",Tech,https://www.youtube.com/watch?v=v1,2024-01-01T12:00:00Z,1500,20,3` + "\n" +
+		"p1,substack,,\"Line\nbreak\",,https://example.substack.com/p/post,,0,0,0\n"
+
+	var out bytes.Buffer
+	if err := WriteCSV(&out, items); err != nil || out.String() != want {
+		t.Errorf("unexpected CSV (err %v):\ngot  %q\nwant %q", err, out.String(), want)
+	}
+
+	stream := make(chan aggregator.FeedItem, len(items))
+	for _, item := range items {
+		stream <- item
+	}
+	close(stream)
+	out.Reset()
+	written, err := StreamCSV(&out, stream)
+	if err != nil || len(written) != 2 || out.String() != want {
+		t.Errorf("streamed CSV should match, got %d items (err %v):\n%q", len(written), err, out.String())
+	}
+}
+
+func TestAC323_JSONFeed_WritesAJSONFeedDocument(t *testing.T) {
+	published := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{
+		{ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeVideo, Title: "Video", Author: "Tech", Group: "tech", URL: "https://www.youtube.com/watch?v=v1", Thumbnail: "https://i.ytimg.com/vi/v1/mqdefault.jpg", PublishedAt: published, Engagement: aggregator.Engagement{Views: 1500}},
+		{ID: "p1", Source: aggregator.SourceSubstack, Title: "Post", Description: "<p>Hello</p>", URL: "https://example.substack.com/p/post", PublishedAt: published, UpdatedAt: published.Add(time.Hour)},
+	}
+
+	var out bytes.Buffer
+	if err := WriteJSONFeed(&out, "feedmix", items); err != nil {
+		t.Fatal(err)
+	}
+	var feed map[string]any
+	if err := json.Unmarshal(out.Bytes(), &feed); err != nil {
+		t.Fatalf("output should be JSON: %v\n%s", err, out.String())
+	}
+	if feed["version"] != "https://jsonfeed.org/version/1.1" || feed["title"] != "feedmix" {
+		t.Errorf("expected a JSON Feed 1.1 header, got %v", feed)
+	}
+	entries, _ := feed["items"].([]any)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 items, got %v", feed["items"])
+	}
+	video, post := entries[0].(map[string]any), entries[1].(map[string]any)
+	if video["id"] != "youtube:v1" || video["content_text"] != "" || video["image"] != items[0].Thumbnail || video["date_published"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("unexpected video item: %v", video)
+	}
+	if authors, _ := video["authors"].([]any); len(authors) != 1 || authors[0].(map[string]any)["name"] != "Tech" {
+		t.Errorf("expected the channel as author, got %v", video["authors"])
+	}
+	if ext, _ := video["_feedmix"].(map[string]any); ext["source"] != "youtube" || ext["engagement"].(map[string]any)["views"] != 1500.0 {
+		t.Errorf("expected source and engagement in the _feedmix extension, got %v", video["_feedmix"])
+	}
+	if post["content_html"] != "<p>Hello</p>" || post["date_modified"] != "2024-01-01T13:00:00Z" || post["authors"] != nil {
+		t.Errorf("unexpected post item: %v", post)
+	}
+}
+
+func TestAC324_Atom_WritesAValidAtomFeed(t *testing.T) {
+	published := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{
+		{ID: "v1", Source: aggregator.SourceYouTube, Title: "Tips & tricks <live>", Author: "Tech", Group: "tech", URL: "https://www.youtube.com/watch?v=v1", PublishedAt: published},
+		{ID: "p1", Source: aggregator.SourceSubstack, Title: "Post", Description: "<p>Hello</p>", URL: "https://example.substack.com/p/post", PublishedAt: published.Add(-time.Hour), UpdatedAt: published.Add(time.Hour)},
+	}
+
+	var out bytes.Buffer
+	if err := WriteAtom(&out, "My feed", items, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var feed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Summary struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"summary"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(out.Bytes(), &feed); err != nil {
+		t.Fatalf("output should be Atom XML: %v\n%s", err, out.String())
+	}
+	if feed.ID == "" || feed.Title != "My feed" || feed.Updated != "2024-01-01T13:00:00Z" || len(feed.Entries) != 2 {
+		t.Fatalf("expected a feed updated with its newest item, got %+v", feed)
+	}
+	video, post := feed.Entries[0], feed.Entries[1]
+	if video.Title != "Tips & tricks <live>" || video.Link.Href != items[0].URL || len(video.Categories) != 2 || video.Categories[0].Term != "youtube" || video.Categories[1].Term != "tech" {
+		t.Errorf("unexpected video entry: %+v", video)
+	}
+	if post.Updated != "2024-01-01T13:00:00Z" || post.Summary.Type != "html" || post.Summary.Body != "<p>Hello</p>" {
+		t.Errorf("unexpected post entry: %+v", post)
+	}
+
+	out.Reset()
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := WriteAtom(&out, "Empty", nil, now); err != nil || !strings.Contains(out.String(), "<updated>2024-02-01T00:00:00Z</updated>") {
+		t.Errorf("an empty feed should be updated now, got %s (err %v)", out.String(), err)
+	}
+}
+
+func TestAC325_Display_ShowsEngagementGrowthAsASparkline(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	item := aggregator.FeedItem{
+		ID: "v1", Source: aggregator.SourceYouTube, Title: "Video", PublishedAt: now.Add(-3 * time.Hour),
+		Engagement: aggregator.Engagement{Views: 4000},
+		Trend: []aggregator.Sample{
+			{At: now.Add(-3 * time.Hour), Views: 1000},
+			{At: now.Add(-2 * time.Hour), Views: 1100},
+			{At: now.Add(-time.Hour), Views: 2000},
+			{At: now, Views: 4000},
+		},
+	}
+	formatter := NewTerminalFormatter(WithClock(func() time.Time { return now }))
+
+	if got := formatter.FormatItem(item); !strings.Contains(got, "4K views • ▁▄█ +1K/h") {
+		t.Errorf("the engagement line should show the hourly gains as a sparkline and the average gain, got:\n%s", got)
+	}
+	item.Trend = nil
+	if got := formatter.FormatItem(item); strings.Contains(got, "/h") {
+		t.Errorf("items without a trend should show no growth, got:\n%s", got)
+	}
+}
+
+func TestAC326_ICS_WritesUpcomingBroadcastsAsEvents(t *testing.T) {
+	start := time.Date(2024, 1, 20, 18, 0, 0, 0, time.UTC)
+	items := []aggregator.FeedItem{
+		{ID: "v1", Source: aggregator.SourceYouTube, Title: "Launch stream; Q&A, live", Author: "Tech", URL: "https://www.youtube.com/watch?v=v1", Description: "Join us\nlive",
+			Broadcast: &aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Start: start}},
+		{ID: "v2", Source: aggregator.SourceYouTube, Title: "Plain upload"},
+		{ID: "v3", Source: aggregator.SourceYouTube, Title: strings.Repeat("Long title ", 10), Author: "Tech",
+			Broadcast: &aggregator.Broadcast{Status: aggregator.BroadcastLive, Start: start.Add(-time.Hour), End: start.Add(2 * time.Hour)}},
+	}
+
+	var out bytes.Buffer
+	if err := WriteICS(&out, items, start.Add(-24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:youtube-v1@feedmix\r\nDTSTAMP:20240119T180000Z\r\nDTSTART:20240120T180000Z\r\nDTEND:20240120T190000Z\r\n",
+		`SUMMARY:Tech: Launch stream\; Q&A\, live` + "\r\n",
+		`DESCRIPTION:https://www.youtube.com/watch?v=v1\n\nJoin us\nlive` + "\r\n",
+		"DTSTART:20240120T170000Z\r\nDTEND:20240120T200000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("calendar should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Plain upload") || strings.Count(got, "BEGIN:VEVENT") != 2 {
+		t.Errorf("only broadcasts should become events, got:\n%s", got)
+	}
+	for _, line := range strings.Split(got, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("lines should be folded at 75 octets, got %d: %q", len(line), line)
+		}
+	}
+}
+
+func TestAC327_Display_BadgesLiveStreamsAndPremieres(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	item := func(b aggregator.Broadcast) aggregator.FeedItem {
+		return aggregator.FeedItem{ID: "v1", Source: aggregator.SourceYouTube, Type: aggregator.ItemTypeLive, Title: "Launch", Author: "Tech", PublishedAt: now.Add(-time.Hour), Broadcast: &b}
+	}
+	formatter := NewTerminalFormatter(WithClock(func() time.Time { return now }))
+
+	for _, tc := range []struct {
+		broadcast aggregator.Broadcast
+		want      string
+	}{
+		{aggregator.Broadcast{Status: aggregator.BroadcastLive, Start: now.Add(-time.Hour)}, "[YOUTUBE] LIVE Launch"},
+		{aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Start: now.Add(2*time.Hour + 10*time.Minute), Premiere: true}, "[YOUTUBE] Premieres in 2h Launch"},
+		{aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Start: now.Add(15 * time.Minute)}, "[YOUTUBE] Live in 15m Launch"},
+		{aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Start: now.Add(-time.Minute)}, "[YOUTUBE] Going live soon Launch"},
+		{aggregator.Broadcast{Status: aggregator.BroadcastUpcoming, Premiere: true}, "[YOUTUBE] Upcoming premiere Launch"},
+	} {
+		if got := formatter.FormatItem(item(tc.broadcast)); !strings.HasPrefix(got, tc.want+"\n") {
+			t.Errorf("%+v: expected the header %q, got:\n%s", tc.broadcast, tc.want, got)
+		}
+	}
+
+	compact := NewTerminalFormatter(WithClock(func() time.Time { return now }), WithCompact(true))
+	if got := compact.FormatFeed([]aggregator.FeedItem{item(aggregator.Broadcast{Status: aggregator.BroadcastLive})}); !strings.Contains(got, " LIVE Launch") {
+		t.Errorf("the compact layout should badge broadcasts too, got:\n%s", got)
+	}
+	if got := formatter.FormatItem(aggregator.FeedItem{Source: aggregator.SourceYouTube, Title: "Upload"}); !strings.HasPrefix(got, "[YOUTUBE] Upload\n") {
+		t.Errorf("other items should have no badge, got:\n%s", got)
+	}
+	if got := formatter.FormatItem(aggregator.FeedItem{Source: aggregator.SourceYouTube, Title: "Upload", Gone: "removed"}); !strings.HasPrefix(got, "[YOUTUBE] Gone: removed Upload\n") {
+		t.Errorf("an item found gone should say so, got:\n%s", got)
+	}
+}
+
+// TestAC328_Display_ListsChaptersWithDetails documents --details:
+// - each chapter of a video is listed under it as "m:ss Title", or "h:mm:ss Title" past an hour
+// - chapters are hidden by default
+func TestAC328_Display_ListsChaptersWithDetails(t *testing.T) {
+	item := aggregator.FeedItem{
+		ID: "v1", Source: aggregator.SourceYouTube, Title: "Deep dive", Author: "Tech", URL: "https://youtube.com/watch?v=v1",
+		Chapters: []aggregator.Chapter{{Start: 0, Title: "Intro"}, {Start: 95 * time.Second, Title: "Setup"}, {Start: time.Hour + 2*time.Minute + 3*time.Second, Title: "Q&A"}},
+	}
+
+	got := NewTerminalFormatter(WithDetails(true)).FormatItem(item)
+	if !strings.Contains(got, "  0:00 Intro\n  1:35 Setup\n  1:02:03 Q&A\n") {
+		t.Errorf("expected the chapters listed under the video, got:\n%s", got)
+	}
+	if got := NewTerminalFormatter().FormatItem(item); strings.Contains(got, "Intro") {
+		t.Errorf("chapters should be hidden without details, got:\n%s", got)
+	}
+}
+
+// TestAC329_Display_FormatsWholeArticles documents 'feedmix read':
+// - the item's header is followed by its content as wrapped paragraphs
+// - headings, list items, quotes and code blocks keep their shape
+// - links are numbered in the text and listed at the end; scripts and buttons are dropped
+// - without content, the description is shown with its own line breaks
+func TestAC329_Display_FormatsWholeArticles(t *testing.T) {
+	item := aggregator.FeedItem{
+		ID: "p1", Source: aggregator.SourceSubstack, Title: "Essay", Author: "Writer", URL: "https://x.substack.com/p/essay",
+		Description: "An excerpt.",
+		Content: `<h2>On reading</h2><p>Long articles deserve a calm place to be read, away from
+			the <a href="https://example.com/noise">noise</a> &amp; the ads.</p>
+			<ul><li>first</li><li><p>second</p></li></ul>
+			<blockquote><p>Quoted words</p></blockquote>
+			<pre>code  line
+  indented</pre><script>track()</script><p><button>Subscribe</button></p>`,
+	}
+
+	got := NewTerminalFormatter(WithWidth(40)).FormatArticle(item)
+	want := "[SUBSTACK] Essay\n" +
+		"  by Writer • Jan 1, 0001\n" +
+		"  https://x.substack.com/p/essay\n" +
+		"\n## On reading\n" +
+		"\nLong articles deserve a calm place to be\nread, away from the noise [1] & the ads.\n" +
+		"\n• first\n• second\n" +
+		"\n│ Quoted words\n" +
+		"\ncode  line\n  indented\n" +
+		"\n[1] https://example.com/noise\n"
+	if got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	item.Content = ""
+	item.Description = "Line one\nLine 2 < 3"
+	if got := NewTerminalFormatter().FormatArticle(item); !strings.HasSuffix(got, "\nLine one\nLine 2 < 3\n") {
+		t.Errorf("the description should stand in for missing content, got:\n%s", got)
+	}
+}
+
+// TestAC330_Display_LocksPaywalledPosts documents paid posts:
+// - posts only paid subscribers can read in full have a 🔒 before their title, in both layouts
+func TestAC330_Display_LocksPaywalledPosts(t *testing.T) {
+	item := aggregator.FeedItem{ID: "p1", Source: aggregator.SourceSubstack, Title: "Members only", Author: "Writer", Paywalled: true}
+
+	if got := NewTerminalFormatter().FormatItem(item); !strings.HasPrefix(got, "[SUBSTACK] 🔒 Members only\n") {
+		t.Errorf("expected a lock before the title, got:\n%s", got)
+	}
+	if got := NewTerminalFormatter(WithCompact(true)).FormatFeed([]aggregator.FeedItem{item}); !strings.Contains(got, "🔒 Members only") {
+		t.Errorf("expected a lock before the compact title, got:\n%s", got)
+	}
+	item.Paywalled = false
+	if got := NewTerminalFormatter().FormatItem(item); strings.Contains(got, "🔒") {
+		t.Errorf("free posts should have no lock, got:\n%s", got)
+	}
+}
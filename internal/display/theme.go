@@ -0,0 +1,113 @@
+package display
+
+import (
+	"sort"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+// Theme holds the ANSI SGR parameters ("1" for bold, "2;37" for dim white,
+// ...) used for each part of an item. Empty parameters leave text unstyled.
+type Theme struct {
+	Title      string
+	Meta       string
+	Engagement string
+	URL        string
+	// Badge styles the LIVE and "Premieres in 2h" badges of broadcasts.
+	Badge string
+	// Sources styles the [SOURCE] tag; unlisted sources use Meta.
+	Sources map[aggregator.Source]string
+}
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "default"
+
+var themes = map[string]Theme{
+	DefaultTheme: {
+		Title:      "1",
+		Meta:       "2",
+		Engagement: "2",
+		URL:        "34",
+		Badge:      "1;31",
+		Sources: map[aggregator.Source]string{
+			aggregator.SourceYouTube:  "31",
+			aggregator.SourceSubstack: "33",
+			aggregator.SourceReader:   "32",
+			aggregator.SourceBridge:   "35",
+			aggregator.SourcePodcast:  "36",
+			aggregator.SourceTwitch:   "95",
+			aggregator.SourceGitHub:   "37",
+			aggregator.SourceMedium:   "32",
+			aggregator.SourceLobsters: "91",
+			aggregator.SourceArxiv:    "94",
+			aggregator.SourcePeerTube: "33",
+		},
+	},
+	"vivid": {
+		Title:      "1;97",
+		Meta:       "36",
+		Engagement: "32",
+		URL:        "4;94",
+		Badge:      "1;97;41",
+		Sources: map[aggregator.Source]string{
+			aggregator.SourceYouTube:  "1;91",
+			aggregator.SourceSubstack: "1;38;5;208",
+			aggregator.SourceReader:   "1;92",
+			aggregator.SourceBridge:   "1;95",
+			aggregator.SourcePodcast:  "1;96",
+			aggregator.SourceTwitch:   "1;38;5;135",
+			aggregator.SourceGitHub:   "1;97",
+			aggregator.SourceMedium:   "1;38;5;34",
+			aggregator.SourceLobsters: "1;38;5;160",
+			aggregator.SourceArxiv:    "1;38;5;124",
+			aggregator.SourcePeerTube: "1;38;5;214",
+		},
+	},
+	// mono only uses weight, for terminals with clashing palettes.
+	"mono": {
+		Title:      "1",
+		Meta:       "2",
+		Engagement: "2",
+		URL:        "4",
+		Badge:      "7",
+	},
+}
+
+// LookupTheme returns the built-in theme called name.
+func LookupTheme(name string) (Theme, bool) {
+	theme, ok := themes[name]
+	return theme, ok
+}
+
+// ThemeNames lists the built-in themes in alphabetical order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithTheme colors output with theme. Output is monochrome by default; only
+// enable colors when writing to a terminal.
+func WithTheme(theme Theme) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.theme = theme
+	}
+}
+
+// paint wraps s in the SGR sequence sgr, resetting the style afterwards.
+func paint(sgr, s string) string {
+	if sgr == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + sgr + "m" + s + "\x1b[0m"
+}
+
+func (t Theme) source(source aggregator.Source) string {
+	if sgr, ok := t.Sources[source]; ok {
+		return sgr
+	}
+	return t.Meta
+}
@@ -0,0 +1,71 @@
+package display
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// bracketTags are [TAGS] and 【TAGS】 such as "[OFFICIAL VIDEO]", which
+	// rarely say anything about the content.
+	bracketTags   = regexp.MustCompile(`\s*(\[[^\]]*\]|【[^】]*】)\s*`)
+	parens        = regexp.MustCompile(`\s*\([^()]*\)\s*`)
+	repeatedMarks = regexp.MustCompile(`[!?]{2,}`)
+)
+
+// calmTitle tones down a clickbait title: it drops emoji and bracketed tags,
+// collapses "!!!" and "?!?" into a single mark and lowercases SHOUTED words
+// of four letters or more, so acronyms such as "AI" or "GPU" survive. A title
+// that would end up empty is returned unchanged.
+func calmTitle(title string) string {
+	calm := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) || r == '\u200d' || unicode.Is(unicode.Variation_Selector, r) {
+			return -1
+		}
+		return r
+	}, title)
+	calm = bracketTags.ReplaceAllString(calm, " ")
+	calm = parens.ReplaceAllStringFunc(calm, func(aside string) string {
+		if isShouted(aside) {
+			return " "
+		}
+		return aside
+	})
+	calm = repeatedMarks.ReplaceAllStringFunc(calm, func(marks string) string {
+		return marks[:1]
+	})
+
+	words := strings.Fields(calm)
+	lowered := false
+	for i, word := range words {
+		if isShouted(word) {
+			words[i] = strings.ToLower(word)
+			lowered = true
+		}
+	}
+	calm = strings.Join(words, " ")
+	if calm == "" {
+		return title
+	}
+	if lowered {
+		first, size := utf8.DecodeRuneInString(calm)
+		calm = string(unicode.ToUpper(first)) + calm[size:]
+	}
+	return calm
+}
+
+// isShouted reports whether s has at least four letters, all upper case.
+func isShouted(s string) bool {
+	letters := 0
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsUpper(r) {
+			letters++
+		}
+	}
+	return letters >= 4
+}
@@ -0,0 +1,94 @@
+package display
+
+import (
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// minWrapWidth is the narrowest column text is wrapped or truncated to, so a
+// tiny terminal still shows a few words per line rather than one letter.
+const minWrapWidth = 20
+
+// WithWidth fits the feed to a terminal n columns wide: titles and
+// descriptions wrap onto indented lines and compact titles are truncated to
+// the end of the line. 0 leaves lines unbounded, which is the default.
+func WithWidth(n int) FormatterOption {
+	return func(f *TerminalFormatter) {
+		f.width = n
+	}
+}
+
+// columns returns how many terminal columns s takes: wide and fullwidth
+// characters, such as CJK ideographs, take two.
+func columns(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeColumns(r)
+	}
+	return n
+}
+
+func runeColumns(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// fitColumns truncates s to at most n columns, ending with "..." when cut.
+func fitColumns(s string, n int) string {
+	if columns(s) <= n {
+		return s
+	}
+	if n <= 3 {
+		return "..."
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		if used+runeColumns(r) > n-3 {
+			break
+		}
+		b.WriteRune(r)
+		used += runeColumns(r)
+	}
+	return b.String() + "..."
+}
+
+// wrap breaks text at spaces into a first line of at most first columns and
+// further lines of at most rest columns. Words longer than a line are split.
+func wrap(text string, first, rest int) []string {
+	first, rest = max(first, minWrapWidth), max(rest, minWrapWidth)
+	var lines []string
+	var line strings.Builder
+	used, limit := 0, first
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		used, limit = 0, rest
+	}
+	for _, word := range strings.Fields(text) {
+		n := columns(word)
+		if used > 0 && used+1+n > limit {
+			flush()
+		}
+		if used > 0 {
+			line.WriteByte(' ')
+			used++
+		}
+		for _, r := range word {
+			if used+runeColumns(r) > limit {
+				flush()
+			}
+			line.WriteRune(r)
+			used += runeColumns(r)
+		}
+	}
+	if used > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
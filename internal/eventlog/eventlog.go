@@ -0,0 +1,140 @@
+// Package eventlog records item lifecycle events as an append-only JSON Lines file.
+package eventlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+const (
+	defaultMaxBytes = 10 << 20
+	defaultKeep     = 5
+)
+
+// Type identifies a lifecycle event.
+type Type string
+
+const (
+	// Discovered is recorded for every item a run finds that no earlier run
+	// had seen.
+	Discovered Type = "discovered"
+	// Displayed is recorded for every item shown to the user.
+	Displayed Type = "displayed"
+	// Opened is recorded when the user opens an item in the browser.
+	Opened Type = "opened"
+	// Read is recorded when the user reads an item with 'feedmix read', or
+	// 'feedmix sync' brings in that it was read on the reader server.
+	Read Type = "read"
+	// Saved is recorded when the user saves an item with 'feedmix save', or
+	// 'feedmix sync' brings in that it was starred on the reader server.
+	Saved Type = "saved"
+)
+
+// Event is one line of the log.
+type Event struct {
+	Time   time.Time         `json:"time"`
+	Type   Type              `json:"type"`
+	Source aggregator.Source `json:"source"`
+	ItemID string            `json:"item_id"`
+	Title  string            `json:"title,omitempty"`
+	URL    string            `json:"url,omitempty"`
+}
+
+// Log appends events to a file, rotating it once it grows past a size limit.
+// Rotated files are named <path>.1 (newest) to <path>.N (oldest).
+type Log struct {
+	path     string
+	maxBytes int64
+	keep     int
+	now      clock.Clock
+}
+
+// Option configures a Log.
+type Option func(*Log)
+
+// WithRotation sets the size at which the log rotates and how many rotated files are kept.
+func WithRotation(maxBytes int64, keep int) Option {
+	return func(l *Log) {
+		l.maxBytes = maxBytes
+		l.keep = keep
+	}
+}
+
+// WithClock sets the clock events are timestamped with.
+func WithClock(now clock.Clock) Option {
+	return func(l *Log) {
+		l.now = now
+	}
+}
+
+// New creates a Log writing to path.
+func New(path string, opts ...Option) *Log {
+	l := &Log{path: path, maxBytes: defaultMaxBytes, keep: defaultKeep, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Record appends one event of type t per item.
+func (l *Log) Record(t Type, items []aggregator.FeedItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	now := l.now().UTC()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, item := range items {
+		if err := enc.Encode(Event{Time: now, Type: t, Source: item.Source, ItemID: item.ID, Title: item.Title, URL: item.URL}); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create event log directory: %w", err)
+	}
+	if err := l.rotateIfNeeded(int64(buf.Len())); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- path comes from user configuration
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return f.Close()
+}
+
+func (l *Log) rotateIfNeeded(incoming int64) error {
+	info, err := os.Stat(l.path)
+	if err != nil || info.Size() == 0 || info.Size()+incoming <= l.maxBytes {
+		return nil
+	}
+
+	_ = os.Remove(l.rotated(l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		_ = os.Rename(l.rotated(i), l.rotated(i+1))
+	}
+	if l.keep < 1 {
+		return os.Remove(l.path)
+	}
+	if err := os.Rename(l.path, l.rotated(1)); err != nil {
+		return fmt.Errorf("failed to rotate event log: %w", err)
+	}
+	return nil
+}
+
+func (l *Log) rotated(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
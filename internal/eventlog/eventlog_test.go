@@ -0,0 +1,102 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("every line should be a JSON event, got %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestLog_AppendsOneJSONLinePerItem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "events.jsonl")
+	log := New(path)
+	log.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	items := []aggregator.FeedItem{
+		{ID: "vid1", Source: aggregator.SourceYouTube, Title: "Video", URL: "https://www.youtube.com/watch?v=vid1"},
+		{ID: "post1", Source: aggregator.SourceSubstack, Title: "Post"},
+	}
+	if err := log.Record(Discovered, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := log.Record(Displayed, items[:1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := readEvents(t, path)
+	if len(events) != 3 {
+		t.Fatalf("log should be append-only with one line per item event, got %d lines", len(events))
+	}
+	if events[0].Type != Discovered || events[0].ItemID != "vid1" || events[0].Source != aggregator.SourceYouTube {
+		t.Errorf("first event should describe the discovered video, got %+v", events[0])
+	}
+	if events[2].Type != Displayed || events[2].URL != "https://www.youtube.com/watch?v=vid1" {
+		t.Errorf("last event should record the displayed item with its URL, got %+v", events[2])
+	}
+	if !events[0].Time.Equal(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("events should be timestamped, got %v", events[0].Time)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("event log should be private to the user (0600), got %v", info.Mode().Perm())
+	}
+}
+
+func TestLog_RotatesWhenSizeLimitReached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log := New(path, WithRotation(200, 2))
+	item := []aggregator.FeedItem{{ID: "item", Source: aggregator.SourceYouTube, Title: "A title long enough to fill the log quickly"}}
+
+	for i := 0; i < 10; i++ {
+		if err := log.Record(Discovered, item); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("expected %s to exist after rotation: %v", filepath.Base(name), err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("only the configured number of rotated files should be kept")
+	}
+	if info, _ := os.Stat(path); info.Size() > 200 {
+		t.Errorf("active log should stay under the size limit, got %d bytes", info.Size())
+	}
+}
+
+func TestLog_IgnoresEmptyBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := New(path).Record(Displayed, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("recording no items should not create the log")
+	}
+}
@@ -0,0 +1,191 @@
+// Package freshness remembers when each channel and publication was last
+// fetched, the IDs of the items it returned and how often it posts, so
+// frequent runs can skip the ones that are unlikely to have anything new yet.
+package freshness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// A feed is refetched after a quarter of the typical time between its
+// posts, within these bounds: sources posting every hour are checked every
+// 15 minutes, those posting daily or less every 6 hours.
+const (
+	MinRefresh = 15 * time.Minute
+	MaxRefresh = 6 * time.Hour
+)
+
+// retention is how long a feed no run fetches any more is remembered.
+const retention = 30 * 24 * time.Hour
+
+// version is the layout of the freshness file. Files of another version are
+// ignored, so every feed is fetched once after an upgrade.
+const version = 2
+
+// Store records the last fetch of each feed, keyed by the source's name
+// for it, such as a YouTube channel ID, and the feeds each listing (such as
+// an account's subscriptions) named. It is safe for concurrent use.
+type Store struct {
+	path string
+	now  clock.Clock
+
+	mu    sync.Mutex
+	feeds map[string]feed
+	lists map[string]list
+}
+
+type feed struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Cadence   time.Duration `json:"cadence"`
+	IDs       []string      `json:"ids"`
+}
+
+type list struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Feeds     []string  `json:"feeds"`
+}
+
+type file struct {
+	Version int             `json:"version"`
+	Feeds   map[string]feed `json:"feeds"`
+	Lists   map[string]list `json:"lists,omitempty"`
+}
+
+// Open loads the store at path, with ages measured at now. A missing file,
+// or one of another version, yields an empty store: every feed is then
+// fetched once.
+func Open(path string, now clock.Clock) (*Store, error) {
+	s := &Store{path: path, now: now, feeds: make(map[string]feed), lists: make(map[string]list)}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the freshness file in the user's cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fetch times: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fetch times: %w", err)
+	}
+	if f.Version != version {
+		return s, nil
+	}
+	if f.Feeds != nil {
+		s.feeds = f.Feeds
+	}
+	if f.Lists != nil {
+		s.lists = f.Lists
+	}
+	return s, nil
+}
+
+// Fresh returns the IDs of the items key's feed returned when last fetched,
+// and true, if it was fetched too recently to be worth fetching again.
+func (s *Store) Fresh(key string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.feeds[key]
+	if !ok || s.now().Sub(f.FetchedAt) >= RefreshAfter(f.Cadence) {
+		return nil, false
+	}
+	return slices.Clone(f.IDs), true
+}
+
+// Record remembers that key's feed was just fetched and returned items.
+func (s *Store) Record(key string, items []aggregator.FeedItem) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeds[key] = feed{FetchedAt: s.now().UTC(), Cadence: Cadence(items), IDs: ids}
+}
+
+// List returns the feeds the listing key named when last recorded, and
+// true, if that was less than MaxRefresh ago.
+func (s *Store) List(key string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.lists[key]
+	if !ok || s.now().Sub(l.FetchedAt) >= MaxRefresh {
+		return nil, false
+	}
+	return slices.Clone(l.Feeds), true
+}
+
+// RecordList remembers that the listing key was just fetched and named feeds.
+func (s *Store) RecordList(key string, feeds []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[key] = list{FetchedAt: s.now().UTC(), Feeds: slices.Clone(feeds)}
+}
+
+// Save writes the store to disk, forgetting feeds not fetched for a month.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := s.now().Add(-retention)
+	for key, f := range s.feeds {
+		if f.FetchedAt.Before(cutoff) {
+			delete(s.feeds, key)
+		}
+	}
+	for key, l := range s.lists {
+		if l.FetchedAt.Before(cutoff) {
+			delete(s.lists, key)
+		}
+	}
+
+	data, err := json.Marshal(file{Version: version, Feeds: s.feeds, Lists: s.lists})
+	if err != nil {
+		return fmt.Errorf("failed to encode fetch times: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save fetch times: %w", err)
+	}
+	return nil
+}
+
+// Cadence is the typical (median) time between the posts in items, or 0
+// if there are fewer than two.
+func Cadence(items []aggregator.FeedItem) time.Duration {
+	if len(items) < 2 {
+		return 0
+	}
+	times := make([]time.Time, len(items))
+	for i, item := range items {
+		times[i] = item.PublishedAt
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	gaps := make([]time.Duration, len(times)-1)
+	for i := range gaps {
+		gaps[i] = times[i+1].Sub(times[i])
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// RefreshAfter is how long after a fetch a feed posting every cadence is
+// worth fetching again. Feeds of unknown cadence wait MaxRefresh.
+func RefreshAfter(cadence time.Duration) time.Duration {
+	if cadence <= 0 {
+		return MaxRefresh
+	}
+	return min(max(cadence/4, MinRefresh), MaxRefresh)
+}
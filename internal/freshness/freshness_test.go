@@ -0,0 +1,122 @@
+package freshness
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func posts(start time.Time, every time.Duration, n int) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, n)
+	for i := range items {
+		items[i] = aggregator.FeedItem{ID: string(rune('a' + i)), PublishedAt: start.Add(-time.Duration(i) * every)}
+	}
+	return items
+}
+
+func TestStore_SkipsFeedsFetchedRecentlyForTheirCadence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freshness.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	current := now
+	store, err := Open(path, func() time.Time { return current })
+	if err != nil {
+		t.Fatalf("a missing file should open empty, got: %v", err)
+	}
+	if _, ok := store.Fresh("youtube:UC1"); ok {
+		t.Error("a feed never fetched should be stale")
+	}
+
+	store.Record("youtube:UC1", posts(now, 2*time.Hour, 5))           // refetched after 30 minutes
+	store.Record("substack:https://a", posts(now, 7*24*time.Hour, 3)) // after MaxRefresh
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	current = now.Add(20 * time.Minute)
+	store, err = Open(path, func() time.Time { return current })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids, ok := store.Fresh("youtube:UC1"); !ok || len(ids) != 5 || ids[0] != "a" {
+		t.Errorf("a feed fetched 20 minutes ago posting every 2 hours should be fresh with its item IDs, got %v, %v", ids, ok)
+	}
+	current = now.Add(40 * time.Minute)
+	if _, ok := store.Fresh("youtube:UC1"); ok {
+		t.Error("a feed posting every 2 hours should be stale after 40 minutes")
+	}
+	if _, ok := store.Fresh("substack:https://a"); !ok {
+		t.Error("a weekly feed should still be fresh after 40 minutes")
+	}
+
+	current = now.Add(31 * 24 * time.Hour)
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if store, _ = Open(path, clock.Fixed(current)); len(store.feeds) != 0 {
+		t.Errorf("feeds not fetched for a month should be forgotten, got %v", store.feeds)
+	}
+}
+
+func TestStore_KeepsOnlyIDsAndTimes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freshness.json")
+	store, _ := Open(path, clock.System)
+	item := aggregator.FeedItem{ID: "vid1", Title: "A title", Description: "A long description", PublishedAt: time.Now()}
+	store.Record("youtube:UC1", []aggregator.FeedItem{item})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"vid1"`) || strings.Contains(string(data), "A title") || strings.Contains(string(data), "description") {
+		t.Errorf("only item IDs should be stored, got %s", data)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"schema_version":1,"feeds":{"youtube:UC1":{"fetched_at":"2099-01-01T00:00:00Z","items":[]}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if store, _ = Open(path, clock.System); len(store.feeds) != 0 {
+		t.Errorf("a file of another version should be ignored, got %v", store.feeds)
+	}
+}
+
+func TestStore_RemembersListingsForMaxRefresh(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	current := now
+	store, _ := Open(filepath.Join(t.TempDir(), "freshness.json"), func() time.Time { return current })
+	if _, ok := store.List("youtube:subscriptions:"); ok {
+		t.Error("a listing never recorded should not be known")
+	}
+	store.RecordList("youtube:subscriptions:", []string{"youtube:UC1", "youtube:UC2"})
+
+	current = now.Add(MaxRefresh - time.Minute)
+	if feeds, ok := store.List("youtube:subscriptions:"); !ok || len(feeds) != 2 {
+		t.Errorf("a recent listing should be remembered, got %v, %v", feeds, ok)
+	}
+	current = now.Add(MaxRefresh)
+	if _, ok := store.List("youtube:subscriptions:"); ok {
+		t.Error("a listing should be fetched again after MaxRefresh")
+	}
+}
+
+func TestRefreshAfter_IsBoundedQuarterOfCadence(t *testing.T) {
+	for cadence, want := range map[time.Duration]time.Duration{
+		0:                  MaxRefresh,
+		10 * time.Minute:   MinRefresh,
+		4 * time.Hour:      time.Hour,
+		7 * 24 * time.Hour: MaxRefresh,
+	} {
+		if got := RefreshAfter(cadence); got != want {
+			t.Errorf("RefreshAfter(%v) = %v, want %v", cadence, got, want)
+		}
+	}
+	if got := Cadence(posts(time.Now(), 3*time.Hour, 4)); got != 3*time.Hour {
+		t.Errorf("expected a 3h cadence, got %v", got)
+	}
+}
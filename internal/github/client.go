@@ -0,0 +1,176 @@
+// Package github provides a client for the GitHub REST API: the releases of
+// repositories and the repositories a user has starred.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
+// pageSize is the most items the API returns per page.
+const pageSize = 100
+
+// Release is a published release of a repository.
+type Release struct {
+	ID int64
+	// Repo is the repository's "owner/name".
+	Repo        string
+	Tag         string
+	Name        string
+	Body        string
+	Author      string
+	URL         string
+	PublishedAt time.Time
+	Prerelease  bool
+	Reactions   int64
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the API endpoint (useful for testing, or GitHub
+// Enterprise).
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithToken authenticates requests with a personal access token, which
+// raises the API's rate limit and gives access to private repositories.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client calls the GitHub REST API.
+type Client struct {
+	httpClient HTTPClient
+	baseURL    string
+	token      string
+}
+
+// NewClient creates a new GitHub client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchReleases returns the latest published releases of repo ("owner/name"),
+// newest first, up to limit. Drafts are left out.
+func (c *Client) FetchReleases(ctx context.Context, repo string, limit int) ([]Release, error) {
+	var resp []struct {
+		ID          int64     `json:"id"`
+		TagName     string    `json:"tag_name"`
+		Name        string    `json:"name"`
+		Body        string    `json:"body"`
+		HTMLURL     string    `json:"html_url"`
+		Draft       bool      `json:"draft"`
+		Prerelease  bool      `json:"prerelease"`
+		PublishedAt time.Time `json:"published_at"`
+		Author      struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Reactions struct {
+			TotalCount int64 `json:"total_count"`
+		} `json:"reactions"`
+	}
+	query := url.Values{"per_page": {strconv.Itoa(min(max(limit, 1), pageSize))}}
+	if err := c.get(ctx, "/repos/"+repo+"/releases", query, &resp); err != nil {
+		return nil, err
+	}
+	releases := make([]Release, 0, len(resp))
+	for _, r := range resp {
+		if r.Draft {
+			continue
+		}
+		releases = append(releases, Release{
+			ID: r.ID, Repo: repo, Tag: r.TagName, Name: strings.TrimSpace(r.Name), Body: r.Body, Author: r.Author.Login,
+			URL: r.HTMLURL, PublishedAt: r.PublishedAt, Prerelease: r.Prerelease, Reactions: r.Reactions.TotalCount,
+		})
+	}
+	return releases, nil
+}
+
+// StarredRepos returns the "owner/name" of every repository user has
+// starred, most recently starred first.
+func (c *Client) StarredRepos(ctx context.Context, user string) ([]string, error) {
+	var repos []string
+	for page := 1; ; page++ {
+		var resp []struct {
+			FullName string `json:"full_name"`
+		}
+		query := url.Values{"per_page": {strconv.Itoa(pageSize)}, "page": {strconv.Itoa(page)}}
+		if err := c.get(ctx, "/users/"+url.PathEscape(user)+"/starred", query, &resp); err != nil {
+			return nil, err
+		}
+		for _, repo := range resp {
+			repos = append(repos, repo.FullName)
+		}
+		if len(resp) < pageSize {
+			return repos, nil
+		}
+	}
+}
+
+// get decodes the response to a GET of path with query into v.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	endpoint := strings.TrimRight(c.baseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != "" {
+			return fmt.Errorf("github API returned HTTP %d for %s: %s", resp.StatusCode, path, apiErr.Message)
+		}
+		return fmt.Errorf("github API returned HTTP %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestClient_FetchReleases documents reading releases:
+//   - requests carry the token when one is given
+//   - drafts are left out; pre-releases are kept and marked
+func TestClient_FetchReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/cli/cli/releases" || r.URL.Query().Get("per_page") != "3" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"id":3,"tag_name":"v2.1.0","name":"","draft":true},
+			{"id":2,"tag_name":"v2.1.0-rc1","name":"Release candidate","prerelease":true,"published_at":"2024-01-15T10:00:00Z","html_url":"https://github.com/cli/cli/releases/tag/v2.1.0-rc1","author":{"login":"octocat"},"reactions":{"total_count":12}},
+			{"id":1,"tag_name":"v2.0.0","name":"GitHub CLI 2.0.0","body":"Notes","published_at":"2024-01-01T10:00:00Z"}
+		]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithToken("secret"))
+	releases, err := client.FetchReleases(context.Background(), "cli/cli", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(releases) != 2 || releases[0].Tag != "v2.1.0-rc1" || releases[1].Name != "GitHub CLI 2.0.0" {
+		t.Fatalf("expected the 2 published releases, got %+v", releases)
+	}
+	if rc := releases[0]; !rc.Prerelease || rc.Author != "octocat" || rc.Reactions != 12 || rc.Repo != "cli/cli" {
+		t.Errorf("expected the pre-release with its author and reactions, got %+v", rc)
+	}
+
+	_, err = client.FetchReleases(context.Background(), "cli/missing", 3)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404") || !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("expected GitHub's error, got %v", err)
+	}
+}
+
+func TestClient_StarredReposReadsEveryPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"full_name":"last/repo"}]`)
+			return
+		}
+		repos := make([]string, pageSize)
+		for i := range repos {
+			repos[i] = fmt.Sprintf(`{"full_name":"owner/repo%d"}`, i)
+		}
+		fmt.Fprint(w, "["+strings.Join(repos, ",")+"]")
+	}))
+	defer server.Close()
+
+	repos, err := NewClient(WithBaseURL(server.URL)).StarredRepos(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != pageSize+1 || repos[pageSize] != "last/repo" {
+		t.Errorf("expected the repositories of both pages, got %d ending with %q", len(repos), repos[len(repos)-1])
+	}
+}
@@ -0,0 +1,298 @@
+// Package greader provides a client for the Google Reader-compatible API
+// served by self-hosted aggregators such as FreshRSS and Miniflux.
+package greader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Streams and tags of the user's items: readingList is every item in their
+// subscriptions, and items are tagged read and starred.
+const (
+	readingList = "user/-/state/com.google/reading-list"
+	stateRead   = "user/-/state/com.google/read"
+	stateStar   = "user/-/state/com.google/starred"
+)
+
+// MaxItems is the most items the API returns in one request.
+const MaxItems = 1000
+
+// Item is an article from one of the aggregator's subscriptions.
+type Item struct {
+	ID      string
+	Title   string
+	Summary string
+	Author  string
+	// Feed is the title of the subscription the item came from.
+	Feed        string
+	URL         string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+	// Read and Starred are the item's state on the server.
+	Read    bool
+	Starred bool
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Client reads the reading list of one account on a Google Reader API
+// server. It signs in on first use and is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	user       string
+	password   string
+	httpClient HTTPClient
+
+	mu    sync.Mutex
+	auth  string
+	token string
+}
+
+// NewClient creates a client for the API at baseURL, such as
+// https://rss.example.com/api/greader.php for FreshRSS or
+// https://miniflux.example.com for Miniflux, signing in as user.
+func NewClient(baseURL, user, password string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		user:       user,
+		password:   password,
+		httpClient: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchItems returns the limit most recent items of the reading list.
+func (c *Client) FetchItems(ctx context.Context, limit int) ([]Item, error) {
+	return c.fetchStream(ctx, readingList, limit)
+}
+
+// FetchStarred returns the limit most recent starred items.
+func (c *Client) FetchStarred(ctx context.Context, limit int) ([]Item, error) {
+	return c.fetchStream(ctx, stateStar, limit)
+}
+
+// MarkRead marks the items with the given IDs read, or unread when read is false.
+func (c *Client) MarkRead(ctx context.Context, ids []string, read bool) error {
+	return c.editTag(ctx, ids, stateRead, read)
+}
+
+// Star stars the items with the given IDs, or unstars them when starred is false.
+func (c *Client) Star(ctx context.Context, ids []string, starred bool) error {
+	return c.editTag(ctx, ids, stateStar, starred)
+}
+
+func (c *Client) fetchStream(ctx context.Context, stream string, limit int) ([]Item, error) {
+	auth, err := c.signIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"output": {"json"}, "n": {strconv.Itoa(min(limit, MaxItems))}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/reader/api/0/stream/contents/"+stream+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "GoogleLogin auth="+auth)
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents streamContents
+	if err := json.Unmarshal(body, &contents); err != nil {
+		return nil, fmt.Errorf("failed to parse items: %w", err)
+	}
+	items := make([]Item, 0, len(contents.Items))
+	for _, entry := range contents.Items {
+		items = append(items, entry.item())
+	}
+	return items, nil
+}
+
+// signIn exchanges the user's password for an auth token with ClientLogin,
+// once per client.
+func (c *Client) signIn(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.auth != "" {
+		return c.auth, nil
+	}
+
+	form := url.Values{"Email": {c.user}, "Passwd": {c.password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/accounts/ClientLogin", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign in: %w", err)
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		if auth, ok := strings.CutPrefix(strings.TrimSpace(line), "Auth="); ok && auth != "" {
+			c.auth = auth
+			return auth, nil
+		}
+	}
+	return "", fmt.Errorf("failed to sign in: no auth token in the response")
+}
+
+// editTag adds tag to the items with ids, or removes it when add is false.
+func (c *Client) editTag(ctx context.Context, ids []string, tag string, add bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	auth, err := c.signIn(ctx)
+	if err != nil {
+		return err
+	}
+	token, err := c.editToken(ctx, auth)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{"i": ids, "T": {token}}
+	if add {
+		form.Set("a", tag)
+	} else {
+		form.Set("r", tag)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/reader/api/0/edit-tag", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "GoogleLogin auth="+auth)
+	if _, err := c.do(req); err != nil {
+		return fmt.Errorf("failed to update %d items: %w", len(ids), err)
+	}
+	return nil
+}
+
+// editToken returns the token the API asks for with every change, fetched
+// once per client.
+func (c *Client) editToken(ctx context.Context, auth string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/reader/api/0/token", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "GoogleLogin auth="+auth)
+	body, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an edit token: %w", err)
+	}
+	c.token = strings.TrimSpace(string(body))
+	return c.token, nil
+}
+
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("the reader API at %s rejected the user name or password (HTTP %d)", c.baseURL, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("the reader API at %s returned HTTP %d", c.baseURL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, nil
+}
+
+type streamContents struct {
+	Items []streamItem `json:"items"`
+}
+
+type streamItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Published int64  `json:"published"`
+	Updated   int64  `json:"updated"`
+	Canonical []link `json:"canonical"`
+	Alternate []link `json:"alternate"`
+	Summary   struct {
+		Content string `json:"content"`
+	} `json:"summary"`
+	Content struct {
+		Content string `json:"content"`
+	} `json:"content"`
+	Origin struct {
+		Title string `json:"title"`
+	} `json:"origin"`
+	Categories []string `json:"categories"`
+}
+
+type link struct {
+	Href string `json:"href"`
+}
+
+func (e streamItem) item() Item {
+	item := Item{
+		ID:      e.ID,
+		Title:   e.Title,
+		Summary: e.Summary.Content,
+		Author:  e.Author,
+		Feed:    e.Origin.Title,
+	}
+	if item.Summary == "" {
+		item.Summary = e.Content.Content
+	}
+	for _, links := range [][]link{e.Canonical, e.Alternate} {
+		if len(links) > 0 && item.URL == "" {
+			item.URL = links[0].Href
+		}
+	}
+	for _, category := range e.Categories {
+		switch {
+		case strings.HasSuffix(category, "/state/com.google/read"):
+			item.Read = true
+		case strings.HasSuffix(category, "/state/com.google/starred"):
+			item.Starred = true
+		}
+	}
+	if e.Published > 0 {
+		item.PublishedAt = time.Unix(e.Published, 0).UTC()
+	}
+	if e.Updated > e.Published {
+		item.UpdatedAt = time.Unix(e.Updated, 0).UTC()
+	}
+	return item
+}
@@ -0,0 +1,141 @@
+package greader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestClient_FetchItems documents reading a Google Reader API reading list:
+// - the client signs in once with ClientLogin and sends the Auth token
+// - items carry their subscription's title, canonical URL and dates
+func TestClient_FetchItems(t *testing.T) {
+	var logins int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accounts/ClientLogin":
+			logins++
+			if r.FormValue("Email") != "me" || r.FormValue("Passwd") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, "SID=none\nLSID=none\nAuth=me/token\n")
+		case "/reader/api/0/stream/contents/user/-/state/com.google/reading-list":
+			if r.Header.Get("Authorization") != "GoogleLogin auth=me/token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("n") != "20" {
+				t.Errorf("expected n=20, got %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{{
+					"id":        "tag:google.com,2005:reader/item/1",
+					"title":     "Release notes",
+					"author":    "Jane",
+					"published": 1705320000,
+					"updated":   1705323600,
+					"canonical": []map[string]string{{"href": "https://blog.example.com/release"}},
+					"summary":   map[string]string{"content": "<p>What's new</p>"},
+					"origin":    map[string]string{"title": "Example Blog"},
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "me", "secret")
+	for range 2 {
+		items, err := client.FetchItems(context.Background(), 20)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := Item{
+			ID: "tag:google.com,2005:reader/item/1", Title: "Release notes", Summary: "<p>What's new</p>", Author: "Jane", Feed: "Example Blog",
+			URL: "https://blog.example.com/release", PublishedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC),
+		}
+		if len(items) != 1 || items[0] != want {
+			t.Errorf("expected %+v, got %+v", want, items)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("expected one sign-in, got %d", logins)
+	}
+
+	_, err := NewClient(server.URL, "me", "wrong").FetchItems(context.Background(), 20)
+	if err == nil || !strings.Contains(err.Error(), "rejected the user name or password") {
+		t.Errorf("expected a sign-in error, got %v", err)
+	}
+}
+
+// TestClient_SyncsState documents reading and changing item state:
+// - read and starred items are tagged with the user's state categories
+// - changes are sent to edit-tag with the token fetched once per client
+func TestClient_SyncsState(t *testing.T) {
+	var tokens int
+	var edits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accounts/ClientLogin":
+			fmt.Fprint(w, "Auth=me/token\n")
+		case "/reader/api/0/stream/contents/user/-/state/com.google/starred":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{{
+					"id":         "1",
+					"categories": []string{"user/-/state/com.google/reading-list", "user/1/state/com.google/read", "user/1/state/com.google/starred"},
+				}},
+			})
+		case "/reader/api/0/token":
+			tokens++
+			fmt.Fprint(w, "edit-token\n")
+		case "/reader/api/0/edit-tag":
+			if r.Header.Get("Authorization") != "GoogleLogin auth=me/token" || r.FormValue("T") != "edit-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = r.ParseForm()
+			edits = append(edits, fmt.Sprintf("a=%s r=%s i=%s", r.Form.Get("a"), r.Form.Get("r"), strings.Join(r.Form["i"], ",")))
+			fmt.Fprint(w, "OK")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "me", "secret")
+	ctx := context.Background()
+
+	items, err := client.FetchStarred(ctx, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || !items[0].Read || !items[0].Starred {
+		t.Errorf("expected a read, starred item, got %+v", items)
+	}
+
+	if err := client.MarkRead(ctx, []string{"1", "2"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Star(ctx, []string{"3"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Star(ctx, nil, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"a=user/-/state/com.google/read r= i=1,2",
+		"a= r=user/-/state/com.google/starred i=3",
+	}
+	if strings.Join(edits, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected edits %q, got %q", want, edits)
+	}
+	if tokens != 1 {
+		t.Errorf("expected one token request, got %d", tokens)
+	}
+}
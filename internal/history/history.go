@@ -0,0 +1,196 @@
+// Package history remembers the items feedmix has fetched so later runs can
+// tell when a source re-publishes an item with edited content, how its
+// engagement grows, and which items the user has opened.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// retention is how long an item that no source returns any more is remembered.
+const retention = 90 * 24 * time.Hour
+
+// An item's engagement is sampled at most every sampleEvery, keeping the
+// samples of the last trendWindow, and no more than maxSamples of them.
+// Runs closer together than sampleEvery update the latest sample instead.
+const (
+	sampleEvery = time.Hour
+	trendWindow = 7 * 24 * time.Hour
+	maxSamples  = 24
+)
+
+// Store maps items to the hash of their content when last seen.
+type Store struct {
+	path    string
+	entries map[string]entry
+	now     clock.Clock
+}
+
+type entry struct {
+	Hash      string              `json:"hash"`
+	FirstSeen time.Time           `json:"first_seen"`
+	LastSeen  time.Time           `json:"last_seen"`
+	UpdatedAt time.Time           `json:"updated_at,omitempty"`
+	OpenedAt  time.Time           `json:"opened_at,omitempty"`
+	Stats     []aggregator.Sample `json:"stats,omitempty"`
+}
+
+// Open loads the store at path, recording times read from now; a missing
+// file yields an empty store.
+func Open(path string, now clock.Clock) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]entry), now: now}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the history file in the user's config directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read item history: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse item history: %w", err)
+	}
+	return s, nil
+}
+
+// ContentHash identifies the user-visible content of an item.
+func ContentHash(item aggregator.FeedItem) string {
+	sum := sha256.Sum256([]byte(item.Title + "\x00" + item.Description))
+	return hex.EncodeToString(sum[:])
+}
+
+// Observe records items and returns them with UpdatedAt set on every item
+// whose content changed since it was first seen. The mark persists on later
+// runs until the content changes again. Each item's engagement is recorded
+// too, and its Trend set once it has changed between runs.
+func (s *Store) Observe(items []aggregator.FeedItem) []aggregator.FeedItem {
+	now := s.now().UTC()
+	observed := make([]aggregator.FeedItem, len(items))
+	for i, item := range items {
+		key := itemKey(item)
+		hash := ContentHash(item)
+
+		e, seen := s.entries[key]
+		switch {
+		case !seen:
+			e = entry{Hash: hash, FirstSeen: now}
+		case e.Hash != hash:
+			e.Hash = hash
+			e.UpdatedAt = now
+		}
+		e.LastSeen = now
+		e.Stats = sample(e.Stats, item.Engagement, now)
+		s.entries[key] = e
+
+		item.UpdatedAt = e.UpdatedAt
+		item.Trend = nil
+		if len(e.Stats) > 1 {
+			item.Trend = slices.Clone(e.Stats)
+		}
+		observed[i] = item
+	}
+	return observed
+}
+
+// sample adds engagement at now to stats, if it changed since the last
+// sample, and drops the samples that no longer fit the trend.
+func sample(stats []aggregator.Sample, engagement aggregator.Engagement, now time.Time) []aggregator.Sample {
+	latest := aggregator.Sample{At: now, Views: engagement.Views, Likes: engagement.Likes}
+	if latest.Views == 0 && latest.Likes == 0 {
+		return stats
+	}
+	if n := len(stats); n > 0 {
+		last := stats[n-1]
+		if last.Views == latest.Views && last.Likes == latest.Likes {
+			return stats
+		}
+		if n > 1 && now.Sub(stats[n-2].At) < sampleEvery {
+			stats = stats[:n-1]
+		}
+	}
+	stats = append(stats, latest)
+
+	cutoff := now.Add(-trendWindow)
+	for len(stats) > maxSamples || (len(stats) > 0 && stats[0].At.Before(cutoff)) {
+		stats = stats[1:]
+	}
+	return stats
+}
+
+// MarkOpened records that the user opened item.
+func (s *Store) MarkOpened(item aggregator.FeedItem) {
+	now := s.now().UTC()
+	e, seen := s.entries[itemKey(item)]
+	if !seen {
+		e = entry{Hash: ContentHash(item), FirstSeen: now, LastSeen: now}
+	}
+	e.OpenedAt = now
+	s.entries[itemKey(item)] = e
+}
+
+// ClearOpened forgets that the user opened item, as when they marked it
+// unread elsewhere.
+func (s *Store) ClearOpened(item aggregator.FeedItem) {
+	if e, seen := s.entries[itemKey(item)]; seen {
+		e.OpenedAt = time.Time{}
+		s.entries[itemKey(item)] = e
+	}
+}
+
+// Known reports whether item was observed before.
+func (s *Store) Known(item aggregator.FeedItem) bool {
+	_, ok := s.entries[itemKey(item)]
+	return ok
+}
+
+// Empty reports whether the store remembers no item, as before the first run.
+func (s *Store) Empty() bool {
+	return len(s.entries) == 0
+}
+
+// Opened reports whether the user has opened item.
+func (s *Store) Opened(item aggregator.FeedItem) bool {
+	return !s.OpenedAt(item).IsZero()
+}
+
+// OpenedAt returns when the user last opened item, zero if never.
+func (s *Store) OpenedAt(item aggregator.FeedItem) time.Time {
+	return s.entries[itemKey(item)].OpenedAt
+}
+
+func itemKey(item aggregator.FeedItem) string {
+	return string(item.Source) + ":" + item.ID
+}
+
+// Save writes the store back to disk, forgetting items not seen for 90 days.
+func (s *Store) Save() error {
+	cutoff := s.now().Add(-retention)
+	for key, e := range s.entries {
+		if e.LastSeen.Before(cutoff) {
+			delete(s.entries, key)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode item history: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write item history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func post(title, description string) aggregator.FeedItem {
+	return aggregator.FeedItem{ID: "p1", Source: aggregator.SourceSubstack, Title: title, Description: description}
+}
+
+func TestStore_MarksEditedItemsAsUpdated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	store, err := Open(path, clock.System)
+	if err != nil {
+		t.Fatalf("missing history should open empty, got: %v", err)
+	}
+	store.now = func() time.Time { return now }
+	if got := store.Observe([]aggregator.FeedItem{post("Title", "Body")}); !got[0].UpdatedAt.IsZero() {
+		t.Error("a newly seen item should not be marked updated")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ = Open(path, clock.System)
+	now = now.Add(time.Hour)
+	store.now = func() time.Time { return now }
+	if got := store.Observe([]aggregator.FeedItem{post("Title", "Body")}); !got[0].UpdatedAt.IsZero() {
+		t.Error("an unchanged item should not be marked updated")
+	}
+	got := store.Observe([]aggregator.FeedItem{post("Title", "Body (corrected)")})
+	if !got[0].UpdatedAt.Equal(now) {
+		t.Errorf("an edited item should be marked updated at detection time, got %v", got[0].UpdatedAt)
+	}
+
+	now = now.Add(time.Hour)
+	if got := store.Observe([]aggregator.FeedItem{post("Title", "Body (corrected)")}); got[0].UpdatedAt.IsZero() {
+		t.Error("the updated mark should persist on later runs")
+	}
+}
+
+func TestStore_ForgetsItemsNotSeenForRetentionPeriod(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	store, _ := Open(path, clock.System)
+	store.now = func() time.Time { return now }
+	store.Observe([]aggregator.FeedItem{post("Title", "Body")})
+
+	now = now.Add(retention + time.Hour)
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store, _ = Open(path, clock.System); len(store.entries) != 0 {
+		t.Errorf("stale entries should be pruned so history stays small, got %d", len(store.entries))
+	}
+}
+
+func TestStore_RemembersOpenedItemsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store, _ := Open(path, clock.System)
+	item := post("Title", "Body")
+	if store.Opened(item) {
+		t.Fatal("a new item should not be opened")
+	}
+
+	store.MarkOpened(item)
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store, _ = Open(path, clock.System)
+	if !store.Opened(item) {
+		t.Error("opened items should be remembered across runs")
+	}
+	if got := store.Observe([]aggregator.FeedItem{item}); !got[0].UpdatedAt.IsZero() || !store.Opened(item) {
+		t.Error("observing an opened item should neither mark it updated nor forget it was opened")
+	}
+}
+
+func TestStore_RecordsEngagementTrend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	video := func(views int64) []aggregator.FeedItem {
+		return []aggregator.FeedItem{{ID: "v1", Source: aggregator.SourceYouTube, Title: "Video", Engagement: aggregator.Engagement{Views: views}}}
+	}
+
+	store, _ := Open(path, clock.System)
+	store.now = func() time.Time { return now }
+	if got := store.Observe(video(100)); got[0].Trend != nil {
+		t.Errorf("a newly seen item should have no trend yet, got %v", got[0].Trend)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ = Open(path, clock.System)
+	store.now = func() time.Time { return now }
+	now = now.Add(10 * time.Minute)
+	store.Observe(video(150))
+	now = now.Add(10 * time.Minute)
+	got := store.Observe(video(200))
+	if len(got[0].Trend) != 2 || got[0].Trend[1].Views != 200 {
+		t.Errorf("runs less than an hour apart should update the latest sample, got %v", got[0].Trend)
+	}
+	now = now.Add(time.Hour)
+	if got := store.Observe(video(200)); len(got[0].Trend) != 2 {
+		t.Errorf("unchanged engagement should not add a sample, got %v", got[0].Trend)
+	}
+	if got := store.Observe(video(900)); len(got[0].Trend) != 3 || got[0].Growth() <= 0 {
+		t.Errorf("a later change should add a sample, got %v", got[0].Trend)
+	}
+
+	for range maxSamples {
+		now = now.Add(2 * time.Hour)
+		got = store.Observe(video(got[0].Engagement.Views + 100))
+	}
+	if len(got[0].Trend) != maxSamples {
+		t.Errorf("the trend should keep at most %d samples, got %d", maxSamples, len(got[0].Trend))
+	}
+	now = now.Add(trendWindow + time.Hour)
+	if got := store.Observe(video(1)); got[0].Trend != nil {
+		t.Errorf("samples older than the trend window should be dropped, got %v", got[0].Trend)
+	}
+}
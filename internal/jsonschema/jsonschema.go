@@ -0,0 +1,179 @@
+// Package jsonschema derives JSON Schema (draft 2020-12) documents from the
+// Go types feedmix serializes, so published schemas can't drift from the
+// output they describe.
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	// Properties and Required describe objects; AdditionalProperties the
+	// values of maps.
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Option configures how For describes types.
+type Option func(*generator)
+
+// WithEnum lists the values a named type takes, such as the constants of a
+// string type; values of one type describe it wherever it appears.
+func WithEnum(values ...any) Option {
+	return func(g *generator) {
+		if len(values) > 0 {
+			t := reflect.TypeOf(values[0])
+			g.enums[t] = append(g.enums[t], values...)
+		}
+	}
+}
+
+// For returns the schema of v's JSON encoding, titled title. Fields follow
+// their json tags; fields with omitempty aren't required. Named struct types
+// other than v's own are described once under $defs.
+func For(v any, title, description string, opts ...Option) *Schema {
+	g := &generator{enums: make(map[reflect.Type][]any), names: make(map[reflect.Type]string), defs: make(map[string]*Schema)}
+	for _, opt := range opts {
+		opt(g)
+	}
+	s := g.object(reflect.TypeOf(v))
+	s.Schema, s.Title, s.Description = Draft, title, description
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return s
+}
+
+type generator struct {
+	enums map[reflect.Type][]any
+	names map[reflect.Type]string
+	defs  map[string]*Schema
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if values, ok := g.enums[t]; ok {
+		s := g.kind(t)
+		s.Enum = values
+		return s
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return &Schema{}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return &Schema{Ref: "#/$defs/" + g.define(t)}
+	}
+	return g.kind(t)
+}
+
+// kind describes t by its underlying kind.
+func (g *generator) kind(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.object(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// define adds t to $defs, under its name or, when another type has that
+// name, its package-qualified name, and returns the key. Instantiated
+// generic types go by their bare name. The key is reserved before t is
+// described, so recursive types refer to it.
+func (g *generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if _, taken := g.defs[name]; taken {
+		name = t.PkgPath()[strings.LastIndexByte(t.PkgPath(), '/')+1:] + "." + name
+	}
+	g.names[t] = name
+	g.defs[name] = nil
+	g.defs[name] = g.object(t)
+	return name
+}
+
+// object describes a struct's encoded fields, including those of embedded
+// structs.
+func (g *generator) object(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.fields(t, s)
+	return s
+}
+
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, s)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schema(field.Type)
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
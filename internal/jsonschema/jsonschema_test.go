@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type color string
+
+type inner struct {
+	At time.Time `json:"at"`
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type document struct {
+	base
+	Color    color           `json:"color"`
+	Count    int64           `json:"count,omitempty"`
+	Ratio    float64         `json:"ratio"`
+	Tags     []string        `json:"tags,omitempty"`
+	Labels   map[string]int  `json:"labels"`
+	Inner    *inner          `json:"inner,omitempty"`
+	Inners   []inner         `json:"inners"`
+	Raw      json.RawMessage `json:"raw,omitempty"`
+	Skipped  string          `json:"-"`
+	Untagged bool
+	private  string
+}
+
+// TestFor documents how Go types map to JSON Schema:
+// - fields follow their json tags, embedded structs are flattened, and fields without omitempty are required
+// - named structs are described once under $defs and referenced
+// - times are date-time strings and enum values constrain named types
+func TestFor(t *testing.T) {
+	s := For(document{}, "Document", "A test document.", WithEnum(color("red"), color("blue")))
+
+	if s.Schema != Draft || s.Title != "Document" || s.Type != "object" {
+		t.Errorf("expected an object document of draft %s, got %+v", Draft, s)
+	}
+	if want := []string{"id", "color", "ratio", "labels", "inners", "Untagged"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("expected required %v, got %v", want, s.Required)
+	}
+	for name, want := range map[string]Schema{
+		"id":     {Type: "string"},
+		"color":  {Type: "string", Enum: []any{color("red"), color("blue")}},
+		"count":  {Type: "integer"},
+		"ratio":  {Type: "number"},
+		"tags":   {Type: "array", Items: &Schema{Type: "string"}},
+		"labels": {Type: "object", AdditionalProperties: &Schema{Type: "integer"}},
+		"inner":  {Ref: "#/$defs/inner"},
+		"inners": {Type: "array", Items: &Schema{Ref: "#/$defs/inner"}},
+		"raw":    {},
+	} {
+		if got := s.Properties[name]; got == nil || !reflect.DeepEqual(*got, want) {
+			t.Errorf("property %s: expected %+v, got %+v", name, want, got)
+		}
+	}
+	for _, name := range []string{"Skipped", "-", "private", "base"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("property %s should not be described", name)
+		}
+	}
+	want := &Schema{Type: "object", Properties: map[string]*Schema{"at": {Type: "string", Format: "date-time"}}, Required: []string{"at"}}
+	if got := s.Defs["inner"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected inner defined as %+v, got %+v", want, got)
+	}
+}
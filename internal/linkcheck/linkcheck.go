@@ -0,0 +1,173 @@
+// Package linkcheck tells whether links still lead to a page, politely:
+// hosts' robots.txt rules are followed, requests to a host are spaced out,
+// and all requests share a rate limit.
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Default spacing of requests.
+const (
+	DefaultHostInterval = 2 * time.Second
+	DefaultInterval     = 200 * time.Millisecond
+)
+
+// Status is what a check found out about a link.
+type Status int
+
+const (
+	// Alive links lead to a page.
+	Alive Status = iota
+	// Dead links lead nowhere: the server says the page is gone, or the
+	// host no longer exists.
+	Dead
+	// Unknown links couldn't be checked, such as when the server errored
+	// or refused the check.
+	Unknown
+)
+
+// Result is the outcome of checking a link.
+type Result struct {
+	Status Status
+	// Code is the HTTP status the server answered with, if it answered.
+	Code int
+	// Err is why the link is dead or couldn't be checked, if not Alive.
+	Err error
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// CheckerOption configures the Checker.
+type CheckerOption func(*Checker)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) CheckerOption {
+	return func(c *Checker) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHostInterval sets the least time between two requests to a host.
+func WithHostInterval(d time.Duration) CheckerOption {
+	return func(c *Checker) {
+		c.hostInterval = d
+	}
+}
+
+// WithInterval sets the least time between any two requests.
+func WithInterval(d time.Duration) CheckerOption {
+	return func(c *Checker) {
+		c.interval = d
+	}
+}
+
+// Checker checks links. It is safe for concurrent use.
+type Checker struct {
+	httpClient   HTTPClient
+	hostInterval time.Duration
+	interval     time.Duration
+
+	mu     sync.Mutex
+	last   time.Time
+	hosts  map[string]time.Time
+	robots map[string]robots
+}
+
+// NewChecker creates a Checker spacing requests by DefaultHostInterval per
+// host and DefaultInterval overall.
+func NewChecker(opts ...CheckerOption) *Checker {
+	c := &Checker{
+		httpClient:   &http.Client{},
+		hostInterval: DefaultHostInterval,
+		interval:     DefaultInterval,
+		hosts:        make(map[string]time.Time),
+		robots:       make(map[string]robots),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Check requests the headers of the page at link, unless the host's
+// robots.txt disallows it. Servers that don't answer HEAD requests are
+// asked for the page itself.
+func (c *Checker) Check(ctx context.Context, link string) Result {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return Result{Status: Unknown, Err: fmt.Errorf("not a web link: %q", link)}
+	}
+	rules := c.robotsFor(ctx, u)
+	if !rules.allows(u.RequestURI()) {
+		return Result{Status: Unknown, Err: fmt.Errorf("robots.txt of %s disallows checking it", u.Host)}
+	}
+	result := c.request(ctx, http.MethodHead, u, rules.delay)
+	if result.Code == http.StatusMethodNotAllowed || result.Code == http.StatusNotImplemented || result.Code == http.StatusForbidden {
+		result = c.request(ctx, http.MethodGet, u, rules.delay)
+	}
+	return result
+}
+
+func (c *Checker) request(ctx context.Context, method string, u *url.URL, delay time.Duration) Result {
+	if err := c.wait(ctx, u.Host, delay); err != nil {
+		return Result{Status: Unknown, Err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return Result{Status: Unknown, Err: fmt.Errorf("failed to create request: %w", err)}
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return Result{Status: Dead, Err: fmt.Errorf("host %s no longer exists", u.Hostname())}
+		}
+		return Result{Status: Unknown, Err: err}
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < http.StatusBadRequest:
+		return Result{Status: Alive, Code: resp.StatusCode}
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return Result{Status: Dead, Code: resp.StatusCode, Err: fmt.Errorf("HTTP %d", resp.StatusCode)}
+	default:
+		return Result{Status: Unknown, Code: resp.StatusCode, Err: fmt.Errorf("HTTP %d", resp.StatusCode)}
+	}
+}
+
+// wait blocks until a request to host keeps both intervals, or the longer
+// delay the host asked for, reserving that time for it.
+func (c *Checker) wait(ctx context.Context, host string, delay time.Duration) error {
+	c.mu.Lock()
+	at := time.Now()
+	if next := c.last.Add(c.interval); next.After(at) {
+		at = next
+	}
+	if next := c.hosts[host].Add(max(c.hostInterval, delay)); next.After(at) {
+		at = next
+	}
+	c.last, c.hosts[host] = at, at
+	c.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestChecker_Check documents link checks:
+//   - a page that answers is alive, even if it takes a redirect
+//   - 404 and 410 mean the page is dead
+//   - servers refusing HEAD are asked with GET
+//   - other errors leave the link's state unknown
+func TestChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+		case "/page":
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	checker := NewChecker(WithHTTPClient(server.Client()), WithHostInterval(0), WithInterval(0))
+	for path, want := range map[string]Status{"/moved": Alive, "/gone": Dead, "/get-only": Alive, "/broken": Unknown} {
+		if got := checker.Check(context.Background(), server.URL+path); got.Status != want {
+			t.Errorf("%s: expected status %d, got %+v", path, want, got)
+		}
+	}
+	if got := checker.Check(context.Background(), "mailto:me@example.com"); got.Status != Unknown || got.Err == nil {
+		t.Errorf("a link that isn't a web page can't be checked, got %+v", got)
+	}
+}
+
+func TestChecker_SpacesRequestsToAHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	checker := NewChecker(WithHTTPClient(server.Client()), WithHostInterval(50*time.Millisecond), WithInterval(0))
+	start := time.Now()
+	for range 3 {
+		checker.Check(context.Background(), server.URL)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three requests to a host should take at least two intervals, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewChecker(WithHTTPClient(server.Client()), WithHostInterval(time.Hour))
+	if got := slow.Check(ctx, server.URL); got.Status != Unknown || got.Err == nil {
+		t.Errorf("a canceled check should stop waiting, got %+v", got)
+	}
+}
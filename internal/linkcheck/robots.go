@@ -0,0 +1,154 @@
+package linkcheck
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserAgent is the name checks go by, and the one robots.txt rules are
+// read for besides those for every robot.
+const UserAgent = "feedmix"
+
+// maxRobotsSize is the most of a robots.txt file that is read.
+const maxRobotsSize = 512 << 10
+
+// maxCrawlDelay caps the Crawl-delay a host can ask for, so that one host
+// can't hold up a check for hours.
+const maxCrawlDelay = 30 * time.Second
+
+// robots holds the rules of a host's robots.txt that apply to feedmix.
+type robots struct {
+	rules []rule
+	// delay is the Crawl-delay the host asks for, if any.
+	delay time.Duration
+}
+
+type rule struct {
+	pattern string
+	allow   bool
+}
+
+// allows reports whether the rules let feedmix request path: the longest
+// matching rule wins, and Allow wins a tie.
+func (r robots) allows(path string) bool {
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !matches(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || len(rule.pattern) == longest && rule.allow {
+			allowed, longest = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// matches reports whether path starts with pattern, in which * stands for
+// any run of characters and a final $ for the end of the path.
+func matches(pattern, path string) bool {
+	pattern, anchored := strings.CutSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// parseRobots reads the group of a robots.txt file for UserAgent, or else
+// the group for every robot.
+func parseRobots(r io.Reader) robots {
+	groups := map[string]*robots{}
+	var current []*robots
+	inAgents := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field, value = strings.ToLower(strings.TrimSpace(field)), strings.TrimSpace(value)
+		if field == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			if groups[agent] == nil {
+				groups[agent] = &robots{}
+			}
+			current = append(current, groups[agent])
+			continue
+		}
+		inAgents = false
+		for _, group := range current {
+			switch field {
+			case "allow", "disallow":
+				if value != "" {
+					group.rules = append(group.rules, rule{pattern: value, allow: field == "allow"})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					group.delay = time.Duration(min(seconds, maxCrawlDelay.Seconds()) * float64(time.Second))
+				}
+			}
+		}
+	}
+	if group, ok := groups[UserAgent]; ok {
+		return *group
+	}
+	if group, ok := groups["*"]; ok {
+		return *group
+	}
+	return robots{}
+}
+
+// robotsFor returns the robots.txt rules of the host u is on, fetching them
+// on first use. A missing or unreadable robots.txt allows everything.
+func (c *Checker) robotsFor(ctx context.Context, u *url.URL) robots {
+	c.mu.Lock()
+	r, ok := c.robots[u.Host]
+	c.mu.Unlock()
+	if ok {
+		return r
+	}
+
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	if err := c.wait(ctx, u.Host, 0); err == nil {
+		if req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL.String(), nil); err == nil {
+			req.Header.Set("User-Agent", UserAgent)
+			if resp, err := c.httpClient.Do(req); err == nil {
+				if resp.StatusCode == http.StatusOK {
+					r = parseRobots(io.LimitReader(resp.Body, maxRobotsSize))
+				}
+				_ = resp.Body.Close()
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.robots[u.Host] = r
+	c.mu.Unlock()
+	return r
+}
@@ -0,0 +1,91 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseRobots documents which robots.txt rules apply:
+//   - the group naming feedmix, else the one for every robot
+//   - the longest matching rule wins, Allow winning ties
+func TestParseRobots(t *testing.T) {
+	r := parseRobots(strings.NewReader(`
+User-agent: *
+Disallow: /
+
+User-agent: Googlebot
+User-agent: feedmix
+Disallow: /private # members only
+Allow: /private/public
+Crawl-delay: 5
+`))
+	for path, want := range map[string]bool{"/post": true, "/private/x": false, "/private/public/y": true} {
+		if got := r.allows(path); got != want {
+			t.Errorf("%s: expected allowed %v, got %v", path, want, got)
+		}
+	}
+	if r.delay != 5*time.Second {
+		t.Errorf("expected the crawl delay read, got %s", r.delay)
+	}
+
+	if parseRobots(strings.NewReader("User-agent: *\nDisallow: /\n")).allows("/post") {
+		t.Error("rules for every robot should apply without a feedmix group")
+	}
+	if !parseRobots(strings.NewReader("User-agent: *\nDisallow:\n")).allows("/post") {
+		t.Error("an empty Disallow should allow everything")
+	}
+	if r := parseRobots(strings.NewReader("User-agent: *\nCrawl-delay: 86400\n")); r.delay != maxCrawlDelay {
+		t.Errorf("a crawl delay should be capped at %s, got %s", maxCrawlDelay, r.delay)
+	}
+}
+
+// TestParseRobots_Wildcards documents that * matches any characters and a
+// final $ the end of the path, the longest pattern winning as for others.
+func TestParseRobots_Wildcards(t *testing.T) {
+	r := parseRobots(strings.NewReader(`
+User-agent: *
+Disallow: /*.pdf$
+Disallow: /*/drafts/
+Allow: /team/drafts/public*
+`))
+	for path, want := range map[string]bool{
+		"/paper.pdf":                false,
+		"/papers/a.pdf.pdf":         false,
+		"/paper.pdf?download=1":     true,
+		"/x/drafts/post":            false,
+		"/team/drafts/public/post":  true,
+		"/team/drafts/private/post": false,
+		"/drafts/post":              true,
+	} {
+		if got := r.allows(path); got != want {
+			t.Errorf("%s: expected allowed %v, got %v", path, want, got)
+		}
+	}
+}
+
+func TestChecker_FollowsRobotsTxt(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /members\n")
+		}
+	}))
+	defer server.Close()
+
+	checker := NewChecker(WithHTTPClient(server.Client()), WithHostInterval(0), WithInterval(0))
+	if got := checker.Check(context.Background(), server.URL+"/members/post"); got.Status != Unknown || got.Err == nil {
+		t.Errorf("a disallowed link shouldn't be checked, got %+v", got)
+	}
+	if got := checker.Check(context.Background(), server.URL+"/post"); got.Status != Alive {
+		t.Errorf("an allowed link should be checked, got %+v", got)
+	}
+	if strings.Join(requested, " ") != "/robots.txt /post" {
+		t.Errorf("robots.txt should be fetched once per host and disallowed pages left alone, got %v", requested)
+	}
+}
@@ -0,0 +1,132 @@
+// Package lobsters provides a client for the JSON API of Lobsters
+// (https://lobste.rs) and the other link aggregators running its software,
+// such as https://tilde.news.
+package lobsters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Story is a link or text post submitted to the site.
+type Story struct {
+	ID    string
+	Title string
+	// URL is the linked page; empty for text posts.
+	URL string
+	// CommentsURL is the story's page on the site, with its discussion.
+	CommentsURL string
+	Description string
+	Submitter   string
+	Tags        []string
+	Score       int64
+	Comments    int64
+	CreatedAt   time.Time
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Client fetches stories from Lobsters sites.
+type Client struct {
+	httpClient HTTPClient
+}
+
+// NewClient creates a new Lobsters client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchStories returns the front page of the site at siteURL, or the
+// stories with any of tags when given, up to limit (0 for the whole page).
+func (c *Client) FetchStories(ctx context.Context, siteURL string, tags []string, limit int) ([]Story, error) {
+	endpoint := strings.TrimRight(siteURL, "/") + "/hottest.json"
+	if len(tags) > 0 {
+		escaped := make([]string, len(tags))
+		for i, tag := range tags {
+			escaped[i] = url.PathEscape(tag)
+		}
+		endpoint = strings.TrimRight(siteURL, "/") + "/t/" + strings.Join(escaped, ",") + ".json"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lobsters API returned HTTP %d for %s", resp.StatusCode, endpoint)
+	}
+	var page []struct {
+		ShortID          string          `json:"short_id"`
+		ShortIDURL       string          `json:"short_id_url"`
+		Title            string          `json:"title"`
+		URL              string          `json:"url"`
+		CommentsURL      string          `json:"comments_url"`
+		DescriptionPlain string          `json:"description_plain"`
+		Submitter        json.RawMessage `json:"submitter_user"`
+		Tags             []string        `json:"tags"`
+		Score            int64           `json:"score"`
+		CommentCount     int64           `json:"comment_count"`
+		CreatedAt        time.Time       `json:"created_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse stories from %s: %w", endpoint, err)
+	}
+
+	stories := make([]Story, 0, len(page))
+	for _, s := range page {
+		story := Story{
+			ID: s.ShortID, Title: s.Title, URL: s.URL, CommentsURL: s.CommentsURL, Description: s.DescriptionPlain,
+			Submitter: submitter(s.Submitter), Tags: s.Tags, Score: s.Score, Comments: s.CommentCount, CreatedAt: s.CreatedAt,
+		}
+		if story.CommentsURL == "" {
+			story.CommentsURL = s.ShortIDURL
+		}
+		stories = append(stories, story)
+		if limit > 0 && len(stories) == limit {
+			break
+		}
+	}
+	return stories, nil
+}
+
+// submitter reads submitter_user, a username in current versions of the
+// API and an object with one in older ones.
+func submitter(raw json.RawMessage) string {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return name
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	_ = json.Unmarshal(raw, &user)
+	return user.Username
+}
@@ -0,0 +1,46 @@
+package lobsters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClient_FetchStories documents reading a Lobsters site:
+//   - tags are read from the site's tag page, the front page otherwise
+//   - the submitter is read from both versions of the API
+//   - a story without its own discussion link gets its short link
+func TestClient_FetchStories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/t/go,rust.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"short_id":"abc","title":"Generics","url":"https://example.com/generics","comments_url":"https://lobste.rs/s/abc/generics","submitter_user":"alice","tags":["go"],"score":42,"comment_count":7,"created_at":"2024-01-15T10:00:00.000-06:00"},
+			{"short_id":"def","short_id_url":"https://lobste.rs/s/def","title":"Ask: borrow checker","url":"","submitter_user":{"username":"bob"},"tags":["rust","ask"]},
+			{"short_id":"ghi","title":"Third"}
+		]`)
+	}))
+	defer server.Close()
+
+	stories, err := NewClient().FetchStories(context.Background(), server.URL+"/", []string{"go", "rust"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stories) != 2 {
+		t.Fatalf("expected the first 2 stories, got %+v", stories)
+	}
+	if s := stories[0]; s.Submitter != "alice" || s.Score != 42 || s.Comments != 7 || s.CreatedAt.IsZero() {
+		t.Errorf("expected the story's submitter, score, comments and date, got %+v", s)
+	}
+	if s := stories[1]; s.Submitter != "bob" || s.CommentsURL != "https://lobste.rs/s/def" {
+		t.Errorf("expected the submitter of the older API and the short link, got %+v", s)
+	}
+
+	if _, err := NewClient().FetchStories(context.Background(), server.URL, nil, 0); err == nil {
+		t.Error("expected an error for a failing front page")
+	}
+}
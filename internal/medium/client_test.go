@@ -0,0 +1,91 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const writerFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Stories by Jane Doe on Medium</title>
+    <item>
+      <title>Writing every day</title>
+      <link>https://medium.com/@jane/writing-every-day-1a2b3c?source=rss-5517fd7b58a6------2</link>
+      <guid isPermaLink="false">https://medium.com/p/1a2b3c</guid>
+      <dc:creator>Jane Doe</dc:creator>
+      <pubDate>Mon, 15 Jan 2024 12:00:00 GMT</pubDate>
+      <atom:updated>2024-01-16T08:00:00.000Z</atom:updated>
+      <content:encoded><![CDATA[<p>One page a day.</p>]]></content:encoded>
+    </item>
+    <item>
+      <title>Unread</title>
+      <link>https://medium.com/@jane/unread-4d5e6f?source=rss-5517fd7b58a6------2&amp;lang=en</link>
+      <guid isPermaLink="false">https://medium.com/p/4d5e6f</guid>
+      <dc:creator>Jane Doe</dc:creator>
+      <pubDate>Sun, 14 Jan 2024 12:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+// TestClient_FetchFeed documents reading a writer's feed:
+//   - story links lose Medium's tracking parameters
+//   - claps and responses come from the story's JSON; a story whose
+//     statistics fail to load has none
+//   - a writer's feed has no title of its own
+func TestClient_FetchFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed/@jane":
+			fmt.Fprint(w, writerFeed)
+		case "/p/1a2b3c":
+			fmt.Fprint(w, jsonPrefix+`{"success":true,"payload":{"value":{"virtuals":{"totalClapCount":1500,"responsesCreatedCount":12}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	feed, err := NewClient(WithAPIURL(server.URL)).FetchFeed(context.Background(), server.URL+"/feed/@jane", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if feed.Title != "" || len(feed.Posts) != 2 {
+		t.Fatalf("expected the 2 stories of an untitled writer's feed, got %+v", feed)
+	}
+	story := feed.Posts[0]
+	if story.URL != "https://medium.com/@jane/writing-every-day-1a2b3c" || story.ID != "1a2b3c" || story.Author != "Jane Doe" {
+		t.Errorf("expected the story's clean link, ID and author, got %+v", story)
+	}
+	if story.Claps != 1500 || story.Responses != 12 || story.UpdatedAt.IsZero() || story.Content == "" {
+		t.Errorf("expected the story's claps, responses, update and content, got %+v", story)
+	}
+	if other := feed.Posts[1]; other.URL != "https://medium.com/@jane/unread-4d5e6f?lang=en" || other.Claps != 0 {
+		t.Errorf("expected other parameters kept and no claps, got %+v", other)
+	}
+}
+
+func TestFeedURL(t *testing.T) {
+	for page, want := range map[string]string{
+		"@jane":                              "https://medium.com/feed/@jane",
+		"https://medium.com/@jane/":          "https://medium.com/feed/@jane",
+		"https://medium.com/the-startup":     "https://medium.com/feed/the-startup",
+		"https://jane.medium.com":            "https://jane.medium.com/feed",
+		"https://blog.example.com":           "https://blog.example.com/feed",
+		"https://medium.com/feed/@jane":      "https://medium.com/feed/@jane",
+		"https://www.medium.com/the-startup": "https://medium.com/feed/the-startup",
+	} {
+		if got := FeedURL(page); got != want {
+			t.Errorf("FeedURL(%q) = %q, want %q", page, got, want)
+		}
+	}
+}
+
+func TestFeedTitle_NamesPublications(t *testing.T) {
+	if got := feedTitle("The Startup - Medium"); got != "The Startup" {
+		t.Errorf("expected the publication's name, got %q", got)
+	}
+}
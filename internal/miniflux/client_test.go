@@ -0,0 +1,80 @@
+package miniflux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestClient_Subscribes documents subscribing through the API:
+//   - every request carries the API key
+//   - feeds and categories are listed, and created from JSON bodies
+//   - Miniflux's error message is reported when it refuses a feed
+func TestClient_Subscribes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error_message":"access unauthorized"}`)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/feeds":
+			fmt.Fprint(w, `[{"id":1,"feed_url":"https://example.com/feed","title":"Example"}]`)
+		case "GET /v1/categories":
+			fmt.Fprint(w, `[{"id":1,"title":"All"}]`)
+		case "POST /v1/categories":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":2,"title":%q}`, body["title"])
+		case "POST /v1/feeds":
+			var body struct {
+				FeedURL    string `json:"feed_url"`
+				CategoryID int64  `json:"category_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.FeedURL == "https://broken.example.com/feed" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error_message":"unable to parse feed"}`)
+				return
+			}
+			if body.CategoryID != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"feed_id":7}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL+"/", "key")
+	feeds, err := client.Feeds(ctx)
+	if err != nil || len(feeds) != 1 || feeds[0].FeedURL != "https://example.com/feed" {
+		t.Fatalf("unexpected feeds %+v (err %v)", feeds, err)
+	}
+	categories, err := client.Categories(ctx)
+	if err != nil || len(categories) != 1 || categories[0].Title != "All" {
+		t.Fatalf("unexpected categories %+v (err %v)", categories, err)
+	}
+	category, err := client.CreateCategory(ctx, "feedmix")
+	if err != nil || category.ID != 2 || category.Title != "feedmix" {
+		t.Fatalf("unexpected category %+v (err %v)", category, err)
+	}
+	if id, err := client.CreateFeed(ctx, "https://new.example.com/feed", category.ID); err != nil || id != 7 {
+		t.Errorf("expected feed 7, got %d (err %v)", id, err)
+	}
+	if _, err := client.CreateFeed(ctx, "https://broken.example.com/feed", category.ID); err == nil || !strings.Contains(err.Error(), "unable to parse feed") {
+		t.Errorf("Miniflux's reason should be reported, got %v", err)
+	}
+	if _, err := NewClient(server.URL, "wrong").Feeds(ctx); err == nil || !strings.Contains(err.Error(), "access unauthorized") {
+		t.Errorf("a wrong API key should be reported, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package ntfy provides a client for ntfy servers, such as ntfy.sh, publishing
+// push notifications to a topic that phones subscribe to.
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultServer is the public ntfy server, used for topics given by name.
+const DefaultServer = "https://ntfy.sh"
+
+// Priorities of a notification; phones may sound or vibrate differently.
+const (
+	PriorityDefault = 3
+	PriorityHigh    = 4
+)
+
+// Notification is a message published to a topic.
+type Notification struct {
+	Title   string
+	Message string
+	// Click is opened when the notification is tapped.
+	Click string
+	// Attach is the URL of an image shown with the notification.
+	Attach   string
+	Priority int
+	Tags     []string
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the access token for topics that require one.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client publishes to ntfy topics.
+type Client struct {
+	httpClient HTTPClient
+	token      string
+}
+
+// NewClient creates a new ntfy client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Publish sends n to the topic at topicURL, such as https://ntfy.sh/mytopic,
+// or to a topic of DefaultServer given by name. It is published as JSON,
+// which keeps titles in any script intact where headers don't.
+func (c *Client) Publish(ctx context.Context, topicURL string, n Notification) error {
+	server, topic, err := splitTopic(topicURL)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(publishRequest{
+		Topic: topic, Title: n.Title, Message: n.Message, Click: n.Click, Attach: n.Attach, Priority: n.Priority, Tags: n.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode ntfy message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error != "" {
+			return fmt.Errorf("ntfy returned HTTP %d for topic %s: %s", resp.StatusCode, topic, apiErr.Error)
+		}
+		return fmt.Errorf("ntfy returned HTTP %d for topic %s", resp.StatusCode, topic)
+	}
+	return nil
+}
+
+// splitTopic returns the server a topic is published through and the
+// topic's name.
+func splitTopic(topicURL string) (server, topic string, err error) {
+	if !strings.Contains(topicURL, "://") {
+		return DefaultServer, topicURL, nil
+	}
+	u, err := url.Parse(strings.TrimRight(topicURL, "/"))
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid ntfy topic URL %q", topicURL)
+	}
+	i := strings.LastIndex(u.Path, "/")
+	topic = u.Path[i+1:]
+	if topic == "" {
+		return "", "", fmt.Errorf("invalid ntfy topic URL %q: expected a topic, such as https://ntfy.sh/mytopic", topicURL)
+	}
+	u.Path = u.Path[:i] + "/"
+	return u.String(), topic, nil
+}
+
+type publishRequest struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message"`
+	Click    string   `json:"click,omitempty"`
+	Attach   string   `json:"attach,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
@@ -0,0 +1,70 @@
+package ntfy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestClient_Publish documents publishing to a topic:
+//   - the notification goes as JSON to the server the topic is on, even under
+//     a path prefix
+//   - the access token, when set, is sent as a bearer token
+func TestClient_Publish(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/ntfy/" || r.Header.Get("Authorization") != "Bearer tk_secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"id":"abc"}`)
+	}))
+	defer server.Close()
+
+	n := Notification{Title: "Générique", Message: "New video", Click: "https://example.com", Priority: PriorityHigh, Tags: []string{"youtube"}}
+	if err := NewClient(WithToken("tk_secret")).Publish(context.Background(), server.URL+"/ntfy/videos", n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["topic"] != "videos" || got["title"] != "Générique" || got["priority"] != float64(PriorityHigh) || got["click"] != "https://example.com" {
+		t.Errorf("unexpected notification: %v", got)
+	}
+	if _, ok := got["attach"]; ok {
+		t.Errorf("a notification without an image shouldn't attach one: %v", got)
+	}
+}
+
+func TestClient_Publish_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"code":40301,"http":403,"error":"forbidden"}`)
+	}))
+	defer server.Close()
+
+	err := NewClient().Publish(context.Background(), server.URL+"/private", Notification{Message: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("the server's reason should be reported, got %v", err)
+	}
+	if err := NewClient().Publish(context.Background(), server.URL+"/", Notification{Message: "hi"}); err == nil {
+		t.Error("a server URL without a topic should be rejected")
+	}
+}
+
+func TestSplitTopic(t *testing.T) {
+	for _, tc := range []struct{ in, server, topic string }{
+		{"mytopic", DefaultServer, "mytopic"},
+		{"https://ntfy.example.com/alerts/", "https://ntfy.example.com/", "alerts"},
+	} {
+		server, topic, err := splitTopic(tc.in)
+		if err != nil || server != tc.server || topic != tc.topic {
+			t.Errorf("splitTopic(%q) = %q, %q, %v; want %q, %q", tc.in, server, topic, err, tc.server, tc.topic)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package obsidian exports feed items into an Obsidian vault (or any folder
+// of Markdown notes): one note per item plus a daily index note linking the
+// items exported that day.
+package obsidian
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// manifestName is the file in the vault folder recording which items were
+// exported and to which note, so later exports skip them even if a note is
+// renamed or an item's title changes.
+const manifestName = ".feedmix-exported.json"
+
+// maxNameLength keeps note file names well under file system limits.
+const maxNameLength = 100
+
+// Exporter writes notes into a vault folder.
+type Exporter struct {
+	dir string
+	now clock.Clock
+}
+
+// NewExporter creates an Exporter writing into dir, dating index notes by now.
+func NewExporter(dir string, now clock.Clock) *Exporter {
+	return &Exporter{dir: dir, now: now}
+}
+
+// Export writes a note for every item not exported before and links the new
+// notes from today's index note. It returns the number of notes written.
+func (e *Exporter) Export(items []aggregator.FeedItem) (int, error) {
+	if err := os.MkdirAll(e.dir, 0700); err != nil {
+		return 0, fmt.Errorf("failed to create vault folder: %w", err)
+	}
+	exported, err := e.loadManifest()
+	if err != nil {
+		return 0, err
+	}
+
+	var notes []string
+	for _, item := range items {
+		key := string(item.Source) + ":" + item.ID
+		if _, ok := exported[key]; ok {
+			continue
+		}
+		name, err := e.writeNote(item)
+		if err != nil {
+			return len(notes), err
+		}
+		exported[key] = name
+		notes = append(notes, name)
+	}
+	if len(notes) == 0 {
+		return 0, nil
+	}
+
+	if err := e.appendToIndex(notes); err != nil {
+		return len(notes), err
+	}
+	return len(notes), e.saveManifest(exported)
+}
+
+// writeNote writes item's note under a free name derived from its title and
+// returns the name without the .md extension, as Obsidian links use it.
+func (e *Exporter) writeNote(item aggregator.FeedItem) (string, error) {
+	base := noteName(item)
+	name := base
+	for i := 2; ; i++ {
+		f, err := os.OpenFile(filepath.Join(e.dir, name+".md"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec G304 - name is sanitized by noteName
+		if errors.Is(err, os.ErrExist) {
+			name = fmt.Sprintf("%s (%d)", base, i)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create note: %w", err)
+		}
+		_, err = f.WriteString(note(item))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write note: %w", err)
+		}
+		return name, nil
+	}
+}
+
+func note(item aggregator.FeedItem) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(item.Title))
+	fmt.Fprintf(&b, "source: %s\n", item.Source)
+	fmt.Fprintf(&b, "author: %s\n", strconv.Quote(item.Author))
+	fmt.Fprintf(&b, "url: %s\n", strconv.Quote(item.URL))
+	if !item.PublishedAt.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", item.PublishedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "tags: [feedmix, %s]\n", item.Source)
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s\n\n", item.Title)
+	if item.URL != "" {
+		fmt.Fprintf(&b, "<%s>\n\n", item.URL)
+	}
+	if description := strings.TrimSpace(item.Description); description != "" {
+		b.WriteString(description + "\n")
+	}
+	return b.String()
+}
+
+// noteName turns the title into a file name Obsidian accepts: characters
+// that are invalid in file names or that break [[links]] are dropped.
+func noteName(item aggregator.FeedItem) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`*"\/<>:|?#^[]`, r) || r < ' ' {
+			return -1
+		}
+		return r
+	}, item.Title)
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = strings.TrimSpace(string(runes[:maxNameLength]))
+	}
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		name = string(item.Source) + " " + item.ID
+	}
+	return name
+}
+
+// appendToIndex links notes from today's index note, creating it if needed.
+func (e *Exporter) appendToIndex(notes []string) error {
+	day := e.now().Format(time.DateOnly)
+	path := filepath.Join(e.dir, day+".md")
+
+	var b strings.Builder
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(&b, "# Feed %s\n\n", day)
+	}
+	for _, name := range notes {
+		fmt.Fprintf(&b, "- [[%s]]\n", name)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600) // #nosec G304 - path is the daily note in the vault folder
+	if err != nil {
+		return fmt.Errorf("failed to open daily note: %w", err)
+	}
+	_, err = f.WriteString(b.String())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write daily note: %w", err)
+	}
+	return nil
+}
+
+func (e *Exporter) loadManifest() (map[string]string, error) {
+	exported := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(e.dir, manifestName)) // #nosec G304 - path is the export manifest in the vault folder
+	if errors.Is(err, os.ErrNotExist) {
+		return exported, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, fmt.Errorf("failed to parse export manifest: %w", err)
+	}
+	return exported, nil
+}
+
+func (e *Exporter) saveManifest(exported map[string]string) error {
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(e.dir, manifestName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write export manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func post(id, title string) aggregator.FeedItem {
+	return aggregator.FeedItem{
+		ID:          id,
+		Source:      aggregator.SourceSubstack,
+		Title:       title,
+		Author:      "Jane \"JD\" Doe",
+		URL:         "https://example.substack.com/p/" + id,
+		PublishedAt: time.Date(2024, 1, 14, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestExporter_WritesNotesAndDailyIndex(t *testing.T) {
+	dir := t.TempDir()
+	exporter := NewExporter(dir, clock.System)
+	exporter.now = func() time.Time { return time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC) }
+
+	n, err := exporter.Export([]aggregator.FeedItem{post("a", "Go: what's new?"), post("b", "Go: what's new?")})
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 notes written, got %d (err %v)", n, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Go what's new.md"))
+	if err != nil {
+		t.Fatalf("note should be named after the title without invalid characters: %v", err)
+	}
+	for _, want := range []string{"source: substack\n", `author: "Jane \"JD\" Doe"`, `url: "https://example.substack.com/p/a"`, "date: 2024-01-14T09:00:00Z"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("note frontmatter should contain %q, got:\n%s", want, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Go what's new (2).md")); err != nil {
+		t.Errorf("a second item with the same title should get its own note: %v", err)
+	}
+
+	index, _ := os.ReadFile(filepath.Join(dir, "2024-01-15.md"))
+	if !strings.Contains(string(index), "- [[Go what's new]]\n- [[Go what's new (2)]]\n") {
+		t.Errorf("daily note should link every exported note, got:\n%s", index)
+	}
+
+	n, err = NewExporter(dir, clock.System).Export([]aggregator.FeedItem{post("a", "Go: what's new?"), post("c", "Another post")})
+	if err != nil || n != 1 {
+		t.Errorf("already exported items should be skipped, got %d notes written (err %v)", n, err)
+	}
+}
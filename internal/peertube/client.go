@@ -0,0 +1,144 @@
+// Package peertube provides a client for the REST API of PeerTube instances,
+// fetching the videos of channels on any instance of the fediverse.
+package peertube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCount is the most videos the API returns per page.
+const maxCount = 100
+
+// Video is a video published on a PeerTube channel.
+type Video struct {
+	ID          string
+	Title       string
+	Description string
+	URL         string
+	Thumbnail   string
+	Views       int64
+	Likes       int64
+	PublishedAt time.Time
+	Channel     string
+	// ChannelHandle is the channel's fediverse handle, name@host.
+	ChannelHandle string
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithScheme sets the scheme used to reach instances, "https" by default
+// (useful for testing).
+func WithScheme(scheme string) ClientOption {
+	return func(c *Client) {
+		c.scheme = scheme
+	}
+}
+
+// Client calls PeerTube instances.
+type Client struct {
+	httpClient HTTPClient
+	scheme     string
+}
+
+// NewClient creates a new PeerTube client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, scheme: "https"}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchChannelVideos returns the latest videos of the channel with handle
+// name@host, newest first, up to limit. The channel's own instance is asked,
+// so no account is needed anywhere.
+func (c *Client) FetchChannelVideos(ctx context.Context, handle string, limit int) ([]Video, error) {
+	name, host, ok := strings.Cut(handle, "@")
+	if !ok || name == "" || host == "" {
+		return nil, fmt.Errorf("invalid PeerTube channel %q: expected name@host", handle)
+	}
+	instance := c.scheme + "://" + host
+	query := url.Values{"count": {strconv.Itoa(min(max(limit, 1), maxCount))}, "sort": {"-publishedAt"}}
+	endpoint := instance + "/api/v1/video-channels/" + url.PathEscape(name) + "/videos?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Detail string `json:"detail"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Detail != "" {
+			return nil, fmt.Errorf("PeerTube API of %s returned HTTP %d: %s", host, resp.StatusCode, apiErr.Detail)
+		}
+		return nil, fmt.Errorf("PeerTube API of %s returned HTTP %d", host, resp.StatusCode)
+	}
+	var page struct {
+		Data []struct {
+			UUID          string    `json:"uuid"`
+			Name          string    `json:"name"`
+			Description   string    `json:"description"`
+			URL           string    `json:"url"`
+			ThumbnailPath string    `json:"thumbnailPath"`
+			Views         int64     `json:"views"`
+			Likes         int64     `json:"likes"`
+			PublishedAt   time.Time `json:"publishedAt"`
+			Channel       struct {
+				Name        string `json:"name"`
+				DisplayName string `json:"displayName"`
+				Host        string `json:"host"`
+			} `json:"channel"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse videos of %s: %w", handle, err)
+	}
+
+	videos := make([]Video, 0, len(page.Data))
+	for _, v := range page.Data {
+		video := Video{
+			ID: v.UUID, Title: v.Name, Description: v.Description, URL: v.URL,
+			Views: v.Views, Likes: v.Likes, PublishedAt: v.PublishedAt,
+			Channel: v.Channel.DisplayName, ChannelHandle: v.Channel.Name + "@" + v.Channel.Host,
+		}
+		if video.URL == "" {
+			video.URL = instance + "/videos/watch/" + v.UUID
+		}
+		if v.ThumbnailPath != "" {
+			video.Thumbnail = instance + v.ThumbnailPath
+		}
+		if v.Channel.Name == "" || v.Channel.Host == "" {
+			video.ChannelHandle = handle
+		}
+		videos = append(videos, video)
+	}
+	return videos, nil
+}
@@ -0,0 +1,65 @@
+package peertube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestClient_FetchChannelVideos documents reading a channel from its instance:
+//   - the newest videos are asked for, up to the limit
+//   - a video without a URL links to its watch page on the instance
+//   - thumbnails are served by the instance
+func TestClient_FetchChannelVideos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/video-channels/news/videos" || r.URL.Query().Get("count") != "2" || r.URL.Query().Get("sort") != "-publishedAt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"total":5,"data":[
+			{"uuid":"u1","name":"First","url":"https://peertube.tv/w/u1","thumbnailPath":"/lazy-static/thumbnails/u1.jpg","views":12,"likes":3,
+			 "publishedAt":"2024-01-15T10:00:00Z","channel":{"name":"news","displayName":"The News","host":"peertube.tv"}},
+			{"uuid":"u2","name":"Second","publishedAt":"2024-01-14T10:00:00Z"}
+		]}`)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	videos, err := NewClient(WithScheme("http")).FetchChannelVideos(context.Background(), "news@"+host, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(videos) != 2 {
+		t.Fatalf("expected 2 videos, got %+v", videos)
+	}
+	first := videos[0]
+	if first.Title != "First" || first.Channel != "The News" || first.ChannelHandle != "news@peertube.tv" || first.Views != 12 || first.Likes != 3 {
+		t.Errorf("unexpected video: %+v", first)
+	}
+	if first.Thumbnail != server.URL+"/lazy-static/thumbnails/u1.jpg" {
+		t.Errorf("thumbnail should be served by the instance, got %q", first.Thumbnail)
+	}
+	if second := videos[1]; second.URL != server.URL+"/videos/watch/u2" || second.ChannelHandle != "news@"+host {
+		t.Errorf("a video without a URL should link to its watch page, got %+v", second)
+	}
+}
+
+func TestClient_FetchChannelVideos_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"detail":"Video channel not found"}`)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	_, err := NewClient(WithScheme("http")).FetchChannelVideos(context.Background(), "gone@"+host, 10)
+	if err == nil || !strings.Contains(err.Error(), "Video channel not found") {
+		t.Errorf("the instance's reason should be reported, got %v", err)
+	}
+	if _, err := NewClient().FetchChannelVideos(context.Background(), "gone", 10); err == nil {
+		t.Error("a handle without an instance should be rejected")
+	}
+}
@@ -0,0 +1,162 @@
+// Package picker is a small fuzzy finder in the style of fzf: it ranks lines
+// against a typed query and lets the user choose among the best matches.
+package picker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// shownMatches is how many of the best matches Prompt lists at a time.
+const shownMatches = 20
+
+// Scores for each matched character, and its bonuses and penalties.
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 8
+	bonusWordStart   = 8
+	penaltyGap       = 1
+)
+
+// Score reports whether every character of query appears in text in order,
+// ignoring case, and how well it matches: characters following each other
+// or starting words score higher, gaps between them lower. Spaces in query
+// separate terms that must each match. An empty query matches everything
+// with a score of 0.
+func Score(query, text string) (int, bool) {
+	haystack := []rune(strings.ToLower(text))
+	total := 0
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		score, ok := scoreTerm([]rune(term), haystack)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// scoreTerm returns the best score of term in text, trying each place its
+// first character appears as the start of the match.
+func scoreTerm(term, text []rune) (int, bool) {
+	best, found := 0, false
+	for start := range text {
+		if text[start] != term[0] {
+			continue
+		}
+		score, ok := scoreFrom(term, text, start)
+		if ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom matches term greedily in text from start, where term[0] is.
+func scoreFrom(term, text []rune, start int) (int, bool) {
+	score := 0
+	prev := -1
+	i := start
+	for _, r := range term {
+		for i < len(text) && text[i] != r {
+			i++
+		}
+		if i == len(text) {
+			return 0, false
+		}
+		score += scoreMatch
+		if prev >= 0 && i == prev+1 {
+			score += bonusConsecutive
+		} else if prev >= 0 {
+			score -= penaltyGap * (i - prev - 1)
+		}
+		if i == 0 || !isWordRune(text[i-1]) {
+			score += bonusWordStart
+		}
+		prev = i
+		i++
+	}
+	return score, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Filter returns the indexes of the lines matching query, best match first.
+// Lines scoring the same keep their order.
+func Filter(query string, lines []string) []int {
+	type match struct{ index, score int }
+	var matches []match
+	for i, line := range lines {
+		if score, ok := Score(query, line); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
+
+// Prompt is the built-in finder, for when fzf isn't available. It lists the
+// lines best matching query on out and reads from in either a new query, to
+// narrow the list, or the numbers of the lines to pick, counted from 1 in
+// the order of lines. It returns the indexes of the picked lines, or none
+// if the user enters nothing.
+func Prompt(in io.Reader, out io.Writer, lines []string, query string) ([]int, error) {
+	reader := bufio.NewReader(in)
+	fmt.Fprintln(out, "Type words to filter, the numbers of items to pick (e.g. 2 or 1 3), or nothing to cancel.")
+	for {
+		matches := Filter(query, lines)
+		for _, i := range matches[:min(len(matches), shownMatches)] {
+			fmt.Fprintf(out, "%3d. %s\n", i+1, lines[i])
+		}
+		switch {
+		case len(matches) == 0:
+			fmt.Fprintf(out, "No matches for %q.\n", query)
+		case len(matches) > shownMatches:
+			fmt.Fprintf(out, "     ... and %d more\n", len(matches)-shownMatches)
+		}
+		fmt.Fprint(out, "> ")
+
+		line, err := reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if input == "" {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("failed to read selection: %w", err)
+			}
+			return nil, nil
+		}
+		picked, ok := parseNumbers(input, len(lines))
+		if ok {
+			return picked, nil
+		}
+		if err != nil {
+			return nil, nil
+		}
+		query = input
+	}
+}
+
+// parseNumbers parses input as line numbers from 1 to n, returning their
+// indexes, or false if it isn't made of such numbers only.
+func parseNumbers(input string, n int) ([]int, bool) {
+	var indexes []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > n {
+			return nil, false
+		}
+		indexes = append(indexes, number-1)
+	}
+	return indexes, len(indexes) > 0
+}
@@ -0,0 +1,66 @@
+package picker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+var lines = []string{
+	"2h · youtube · Tech Channel · Go generics explained",
+	"5h · substack · Simon Willison · Everything I built this week",
+	"1d · youtube · Dev Talks · Profiling Go programs",
+}
+
+func TestScore_MatchesCharactersInOrderIgnoringCase(t *testing.T) {
+	for _, query := range []string{"", "go", "GGE", "tech generics", "pgp"} {
+		if _, ok := Score(query, lines[0]+" "+lines[2]); !ok {
+			t.Errorf("Score(%q) should match", query)
+		}
+	}
+	for _, query := range []string{"xyz", "seneg", "go rust"} {
+		if _, ok := Score(query, lines[0]); ok {
+			t.Errorf("Score(%q) should not match %q", query, lines[0])
+		}
+	}
+
+	consecutive, _ := Score("prof", "Profiling")
+	scattered, _ := Score("prof", "Pretty rough ordeal, friend")
+	if consecutive <= scattered {
+		t.Errorf("consecutive characters should score higher: %d <= %d", consecutive, scattered)
+	}
+}
+
+func TestFilter_RanksBestMatchesFirst(t *testing.T) {
+	if got := Filter("", lines); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("an empty query should keep every line in order, got %v", got)
+	}
+	if got := Filter("go prog", lines); !slices.Equal(got, []int{2}) {
+		t.Errorf("expected only the profiling talk, got %v", got)
+	}
+	if got := Filter("youtube go", lines); len(got) != 2 || slices.Contains(got, 1) {
+		t.Errorf("expected both YouTube videos, got %v", got)
+	}
+}
+
+func TestPrompt_NarrowsByQueryAndPicksByNumber(t *testing.T) {
+	var out strings.Builder
+	picked, err := Prompt(strings.NewReader("willison\n2\n"), &out, lines, "")
+	if err != nil || !slices.Equal(picked, []int{1}) {
+		t.Fatalf("expected the second line picked, got %v, %v", picked, err)
+	}
+	if shown := out.String(); !strings.Contains(shown, "  3. 1d") || strings.Count(shown, "  2. 5h") != 2 {
+		t.Errorf("expected every line, then only the match, numbered as given, got:\n%s", shown)
+	}
+
+	picked, _ = Prompt(strings.NewReader("1, 3\n"), &out, lines, "youtube")
+	if !slices.Equal(picked, []int{0, 2}) {
+		t.Errorf("expected several lines picked at once, got %v", picked)
+	}
+
+	for _, input := range []string{"\n", "", "rust\n"} {
+		if picked, err := Prompt(strings.NewReader(input), &out, lines, ""); picked != nil || err != nil {
+			t.Errorf("input %q should pick nothing, got %v, %v", input, picked, err)
+		}
+	}
+}
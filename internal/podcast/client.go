@@ -0,0 +1,200 @@
+// Package podcast provides a client for podcast RSS feeds, reading the
+// iTunes tags podcast apps rely on: episode lengths, numbers and artwork.
+package podcast
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
+)
+
+// Episode is an episode of a podcast.
+type Episode struct {
+	ID          string
+	Title       string
+	Description string
+	Author      string
+	URL         string
+	PublishedAt time.Time
+	// AudioURL, AudioType and AudioLength describe the enclosed audio file;
+	// AudioLength is 0 when unknown.
+	AudioURL    string
+	AudioType   string
+	AudioLength int64
+	// Duration is 0 when the feed doesn't give it.
+	Duration time.Duration
+	// Season and Number are 0 when the feed doesn't number episodes.
+	Season int
+	Number int
+	// Image is the episode's artwork, or the podcast's when it has none.
+	Image string
+}
+
+// Feed is a podcast and its episodes, newest first.
+type Feed struct {
+	Title    string
+	Episodes []Episode
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Client fetches podcast feeds.
+type Client struct {
+	httpClient HTTPClient
+}
+
+// NewClient creates a new podcast client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchFeed fetches the podcast feed at feedURL and returns at most limit
+// episodes (0 for all). Items without an audio enclosure, such as
+// announcements, are skipped.
+func (c *Client) FetchFeed(ctx context.Context, feedURL string, limit int) (Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return Feed{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Feed{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Feed{}, fmt.Errorf("podcast feed returned HTTP %d for %s", resp.StatusCode, feedURL)
+	}
+	body, err := rss.ReadFeed(resp, feedURL)
+	if err != nil {
+		return Feed{}, err
+	}
+	return parseFeed(body, limit)
+}
+
+func parseFeed(data []byte, limit int) (Feed, error) {
+	var doc rssDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return Feed{}, fmt.Errorf("failed to parse podcast feed: %w", err)
+	}
+	channel := doc.Channel
+	image := strings.TrimSpace(channel.ITunesImage.Href)
+	if image == "" {
+		image = strings.TrimSpace(channel.Image.URL)
+	}
+
+	feed := Feed{Title: strings.TrimSpace(channel.Title)}
+	for _, item := range channel.Items {
+		episode, ok := item.episode(image)
+		if !ok {
+			continue
+		}
+		feed.Episodes = append(feed.Episodes, episode)
+		if limit > 0 && len(feed.Episodes) == limit {
+			break
+		}
+	}
+	return feed, nil
+}
+
+// episode returns the item as an episode, with the podcast's artwork when it
+// has none, or false when it encloses no audio.
+func (item rssItem) episode(podcastImage string) (Episode, bool) {
+	enclosure := item.Enclosure
+	if !enclosure.IsAudio() {
+		return Episode{}, false
+	}
+	episode := Episode{
+		ID:          strings.TrimSpace(item.GUID),
+		Title:       strings.TrimSpace(item.Title),
+		Description: item.Description,
+		Author:      strings.TrimSpace(item.Author),
+		URL:         strings.TrimSpace(item.Link),
+		PublishedAt: rss.ParseDate(item.PubDate),
+		AudioURL:    enclosure.URL,
+		AudioType:   enclosure.Type,
+		AudioLength: enclosure.Size(),
+		Duration:    rss.ParseDuration(item.Duration),
+		Season:      parseNumber(item.Season),
+		Number:      parseNumber(item.Episode),
+		Image:       strings.TrimSpace(item.ITunesImage.Href),
+	}
+	if episode.Description == "" {
+		episode.Description = item.Summary
+	}
+	if episode.ID == "" {
+		episode.ID = enclosure.URL
+	}
+	if episode.URL == "" {
+		episode.URL = enclosure.URL
+	}
+	if episode.Image == "" {
+		episode.Image = podcastImage
+	}
+	return episode, true
+}
+
+// parseNumber parses an itunes:season or itunes:episode, returning 0 when
+// it is missing or not a positive number.
+func parseNumber(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// rssDoc and rssItem are private XML parsing structs. Fields in the itunes
+// namespace come before plain RSS fields of the same name, which would
+// otherwise match them too.
+type rssDoc struct {
+	Channel struct {
+		Title       string      `xml:"title"`
+		ITunesImage itunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+		Image       struct {
+			URL string `xml:"url"`
+		} `xml:"image"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        string        `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Enclosure   rss.Enclosure `xml:"enclosure"`
+	Author      string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Summary     string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Duration    string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Season      string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	Episode     string        `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesImage itunesImage   `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
@@ -0,0 +1,95 @@
+package podcast
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const podcastXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Go Time</title>
+    <itunes:image href="https://example.com/show.jpg"/>
+    <image><url>https://example.com/rss.jpg</url></image>
+    <item>
+      <title>Generics, one year on</title>
+      <link>https://example.com/episodes/12</link>
+      <guid>ep-12</guid>
+      <pubDate>Mon, 15 Jan 2024 12:00:00 +0000</pubDate>
+      <itunes:author>Jane Doe</itunes:author>
+      <itunes:summary>What we learned.</itunes:summary>
+      <itunes:duration>1:02:03</itunes:duration>
+      <itunes:season>2</itunes:season>
+      <itunes:episode>12</itunes:episode>
+      <itunes:image href="https://example.com/12.jpg"/>
+      <enclosure url="https://cdn.example.com/12.mp3" type="audio/mpeg" length="1000"/>
+    </item>
+    <item>
+      <title>We're moving</title>
+      <description>No episode this week.</description>
+    </item>
+    <item>
+      <title>Bonus</title>
+      <description>A short one.</description>
+      <itunes:duration>95</itunes:duration>
+      <enclosure url="https://cdn.example.com/bonus.mp3" type="audio/mpeg" length="oops"/>
+    </item>
+  </channel>
+</rss>`
+
+// TestClient_FetchFeed documents reading a podcast feed:
+//   - episodes get their length, season, number and artwork from itunes: tags
+//   - an episode without artwork gets the podcast's
+//   - items without audio are skipped; an episode without a page or guid is
+//     identified by its audio file
+func TestClient_FetchFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, podcastXML)
+	}))
+	defer server.Close()
+
+	feed, err := NewClient().FetchFeed(context.Background(), server.URL, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if feed.Title != "Go Time" || len(feed.Episodes) != 2 {
+		t.Fatalf("expected the 2 episodes of Go Time, got %+v", feed)
+	}
+	first := feed.Episodes[0]
+	if first.ID != "ep-12" || first.Author != "Jane Doe" || first.Description != "What we learned." || first.URL != "https://example.com/episodes/12" {
+		t.Errorf("unexpected episode: %+v", first)
+	}
+	if first.Duration != time.Hour+2*time.Minute+3*time.Second || first.Season != 2 || first.Number != 12 || first.Image != "https://example.com/12.jpg" {
+		t.Errorf("episode should keep its itunes tags, got %+v", first)
+	}
+	if first.AudioURL != "https://cdn.example.com/12.mp3" || first.AudioType != "audio/mpeg" || first.AudioLength != 1000 {
+		t.Errorf("episode should keep its enclosure, got %+v", first)
+	}
+	bonus := feed.Episodes[1]
+	if bonus.ID != "https://cdn.example.com/bonus.mp3" || bonus.URL != bonus.AudioURL || bonus.Image != "https://example.com/show.jpg" {
+		t.Errorf("an episode without guid, page or artwork should fall back to its audio and the show's artwork, got %+v", bonus)
+	}
+	if bonus.Duration != 95*time.Second || bonus.AudioLength != 0 || bonus.Number != 0 {
+		t.Errorf("unexpected bonus episode: %+v", bonus)
+	}
+
+	limited, err := NewClient().FetchFeed(context.Background(), server.URL, 1)
+	if err != nil || len(limited.Episodes) != 1 {
+		t.Errorf("expected 1 episode within the limit, got %+v, %v", limited, err)
+	}
+}
+
+func TestClient_FetchFeed_ReturnsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient().FetchFeed(context.Background(), server.URL, 0); err == nil {
+		t.Error("expected an error for HTTP 404")
+	}
+}
@@ -0,0 +1,111 @@
+// Package pushover provides a client for the Pushover API, sending push
+// notifications to a user's devices.
+package pushover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseURL is the Pushover API endpoint.
+const DefaultBaseURL = "https://api.pushover.net/1"
+
+// Priorities of a message: high priority bypasses the user's quiet hours.
+const (
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+// Message is a notification sent to a user.
+type Message struct {
+	Title   string
+	Message string
+	// URL is opened from the notification, captioned URLTitle.
+	URL      string
+	URLTitle string
+	Priority int
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the Pushover API endpoint (useful for testing).
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// Client sends messages on behalf of an application.
+type Client struct {
+	httpClient HTTPClient
+	baseURL    string
+	token      string
+	user       string
+}
+
+// NewClient creates a Pushover client for the application token, sending
+// to the user (or group) key.
+func NewClient(token, user string, opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, baseURL: DefaultBaseURL, token: token, user: user}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Send delivers m to the user's devices.
+func (c *Client) Send(ctx context.Context, m Message) error {
+	form := url.Values{
+		"token":    {c.token},
+		"user":     {c.user},
+		"title":    {m.Title},
+		"message":  {m.Message},
+		"priority": {strconv.Itoa(m.Priority)},
+	}
+	if m.URL != "" {
+		form.Set("url", m.URL)
+		form.Set("url_title", m.URLTitle)
+	}
+	endpoint := strings.TrimRight(c.baseURL, "/") + "/messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var result struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK || result.Status != 1 {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("pushover returned HTTP %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+		}
+		return fmt.Errorf("pushover returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
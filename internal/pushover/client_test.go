@@ -0,0 +1,39 @@
+package pushover
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestClient_Send documents sending a message:
+//   - the application token and user key go with every message, as a form
+//   - the link and priority are sent along
+func TestClient_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages.json" || r.FormValue("token") != "app" || r.FormValue("user") != "me" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":0,"errors":["application token is invalid"]}`)
+			return
+		}
+		if r.FormValue("url") != "https://example.com" || r.FormValue("priority") != "1" || r.FormValue("message") != "New video" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":0,"errors":["unexpected message"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":1,"request":"abc"}`)
+	}))
+	defer server.Close()
+
+	m := Message{Title: "Go", Message: "New video", URL: "https://example.com", URLTitle: "Watch", Priority: PriorityHigh}
+	if err := NewClient("app", "me", WithBaseURL(server.URL)).Send(context.Background(), m); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := NewClient("bad", "me", WithBaseURL(server.URL)).Send(context.Background(), m)
+	if err == nil || !strings.Contains(err.Error(), "application token is invalid") {
+		t.Errorf("Pushover's reason should be reported, got %v", err)
+	}
+}
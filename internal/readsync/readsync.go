@@ -0,0 +1,187 @@
+// Package readsync keeps the read and starred state of reader items in step
+// between feedmix, where reading means opening an item and starring means
+// saving it, and the Google Reader API server the items come from.
+package readsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// retention is how long an item no sync sees any more is remembered.
+const retention = 90 * 24 * time.Hour
+
+// Item is a reader item's state in feedmix (Local) and on the server
+// (Remote).
+type Item struct {
+	ID string
+	// Published is when the item was published, the time an item that was
+	// never read or starred last changed.
+	Published time.Time
+	Local     State
+	Remote    State
+}
+
+// State is whether an item is read and starred on one side. ReadAt and
+// StarredAt are when the user last read or starred it there, zero if
+// unknown: the server doesn't say.
+type State struct {
+	Read      bool
+	Starred   bool
+	ReadAt    time.Time
+	StarredAt time.Time
+}
+
+// Changes are the item IDs to update on one side.
+type Changes struct {
+	Read   []string
+	Unread []string
+	Star   []string
+	Unstar []string
+}
+
+// Empty reports whether there is nothing to change.
+func (c Changes) Empty() bool {
+	return len(c.Read)+len(c.Unread)+len(c.Star)+len(c.Unstar) == 0
+}
+
+// Plan is what a sync changes on each side.
+type Plan struct {
+	Local  Changes
+	Remote Changes
+}
+
+// Store records the state each item was last synced with, and when that
+// state last changed on either side.
+type Store struct {
+	path    string
+	now     clock.Clock
+	entries map[string]entry
+}
+
+type entry struct {
+	Read     flag      `json:"read"`
+	Starred  flag      `json:"starred"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// flag is the value of a flag and when it was set.
+type flag struct {
+	On bool      `json:"on"`
+	At time.Time `json:"at"`
+}
+
+// Open loads the store at path; a missing file yields an empty store.
+func Open(path string, now clock.Clock) (*Store, error) {
+	s := &Store{path: path, now: now, entries: make(map[string]entry)}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the sync state in the user's config directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reader sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse reader sync state: %w", err)
+	}
+	return s, nil
+}
+
+// Reconcile returns the changes that bring both sides of items in step,
+// and records the state they agree on.
+//
+// For each flag the most recent change wins. A side still holding the value
+// last synced keeps that sync's time, so a side that changed since always
+// wins over one that didn't. Local changes are timed by when the user made
+// them; remote ones, which the server doesn't time, by when they are first
+// seen. An item synced for the first time is taken as unread and unstarred
+// since it was published, so whichever side read or starred it wins.
+func (s *Store) Reconcile(items []Item) Plan {
+	now := s.now().UTC()
+	var plan Plan
+	for _, item := range items {
+		lastRead, lastStarred := flag{At: item.Published}, flag{At: item.Published}
+		if synced, ok := s.entries[item.ID]; ok {
+			lastRead, lastStarred = synced.Read, synced.Starred
+		}
+		read := latest(
+			change(item.Local.Read, item.Local.ReadAt, lastRead, now),
+			change(item.Remote.Read, item.Remote.ReadAt, lastRead, now),
+		)
+		starred := latest(
+			change(item.Local.Starred, item.Local.StarredAt, lastStarred, now),
+			change(item.Remote.Starred, item.Remote.StarredAt, lastStarred, now),
+		)
+
+		plan.Local.add(item.ID, item.Local, read.On, starred.On)
+		plan.Remote.add(item.ID, item.Remote, read.On, starred.On)
+		s.entries[item.ID] = entry{Read: read, Starred: starred, SyncedAt: now}
+	}
+	return plan
+}
+
+// change returns one side's flag and when it last changed: synced when the
+// side still holds its value, else at if that is later, else now.
+func change(on bool, at time.Time, synced flag, now time.Time) flag {
+	if on == synced.On {
+		return synced
+	}
+	if !at.After(synced.At) {
+		at = now
+	}
+	return flag{On: on, At: at}
+}
+
+// latest returns the flag changed last, the remote one on a tie.
+func latest(local, remote flag) flag {
+	if local.At.After(remote.At) {
+		return local
+	}
+	return remote
+}
+
+// add records the changes that take state to read and starred.
+func (c *Changes) add(id string, state State, read, starred bool) {
+	switch {
+	case read && !state.Read:
+		c.Read = append(c.Read, id)
+	case !read && state.Read:
+		c.Unread = append(c.Unread, id)
+	}
+	switch {
+	case starred && !state.Starred:
+		c.Star = append(c.Star, id)
+	case !starred && state.Starred:
+		c.Unstar = append(c.Unstar, id)
+	}
+}
+
+// Save writes the store back to disk, forgetting items not synced for 90
+// days.
+func (s *Store) Save() error {
+	cutoff := s.now().Add(-retention)
+	for id, e := range s.entries {
+		if e.SyncedAt.Before(cutoff) {
+			delete(s.entries, id)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create reader sync directory: %w", err)
+	}
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode reader sync state: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write reader sync state: %w", err)
+	}
+	return nil
+}
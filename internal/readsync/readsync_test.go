@@ -0,0 +1,84 @@
+package readsync
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestStore_Reconcile documents two-way sync of read and starred state:
+// - on the first sync, whichever side read or starred an item wins
+// - afterwards, the side that changed since the last sync wins
+// - the state agreed on is remembered across runs
+func TestStore_Reconcile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reader-sync.json")
+	published := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	current := published.Add(4 * time.Hour)
+	store, err := Open(path, func() time.Time { return current })
+	if err != nil {
+		t.Fatalf("a missing file should open empty, got: %v", err)
+	}
+
+	plan := store.Reconcile([]Item{
+		{ID: "opened-here", Published: published, Local: State{Read: true, ReadAt: published.Add(time.Hour)}},
+		{ID: "read-there", Published: published, Remote: State{Read: true, Starred: true}},
+		{ID: "saved-here", Published: published, Local: State{Starred: true, StarredAt: published.Add(2 * time.Hour)}, Remote: State{Read: true}},
+		{ID: "untouched", Published: published},
+	})
+	want := Plan{
+		Local:  Changes{Read: []string{"read-there", "saved-here"}, Star: []string{"read-there"}},
+		Remote: Changes{Read: []string{"opened-here"}, Star: []string{"saved-here"}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("first sync: expected %+v, got %+v", want, plan)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The user unstars one item in the reader and marks another unread in
+	// feedmix; the other side still has the synced state.
+	current = current.Add(time.Hour)
+	store, err = Open(path, func() time.Time { return current })
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan = store.Reconcile([]Item{
+		{ID: "read-there", Published: published, Local: State{Read: true, Starred: true, StarredAt: published}, Remote: State{Read: true}},
+		{ID: "opened-here", Published: published, Remote: State{Read: true}},
+		{ID: "untouched", Published: published},
+	})
+	want = Plan{
+		Local:  Changes{Unstar: []string{"read-there"}},
+		Remote: Changes{Unread: []string{"opened-here"}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("second sync: expected %+v, got %+v", want, plan)
+	}
+	if !store.Reconcile(nil).Local.Empty() {
+		t.Error("nothing to sync should change nothing")
+	}
+}
+
+// TestStore_Reconcile_MostRecentChangeWins covers an item both sides changed
+// before it was first synced: opening it after it was published beats the
+// server's unread state, and starring it on the server, seen now, beats an
+// older local state.
+func TestStore_Reconcile_MostRecentChangeWins(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	store, err := Open(filepath.Join(t.TempDir(), "reader-sync.json"), func() time.Time { return now })
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := store.Reconcile([]Item{{
+		ID:        "both",
+		Published: now.Add(-48 * time.Hour),
+		Local:     State{Read: true, ReadAt: now.Add(-time.Hour)},
+		Remote:    State{Starred: true},
+	}})
+	want := Plan{Local: Changes{Star: []string{"both"}}, Remote: Changes{Read: []string{"both"}}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("expected %+v, got %+v", want, plan)
+	}
+}
@@ -0,0 +1,104 @@
+// Package rss holds what the clients of RSS, Atom and JSON feeds share:
+// telling a feed from the web page some hosts serve in its place, and
+// reading the dates, enclosures and itunes:duration tags feeds use.
+package rss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrHTMLPage is returned when a feed URL answers with a web page, such as a
+// login page or a bot check, instead of a feed.
+var ErrHTMLPage = errors.New("the feed URL returned an HTML page, not a feed")
+
+// ReadFeed reads the body of a successful feed response. When it is a web
+// page rather than a feed, it returns ErrHTMLPage naming url, the address
+// the user configured.
+func ReadFeed(resp *http.Response, url string) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed of %s: %w", url, err)
+	}
+	if isHTML(resp.Header.Get("Content-Type"), body) {
+		return nil, fmt.Errorf("%w: is %s the right URL, or is its feed behind a login?", ErrHTMLPage, url)
+	}
+	return body, nil
+}
+
+// feedPrefixes are how RSS, Atom and JSON feeds start.
+var feedPrefixes = []string{"<?xml", "<rss", "<feed", "<rdf", "{"}
+
+// isHTML reports whether a feed response is a web page rather than a feed,
+// going by the start of the body and, when that is inconclusive, by its
+// content type. Feeds served as text/html still parse.
+func isHTML(contentType string, body []byte) bool {
+	start := bytes.ToLower(bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	start = start[:min(len(start), 64)]
+	for _, prefix := range feedPrefixes {
+		if bytes.HasPrefix(start, []byte(prefix)) {
+			return false
+		}
+	}
+	if bytes.HasPrefix(start, []byte("<!doctype html")) || bytes.HasPrefix(start, []byte("<html")) {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// dateLayouts are the dates feeds use in pubDate and Atom's published.
+var dateLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339}
+
+// ParseDate parses a feed date, returning the zero time when it is missing
+// or in no known layout.
+func ParseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ParseDuration parses an itunes:duration, either seconds ("2530") or
+// [hh:]mm:ss ("42:10", "1:02:03"). It returns 0 for anything else.
+func ParseDuration(s string) time.Duration {
+	var seconds int
+	for _, part := range strings.Split(strings.TrimSpace(s), ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Enclosure is the <enclosure> of an RSS item: the file it carries, such as
+// a podcast episode or a cover image.
+type Enclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// IsAudio reports whether the enclosure is an audio file.
+func (e Enclosure) IsAudio() bool {
+	return e.URL != "" && strings.HasPrefix(e.Type, "audio/")
+}
+
+// Size returns the length of the enclosed file in bytes, or 0 when unknown.
+// Feeds often give a length of 0 or leave it out; a bad one is unknown.
+func (e Enclosure) Size() int64 {
+	length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+	return max(length, 0)
+}
@@ -0,0 +1,84 @@
+package rss
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func response(contentType, body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// TestReadFeed_ReportsHTMLPages documents how feed responses are told apart
+// from web pages:
+//   - a login page or bot check → ErrHTMLPage naming the configured URL
+//   - RSS, Atom and JSON feeds pass, even when served as text/html
+//   - a body that starts like neither falls back to the content type
+func TestReadFeed_ReportsHTMLPages(t *testing.T) {
+	_, err := ReadFeed(response("text/html; charset=utf-8", "<!DOCTYPE html><html><title>Just a moment...</title></html>"), "https://example.com")
+	if !errors.Is(err, ErrHTMLPage) || !strings.Contains(err.Error(), "https://example.com") {
+		t.Errorf("expected ErrHTMLPage naming the URL, got %v", err)
+	}
+
+	for _, body := range []string{"\xef\xbb\xbf<?xml version=\"1.0\"?><rss/>", "<rss version=\"2.0\"/>", "<feed xmlns=\"http://www.w3.org/2005/Atom\"/>", `{"version":"https://jsonfeed.org/version/1.1"}`} {
+		if got, err := ReadFeed(response("text/html", body), "https://example.com"); err != nil || string(got) != body {
+			t.Errorf("feed %.20q should pass, got %v", body, err)
+		}
+	}
+
+	for contentType, want := range map[string]bool{"text/html": true, "application/xhtml+xml": true, "application/octet-stream": false, "": false} {
+		if got := isHTML(contentType, []byte("  <body>hello</body>")); got != want {
+			t.Errorf("isHTML(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
+
+// TestParseDate_ReadsFeedDateLayouts documents the dates feeds use:
+//   - RFC 1123 with a numeric zone or a zone name, as in RSS pubDate
+//   - RFC 3339, as in Atom
+//   - anything else is the zero time
+func TestParseDate_ReadsFeedDateLayouts(t *testing.T) {
+	want := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	for _, s := range []string{"Mon, 15 Jan 2024 10:00:00 +0000", " Mon, 15 Jan 2024 10:00:00 UTC ", "2024-01-15T10:00:00Z"} {
+		if got := ParseDate(s); !got.Equal(want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", s, got, want)
+		}
+	}
+	if got := ParseDate("yesterday"); !got.IsZero() {
+		t.Errorf("expected the zero time for an unknown layout, got %v", got)
+	}
+}
+
+func TestParseDuration_ReadsSecondsAndClockForms(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"2530":    2530 * time.Second,
+		"42:10":   42*time.Minute + 10*time.Second,
+		"1:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		"":        0,
+		"1h":      0,
+		"-5":      0,
+	} {
+		if got := ParseDuration(s); got != want {
+			t.Errorf("ParseDuration(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestEnclosure_RecognizesAudioAndItsSize(t *testing.T) {
+	audio := Enclosure{URL: "https://example.com/ep.mp3", Type: "audio/mpeg", Length: " 1000 "}
+	if !audio.IsAudio() || audio.Size() != 1000 {
+		t.Errorf("expected an audio enclosure of 1000 bytes, got %+v", audio)
+	}
+	cover := Enclosure{URL: "https://example.com/cover.jpg", Type: "image/jpeg", Length: "-1"}
+	if cover.IsAudio() || cover.Size() != 0 {
+		t.Errorf("expected a cover image of unknown size, got %+v", cover)
+	}
+}
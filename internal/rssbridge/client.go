@@ -0,0 +1,154 @@
+// Package rssbridge provides a client for feeds generated by RSS-Bridge
+// (https://github.com/RSS-Bridge/rss-bridge), which turns sites without a
+// feed into one.
+package rssbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
+)
+
+// Item is an entry of a bridge's feed.
+type Item struct {
+	ID          string
+	Title       string
+	Content     string
+	Author      string
+	URL         string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// Feed is what a bridge returned: its title and entries, newest first.
+type Feed struct {
+	Title string
+	Items []Item
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Client fetches feeds from RSS-Bridge instances.
+type Client struct {
+	httpClient HTTPClient
+}
+
+// NewClient creates a new RSS-Bridge client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FetchFeed fetches the feed at feedURL, a bridge's display URL such as
+// https://rss-bridge.org/bridge01/?action=display&bridge=GithubIssueBridge&...
+// Whatever format the URL asks for, the feed is requested as JSON. At most
+// limit items are returned.
+func (c *Client) FetchFeed(ctx context.Context, feedURL string, limit int) (Feed, error) {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return Feed{}, fmt.Errorf("invalid bridge URL %q: %w", feedURL, err)
+	}
+	query := u.Query()
+	query.Set("format", "Json")
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Feed{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Feed{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Feed{}, fmt.Errorf("bridge returned HTTP %d for %s", resp.StatusCode, feedURL)
+	}
+	body, err := rss.ReadFeed(resp, feedURL)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	var doc jsonFeed
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return Feed{}, fmt.Errorf("failed to parse bridge feed: %w", err)
+	}
+	items := doc.Items
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	feed := Feed{Title: doc.Title, Items: make([]Item, 0, len(items))}
+	for _, entry := range items {
+		feed.Items = append(feed.Items, entry.item())
+	}
+	return feed, nil
+}
+
+// jsonFeed is the JSON Feed (https://www.jsonfeed.org) RSS-Bridge writes.
+type jsonFeed struct {
+	Title string      `json:"title"`
+	Items []jsonEntry `json:"items"`
+}
+
+type jsonEntry struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	ContentHTML   string       `json:"content_html"`
+	ContentText   string       `json:"content_text"`
+	DatePublished string       `json:"date_published"`
+	DateModified  string       `json:"date_modified"`
+	Author        *jsonAuthor  `json:"author"`
+	Authors       []jsonAuthor `json:"authors"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func (e jsonEntry) item() Item {
+	item := Item{ID: e.ID, Title: e.Title, Content: e.ContentHTML, URL: e.URL}
+	if item.ID == "" {
+		item.ID = e.URL
+	}
+	if item.Content == "" {
+		item.Content = e.ContentText
+	}
+	switch {
+	case len(e.Authors) > 0:
+		item.Author = e.Authors[0].Name
+	case e.Author != nil:
+		item.Author = e.Author.Name
+	}
+	published, _ := time.Parse(time.RFC3339, e.DatePublished)
+	modified, _ := time.Parse(time.RFC3339, e.DateModified)
+	if published.IsZero() {
+		published = modified
+	}
+	item.PublishedAt = published
+	if modified.After(published) {
+		item.UpdatedAt = modified
+	}
+	return item
+}
@@ -0,0 +1,46 @@
+package rssbridge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClient_FetchFeed documents fetching a bridge:
+// - the feed is requested as JSON whatever format its URL asks for
+// - entries without a publication date are dated by their last change
+func TestClient_FetchFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "Json" || r.URL.Query().Get("bridge") != "GithubIssueBridge" {
+			t.Errorf("expected the bridge's feed as JSON, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"version": "https://jsonfeed.org/version/1", "title": "Issues of feedmix", "items": [
+			{"id": "1", "url": "https://github.com/o/r/issues/1", "title": "Crash", "content_html": "<p>Boom</p>", "date_modified": "2024-01-15T12:00:00+00:00", "author": {"name": "jane"}},
+			{"id": "2", "url": "https://github.com/o/r/issues/2", "title": "Typo", "date_modified": "2024-01-14T12:00:00+00:00"}
+		]}`)
+	}))
+	defer server.Close()
+
+	feed, err := NewClient().FetchFeed(context.Background(), server.URL+"/?action=display&bridge=GithubIssueBridge&format=Atom", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Item{ID: "1", Title: "Crash", Content: "<p>Boom</p>", Author: "jane", URL: "https://github.com/o/r/issues/1", PublishedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)}
+	if feed.Title != "Issues of feedmix" || len(feed.Items) != 1 || !feed.Items[0].PublishedAt.Equal(want.PublishedAt) || feed.Items[0].Title != want.Title || feed.Items[0].Author != want.Author {
+		t.Errorf("expected the first entry %+v of the feed, got %+v", want, feed)
+	}
+}
+
+func TestClient_FetchFeed_ReportsFailingBridges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient().FetchFeed(context.Background(), server.URL+"/?action=display&bridge=Broken", 5); err == nil {
+		t.Fatal("expected an error for a failing bridge")
+	}
+}
@@ -0,0 +1,303 @@
+// Package runs records a manifest of every feed refresh: what was fetched
+// from where, how long it took and what went wrong, so a missing or
+// unexpected item can be traced back to the run that produced it.
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+// idFormat names manifests by their start time, so IDs sort chronologically.
+const idFormat = "20060102T150405.000Z"
+
+// Manifest describes one feed refresh.
+type Manifest struct {
+	ID         string           `json:"id"`
+	StartedAt  time.Time        `json:"started_at"`
+	Duration   time.Duration    `json:"duration_ns"`
+	Version    string           `json:"version"`
+	GoVersion  string           `json:"go_version"`
+	Args       []string         `json:"args,omitempty"`
+	Sources    []Source         `json:"sources"`
+	Requests   []httpx.Exchange `json:"requests"`
+	Warnings   []string         `json:"warnings,omitempty"`
+	Unhealthy  []Feed           `json:"unhealthy,omitempty"`
+	QuotaUnits int              `json:"quota_units"`
+	Items      []Item           `json:"items"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// Source is the outcome of fetching one source.
+type Source struct {
+	Name     string        `json:"name"`
+	Items    int           `json:"items"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Feed is a channel or publication whose URL served a web page instead of
+// a feed, so it needs fixing rather than retrying.
+type Feed struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// Item identifies a fetched item and whether it made it into the displayed feed.
+type Item struct {
+	Source    aggregator.Source `json:"source"`
+	ID        string            `json:"id"`
+	Author    string            `json:"author"`
+	Title     string            `json:"title"`
+	Displayed bool              `json:"displayed"`
+}
+
+// Fetched returns how many items the run fetched.
+func (m Manifest) Fetched() int {
+	return len(m.Items)
+}
+
+// Displayed returns how many items the run displayed.
+func (m Manifest) Displayed() int {
+	n := 0
+	for _, item := range m.Items {
+		if item.Displayed {
+			n++
+		}
+	}
+	return n
+}
+
+// Recorder collects a manifest while a run is in progress. Its methods may
+// be called concurrently.
+type Recorder struct {
+	mu       sync.Mutex
+	manifest Manifest
+	now      func() time.Time
+}
+
+// NewRecorder starts recording, at now, a run of the given feedmix version.
+func NewRecorder(now func() time.Time, version, goVersion string, args []string) *Recorder {
+	r := &Recorder{now: now}
+	started := r.now().UTC()
+	r.manifest = Manifest{
+		ID:        started.Format(idFormat),
+		StartedAt: started,
+		Version:   version,
+		GoVersion: goVersion,
+		Args:      args,
+	}
+	return r
+}
+
+// Request records an HTTP exchange.
+func (r *Recorder) Request(e httpx.Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifest.Requests = append(r.manifest.Requests, e)
+}
+
+// Warn records a non-fatal error.
+func (r *Recorder) Warn(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifest.Warnings = append(r.manifest.Warnings, err.Error())
+}
+
+// Unhealthy records a feed, by source:id key, whose URL served a web page.
+func (r *Recorder) Unhealthy(key string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifest.Unhealthy = append(r.manifest.Unhealthy, Feed{Key: key, Error: err.Error()})
+}
+
+// Source records the outcome of fetching a source.
+func (r *Recorder) Source(name string, items int, elapsed time.Duration, err error) {
+	s := Source{Name: name, Items: items, Duration: elapsed}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifest.Sources = append(r.manifest.Sources, s)
+}
+
+// Finish completes the manifest with the items fetched and displayed, the
+// quota spent and the error that ended the run, if any.
+func (r *Recorder) Finish(fetched, displayed []aggregator.FeedItem, quotaUnits int, err error) Manifest {
+	shown := make(map[string]bool, len(displayed))
+	for _, item := range displayed {
+		shown[string(item.Source)+":"+item.ID] = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := &r.manifest
+	m.Duration = r.now().UTC().Sub(m.StartedAt)
+	m.QuotaUnits = quotaUnits
+	m.Items = make([]Item, 0, len(fetched))
+	for _, item := range fetched {
+		m.Items = append(m.Items, Item{
+			Source:    item.Source,
+			ID:        item.ID,
+			Author:    item.Author,
+			Title:     item.Title,
+			Displayed: shown[string(item.Source)+":"+item.ID],
+		})
+	}
+	if err != nil {
+		m.Error = err.Error()
+	}
+	sort.SliceStable(m.Sources, func(i, j int) bool { return m.Sources[i].Name < m.Sources[j].Name })
+	sort.SliceStable(m.Unhealthy, func(i, j int) bool { return m.Unhealthy[i].Key < m.Unhealthy[j].Key })
+	return *m
+}
+
+// Save writes m to dir as <ID>.json.
+func Save(dir string, m Manifest) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, m.ID+".json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write run manifest: %w", err)
+	}
+	return nil
+}
+
+// List returns the manifests in dir, most recent first. A missing directory
+// yields no manifests.
+func List(dir string) ([]Manifest, error) {
+	ids, err := IDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]Manifest, 0, len(ids))
+	for _, id := range ids {
+		m, err := Load(dir, id)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// IDs returns the IDs of the manifests in dir, most recent first.
+func IDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// Load reads the manifest with the given ID from dir.
+func Load(dir, id string) (Manifest, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return Manifest{}, fmt.Errorf("invalid run ID %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json")) // #nosec G304 - id is checked to stay within the runs directory
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{}, fmt.Errorf("no run %q: see 'feedmix runs list'", id)
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read run manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse run manifest %s: %w", id, err)
+	}
+	return m, nil
+}
+
+// Prune removes the manifests in dir beyond the keep most recent ones or
+// started more than maxAge before now, and returns the IDs it removed. A
+// zero keep or maxAge doesn't limit.
+func Prune(dir string, keep int, maxAge time.Duration, now time.Time) ([]string, error) {
+	ids, err := IDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for i, id := range ids {
+		started, err := time.Parse(idFormat, id)
+		if err != nil {
+			continue
+		}
+		if (keep > 0 && i >= keep) || (maxAge > 0 && now.Sub(started) > maxAge) {
+			if err := os.Remove(filepath.Join(dir, id+".json")); err != nil {
+				return removed, fmt.Errorf("failed to remove run %s: %w", id, err)
+			}
+			removed = append(removed, id)
+		}
+	}
+	return removed, nil
+}
+
+// Diff is what changed between two runs.
+type Diff struct {
+	// Added and Removed are items fetched by only the later or the earlier run.
+	Added   []Item
+	Removed []Item
+	// Shown and Hidden are items both runs fetched that only the later or
+	// the earlier run displayed.
+	Shown  []Item
+	Hidden []Item
+}
+
+// Compare returns what changed from run a to run b.
+func Compare(a, b Manifest) Diff {
+	before := make(map[string]Item, len(a.Items))
+	for _, item := range a.Items {
+		before[item.key()] = item
+	}
+	after := make(map[string]bool, len(b.Items))
+
+	var d Diff
+	for _, item := range b.Items {
+		after[item.key()] = true
+		old, ok := before[item.key()]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, item)
+		case item.Displayed && !old.Displayed:
+			d.Shown = append(d.Shown, item)
+		case !item.Displayed && old.Displayed:
+			d.Hidden = append(d.Hidden, item)
+		}
+	}
+	for _, item := range a.Items {
+		if !after[item.key()] {
+			d.Removed = append(d.Removed, item)
+		}
+	}
+	return d
+}
+
+func (i Item) key() string {
+	return string(i.Source) + ":" + i.ID
+}
@@ -0,0 +1,140 @@
+package runs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
+)
+
+func recorderAt(t time.Time) *Recorder {
+	calls := 0
+	return NewRecorder(func() time.Time {
+		calls++
+		if calls == 1 {
+			return t
+		}
+		return t.Add(2 * time.Second)
+	}, "v1.2.3", "go1.24", []string{"feed"})
+}
+
+func TestRecorder_BuildsManifestOfTheRun(t *testing.T) {
+	r := recorderAt(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	r.Request(httpx.Exchange{Method: "GET", URL: "https://example.com/feed", Status: 200})
+	r.Source("youtube", 2, time.Second, nil)
+	r.Source("substack", 0, time.Second, errors.New("boom"))
+	r.Warn(errors.New("channel X failed"))
+	r.Unhealthy("substack:x", errors.New("HTML page"))
+
+	fetched := []aggregator.FeedItem{
+		{ID: "a", Source: aggregator.SourceYouTube, Title: "Shown"},
+		{ID: "b", Source: aggregator.SourceYouTube, Title: "Cut by the limit"},
+	}
+	m := r.Finish(fetched, fetched[:1], 102, nil)
+
+	if m.ID != "20240115T120000.000Z" || m.Duration != 2*time.Second {
+		t.Errorf("unexpected ID or duration: %s, %s", m.ID, m.Duration)
+	}
+	if m.Fetched() != 2 || m.Displayed() != 1 {
+		t.Errorf("expected 2 fetched and 1 displayed, got %d and %d", m.Fetched(), m.Displayed())
+	}
+	if m.Sources[0].Name != "substack" || m.Sources[0].Error != "boom" {
+		t.Errorf("sources should be sorted by name and keep errors, got %+v", m.Sources)
+	}
+	if len(m.Requests) != 1 || len(m.Warnings) != 1 || len(m.Unhealthy) != 1 || m.QuotaUnits != 102 {
+		t.Errorf("requests, warnings, unhealthy feeds and quota should be recorded, got %+v", m)
+	}
+}
+
+func TestSaveAndList_ReturnMostRecentRunFirst(t *testing.T) {
+	dir := t.TempDir()
+	older := recorderAt(time.Date(2024, 1, 14, 8, 0, 0, 0, time.UTC)).Finish(nil, nil, 0, nil)
+	newer := recorderAt(time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)).Finish(nil, nil, 0, errors.New("quota exceeded"))
+	for _, m := range []Manifest{older, newer} {
+		if err := Save(dir, m); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	manifests, err := List(dir)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(manifests) != 2 || manifests[0].ID != newer.ID || manifests[0].Error != "quota exceeded" {
+		t.Fatalf("expected newest run first with its error, got %+v", manifests)
+	}
+
+	loaded, err := Load(dir, older.ID)
+	if err != nil || loaded.Version != "v1.2.3" {
+		t.Errorf("load should return the saved manifest, got %+v, %v", loaded, err)
+	}
+}
+
+func TestLoad_RejectsUnknownAndPathLikeIDs(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(dir, "20240101T000000.000Z"); err == nil || !strings.Contains(err.Error(), "runs list") {
+		t.Errorf("unknown run should point to 'runs list', got %v", err)
+	}
+	if _, err := Load(dir, "../saved"); err == nil {
+		t.Error("expected an error for an ID outside the runs directory")
+	}
+}
+
+func TestList_MissingDirectoryHasNoRuns(t *testing.T) {
+	manifests, err := List(t.TempDir() + "/runs")
+	if err != nil || len(manifests) != 0 {
+		t.Errorf("expected no runs and no error, got %v, %v", manifests, err)
+	}
+}
+
+func TestPrune_AppliesCountAndAgeLimits(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, age := range []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 40 * 24 * time.Hour} {
+		if err := Save(dir, recorderAt(now.Add(-age)).Finish(nil, nil, 0, nil)); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	removed, err := Prune(dir, 0, 30*24*time.Hour, now)
+	if err != nil || len(removed) != 1 || removed[0] != "20240121T120000.000Z" {
+		t.Fatalf("runs older than the max age should be removed, got %v (err %v)", removed, err)
+	}
+	removed, err = Prune(dir, 2, 0, now)
+	if err != nil || len(removed) != 1 || removed[0] != "20240301T090000.000Z" {
+		t.Fatalf("runs beyond the most recent 2 should be removed, got %v (err %v)", removed, err)
+	}
+	if ids, _ := IDs(dir); len(ids) != 2 {
+		t.Errorf("expected 2 runs left, got %v", ids)
+	}
+}
+
+func TestCompare_ReportsFetchedAndDisplayedChanges(t *testing.T) {
+	a := Manifest{Items: []Item{
+		{ID: "gone", Title: "Gone"},
+		{ID: "kept", Title: "Kept", Displayed: true},
+		{ID: "cut", Title: "Cut", Displayed: true},
+		{ID: "promoted", Title: "Promoted"},
+	}}
+	b := Manifest{Items: []Item{
+		{ID: "new", Title: "New", Displayed: true},
+		{ID: "kept", Title: "Kept", Displayed: true},
+		{ID: "cut", Title: "Cut"},
+		{ID: "promoted", Title: "Promoted", Displayed: true},
+	}}
+
+	d := Compare(a, b)
+	titles := func(items []Item) string {
+		var s []string
+		for _, item := range items {
+			s = append(s, item.Title)
+		}
+		return strings.Join(s, ",")
+	}
+	if titles(d.Added) != "New" || titles(d.Removed) != "Gone" || titles(d.Shown) != "Promoted" || titles(d.Hidden) != "Cut" {
+		t.Errorf("unexpected diff: added %q, removed %q, shown %q, hidden %q", titles(d.Added), titles(d.Removed), titles(d.Shown), titles(d.Hidden))
+	}
+}
@@ -0,0 +1,193 @@
+// Package saved keeps the items the user bookmarked to revisit later.
+package saved
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// Item is a saved feed item and when it was saved.
+type Item struct {
+	aggregator.FeedItem
+	SavedAt time.Time `json:"saved_at"`
+	// ArchivedURL is a copy of the item's page on the Wayback Machine.
+	ArchivedURL string `json:"archived_url,omitempty"`
+	// TombstonedAt is when the item was found gone; FeedItem.Gone says why.
+	TombstonedAt *time.Time `json:"tombstoned_at,omitempty"`
+}
+
+// Store is the list of saved items, kept as a JSON file.
+type Store struct {
+	path  string
+	items []Item
+	now   clock.Clock
+	// schema is the version the file was written with; a newer feedmix may
+	// have stored fields this one would drop, so such a file is read-only.
+	schema int
+}
+
+// Open loads the store at path, stamping newly saved items with now; a
+// missing file yields an empty store.
+func Open(path string, now clock.Clock) (*Store, error) {
+	s := &Store{path: path, now: now}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the saved list in the user's config directory
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved items: %w", err)
+	}
+	if s.items, s.schema, err = aggregator.UnmarshalItems[Item](data); err != nil {
+		return nil, fmt.Errorf("failed to parse saved items: %w", err)
+	}
+	return s, nil
+}
+
+// Add saves item. It reports false if the item was already saved.
+func (s *Store) Add(item aggregator.FeedItem) bool {
+	if s.index(item.Source, item.ID) >= 0 {
+		return false
+	}
+	s.items = append(s.items, Item{FeedItem: item, SavedAt: s.now().UTC()})
+	return true
+}
+
+// Remove drops the saved item with the given ID. It reports false if no item matched.
+func (s *Store) Remove(id string) bool {
+	for i, item := range s.items {
+		if item.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// SavedAt returns when item was saved, zero if it isn't.
+func (s *Store) SavedAt(item aggregator.FeedItem) time.Time {
+	if i := s.index(item.Source, item.ID); i >= 0 {
+		return s.items[i].SavedAt
+	}
+	return time.Time{}
+}
+
+// Lookup returns the saved copy of item, if it is saved.
+func (s *Store) Lookup(item aggregator.FeedItem) (Item, bool) {
+	if i := s.index(item.Source, item.ID); i >= 0 {
+		return s.items[i], true
+	}
+	return Item{}, false
+}
+
+// Tombstone marks the saved item as gone for reason, such as "removed", or
+// as there again when reason is empty. It reports false if the item isn't
+// saved.
+func (s *Store) Tombstone(item aggregator.FeedItem, reason string) bool {
+	i := s.index(item.Source, item.ID)
+	if i < 0 {
+		return false
+	}
+	saved := &s.items[i]
+	switch {
+	case reason == "":
+		saved.Gone, saved.TombstonedAt = "", nil
+	case saved.Gone != reason:
+		now := s.now().UTC()
+		saved.Gone, saved.TombstonedAt = reason, &now
+	}
+	return true
+}
+
+// SetArchivedURL records where a copy of the saved item's page is archived.
+// It reports false if the item isn't saved.
+func (s *Store) SetArchivedURL(item aggregator.FeedItem, archived string) bool {
+	i := s.index(item.Source, item.ID)
+	if i < 0 {
+		return false
+	}
+	s.items[i].ArchivedURL = archived
+	return true
+}
+
+// Items returns the saved items, most recently saved first.
+func (s *Store) Items() []Item {
+	items := append([]Item(nil), s.items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SavedAt.After(items[j].SavedAt)
+	})
+	return items
+}
+
+// Save writes the store back to disk. It refuses to overwrite a file written
+// by a newer version of feedmix.
+func (s *Store) Save() error {
+	if s.schema > aggregator.SchemaVersion {
+		return fmt.Errorf("saved items were written by a newer feedmix (schema %d, this one writes %d): upgrade feedmix to change them", s.schema, aggregator.SchemaVersion)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create saved items directory: %w", err)
+	}
+	data, err := json.MarshalIndent(aggregator.NewEnvelope(s.items), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved items: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write saved items: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) index(source aggregator.Source, id string) int {
+	for i, item := range s.items {
+		if item.Source == source && item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// WriteJSON exports items as a JSON object holding the items array and the
+// schema version they are written with.
+func WriteJSON(w io.Writer, items []Item) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(aggregator.NewEnvelope(items))
+}
+
+// WriteMarkdown exports items as a Markdown list of links, one per item.
+func WriteMarkdown(w io.Writer, items []Item) error {
+	var b strings.Builder
+	b.WriteString("# Saved items\n\n")
+	for _, item := range items {
+		title := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(item.Title)
+		if item.URL != "" {
+			fmt.Fprintf(&b, "- [%s](%s)", title, item.URL)
+		} else {
+			fmt.Fprintf(&b, "- %s", title)
+		}
+		fmt.Fprintf(&b, " — %s, %s", item.Author, item.Source)
+		if !item.PublishedAt.IsZero() {
+			fmt.Fprintf(&b, ", %s", item.PublishedAt.Format("2006-01-02"))
+		}
+		if item.Gone != "" {
+			fmt.Fprintf(&b, ", gone: %s", item.Gone)
+		}
+		if item.ArchivedURL != "" {
+			fmt.Fprintf(&b, " ([archived](%s))", item.ArchivedURL)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
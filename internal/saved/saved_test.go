@@ -0,0 +1,125 @@
+package saved
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+func video(id, title string) aggregator.FeedItem {
+	return aggregator.FeedItem{ID: id, Source: aggregator.SourceYouTube, Title: title, Author: "Channel", URL: "https://www.youtube.com/watch?v=" + id}
+}
+
+func TestStore_KeepsSavedItemsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saved.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	store, err := Open(path, clock.System)
+	if err != nil {
+		t.Fatalf("missing saved list should open empty, got: %v", err)
+	}
+	store.now = func() time.Time { return now }
+	store.Add(video("a", "First"))
+	now = now.Add(time.Hour)
+	store.Add(video("b", "Second"))
+	if store.Add(video("a", "First")) {
+		t.Error("saving an item twice should be a no-op")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ = Open(path, clock.System)
+	items := store.Items()
+	if len(items) != 2 || items[0].ID != "b" {
+		t.Fatalf("saved items should persist, most recently saved first, got %+v", items)
+	}
+
+	if !store.Remove("b") || store.Remove("missing") {
+		t.Error("Remove should report whether an item was removed")
+	}
+	if len(store.Items()) != 1 {
+		t.Errorf("removed item should be gone, got %d items", len(store.Items()))
+	}
+
+	if !store.SetArchivedURL(video("a", "First"), "https://web.archive.org/web/2024/a") || store.SetArchivedURL(video("b", "Second"), "x") {
+		t.Error("SetArchivedURL should report whether the item is saved")
+	}
+	if got := store.Items()[0].ArchivedURL; got != "https://web.archive.org/web/2024/a" {
+		t.Errorf("the archived copy should be recorded, got %q", got)
+	}
+
+	store.Tombstone(video("a", "First"), "removed")
+	if got, _ := store.Lookup(video("a", "First")); got.Gone != "removed" || got.TombstonedAt == nil {
+		t.Errorf("the item should be tombstoned with its reason and time, got %+v", got)
+	}
+	store.Tombstone(video("a", "First"), "")
+	if got, _ := store.Lookup(video("a", "First")); got.Gone != "" || got.TombstonedAt != nil {
+		t.Errorf("an item found there again should lose its tombstone, got %+v", got)
+	}
+}
+
+func TestExport_JSONAndMarkdown(t *testing.T) {
+	items := []Item{{FeedItem: video("a", "A [bracketed] title"), SavedAt: time.Now()}}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded struct {
+		SchemaVersion int                      `json:"schema_version"`
+		Items         []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.SchemaVersion != aggregator.SchemaVersion || len(decoded.Items) != 1 || decoded.Items[0]["url"] == nil || decoded.Items[0]["saved_at"] == nil {
+		t.Errorf("JSON export should list items with their URL and save time under the schema version, got %s (err %v)", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `- [A \[bracketed\] title](https://www.youtube.com/watch?v=a)`) {
+		t.Errorf("Markdown export should link each title with brackets escaped, got:\n%s", buf.String())
+	}
+}
+
+func TestOpen_ReadsUnversionedFilesAndProtectsNewerOnes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saved.json")
+	legacy := `[{"id":"a","source":"youtube","title":"Before versioning","saved_at":"2024-01-15T12:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := Open(path, clock.System)
+	if err != nil || len(store.Items()) != 1 || store.Items()[0].Title != "Before versioning" {
+		t.Fatalf("a file written before versioning should load, got %+v (err %v)", store, err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("an old file should be upgraded on save: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `"schema_version": 1`) {
+		t.Errorf("the saved file should record its schema version, got %s", data)
+	}
+
+	newer := `{"schema_version": 99, "items": [{"id":"b","source":"substack","title":"From the future","mood":"calm"}]}`
+	if err := os.WriteFile(path, []byte(newer), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err = Open(path, clock.System)
+	if err != nil || len(store.Items()) != 1 || store.Items()[0].Title != "From the future" {
+		t.Fatalf("a newer file should still be readable, got %+v (err %v)", store, err)
+	}
+	store.Remove("b")
+	if err := store.Save(); err == nil || !strings.Contains(err.Error(), "newer feedmix") {
+		t.Errorf("a newer file must not be overwritten, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != newer {
+		t.Errorf("the newer file should be left untouched, got %s", data)
+	}
+}
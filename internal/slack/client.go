@@ -0,0 +1,134 @@
+// Package slack provides a client for Slack incoming webhooks, posting
+// messages with attachments to a workspace's channels.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MinInterval is the least time a Client leaves between two posts: Slack
+// takes about one message per second per incoming webhook.
+const MinInterval = time.Second
+
+// Message is the payload of an incoming webhook.
+type Message struct {
+	// Channel overrides the webhook's own channel, such as "#videos"; empty
+	// posts to the channel the webhook was created for.
+	Channel     string       `json:"channel,omitempty"`
+	Text        string       `json:"text"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a message attachment, shown as a card under the text.
+type Attachment struct {
+	// Fallback is the plain-text summary notifications show.
+	Fallback   string `json:"fallback"`
+	Color      string `json:"color,omitempty"`
+	AuthorName string `json:"author_name,omitempty"`
+	Title      string `json:"title"`
+	TitleLink  string `json:"title_link,omitempty"`
+	Text       string `json:"text,omitempty"`
+	ThumbURL   string `json:"thumb_url,omitempty"`
+	Footer     string `json:"footer,omitempty"`
+	// Timestamp is shown next to the footer, in Unix seconds.
+	Timestamp int64 `json:"ts,omitempty"`
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithMinInterval sets the least time between two posts, MinInterval by
+// default.
+func WithMinInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.interval = interval
+	}
+}
+
+// Client posts to incoming webhooks, one post at a time and no more often
+// than its minimum interval. It is safe for concurrent use.
+type Client struct {
+	httpClient HTTPClient
+	interval   time.Duration
+	mu         sync.Mutex
+	last       time.Time
+}
+
+// NewClient creates a new Slack client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, interval: MinInterval}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Post sends msg to the incoming webhook at webhookURL, first waiting out
+// the rest of the minimum interval since the previous post. It returns nil
+// only once Slack accepted the message.
+func (c *Client) Post(ctx context.Context, webhookURL string, msg Message) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if text := strings.TrimSpace(string(reason)); text != "" {
+			return fmt.Errorf("slack webhook returned HTTP %d: %s", resp.StatusCode, text)
+		}
+		return fmt.Errorf("slack webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// wait blocks until the minimum interval since the previous post has
+// passed, or ctx is done.
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if delay := c.interval - time.Since(c.last); !c.last.IsZero() && delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.last = time.Now()
+	return nil
+}
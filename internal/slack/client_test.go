@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestClient_Post documents posting to an incoming webhook:
+//   - the message goes as JSON, with its channel override and attachments
+//   - empty fields are left out, so the webhook's defaults apply
+func TestClient_Post(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	msg := Message{Channel: "#videos", Text: "New video", Attachments: []Attachment{{Fallback: "Go", Title: "Go", TitleLink: "https://example.com"}}}
+	if err := NewClient().Post(context.Background(), server.URL, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["channel"] != "#videos" || got["text"] != "New video" {
+		t.Errorf("unexpected message: %v", got)
+	}
+	attachment := got["attachments"].([]any)[0].(map[string]any)
+	if attachment["title_link"] != "https://example.com" {
+		t.Errorf("unexpected attachment: %v", attachment)
+	}
+	if _, ok := attachment["thumb_url"]; ok {
+		t.Errorf("an attachment without a thumbnail shouldn't send one: %v", attachment)
+	}
+}
+
+func TestClient_Post_ReportsWebhookErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "channel_not_found")
+	}))
+	defer server.Close()
+
+	err := NewClient().Post(context.Background(), server.URL, Message{Channel: "#gone", Text: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("the webhook's reason should be reported, got %v", err)
+	}
+}
+
+func TestClient_Post_SpacesPostsByTheMinimumInterval(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer server.Close()
+
+	client := NewClient(WithMinInterval(50 * time.Millisecond))
+	start := time.Now()
+	for range 2 {
+		if err := client.Post(context.Background(), server.URL, Message{Text: "hi"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); posts != 2 || elapsed < 50*time.Millisecond {
+		t.Errorf("a burst of posts should be spaced to stay within Slack's rate limit, got %d posts in %v", posts, elapsed)
+	}
+
+	client = NewClient(WithMinInterval(time.Hour))
+	_ = client.Post(context.Background(), server.URL, Message{Text: "hi"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Post(ctx, server.URL, Message{Text: "hi"}); err == nil || posts != 3 {
+		t.Errorf("a post still waiting when the context ends should be abandoned, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/arxiv"
+)
+
+// maxListedAuthors is how many authors of a paper are named before "et al.".
+const maxListedAuthors = 3
+
+// Arxiv fetches the newest arXiv papers in the categories, by the authors,
+// or on the keywords the user follows.
+type Arxiv struct {
+	client *arxiv.Client
+	query  arxiv.Query
+	limit  int
+}
+
+// NewArxiv creates an arXiv source. limit is how many papers to fetch in all.
+func NewArxiv(client *arxiv.Client, query arxiv.Query, limit int) *Arxiv {
+	return &Arxiv{client: client, query: query, limit: limit}
+}
+
+// Name returns the source identifier.
+func (a *Arxiv) Name() string {
+	return string(aggregator.SourceArxiv)
+}
+
+// Fetch returns the newest papers matching the query. Everything is one
+// request, whose failure is fatal.
+func (a *Arxiv) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	items, err := opts.fetch(feedKey(aggregator.SourceArxiv, a.query.String()), func() ([]aggregator.FeedItem, error) {
+		papers, err := a.client.Search(ctx, a.query, a.limit)
+		if err != nil {
+			return nil, err
+		}
+		return paperItems(papers), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search arXiv: %w", err)
+	}
+	opts.progress(items)
+	return items, nil
+}
+
+func paperItems(papers []arxiv.Paper) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(papers))
+	for _, paper := range papers {
+		item := aggregator.FeedItem{
+			ID:          paper.ID,
+			Source:      aggregator.SourceArxiv,
+			Type:        aggregator.ItemTypePaper,
+			Title:       paper.Title,
+			Description: paper.Abstract,
+			Author:      authorList(paper.Authors),
+			Group:       paper.PrimaryCategory,
+			URL:         paper.URL,
+			PublishedAt: paper.PublishedAt,
+		}
+		if paper.Version > 1 {
+			item.UpdatedAt = paper.UpdatedAt
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// authorList names the first authors of a paper, then "et al." for the rest.
+func authorList(authors []string) string {
+	if len(authors) > maxListedAuthors {
+		return strings.Join(authors[:maxListedAuthors], ", ") + " et al."
+	}
+	return strings.Join(authors, ", ")
+}
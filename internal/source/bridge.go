@@ -0,0 +1,72 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/rssbridge"
+)
+
+// Bridge fetches feeds generated by RSS-Bridge.
+type Bridge struct {
+	client *rssbridge.Client
+	urls   []string
+	limit  func(feedURL string) int
+}
+
+// NewBridge creates a Bridge source. limit returns how many entries to
+// fetch per feed.
+func NewBridge(client *rssbridge.Client, urls []string, limit func(feedURL string) int) *Bridge {
+	return &Bridge{client: client, urls: urls, limit: limit}
+}
+
+// Name returns the source identifier.
+func (b *Bridge) Name() string {
+	return string(aggregator.SourceBridge)
+}
+
+// Fetch returns recent entries from every feed. A failing feed is reported via opts.Warn.
+func (b *Bridge) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(b.urls), func(i int) {
+		feedURL := b.urls[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceBridge, feedURL), func() ([]aggregator.FeedItem, error) {
+			feed, err := b.client.FetchFeed(ctx, feedURL, b.limit(feedURL))
+			if err != nil {
+				return nil, err
+			}
+			return bridgeItems(feed), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch bridge feed from %s: %w", feedURL, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
+
+func bridgeItems(feed rssbridge.Feed) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(feed.Items))
+	for _, entry := range feed.Items {
+		items = append(items, aggregator.FeedItem{
+			ID:          entry.ID,
+			Source:      aggregator.SourceBridge,
+			Type:        aggregator.ItemTypeArticle,
+			Title:       entry.Title,
+			Description: entry.Content,
+			Author:      byline(feed.Title, entry.Author),
+			URL:         entry.URL,
+			PublishedAt: entry.PublishedAt,
+			UpdatedAt:   entry.UpdatedAt,
+		})
+	}
+	return items
+}
@@ -0,0 +1,114 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/github"
+)
+
+// GitHub fetches recent releases of a set of repositories and, optionally,
+// of every repository a user has starred.
+type GitHub struct {
+	client  *github.Client
+	repos   []string
+	starred string
+	limit   func(repo string) int
+}
+
+// NewGitHub creates a GitHub source. repos are "owner/name"; starred, when
+// not empty, is the user whose starred repositories are followed too. limit
+// returns how many releases to fetch per repository.
+func NewGitHub(client *github.Client, repos []string, starred string, limit func(repo string) int) *GitHub {
+	return &GitHub{client: client, repos: repos, starred: starred, limit: limit}
+}
+
+// Name returns the source identifier.
+func (g *GitHub) Name() string {
+	return string(aggregator.SourceGitHub)
+}
+
+// Fetch returns recent releases of every repository. Failing to list the
+// starred repositories is fatal; a failing repository is reported via
+// opts.Warn.
+func (g *GitHub) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	repos := g.repos
+	if g.starred != "" {
+		starred, err := g.client.StarredRepos(ctx, g.starred)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch repositories starred by %s: %w", g.starred, err)
+		}
+		repos = mergeRepos(repos, starred)
+	}
+
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(repos), func(i int) {
+		repo := repos[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceGitHub, repo), func() ([]aggregator.FeedItem, error) {
+			releases, err := g.client.FetchReleases(ctx, repo, g.limit(repo))
+			if err != nil {
+				return nil, err
+			}
+			return releaseItems(releases), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch GitHub releases of %s: %w", repo, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+	return items, nil
+}
+
+// mergeRepos appends the starred repositories not already in repos, which
+// GitHub names case-insensitively.
+func mergeRepos(repos, starred []string) []string {
+	seen := make(map[string]bool, len(repos)+len(starred))
+	merged := make([]string, 0, len(repos)+len(starred))
+	for _, repo := range append(append([]string{}, repos...), starred...) {
+		if key := strings.ToLower(repo); !seen[key] {
+			seen[key] = true
+			merged = append(merged, repo)
+		}
+	}
+	return merged
+}
+
+func releaseItems(releases []github.Release) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(releases))
+	for _, release := range releases {
+		items = append(items, aggregator.FeedItem{
+			ID:           release.Repo + "@" + release.Tag,
+			Source:       aggregator.SourceGitHub,
+			Type:         aggregator.ItemTypeRelease,
+			Title:        releaseTitle(release),
+			Description:  release.Body,
+			Author:       release.Repo,
+			AuthorHandle: release.Author,
+			URL:          release.URL,
+			PublishedAt:  release.PublishedAt,
+			Engagement:   aggregator.Engagement{Likes: release.Reactions},
+		})
+	}
+	return items
+}
+
+// releaseTitle names a release, by its tag when it has no name, and marks
+// pre-releases.
+func releaseTitle(release github.Release) string {
+	title := release.Name
+	if title == "" {
+		title = release.Tag
+	}
+	if release.Prerelease {
+		title += " (pre-release)"
+	}
+	return title
+}
@@ -0,0 +1,108 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/lobsters"
+)
+
+// Lobsters fetches stories from Lobsters and similar link aggregators.
+type Lobsters struct {
+	client *lobsters.Client
+	sites  []string
+	limit  func(siteURL string) int
+	tags   func(siteURL string) []string
+}
+
+// NewLobsters creates a Lobsters source. limit returns how many stories to
+// fetch per site; tags returns the tags to read on a site, where "-tag"
+// leaves out stories with that tag, or nil for the front page.
+func NewLobsters(client *lobsters.Client, sites []string, limit func(siteURL string) int, tags func(siteURL string) []string) *Lobsters {
+	return &Lobsters{client: client, sites: sites, limit: limit, tags: tags}
+}
+
+// Name returns the source identifier.
+func (l *Lobsters) Name() string {
+	return string(aggregator.SourceLobsters)
+}
+
+// Fetch returns the stories of every site. A failing site is reported via opts.Warn.
+func (l *Lobsters) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(l.sites), func(i int) {
+		siteURL := l.sites[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceLobsters, siteURL), func() ([]aggregator.FeedItem, error) {
+			include, exclude := splitTags(l.tags(siteURL))
+			limit := l.limit(siteURL)
+			if len(exclude) > 0 {
+				limit = 0
+			}
+			stories, err := l.client.FetchStories(ctx, siteURL, include, limit)
+			if err != nil {
+				return nil, err
+			}
+			stories = slices.DeleteFunc(stories, func(story lobsters.Story) bool {
+				return slices.ContainsFunc(story.Tags, func(tag string) bool { return slices.Contains(exclude, tag) })
+			})
+			if n := l.limit(siteURL); n > 0 && len(stories) > n {
+				stories = stories[:n]
+			}
+			return storyLinkItems(siteURL, stories), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch stories from %s: %w", siteURL, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+	return items, nil
+}
+
+// splitTags separates the tags to read from the "-tag" ones to leave out.
+func splitTags(tags []string) (include, exclude []string) {
+	for _, tag := range tags {
+		if name, ok := strings.CutPrefix(tag, "-"); ok {
+			exclude = append(exclude, name)
+		} else {
+			include = append(include, tag)
+		}
+	}
+	return include, exclude
+}
+
+func storyLinkItems(siteURL string, stories []lobsters.Story) []aggregator.FeedItem {
+	site := siteURL
+	if u, err := url.Parse(siteURL); err == nil && u.Host != "" {
+		site = u.Host
+	}
+	items := make([]aggregator.FeedItem, 0, len(stories))
+	for _, story := range stories {
+		link := story.URL
+		if link == "" {
+			link = story.CommentsURL
+		}
+		items = append(items, aggregator.FeedItem{
+			ID:           site + "/s/" + story.ID,
+			Source:       aggregator.SourceLobsters,
+			Type:         aggregator.ItemTypeLink,
+			Title:        story.Title,
+			Description:  story.Description,
+			Author:       byline(site, story.Submitter),
+			AuthorHandle: story.Submitter,
+			URL:          link,
+			PublishedAt:  story.CreatedAt,
+			Engagement:   aggregator.Engagement{Likes: story.Score, Comments: story.Comments},
+		})
+	}
+	return items
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/medium"
+)
+
+// Medium fetches recent stories from a set of Medium writers and
+// publications.
+type Medium struct {
+	client *medium.Client
+	urls   []string
+	limit  func(pageURL string) int
+}
+
+// NewMedium creates a Medium source. limit returns how many stories to fetch
+// per writer or publication.
+func NewMedium(client *medium.Client, urls []string, limit func(pageURL string) int) *Medium {
+	return &Medium{client: client, urls: urls, limit: limit}
+}
+
+// Name returns the source identifier.
+func (m *Medium) Name() string {
+	return string(aggregator.SourceMedium)
+}
+
+// Fetch returns recent stories from every writer and publication. A failing
+// feed is reported via opts.Warn.
+func (m *Medium) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(m.urls), func(i int) {
+		pageURL := m.urls[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceMedium, pageURL), func() ([]aggregator.FeedItem, error) {
+			feed, err := m.client.FetchFeed(ctx, pageURL, m.limit(pageURL))
+			if err != nil {
+				return nil, err
+			}
+			return storyItems(feed), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch Medium feed from %s: %w", pageURL, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+	return items, nil
+}
+
+func storyItems(feed medium.Feed) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(feed.Posts))
+	for _, post := range feed.Posts {
+		items = append(items, aggregator.FeedItem{
+			ID:          post.ID,
+			Source:      aggregator.SourceMedium,
+			Type:        aggregator.ItemTypeArticle,
+			Title:       post.Title,
+			Author:      byline(feed.Title, post.Author),
+			URL:         post.URL,
+			PublishedAt: post.PublishedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Content:     post.Content,
+			Engagement:  aggregator.Engagement{Likes: post.Claps, Comments: post.Responses},
+		})
+	}
+	return items
+}
@@ -0,0 +1,75 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/peertube"
+)
+
+// PeerTube fetches recent videos from channels on PeerTube instances.
+type PeerTube struct {
+	client   *peertube.Client
+	channels []string
+	limit    func(handle string) int
+}
+
+// NewPeerTube creates a PeerTube source for channels given as name@host.
+// limit returns how many videos to fetch per channel.
+func NewPeerTube(client *peertube.Client, channels []string, limit func(handle string) int) *PeerTube {
+	return &PeerTube{client: client, channels: channels, limit: limit}
+}
+
+// Name returns the source identifier.
+func (p *PeerTube) Name() string {
+	return string(aggregator.SourcePeerTube)
+}
+
+// Fetch returns recent videos from every channel. A failing channel or
+// instance is reported via opts.Warn.
+func (p *PeerTube) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(p.channels), func(i int) {
+		handle := p.channels[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourcePeerTube, handle), func() ([]aggregator.FeedItem, error) {
+			videos, err := p.client.FetchChannelVideos(ctx, handle, p.limit(handle))
+			if err != nil {
+				return nil, err
+			}
+			return peerTubeItems(videos), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch PeerTube videos of %s: %w", handle, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+	return items, nil
+}
+
+func peerTubeItems(videos []peertube.Video) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(videos))
+	for _, video := range videos {
+		items = append(items, aggregator.FeedItem{
+			ID:           video.ID,
+			Source:       aggregator.SourcePeerTube,
+			Type:         aggregator.ItemTypeVideo,
+			Title:        video.Title,
+			Description:  video.Description,
+			Author:       video.Channel,
+			AuthorID:     video.ChannelHandle,
+			AuthorHandle: "@" + video.ChannelHandle,
+			URL:          video.URL,
+			Thumbnail:    video.Thumbnail,
+			PublishedAt:  video.PublishedAt,
+			Engagement:   aggregator.Engagement{Views: video.Views, Likes: video.Likes},
+		})
+	}
+	return items
+}
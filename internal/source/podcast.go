@@ -0,0 +1,80 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/podcast"
+)
+
+// Podcast fetches recent episodes from a set of podcast feeds.
+type Podcast struct {
+	client *podcast.Client
+	urls   []string
+	limit  func(feedURL string) int
+}
+
+// NewPodcast creates a Podcast source. limit returns how many episodes to
+// fetch per feed.
+func NewPodcast(client *podcast.Client, urls []string, limit func(feedURL string) int) *Podcast {
+	return &Podcast{client: client, urls: urls, limit: limit}
+}
+
+// Name returns the source identifier.
+func (p *Podcast) Name() string {
+	return string(aggregator.SourcePodcast)
+}
+
+// Fetch returns recent episodes from every feed. A failing feed is reported via opts.Warn.
+func (p *Podcast) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(p.urls), func(i int) {
+		feedURL := p.urls[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourcePodcast, feedURL), func() ([]aggregator.FeedItem, error) {
+			feed, err := p.client.FetchFeed(ctx, feedURL, p.limit(feedURL))
+			if err != nil {
+				return nil, err
+			}
+			return episodeItems(feed), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch podcast feed from %s: %w", feedURL, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
+
+func episodeItems(feed podcast.Feed) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(feed.Episodes))
+	for _, episode := range feed.Episodes {
+		items = append(items, aggregator.FeedItem{
+			ID:          episode.ID,
+			Source:      aggregator.SourcePodcast,
+			Type:        aggregator.ItemTypePodcast,
+			Title:       episode.Title,
+			Description: episode.Description,
+			Author:      byline(feed.Title, episode.Author),
+			URL:         episode.URL,
+			Thumbnail:   episode.Image,
+			PublishedAt: episode.PublishedAt,
+			Audio: &aggregator.Audio{
+				URL:      episode.AudioURL,
+				Type:     episode.AudioType,
+				Length:   episode.AudioLength,
+				Duration: episode.Duration,
+				Season:   episode.Season,
+				Episode:  episode.Number,
+			},
+		})
+	}
+	return items
+}
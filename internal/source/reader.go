@@ -0,0 +1,65 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/greader"
+)
+
+// Reader fetches the reading list of a Google Reader API server, so every
+// feed (and bridge) it subscribes to shows in the feed.
+type Reader struct {
+	client *greader.Client
+	key    string
+	limit  int
+}
+
+// NewReader creates a Reader source fetching the limit newest items of the
+// server at url with client.
+func NewReader(client *greader.Client, url string, limit int) *Reader {
+	return &Reader{client: client, key: url, limit: limit}
+}
+
+// Name returns the source identifier.
+func (r *Reader) Name() string {
+	return string(aggregator.SourceReader)
+}
+
+// Fetch returns the newest items of the reading list. A server that can't be
+// reached is reported via opts.Warn, like a failing publication.
+func (r *Reader) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	items, err := opts.fetch(feedKey(aggregator.SourceReader, r.key), func() ([]aggregator.FeedItem, error) {
+		entries, err := r.client.FetchItems(ctx, r.limit)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]aggregator.FeedItem, 0, len(entries))
+		for _, entry := range entries {
+			items = append(items, ReaderItem(entry))
+		}
+		return items, nil
+	})
+	if err != nil {
+		opts.warn(fmt.Errorf("failed to fetch the reading list from %s: %w", r.key, err))
+		return nil, nil
+	}
+	opts.progress(items)
+	return items, nil
+}
+
+// ReaderItem returns entry of a reading list as a feed item.
+func ReaderItem(entry greader.Item) aggregator.FeedItem {
+	return aggregator.FeedItem{
+		ID:          entry.ID,
+		Source:      aggregator.SourceReader,
+		Type:        aggregator.ItemTypeArticle,
+		Title:       entry.Title,
+		Description: entry.Summary,
+		Author:      byline(entry.Feed, entry.Author),
+		URL:         entry.URL,
+		PublishedAt: entry.PublishedAt,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+}
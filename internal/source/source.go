@@ -0,0 +1,225 @@
+// Package source defines the provider interface that feeds items into the aggregator.
+package source
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/rss"
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// DefaultConcurrency is the number of channels or publications a source
+// fetches at once when FetchOptions.Concurrency is unset.
+const DefaultConcurrency = 8
+
+// FetchOptions configures a single fetch across sources.
+type FetchOptions struct {
+	// Warn receives non-fatal failures, such as a single channel or publication
+	// that could not be fetched. It may be called concurrently.
+	Warn func(error)
+	// Progress, if set, receives the items of each channel or publication as
+	// soon as they are fetched, before FetchAll returns. It may be called
+	// concurrently and may see an item more than once.
+	Progress func([]aggregator.FeedItem)
+	// Concurrency caps how many channels or publications each source fetches
+	// at once, so users with hundreds of subscriptions don't open hundreds of
+	// connections. Zero means DefaultConcurrency.
+	Concurrency int
+	// Finished, if set, is called once per source when its fetch returns,
+	// with the number of items, the time taken and the fatal error, if any.
+	// It may be called concurrently.
+	Finished func(name string, items int, elapsed time.Duration, err error)
+	// Cached, if set, is asked for each channel or publication, by feedKey,
+	// before fetching it: when it returns true, its items are used instead.
+	// It may be called concurrently.
+	Cached func(key string) ([]aggregator.FeedItem, bool)
+	// Fetched, if set, receives the items of each channel or publication
+	// actually fetched, by feedKey. It may be called concurrently.
+	Fetched func(key string, items []aggregator.FeedItem)
+	// CachedList, if set, is asked before a source lists the channels it
+	// fetches, such as an account's subscriptions, by feedKey of the listing.
+	// When it returns their feedKeys and Cached has every one of them, the
+	// listing is skipped. It may be called concurrently.
+	CachedList func(key string) ([]string, bool)
+	// Listed, if set, receives the feedKeys of the channels a source listed,
+	// by feedKey of the listing. It may be called concurrently.
+	Listed func(key string, feeds []string)
+	// Unhealthy, if set, receives the feedKey and error of each channel or
+	// publication whose URL served a web page instead of a feed, such as a
+	// login page or a bot challenge. It may be called concurrently.
+	Unhealthy func(key string, err error)
+	// Clock times each source's fetch for Finished. Nil means clock.System.
+	Clock clock.Clock
+}
+
+// feedKey names a channel or publication of source for Cached and Fetched.
+func feedKey(source aggregator.Source, id string) string {
+	return string(source) + ":" + id
+}
+
+// fetch returns the items of the channel or publication named key, from
+// o.Cached if it has them, else from fetchItems, reporting it to o.Unhealthy
+// if its URL served a web page.
+func (o FetchOptions) fetch(key string, fetchItems func() ([]aggregator.FeedItem, error)) ([]aggregator.FeedItem, error) {
+	if o.Cached != nil {
+		if items, ok := o.Cached(key); ok {
+			return items, nil
+		}
+	}
+	items, err := fetchItems()
+	if err == nil && o.Fetched != nil {
+		o.Fetched(key, items)
+	}
+	if errors.Is(err, rss.ErrHTMLPage) && o.Unhealthy != nil {
+		o.Unhealthy(key, err)
+	}
+	return items, err
+}
+
+// cachedListing returns the items of every feed the listing key named last
+// time, and true, if o.CachedList remembers the listing and o.Cached has
+// each of those feeds.
+func (o FetchOptions) cachedListing(key string) ([]aggregator.FeedItem, bool) {
+	if o.CachedList == nil || o.Cached == nil {
+		return nil, false
+	}
+	feeds, ok := o.CachedList(key)
+	if !ok {
+		return nil, false
+	}
+	var items []aggregator.FeedItem
+	for _, feed := range feeds {
+		batch, ok := o.Cached(feed)
+		if !ok {
+			return nil, false
+		}
+		items = append(items, batch...)
+	}
+	return items, true
+}
+
+func (o FetchOptions) listed(key string, feeds []string) {
+	if o.Listed != nil {
+		o.Listed(key, feeds)
+	}
+}
+
+func (o FetchOptions) warn(err error) {
+	if o.Warn != nil {
+		o.Warn(err)
+	}
+}
+
+func (o FetchOptions) progress(items []aggregator.FeedItem) {
+	if o.Progress != nil && len(items) > 0 {
+		o.Progress(items)
+	}
+}
+
+// forEach calls fn for every index in [0, n) from a pool of at most
+// o.Concurrency workers and waits for all calls to return.
+func (o FetchOptions) forEach(n int, fn func(i int)) {
+	workers := o.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// Source is a content provider that produces feed items.
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error)
+}
+
+// Registry holds the sources that make up a feed.
+type Registry struct {
+	sources []Source
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a source to the registry.
+func (r *Registry) Register(s Source) {
+	r.sources = append(r.sources, s)
+}
+
+// Sources returns the registered sources in registration order.
+func (r *Registry) Sources() []Source {
+	return r.sources
+}
+
+// FetchAll fetches every registered source concurrently.
+// It returns the first fatal source error, if any, along with all items fetched.
+// An item returned by several sources (the same channel followed from two
+// YouTube accounts) is kept once.
+func (r *Registry) FetchAll(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var items []aggregator.FeedItem
+	seen := make(map[string]bool)
+	errs := make([]error, len(r.sources))
+	now := opts.Clock
+	if now == nil {
+		now = clock.System
+	}
+
+	for i, s := range r.sources {
+		wg.Add(1)
+		go func(i int, s Source) {
+			defer wg.Done()
+			start := now()
+			fetched, err := s.Fetch(ctx, opts)
+			if opts.Finished != nil {
+				opts.Finished(s.Name(), len(fetched), now().Sub(start), err)
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			mu.Lock()
+			for _, item := range fetched {
+				key := string(item.Source) + ":" + item.ID
+				if !seen[key] {
+					seen[key] = true
+					items = append(items, item)
+				}
+			}
+			mu.Unlock()
+		}(i, s)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return items, err
+		}
+	}
+	return items, nil
+}
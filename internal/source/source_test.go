@@ -0,0 +1,678 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/arxiv"
+	"github.com/gauthierbraillon/feedmix/internal/github"
+	"github.com/gauthierbraillon/feedmix/internal/lobsters"
+	"github.com/gauthierbraillon/feedmix/internal/medium"
+	"github.com/gauthierbraillon/feedmix/internal/peertube"
+	"github.com/gauthierbraillon/feedmix/internal/podcast"
+	"github.com/gauthierbraillon/feedmix/internal/substack"
+	"github.com/gauthierbraillon/feedmix/internal/twitch"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func fixedLimit(n int) func(string) int {
+	return func(string) int { return n }
+}
+
+func noAuthors(string) []string { return nil }
+
+func noSections(string) []string { return nil }
+
+func youtubeServer(t *testing.T, failingChannel string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/subscriptions"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_A"}, "title": "Channel A"}},
+					{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": "UC_B"}, "title": "Channel B"}},
+				},
+			})
+		case strings.Contains(r.URL.Path, "/search"):
+			channelID := r.URL.Query().Get("channelId")
+			if channelID == failingChannel {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": map[string]interface{}{"videoId": "vid_" + channelID}, "snippet": map[string]interface{}{"title": "Video " + channelID, "channelId": channelID, "channelTitle": channelID, "publishedAt": "2024-01-15T00:00:00Z"}},
+				},
+			})
+		case strings.Contains(r.URL.Path, "/channels"):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{
+					{"id": "UC_A", "snippet": map[string]interface{}{"customUrl": "@channela"}, "topicDetails": map[string]interface{}{"topicCategories": []string{"https://en.wikipedia.org/wiki/Technology"}}},
+					{"id": "UC_B", "topicDetails": map[string]interface{}{"topicCategories": []string{"https://en.wikipedia.org/wiki/Music"}}},
+				},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newYouTubeSource(server *httptest.Server) *YouTube {
+	client := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(server.URL))
+	return NewYouTube(client, "", fixedLimit(5), nil, "")
+}
+
+func TestYouTube_FetchReturnsVideosFromEverySubscription(t *testing.T) {
+	items, err := newYouTubeSource(youtubeServer(t, "")).Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("user should see one video per subscribed channel, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.Source != aggregator.SourceYouTube || item.Type != aggregator.ItemTypeVideo {
+			t.Errorf("YouTube items should be videos from youtube, got %s/%s", item.Source, item.Type)
+		}
+	}
+}
+
+func TestYouTube_LabelsItemsWithChannelHandleAndGroup(t *testing.T) {
+	client := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(youtubeServer(t, "").URL))
+	items, err := NewYouTube(client, "", fixedLimit(5), map[string]string{"UC_B": "chill"}, "").Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byChannel := make(map[string]aggregator.FeedItem)
+	for _, item := range items {
+		byChannel[item.AuthorID] = item
+	}
+	if a := byChannel["UC_A"]; a.AuthorHandle != "@channela" || a.Group != youtube.GroupTech {
+		t.Errorf("channel A should carry its handle and topic group, got %q/%q", a.AuthorHandle, a.Group)
+	}
+	if b := byChannel["UC_B"]; b.Group != "chill" {
+		t.Errorf("a configured group should override the topic group, got %q", b.Group)
+	}
+}
+
+func TestYouTube_UsesCachedChannelsInsteadOfFetchingThem(t *testing.T) {
+	server := youtubeServer(t, "UC_B") // fetching channel B would fail
+	cached := []aggregator.FeedItem{{ID: "old_B", Source: aggregator.SourceYouTube, AuthorID: "UC_B"}}
+	var mu sync.Mutex
+	fetched := make(map[string]int)
+	opts := FetchOptions{
+		Cached: func(key string) ([]aggregator.FeedItem, bool) {
+			return cached, key == "youtube:UC_B"
+		},
+		Fetched: func(key string, items []aggregator.FeedItem) {
+			mu.Lock()
+			fetched[key] = len(items)
+			mu.Unlock()
+		},
+		Warn: func(err error) { t.Errorf("unexpected warning: %v", err) },
+	}
+
+	items, err := newYouTubeSource(server).Fetch(context.Background(), opts)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected channel A's video and channel B's cached one, got %v (err %v)", items, err)
+	}
+	for _, item := range items {
+		if item.AuthorID == "UC_B" && (item.ID != "old_B" || item.Group != youtube.GroupMusic) {
+			t.Errorf("the cached video should be used and grouped like a fetched one, got %+v", item)
+		}
+	}
+	if len(fetched) != 1 || fetched["youtube:UC_A"] != 1 {
+		t.Errorf("only channel A should be reported as fetched, got %v", fetched)
+	}
+}
+
+func TestYouTube_SkipsSubscriptionsWhenEveryChannelIsCached(t *testing.T) {
+	var requests []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(server.URL))
+	opts := FetchOptions{
+		CachedList: func(key string) ([]string, bool) {
+			return []string{"youtube:UC_A", "youtube:UC_B"}, key == "youtube:subscriptions:work"
+		},
+		Cached: func(key string) ([]aggregator.FeedItem, bool) {
+			return []aggregator.FeedItem{{ID: "old_" + key, Source: aggregator.SourceYouTube}}, true
+		},
+	}
+
+	items, err := NewYouTube(client, "work", fixedLimit(5), nil, "").Fetch(context.Background(), opts)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected the cached videos of both channels, got %v (err %v)", items, err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("nothing should be fetched when every subscribed channel is cached, got %v", requests)
+	}
+}
+
+func TestYouTube_ListsSubscribedChannelsPerAccount(t *testing.T) {
+	var listed []string
+	opts := FetchOptions{Listed: func(key string, feeds []string) {
+		listed = append([]string{key}, feeds...)
+	}}
+	client := youtube.NewClient(&oauth.Token{AccessToken: "test"}, youtube.WithBaseURL(youtubeServer(t, "").URL))
+	if _, err := NewYouTube(client, "work", fixedLimit(5), nil, "").Fetch(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"youtube:subscriptions:work", "youtube:UC_A", "youtube:UC_B"}; !slices.Equal(listed, want) {
+		t.Errorf("expected the account's subscribed channels to be listed as %v, got %v", want, listed)
+	}
+}
+
+func TestYouTube_FailingChannelIsWarnedNotFatal(t *testing.T) {
+	var warnings []error
+	var mu sync.Mutex
+	opts := FetchOptions{Warn: func(err error) {
+		mu.Lock()
+		warnings = append(warnings, err)
+		mu.Unlock()
+	}}
+
+	items, err := newYouTubeSource(youtubeServer(t, "UC_B")).Fetch(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("one failing channel should not fail the feed, got: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("user should still see videos from healthy channels, got %d", len(items))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "Channel B") {
+		t.Errorf("user should be warned about the failing channel by name, got %v", warnings)
+	}
+}
+
+func TestYouTube_FetchBoundsConcurrentChannelRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/subscriptions") {
+			subs := make([]map[string]interface{}, 30)
+			for i := range subs {
+				subs[i] = map[string]interface{}{"snippet": map[string]interface{}{"resourceId": map[string]interface{}{"channelId": fmt.Sprintf("UC_%d", i)}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": subs})
+			return
+		}
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	}))
+	defer server.Close()
+
+	if _, err := newYouTubeSource(server).Fetch(context.Background(), FetchOptions{Concurrency: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peak > 3 {
+		t.Errorf("at most 3 channels should be fetched at once, saw %d concurrent requests", peak)
+	}
+}
+
+func TestYouTube_FetchReportsEachChannelAsItCompletes(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]aggregator.FeedItem
+	opts := FetchOptions{Progress: func(items []aggregator.FeedItem) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, items)
+	}}
+
+	items, err := newYouTubeSource(youtubeServer(t, "")).Fetch(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 || len(batches[0])+len(batches[1]) != len(items) {
+		t.Errorf("each channel's videos should be reported as one batch, got %d batches for %d items", len(batches), len(items))
+	}
+}
+
+func TestSubstack_FetchReturnsArticles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss><channel><item><title>Post</title><link>https://x.substack.com/p/post</link><guid>post</guid></item>`+
+			`<item><title>Episode</title><guid>episode</guid><enclosure url="https://x.substack.com/ep.mp3" type="audio/mpeg" length="1000"/><duration>61</duration></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	src := NewSubstack(substack.NewClient(), []string{server.URL}, fixedLimit(5), noAuthors, noSections)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Type != aggregator.ItemTypeArticle || items[0].Source != aggregator.SourceSubstack {
+		t.Fatalf("user should see the post as a Substack article, got %+v", items)
+	}
+	if audio := items[1].Audio; items[1].Type != aggregator.ItemTypePodcast || audio == nil || audio.URL != "https://x.substack.com/ep.mp3" || audio.Duration != 61*time.Second {
+		t.Errorf("user should see the episode as a podcast with its audio, got %+v", items[1])
+	}
+}
+
+func TestSubstack_ReportsPublicationServingAWebPageAsUnhealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body>Just a moment...</body></html>`)
+	}))
+	defer server.Close()
+
+	var unhealthy []string
+	src := NewSubstack(substack.NewClient(), []string{server.URL}, fixedLimit(5), noAuthors, noSections)
+	_, _ = src.Fetch(context.Background(), FetchOptions{
+		Warn:      func(error) {},
+		Unhealthy: func(key string, err error) { unhealthy = append(unhealthy, key) },
+	})
+
+	if len(unhealthy) != 1 || !strings.HasPrefix(unhealthy[0], "substack:") {
+		t.Errorf("user should see the publication serving a web page marked unhealthy, got %v", unhealthy)
+	}
+}
+
+func TestSubstackNotes_FetchReturnsPosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/user/jane/public_profile":
+			fmt.Fprint(w, `{"id":7,"name":"Jane Doe","handle":"jane"}`)
+		case "/api/v1/reader/feed/profile/7":
+			fmt.Fprint(w, `{"items":[{"type":"comment","comment":{"id":42,"body":"Short thought","date":"2024-01-02T10:00:00Z","reaction_count":3,"children_count":1}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var warnings []error
+	src := NewSubstackNotes(substack.NewClient(substack.WithAPIURL(server.URL)), []string{"jane", "ghost"}, 5)
+	items, err := src.Fetch(context.Background(), FetchOptions{Warn: func(err error) { warnings = append(warnings, err) }})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Type != aggregator.ItemTypePost || items[0].Source != aggregator.SourceSubstack {
+		t.Fatalf("user should see the note as a Substack post, got %+v", items)
+	}
+	if item := items[0]; item.Title != "Short thought" || item.AuthorHandle != "@jane" || item.URL != "https://substack.com/@jane/note/c-42" || item.Engagement.Likes != 3 {
+		t.Errorf("the note should keep its text, author, link and likes, got %+v", item)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "@ghost") {
+		t.Errorf("a writer whose notes can't be fetched should be a warning naming them, got %v", warnings)
+	}
+}
+
+type stubSource struct {
+	name  string
+	items []aggregator.FeedItem
+	err   error
+}
+
+func (s stubSource) Name() string { return s.name }
+
+func (s stubSource) Fetch(context.Context, FetchOptions) ([]aggregator.FeedItem, error) {
+	return s.items, s.err
+}
+
+func TestRegistry_FetchAllMergesEverySource(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(stubSource{name: "a", items: []aggregator.FeedItem{{ID: "a1"}, {ID: "a2"}}})
+	registry.Register(stubSource{name: "b", items: []aggregator.FeedItem{{ID: "b1"}}})
+
+	items, err := registry.FetchAll(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("user should see items from every registered source, got %d", len(items))
+	}
+	if len(registry.Sources()) != 2 || registry.Sources()[0].Name() != "a" {
+		t.Error("registry should keep sources in registration order")
+	}
+}
+
+func TestRegistry_FetchAllKeepsItemsSharedBySourcesOnce(t *testing.T) {
+	shared := aggregator.FeedItem{ID: "v1", Source: aggregator.SourceYouTube}
+	registry := NewRegistry()
+	registry.Register(stubSource{name: "personal", items: []aggregator.FeedItem{shared, {ID: "v2", Source: aggregator.SourceYouTube}}})
+	registry.Register(stubSource{name: "work", items: []aggregator.FeedItem{shared}})
+
+	items, err := registry.FetchAll(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("a video seen from two accounts should appear once, got %d items", len(items))
+	}
+}
+
+func TestRegistry_FetchAllReturnsSourceError(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(stubSource{name: "ok", items: []aggregator.FeedItem{{ID: "ok"}}})
+	registry.Register(stubSource{name: "broken", err: errors.New("auth failed")})
+
+	_, err := registry.FetchAll(context.Background(), FetchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "auth failed") {
+		t.Errorf("a fatal source error should be surfaced, got: %v", err)
+	}
+}
+
+func TestRegistry_FetchAllReportsEachSourceOutcome(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(stubSource{name: "ok", items: []aggregator.FeedItem{{ID: "ok"}}})
+	registry.Register(stubSource{name: "broken", err: errors.New("auth failed")})
+
+	var mu sync.Mutex
+	outcomes := make(map[string]string)
+	_, _ = registry.FetchAll(context.Background(), FetchOptions{
+		Finished: func(name string, items int, _ time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			outcomes[name] = fmt.Sprintf("%d %v", items, err)
+		},
+	})
+
+	if outcomes["ok"] != "1 <nil>" || outcomes["broken"] != "0 auth failed" {
+		t.Errorf("every source should report its item count and error, got %v", outcomes)
+	}
+}
+
+func TestSubstack_ShowsPublicationAndAuthorAndFiltersByAuthor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>The Collective</title>
+<item><title>By Jane 1</title><dc:creator>Jane Doe</dc:creator><guid>1</guid></item>
+<item><title>By John</title><dc:creator>John Roe</dc:creator><guid>2</guid></item>
+<item><title>By Jane 2</title><dc:creator>Jane Doe</dc:creator><guid>3</guid></item>
+<item><title>By Jane 3</title><dc:creator>Jane Doe</dc:creator><guid>4</guid></item>
+</channel></rss>`)
+	}))
+	defer server.Close()
+
+	janeOnly := func(string) []string { return []string{"Jane Doe"} }
+	src := NewSubstack(substack.NewClient(), []string{server.URL}, fixedLimit(2), janeOnly, noSections)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("user should see the 2 most recent posts by Jane (limit applies after filter), got %d", len(items))
+	}
+	if items[0].Title != "By Jane 1" || items[1].Title != "By Jane 2" {
+		t.Errorf("user should only see Jane's posts, got %q and %q", items[0].Title, items[1].Title)
+	}
+	if items[0].Author != "The Collective — Jane Doe" {
+		t.Errorf("user should see \"Publication — Author\", got %q", items[0].Author)
+	}
+}
+
+func TestSubstack_ReadsOnlyConfiguredSections(t *testing.T) {
+	feeds := map[string]string{
+		"/feed":        `<item><title>Everything</title><guid>all</guid></item>`,
+		"/s/tech/feed": `<item><title>Tech 2</title><guid>t2</guid><pubDate>Wed, 03 Jan 2024 12:00:00 +0000</pubDate></item><item><title>Tech 1</title><guid>t1</guid><pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate></item>`,
+		"/s/ai/feed":   `<item><title>AI 1</title><guid>a1</guid><pubDate>Tue, 02 Jan 2024 12:00:00 +0000</pubDate></item><item><title>Tech 2</title><guid>t2</guid><pubDate>Wed, 03 Jan 2024 12:00:00 +0000</pubDate></item>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feed, ok := feeds[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "<rss><channel>"+feed+"</channel></rss>")
+	}))
+	defer server.Close()
+
+	sections := func(string) []string { return []string{"tech", "ai"} }
+	src := NewSubstack(substack.NewClient(), []string{server.URL}, fixedLimit(2), noAuthors, sections)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "Tech 2, AI 1" {
+		t.Errorf("user should see the newest posts of the chosen sections, each once, got %q", got)
+	}
+}
+
+func TestPodcast_FetchReturnsEpisodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"><channel><title>Go Time</title><itunes:image href="https://example.com/show.jpg"/>`+
+			`<item><title>Episode</title><guid>ep</guid><itunes:author>Jane Doe</itunes:author><itunes:episode>7</itunes:episode>`+
+			`<enclosure url="https://example.com/ep.mp3" type="audio/mpeg"/></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	src := NewPodcast(podcast.NewClient(), []string{server.URL}, fixedLimit(5))
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Type != aggregator.ItemTypePodcast || items[0].Source != aggregator.SourcePodcast {
+		t.Fatalf("user should see the episode as a podcast, got %+v", items)
+	}
+	item := items[0]
+	if item.Author != "Go Time — Jane Doe" || item.Thumbnail != "https://example.com/show.jpg" || item.Audio == nil || item.Audio.Episode != 7 {
+		t.Errorf("user should see the show, host, artwork and episode number, got %+v", item)
+	}
+}
+
+type staticTokens struct{}
+
+func (staticTokens) Token(context.Context) (*oauth.Token, error) {
+	return &oauth.Token{AccessToken: "test"}, nil
+}
+
+func TestTwitch_FetchReturnsLiveStreamsAndPastBroadcasts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users":
+			fmt.Fprint(w, `{"data":[{"id":"42","login":"me"}]}`)
+		case "/streams/followed":
+			fmt.Fprint(w, `{"data":[{"id":"s1","user_id":"1","user_login":"alpha","user_name":"Alpha","game_name":"Celeste","title":"Any%","viewer_count":300,"started_at":"2024-01-15T10:00:00Z"}]}`)
+		case "/channels/followed":
+			fmt.Fprint(w, `{"data":[{"broadcaster_id":"1","broadcaster_login":"alpha","broadcaster_name":"Alpha"}]}`)
+		case "/videos":
+			fmt.Fprint(w, `{"data":[{"id":"v2","user_name":"Alpha","title":"Any% (recording)","stream_id":"s1","created_at":"2024-01-15T10:00:00Z"},`+
+				`{"id":"v1","user_name":"Alpha","title":"Yesterday","stream_id":"s0","created_at":"2024-01-14T10:00:00Z"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := NewTwitch(twitch.NewClient("app", staticTokens{}, twitch.WithBaseURL(server.URL)), 5)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("user should see the live stream and yesterday's broadcast, not the one being recorded, got %+v", items)
+	}
+	if live := items[0]; !live.LiveNow() || live.Title != "Any% (Celeste)" || live.URL != "https://www.twitch.tv/alpha" || live.Engagement.Views != 300 {
+		t.Errorf("user should see the stream live with its game, channel and viewers, got %+v", live)
+	}
+	if vod := items[1]; vod.ID != "v1" || vod.Type != aggregator.ItemTypeVideo || vod.Source != aggregator.SourceTwitch {
+		t.Errorf("user should see the past broadcast as a video, got %+v", vod)
+	}
+}
+
+func TestGitHub_FetchReturnsReleasesOfReposAndStars(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/octocat/starred":
+			fmt.Fprint(w, `[{"full_name":"CLI/cli"},{"full_name":"junegunn/fzf"}]`)
+		case "/repos/cli/cli/releases":
+			fmt.Fprint(w, `[{"id":1,"tag_name":"v2.0.0","name":"GitHub CLI 2.0.0","published_at":"2024-01-15T10:00:00Z","reactions":{"total_count":40}}]`)
+		case "/repos/junegunn/fzf/releases":
+			fmt.Fprint(w, `[{"id":2,"tag_name":"0.46.0","name":"","prerelease":true,"published_at":"2024-01-14T10:00:00Z"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := NewGitHub(github.NewClient(github.WithBaseURL(server.URL)), []string{"cli/cli"}, "octocat", fixedLimit(5))
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	titles := make(map[string]aggregator.FeedItem)
+	for _, item := range items {
+		titles[item.Title] = item
+	}
+	if len(items) != 2 {
+		t.Fatalf("user should see each repository's release once, got %+v", items)
+	}
+	cli, ok := titles["GitHub CLI 2.0.0"]
+	if !ok || cli.Author != "cli/cli" || cli.Type != aggregator.ItemTypeRelease || cli.Engagement.Likes != 40 {
+		t.Errorf("user should see the release named after its repository, with its reactions, got %+v", items)
+	}
+	if _, ok := titles["0.46.0 (pre-release)"]; !ok {
+		t.Errorf("user should see an unnamed pre-release by its tag, got %+v", items)
+	}
+}
+
+func TestMedium_FetchReturnsStoriesWithClaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed":
+			fmt.Fprint(w, `<rss xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>The Startup - Medium</title>`+
+				`<item><title>Story</title><link>https://medium.com/the-startup/story-1a2b3c?source=rss----f5af2b715248---4</link>`+
+				`<guid>https://medium.com/p/1a2b3c</guid><dc:creator>Jane Doe</dc:creator></item></channel></rss>`)
+		case "/p/1a2b3c":
+			fmt.Fprint(w, `])}while(1);</x>{"payload":{"value":{"virtuals":{"totalClapCount":320,"responsesCreatedCount":4}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	src := NewMedium(medium.NewClient(medium.WithAPIURL(server.URL)), []string{server.URL}, fixedLimit(5))
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Source != aggregator.SourceMedium || items[0].Type != aggregator.ItemTypeArticle {
+		t.Fatalf("user should see the story as an article, got %+v", items)
+	}
+	item := items[0]
+	if item.Author != "The Startup — Jane Doe" || item.URL != "https://medium.com/the-startup/story-1a2b3c" {
+		t.Errorf("user should see the publication, writer and a clean link, got %+v", item)
+	}
+	if item.Engagement.Likes != 320 || item.Engagement.Comments != 4 {
+		t.Errorf("user should see claps as likes and responses as comments, got %+v", item.Engagement)
+	}
+}
+
+func TestLobsters_FetchLeavesOutExcludedTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/t/go.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[{"short_id":"a","title":"Drama","url":"https://example.com/a","tags":["go","culture"]},`+
+			`{"short_id":"b","title":"Ask","comments_url":"https://lobste.rs/s/b/ask","submitter_user":"alice","tags":["go","ask"],"score":12,"comment_count":3},`+
+			`{"short_id":"c","title":"Release","url":"https://example.com/c","tags":["go"]}]`)
+	}))
+	defer server.Close()
+
+	tags := func(string) []string { return []string{"go", "-culture"} }
+	src := NewLobsters(lobsters.NewClient(), []string{server.URL}, fixedLimit(1), tags)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Ask" {
+		t.Fatalf("user should see the first story not tagged culture, got %+v", items)
+	}
+	item := items[0]
+	if item.URL != "https://lobste.rs/s/b/ask" || item.Type != aggregator.ItemTypeLink || item.Engagement.Likes != 12 || item.Engagement.Comments != 3 {
+		t.Errorf("user should see the text post's discussion, with its score and comments, got %+v", item)
+	}
+}
+
+func TestArxiv_FetchReturnsPapers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">`+
+			`<entry><id>http://arxiv.org/abs/2401.00001v3</id><title>Revised</title><summary>Abstract.</summary>`+
+			`<published>2024-01-10T00:00:00Z</published><updated>2024-01-15T00:00:00Z</updated>`+
+			`<author><name>A</name></author><author><name>B</name></author><author><name>C</name></author><author><name>D</name></author>`+
+			`<arxiv:primary_category term="cs.LG"/></entry>`+
+			`<entry><id>http://arxiv.org/abs/2401.00002v1</id><title>New</title>`+
+			`<published>2024-01-14T00:00:00Z</published><updated>2024-01-14T00:00:00Z</updated><author><name>E</name></author></entry></feed>`)
+	}))
+	defer server.Close()
+
+	src := NewArxiv(arxiv.NewClient(arxiv.WithBaseURL(server.URL)), arxiv.Query{Categories: []string{"cs.LG"}}, 25)
+	items, err := src.Fetch(context.Background(), FetchOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Type != aggregator.ItemTypePaper || items[0].Source != aggregator.SourceArxiv {
+		t.Fatalf("user should see both papers, got %+v", items)
+	}
+	revised := items[0]
+	if revised.Author != "A, B, C et al." || revised.Description != "Abstract." || revised.Group != "cs.LG" || revised.UpdatedAt.IsZero() {
+		t.Errorf("user should see the first authors, abstract, category and revision date, got %+v", revised)
+	}
+	if !items[1].UpdatedAt.IsZero() {
+		t.Errorf("a first version shouldn't count as updated, got %v", items[1].UpdatedAt)
+	}
+}
+
+func TestPeerTube_FetchReturnsChannelVideos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/video-channels/news/videos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"uuid":"u1","name":"Release party","url":"https://peertube.tv/w/u1","views":120,"likes":9,`+
+			`"publishedAt":"2024-01-15T10:00:00Z","channel":{"name":"news","displayName":"The News","host":"peertube.tv"}}]}`)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	src := NewPeerTube(peertube.NewClient(peertube.WithScheme("http")), []string{"news@" + host, "gone@" + host}, fixedLimit(10))
+	var warnings []error
+	items, err := src.Fetch(context.Background(), FetchOptions{Warn: func(err error) { warnings = append(warnings, err) }})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || len(warnings) != 1 {
+		t.Fatalf("user should see the working channel's video and a warning for the other, got %+v and %v", items, warnings)
+	}
+	video := items[0]
+	if video.Source != aggregator.SourcePeerTube || video.Type != aggregator.ItemTypeVideo || video.Author != "The News" ||
+		video.AuthorHandle != "@news@peertube.tv" || video.Engagement.Views != 120 || video.Engagement.Likes != 9 {
+		t.Errorf("user should see the video with its channel, views and likes, got %+v", video)
+	}
+}
@@ -0,0 +1,146 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/substack"
+)
+
+// Substack fetches recent posts from a set of Substack publications.
+type Substack struct {
+	client   *substack.Client
+	urls     []string
+	limit    func(publicationURL string) int
+	authors  func(publicationURL string) []string
+	sections func(publicationURL string) []string
+}
+
+// NewSubstack creates a Substack source. limit returns how many posts to fetch
+// per publication; authors returns the authors to keep (nil keeps every author)
+// and sections the sections to read (nil reads the whole publication).
+func NewSubstack(client *substack.Client, urls []string, limit func(publicationURL string) int, authors, sections func(publicationURL string) []string) *Substack {
+	return &Substack{client: client, urls: urls, limit: limit, authors: authors, sections: sections}
+}
+
+// Name returns the source identifier.
+func (s *Substack) Name() string {
+	return string(aggregator.SourceSubstack)
+}
+
+// Fetch returns recent posts from every publication. A failing publication is reported via opts.Warn.
+func (s *Substack) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(s.urls), func(i int) {
+		pubURL := s.urls[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceSubstack, pubURL), func() ([]aggregator.FeedItem, error) {
+			posts, err := s.fetchPublication(ctx, pubURL)
+			if err != nil {
+				return nil, err
+			}
+			return postItems(posts), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch Substack feed from %s: %w", pubURL, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
+
+// fetchPublication applies the author filter before the limit, so a
+// publication filtered to one author still yields up to limit of their posts.
+// A publication restricted to sections yields the newest posts of them all.
+func (s *Substack) fetchPublication(ctx context.Context, pubURL string) ([]substack.Post, error) {
+	limit := s.limit(pubURL)
+	authors := s.authors(pubURL)
+	sections := s.sections(pubURL)
+	if len(authors) == 0 && len(sections) == 0 {
+		return s.client.FetchPosts(ctx, pubURL, limit)
+	}
+
+	posts, err := s.fetchSections(ctx, pubURL, sections)
+	if err != nil {
+		return nil, err
+	}
+	posts = substack.FilterByAuthor(posts, authors)
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts, nil
+}
+
+// fetchSections returns every post of the given sections of a publication,
+// newest first, or of the whole publication when there are none.
+func (s *Substack) fetchSections(ctx context.Context, pubURL string, sections []string) ([]substack.Post, error) {
+	if len(sections) == 0 {
+		return s.client.FetchPosts(ctx, pubURL, 0)
+	}
+	var posts []substack.Post
+	seen := make(map[string]bool)
+	for _, section := range sections {
+		batch, err := s.client.FetchSectionPosts(ctx, pubURL, section, 0)
+		if err != nil {
+			return nil, fmt.Errorf("section %s: %w", section, err)
+		}
+		for _, post := range batch {
+			if !seen[post.ID] {
+				seen[post.ID] = true
+				posts = append(posts, post)
+			}
+		}
+	}
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].PublishedAt.After(posts[j].PublishedAt) })
+	return posts, nil
+}
+
+func postItems(posts []substack.Post) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(posts))
+	for _, post := range posts {
+		item := aggregator.FeedItem{
+			ID:          post.ID,
+			Source:      aggregator.SourceSubstack,
+			Type:        aggregator.ItemTypeArticle,
+			Title:       post.Title,
+			Description: post.Description,
+			Content:     post.Content,
+			Paywalled:   post.Paywalled,
+			Author:      postAuthor(post),
+			URL:         post.URL,
+			PublishedAt: post.PublishedAt,
+		}
+		if audio := post.Audio; audio != nil {
+			item.Type = aggregator.ItemTypePodcast
+			item.Audio = &aggregator.Audio{URL: audio.URL, Type: audio.Type, Length: audio.Length, Duration: audio.Duration}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// postAuthor renders "Publication — Author" so posts from multi-author
+// publications are attributed to both.
+func postAuthor(post substack.Post) string {
+	return byline(post.Publication, post.Author)
+}
+
+// byline attributes an item to the feed it came from and its author.
+func byline(feed, author string) string {
+	switch {
+	case feed == "":
+		return author
+	case author == "" || author == feed:
+		return feed
+	default:
+		return feed + " — " + author
+	}
+}
@@ -0,0 +1,73 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/substack"
+)
+
+// SubstackNotes fetches recent Substack Notes from a set of writers.
+type SubstackNotes struct {
+	client  *substack.Client
+	handles []string
+	limit   int
+}
+
+// NewSubstackNotes creates a source of the notes of the writers with the
+// given handles, up to limit notes each (0 for all the API returns).
+func NewSubstackNotes(client *substack.Client, handles []string, limit int) *SubstackNotes {
+	return &SubstackNotes{client: client, handles: handles, limit: limit}
+}
+
+// Name returns the source identifier.
+func (s *SubstackNotes) Name() string {
+	return string(aggregator.SourceSubstack)
+}
+
+// Fetch returns recent notes from every writer. A failing writer is reported via opts.Warn.
+func (s *SubstackNotes) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(s.handles), func(i int) {
+		handle := s.handles[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceSubstack, "@"+handle), func() ([]aggregator.FeedItem, error) {
+			notes, err := s.client.FetchNotes(ctx, handle, s.limit)
+			if err != nil {
+				return nil, err
+			}
+			return noteItems(notes), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch Substack notes of @%s: %w", handle, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
+
+func noteItems(notes []substack.Note) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(notes))
+	for _, note := range notes {
+		items = append(items, aggregator.FeedItem{
+			ID:           note.ID,
+			Source:       aggregator.SourceSubstack,
+			Type:         aggregator.ItemTypePost,
+			Title:        note.Title,
+			Description:  note.Body,
+			Author:       note.Author,
+			AuthorHandle: "@" + note.Handle,
+			URL:          note.URL,
+			PublishedAt:  note.PublishedAt,
+			Engagement:   aggregator.Engagement{Likes: note.Likes, Comments: note.Replies},
+		})
+	}
+	return items
+}
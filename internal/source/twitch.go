@@ -0,0 +1,122 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/twitch"
+)
+
+// Twitch fetches the live streams and past broadcasts of the channels the
+// user follows on Twitch.
+type Twitch struct {
+	client *twitch.Client
+	limit  int
+}
+
+// NewTwitch creates a Twitch source. limit is how many past broadcasts to
+// fetch per channel.
+func NewTwitch(client *twitch.Client, limit int) *Twitch {
+	return &Twitch{client: client, limit: limit}
+}
+
+// Name returns the source identifier.
+func (t *Twitch) Name() string {
+	return string(aggregator.SourceTwitch)
+}
+
+// Fetch returns the followed channels that are live, then their past
+// broadcasts. Failing to identify the user or list what they follow is
+// fatal; a failing channel is reported via opts.Warn.
+func (t *Twitch) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	user, err := t.client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify Twitch user: %w", err)
+	}
+	streams, err := t.client.FollowedStreams(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch followed Twitch streams: %w", err)
+	}
+	channels, err := t.client.FollowedChannels(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch followed Twitch channels: %w", err)
+	}
+
+	live := make(map[string]bool, len(streams))
+	items := make([]aggregator.FeedItem, 0, len(streams))
+	for _, stream := range streams {
+		live[stream.ID] = true
+		items = append(items, streamItem(stream))
+	}
+	opts.progress(items)
+
+	var mu sync.Mutex
+	opts.forEach(len(channels), func(i int) {
+		channel := channels[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceTwitch, channel.ID), func() ([]aggregator.FeedItem, error) {
+			videos, err := t.client.RecentVideos(ctx, channel.ID, t.limit)
+			if err != nil {
+				return nil, err
+			}
+			return vodItems(videos, live), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch Twitch videos of %s: %w", channel.Name, err))
+			return
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+	return items, nil
+}
+
+func streamItem(stream twitch.Stream) aggregator.FeedItem {
+	title := stream.Title
+	if stream.Game != "" {
+		title += " (" + stream.Game + ")"
+	}
+	return aggregator.FeedItem{
+		ID:           "live-" + stream.ID,
+		Source:       aggregator.SourceTwitch,
+		Type:         aggregator.ItemTypeLive,
+		Title:        title,
+		Author:       stream.UserName,
+		AuthorID:     stream.UserID,
+		AuthorHandle: stream.UserLogin,
+		URL:          "https://www.twitch.tv/" + stream.UserLogin,
+		Thumbnail:    strings.NewReplacer("{width}", "440", "{height}", "248").Replace(stream.Thumbnail),
+		PublishedAt:  stream.StartedAt,
+		Engagement:   aggregator.Engagement{Views: stream.Viewers},
+		Broadcast:    &aggregator.Broadcast{Status: aggregator.BroadcastLive, Start: stream.StartedAt},
+	}
+}
+
+// vodItems maps past broadcasts to items, leaving out the recordings of
+// streams in live, which are listed as live already.
+func vodItems(videos []twitch.Video, live map[string]bool) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(videos))
+	for _, video := range videos {
+		if video.StreamID != "" && live[video.StreamID] {
+			continue
+		}
+		items = append(items, aggregator.FeedItem{
+			ID:           video.ID,
+			Source:       aggregator.SourceTwitch,
+			Type:         aggregator.ItemTypeVideo,
+			Title:        video.Title,
+			Description:  video.Description,
+			Author:       video.UserName,
+			AuthorHandle: video.UserLogin,
+			URL:          video.URL,
+			Thumbnail:    strings.NewReplacer("%{width}", "320", "%{height}", "180").Replace(video.Thumbnail),
+			PublishedAt:  video.CreatedAt,
+			Engagement:   aggregator.Engagement{Views: video.Views},
+		})
+	}
+	return items
+}
@@ -0,0 +1,166 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+)
+
+// YouTube fetches recent videos from the authenticated user's subscriptions.
+type YouTube struct {
+	client  *youtube.Client
+	account string
+	limit   func(channelID string) int
+	groups  map[string]string
+	region  string
+}
+
+// NewYouTube creates a YouTube source for the signed-in account ("" for the
+// default one). limit returns how many videos to fetch per channel. groups
+// assigns channel IDs to a group; other channels are grouped by their
+// topics. Videos that don't play in region (an ISO 3166-1 code, or "" for
+// none) are flagged.
+func NewYouTube(client *youtube.Client, account string, limit func(channelID string) int, groups map[string]string, region string) *YouTube {
+	return &YouTube{client: client, account: account, limit: limit, groups: groups, region: region}
+}
+
+// Name returns the source identifier.
+func (y *YouTube) Name() string {
+	return string(aggregator.SourceYouTube)
+}
+
+// Fetch returns recent videos from every subscribed channel. Failing to
+// list subscriptions is fatal; a failing channel is reported via opts.Warn.
+// When opts has every channel subscribed to last time cached, neither the
+// subscriptions nor the channel details are fetched.
+func (y *YouTube) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	subscriptionsKey := feedKey(aggregator.SourceYouTube, "subscriptions:"+y.account)
+	if items, ok := opts.cachedListing(subscriptionsKey); ok {
+		opts.progress(items)
+		return items, nil
+	}
+	subs, err := y.client.FetchSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeds := make([]string, len(subs))
+	for i, sub := range subs {
+		feeds[i] = feedKey(aggregator.SourceYouTube, sub.ChannelID)
+	}
+	opts.listed(subscriptionsKey, feeds)
+	channels := y.channels(ctx, subs, opts)
+
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(subs), func(i int) {
+		sub := subs[i]
+		batch, err := opts.fetch(feeds[i], func() ([]aggregator.FeedItem, error) {
+			videos, err := y.client.FetchRecentVideos(ctx, sub.ChannelID, y.limit(sub.ChannelID))
+			if err != nil {
+				return nil, err
+			}
+			return videoItems(videos, y.region), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch videos from %s: %w", sub.ChannelTitle, err))
+			return
+		}
+		for i := range batch {
+			batch[i].AuthorHandle = channels[batch[i].AuthorID].Handle
+			batch[i].Group = y.group(batch[i].AuthorID, channels[batch[i].AuthorID])
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
+
+// channels looks up the handle and topics of every subscribed channel. They
+// only label and group items, so a failed lookup is a warning.
+func (y *YouTube) channels(ctx context.Context, subs []youtube.Subscription, opts FetchOptions) map[string]youtube.ChannelDetails {
+	ids := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ChannelID)
+	}
+	channels, err := y.client.FetchChannels(ctx, ids)
+	if err != nil {
+		opts.warn(fmt.Errorf("failed to fetch channel details: %w", err))
+	}
+	return channels
+}
+
+func (y *YouTube) group(channelID string, channel youtube.ChannelDetails) string {
+	if group, ok := y.groups[channelID]; ok {
+		return group
+	}
+	return youtube.TopicGroup(channel.Topics)
+}
+
+func videoItems(videos []youtube.Video, region string) []aggregator.FeedItem {
+	items := make([]aggregator.FeedItem, 0, len(videos))
+	for _, video := range videos {
+		items = append(items, aggregator.FeedItem{
+			ID:          video.ID,
+			Source:      aggregator.SourceYouTube,
+			Type:        videoType(video),
+			Title:       video.Title,
+			Description: video.Description,
+			Author:      video.ChannelTitle,
+			AuthorID:    video.ChannelID,
+			URL:         video.URL,
+			Thumbnail:   video.Thumbnail,
+			PublishedAt: video.PublishedAt,
+			Engagement: aggregator.Engagement{
+				Views: video.ViewCount,
+				Likes: video.LikeCount,
+			},
+			Restriction: restriction(video, region),
+			Broadcast:   broadcast(video),
+			Chapters:    chapters(video),
+		})
+	}
+	return items
+}
+
+// videoType is ItemTypeLive for premieres and live streams that haven't
+// ended, ItemTypeVideo otherwise.
+func videoType(video youtube.Video) aggregator.ItemType {
+	if video.Broadcast != nil {
+		return aggregator.ItemTypeLive
+	}
+	return aggregator.ItemTypeVideo
+}
+
+// broadcast returns when video airs, if it is an upcoming or live broadcast.
+func broadcast(video youtube.Video) *aggregator.Broadcast {
+	if video.Broadcast == nil {
+		return nil
+	}
+	b := aggregator.Broadcast(*video.Broadcast)
+	return &b
+}
+
+func chapters(video youtube.Video) []aggregator.Chapter {
+	var chapters []aggregator.Chapter
+	for _, chapter := range video.Chapters {
+		chapters = append(chapters, aggregator.Chapter(chapter))
+	}
+	return chapters
+}
+
+// restriction describes why video may not play in region, or returns "".
+func restriction(video youtube.Video, region string) string {
+	if !video.PlayableIn(region) {
+		return "not available in " + region
+	}
+	if video.AgeRestricted {
+		return "age-restricted"
+	}
+	return ""
+}
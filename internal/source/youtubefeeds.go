@@ -0,0 +1,61 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gauthierbraillon/feedmix/internal/aggregator"
+	"github.com/gauthierbraillon/feedmix/internal/youtube"
+)
+
+// YouTubeFeeds fetches recent videos of a list of channels from their public
+// RSS feeds, without credentials or quota. It stands in for YouTube when
+// the Data API isn't set up, with less detail on each video.
+type YouTubeFeeds struct {
+	client   *youtube.Client
+	channels []string
+	limit    func(channelID string) int
+	groups   map[string]string
+}
+
+// NewYouTubeFeeds creates a YouTubeFeeds source for the channels with the
+// given IDs. limit returns how many videos to fetch per channel, and groups
+// assigns channel IDs to a group; without the API, other channels have none.
+func NewYouTubeFeeds(client *youtube.Client, channels []string, limit func(channelID string) int, groups map[string]string) *YouTubeFeeds {
+	return &YouTubeFeeds{client: client, channels: channels, limit: limit, groups: groups}
+}
+
+// Name returns the source identifier.
+func (y *YouTubeFeeds) Name() string {
+	return string(aggregator.SourceYouTube)
+}
+
+// Fetch returns recent videos from every channel. A failing channel is reported via opts.Warn.
+func (y *YouTubeFeeds) Fetch(ctx context.Context, opts FetchOptions) ([]aggregator.FeedItem, error) {
+	var mu sync.Mutex
+	var items []aggregator.FeedItem
+	opts.forEach(len(y.channels), func(i int) {
+		channelID := y.channels[i]
+		batch, err := opts.fetch(feedKey(aggregator.SourceYouTube, channelID), func() ([]aggregator.FeedItem, error) {
+			videos, err := y.client.FetchChannelFeed(ctx, channelID, y.limit(channelID))
+			if err != nil {
+				return nil, err
+			}
+			return videoItems(videos, ""), nil
+		})
+		if err != nil {
+			opts.warn(fmt.Errorf("failed to fetch the feed of channel %s: %w", channelID, err))
+			return
+		}
+		for i := range batch {
+			batch[i].Group = y.groups[channelID]
+		}
+		mu.Lock()
+		items = append(items, batch...)
+		mu.Unlock()
+		opts.progress(batch)
+	})
+
+	return items, nil
+}
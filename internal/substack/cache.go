@@ -0,0 +1,74 @@
+package substack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// feedCache keeps the last successful response of each feed together with
+// its ETag and Last-Modified validators, so unchanged feeds can be requested
+// conditionally and served locally on 304 Not Modified.
+type feedCache struct {
+	dir string
+}
+
+type cachedFeed struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+func (c feedCache) path(feedURL string) string {
+	sum := sha256.Sum256([]byte(feedURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:12])+".json")
+}
+
+func (c feedCache) load(feedURL string) (cachedFeed, bool) {
+	data, err := os.ReadFile(c.path(feedURL))
+	if err != nil {
+		return cachedFeed{}, false
+	}
+	var entry cachedFeed
+	if err := json.Unmarshal(data, &entry); err != nil || len(entry.Body) == 0 {
+		return cachedFeed{}, false
+	}
+	return entry, true
+}
+
+// store saves the response body if the server sent a validator. Errors are
+// ignored: the cache only saves bandwidth and must never fail a fetch.
+func (c feedCache) store(feedURL string, header http.Header, body []byte) {
+	entry := cachedFeed{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified"), Body: body}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "feed-*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), c.path(feedURL)) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (e cachedFeed) setConditionalHeaders(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
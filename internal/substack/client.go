@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"strings"
-	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
 )
 
 // HTTPClient interface for making HTTP requests (allows injection for testing).
@@ -32,10 +33,29 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithCacheDir keeps each feed's last response in dir and revalidates it with
+// conditional requests (ETag / If-Modified-Since).
+func WithCacheDir(dir string) ClientOption {
+	return func(c *Client) {
+		c.cache = &feedCache{dir: dir}
+	}
+}
+
+// WithHeaders adds the headers returned for each publication URL to its feed
+// request, e.g. a session cookie for a paid publication.
+func WithHeaders(headers func(publicationURL string) http.Header) ClientOption {
+	return func(c *Client) {
+		c.headers = headers
+	}
+}
+
 // Client fetches RSS feeds from Substack publications.
 type Client struct {
 	httpClient HTTPClient
 	baseURL    string
+	cache      *feedCache
+	headers    func(publicationURL string) http.Header
+	apiURL     string
 }
 
 // NewClient creates a new Substack RSS client.
@@ -53,12 +73,34 @@ func NewClient(opts ...ClientOption) *Client {
 // publicationURL is the base URL (e.g. https://simonwillison.substack.com).
 // /feed is appended internally. Results are limited to limit items.
 func (c *Client) FetchPosts(ctx context.Context, publicationURL string, limit int) ([]Post, error) {
-	feedURL := c.buildFeedURL(publicationURL)
+	return c.fetchFeed(ctx, publicationURL, c.buildFeedURL(publicationURL, ""), limit)
+}
+
+// FetchSectionPosts fetches recent posts from one section of a publication,
+// such as "podcast" for https://example.substack.com/s/podcast, from the
+// section's own feed.
+func (c *Client) FetchSectionPosts(ctx context.Context, publicationURL, section string, limit int) ([]Post, error) {
+	return c.fetchFeed(ctx, publicationURL, c.buildFeedURL(publicationURL, section), limit)
+}
 
+func (c *Client) fetchFeed(ctx context.Context, publicationURL, feedURL string, limit int) ([]Post, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if c.headers != nil {
+		for name, values := range c.headers(publicationURL) {
+			req.Header[name] = values
+		}
+	}
+
+	var cached cachedFeed
+	var hasCached bool
+	if c.cache != nil {
+		if cached, hasCached = c.cache.load(feedURL); hasCached {
+			cached.setConditionalHeaders(req)
+		}
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -66,22 +108,38 @@ func (c *Client) FetchPosts(ctx context.Context, publicationURL string, limit in
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return parseRSS(cached.Body, limit)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("substack RSS feed returned HTTP %d for %s", resp.StatusCode, publicationURL)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := rss.ReadFeed(resp, publicationURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read RSS feed: %w", err)
+		return nil, err
 	}
 
-	return parseRSS(body, limit)
+	posts, err := parseRSS(body, limit)
+	if err == nil && c.cache != nil {
+		c.cache.store(feedURL, resp.Header, body)
+	}
+	return posts, err
 }
 
-func (c *Client) buildFeedURL(publicationURL string) string {
+func (c *Client) buildFeedURL(publicationURL, section string) string {
 	if c.baseURL != "" {
-		return strings.TrimRight(c.baseURL, "/") + "/feed"
+		publicationURL = c.baseURL
 	}
+	if section == "" {
+		return FeedURL(publicationURL)
+	}
+	return strings.TrimRight(resolveSubstackURL(publicationURL), "/") + "/s/" + url.PathEscape(section) + "/feed"
+}
+
+// FeedURL returns the RSS feed of the publication at publicationURL, which
+// may also be a substack.com/@username profile.
+func FeedURL(publicationURL string) string {
 	return strings.TrimRight(resolveSubstackURL(publicationURL), "/") + "/feed"
 }
 
@@ -108,51 +166,78 @@ func parseRSS(data []byte, limit int) ([]Post, error) {
 		items = items[:limit]
 	}
 
+	publication := strings.TrimSpace(doc.Channel.Title)
 	posts := make([]Post, 0, len(items))
 	for _, item := range items {
-		author := item.DCCreator
-		if author == "" {
-			author = item.Author
-		}
+		authors := item.authors()
 		posts = append(posts, Post{
 			ID:          item.GUID,
 			Title:       item.Title,
 			Description: item.Desc,
-			Author:      author,
+			Content:     item.Content,
+			Paywalled:   paywalled(item.Content),
+			Audio:       item.audio(),
+			Author:      strings.Join(authors, ", "),
+			Authors:     authors,
+			Publication: publication,
 			URL:         item.Link,
-			PublishedAt: parsePubDate(item.PubDate),
+			PublishedAt: rss.ParseDate(item.PubDate),
 		})
 	}
 	return posts, nil
 }
 
-func parsePubDate(s string) time.Time {
-	formats := []string{
-		time.RFC1123Z,
-		time.RFC1123,
-		time.RFC3339,
-	}
-	for _, f := range formats {
-		if t, err := time.Parse(f, s); err == nil {
-			return t
+// authors returns every dc:creator on the item, falling back to the RSS
+// <author> element, whose "email (Name)" form is reduced to the name.
+func (item rssItem) authors() []string {
+	var authors []string
+	for _, creator := range item.DCCreators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			authors = append(authors, creator)
 		}
 	}
-	return time.Time{}
+	if len(authors) > 0 {
+		return authors
+	}
+
+	author := strings.TrimSpace(item.Author)
+	if open, end := strings.Index(author, "("), strings.LastIndex(author, ")"); open >= 0 && end > open {
+		author = strings.TrimSpace(author[open+1 : end])
+	}
+	if author == "" {
+		return nil
+	}
+	return []string{author}
+}
+
+// audio returns the podcast episode the item encloses, or nil when it
+// encloses none or something other than audio, such as a cover image.
+func (item rssItem) audio() *Audio {
+	enclosure := item.Enclosure
+	if !enclosure.IsAudio() {
+		return nil
+	}
+	return &Audio{URL: enclosure.URL, Type: enclosure.Type, Length: enclosure.Size(), Duration: rss.ParseDuration(item.Duration)}
 }
 
 // rssDoc and rssItem are private XML parsing structs.
 type rssDoc struct {
 	Channel struct {
+		Title string    `xml:"title"`
 		Items []rssItem `xml:"item"`
 	} `xml:"channel"`
 }
 
 type rssItem struct {
-	Title     string `xml:"title"`
-	Link      string `xml:"link"`
-	Author    string `xml:"author"`
-	DCCreator string `xml:"creator"`
-	PubDate   string `xml:"pubDate"`
-	Desc      string `xml:"description"`
-	GUID      string `xml:"guid"`
+	Title      string        `xml:"title"`
+	Link       string        `xml:"link"`
+	Author     string        `xml:"author"`
+	DCCreators []string      `xml:"creator"`
+	PubDate    string        `xml:"pubDate"`
+	Desc       string        `xml:"description"`
+	Content    string        `xml:"encoded"`
+	GUID       string        `xml:"guid"`
+	Enclosure  rss.Enclosure `xml:"enclosure"`
+	// Duration is itunes:duration, in seconds or [h:]mm:ss.
+	Duration string `xml:"duration"`
 }
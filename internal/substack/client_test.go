@@ -3,24 +3,32 @@
 // TDD Cycle: RED -> GREEN -> REFACTOR
 //
 // Test requirements (this file serves as documentation):
-// - Client fetches and parses RSS feed from a Substack publication URL
+// - Client fetches and parses RSS feed from a Substack publication URL, with full post content
 // - Client limits results to the requested count
 // - Client appends /feed to the publication URL
 // - Client returns errors on HTTP failures
 // - Client returns errors on malformed XML
+// - Client sends per-publication headers (e.g. cookies for paid feeds)
+// - Client revalidates cached feeds with ETag / If-Modified-Since and serves 304s locally
+// - Client fetches a writer's Notes from their profile, skipping other activity
 package substack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
 )
 
 const validRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
-<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:content="http://purl.org/rss/1.0/modules/content/">
   <channel>
     <title>Test Publication</title>
     <item>
@@ -29,6 +37,7 @@ const validRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
       <dc:creator>Jane Doe</dc:creator>
       <pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate>
       <description>A great article about things.</description>
+      <content:encoded><![CDATA[<p>The whole article about things.</p>]]></content:encoded>
       <guid>https://example.substack.com/p/hello-world</guid>
     </item>
     <item>
@@ -44,6 +53,7 @@ const validRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
 
 // TestClient_FetchPosts_ReturnsParsedPosts documents RSS parsing:
 // - Parses title, author (dc:creator), URL (link), pubDate, description, and guid as ID
+// - Parses the full post from content:encoded; posts without it have no content
 func TestClient_FetchPosts_ReturnsParsedPosts(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/rss+xml")
@@ -80,6 +90,12 @@ func TestClient_FetchPosts_ReturnsParsedPosts(t *testing.T) {
 	if post.PublishedAt.IsZero() {
 		t.Error("expected non-zero PublishedAt")
 	}
+	if post.Content != "<p>The whole article about things.</p>" {
+		t.Errorf("expected the full post from content:encoded, got %q", post.Content)
+	}
+	if posts[1].Content != "" {
+		t.Errorf("a post without content:encoded should have no content, got %q", posts[1].Content)
+	}
 }
 
 // TestClient_FetchPosts_RespectsLimit documents limit behavior:
@@ -149,6 +165,32 @@ func TestClient_FetchPosts_ReturnsErrorOnInvalidXML(t *testing.T) {
 	}
 }
 
+// TestClient_FetchPosts_ReportsHTMLPages documents HTML responses to feed URLs:
+// - a login page or bot check served with status 200 → ErrHTMLPage naming the publication
+// - an RSS feed mislabeled as text/html still parses
+func TestClient_FetchPosts_ReportsHTMLPages(t *testing.T) {
+	serve := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, body)
+		}))
+	}
+	page := serve("<!DOCTYPE html><html><head><title>Just a moment...</title></head></html>")
+	defer page.Close()
+	feed := serve(validRSSXML)
+	defer feed.Close()
+
+	_, err := NewClient(WithBaseURL(page.URL)).FetchPosts(context.Background(), "https://example.substack.com", 10)
+	if !errors.Is(err, rss.ErrHTMLPage) || !strings.Contains(err.Error(), "https://example.substack.com") {
+		t.Errorf("expected ErrHTMLPage naming the publication, got %v", err)
+	}
+
+	posts, err := NewClient(WithBaseURL(feed.URL)).FetchPosts(context.Background(), "https://example.substack.com", 10)
+	if err != nil || len(posts) == 0 {
+		t.Errorf("an RSS feed served as text/html should parse, got %d posts, %v", len(posts), err)
+	}
+}
+
 // TestResolveSubstackURL_NormalizesAtUsernameFormat documents @username URL normalization:
 // - https://substack.com/@username → https://username.substack.com
 // - traditional subdomain URLs pass through unchanged
@@ -188,3 +230,252 @@ func TestClient_FetchPosts_AppendsRSSPathToPublicationURL(t *testing.T) {
 		t.Errorf("expected request path to end with /feed, got %q", capturedPath)
 	}
 }
+
+// BenchmarkParseRSS measures RSS decoding of a 50-item publication feed.
+// Run with: go test -bench=. -benchmem ./internal/substack
+func BenchmarkParseRSS(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?><rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>`)
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&sb, `<item><title>Post %d</title><link>https://example.substack.com/p/%d</link><dc:creator>Jane Doe</dc:creator><pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate><description>Article body %d</description><guid>%d</guid></item>`, i, i, i, i)
+	}
+	sb.WriteString(`</channel></rss>`)
+	data := []byte(sb.String())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parseRSS(data, 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+const multiAuthorRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>The Collective</title>
+    <item><title>Solo</title><dc:creator>Jane Doe</dc:creator><guid>1</guid></item>
+    <item><title>Duet</title><dc:creator>Jane Doe</dc:creator><dc:creator>John Roe</dc:creator><guid>2</guid></item>
+    <item><title>Guest</title><author>guest@example.com (Guest Writer)</author><guid>3</guid></item>
+  </channel>
+</rss>`
+
+// TestClient_FetchPosts_ParsesPerItemAuthors documents multi-author publications:
+// - Each item keeps its own dc:creator values (co-authored posts list all authors)
+// - RSS <author> "email (Name)" falls back to the name
+// - The channel title is recorded as the post's publication
+func TestClient_FetchPosts_ParsesPerItemAuthors(t *testing.T) {
+	posts, err := parseRSS([]byte(multiAuthorRSSXML), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts) != 3 {
+		t.Fatalf("expected 3 posts, got %d", len(posts))
+	}
+	if posts[0].Author != "Jane Doe" {
+		t.Errorf("solo post should be attributed to Jane Doe, got %q", posts[0].Author)
+	}
+	if posts[1].Author != "Jane Doe, John Roe" || len(posts[1].Authors) != 2 {
+		t.Errorf("co-authored post should list both authors, got %q", posts[1].Author)
+	}
+	if posts[2].Author != "Guest Writer" {
+		t.Errorf("RSS author element should be reduced to the name, got %q", posts[2].Author)
+	}
+	for _, post := range posts {
+		if post.Publication != "The Collective" {
+			t.Errorf("post should record its publication title, got %q", post.Publication)
+		}
+	}
+}
+
+const paidRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Paid Newsletter</title>
+    <item><title>Free</title><guid>1</guid><content:encoded><![CDATA[<p>Everything.</p>]]></content:encoded></item>
+    <item><title>Paid</title><guid>2</guid><content:encoded><![CDATA[<p>The start.</p><div class="paywall-jump" data-component-name="PaywallToDOM"></div>]]></content:encoded></item>
+    <item><title>Trial</title><guid>3</guid><content:encoded><![CDATA[<p>The start.</p><h3>Keep reading with a 7-day free trial</h3>]]></content:encoded></item>
+  </channel>
+</rss>`
+
+// TestClient_FetchPosts_FlagsPaywalledPosts documents paid posts:
+// - A post whose content stops at the paywall, or at the call to subscribe, is paywalled
+// - A post with its full content is not
+func TestClient_FetchPosts_FlagsPaywalledPosts(t *testing.T) {
+	posts, err := parseRSS([]byte(paidRSSXML), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []bool{false, true, true} {
+		if posts[i].Paywalled != want {
+			t.Errorf("post %q: expected paywalled %v, got %v", posts[i].Title, want, posts[i].Paywalled)
+		}
+	}
+}
+
+const podcastRSSXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>The Show</title>
+    <item><title>Episode 1</title><guid>1</guid><enclosure url="https://api.substack.com/feed/podcast/1.mp3" length="40960000" type="audio/mpeg"/><itunes:duration>42:10</itunes:duration></item>
+    <item><title>Episode 2</title><guid>2</guid><enclosure url="https://api.substack.com/feed/podcast/2.mp3" length="" type="audio/mpeg"/><itunes:duration>3723</itunes:duration></item>
+    <item><title>Cover</title><guid>3</guid><enclosure url="https://substackcdn.com/image.jpg" type="image/jpeg"/></item>
+  </channel>
+</rss>`
+
+// TestClient_FetchPosts_ParsesPodcastEpisodes documents podcast feeds:
+// - An audio enclosure makes the post an episode with its URL, MIME type, size and itunes:duration
+// - Durations are seconds or [h:]mm:ss; a missing size is unknown
+// - Other enclosures, such as cover images, are ignored
+func TestClient_FetchPosts_ParsesPodcastEpisodes(t *testing.T) {
+	posts, err := parseRSS([]byte(podcastRSSXML), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Audio{URL: "https://api.substack.com/feed/podcast/1.mp3", Type: "audio/mpeg", Length: 40960000, Duration: 42*time.Minute + 10*time.Second}
+	if posts[0].Audio == nil || *posts[0].Audio != want {
+		t.Errorf("expected %+v, got %+v", want, posts[0].Audio)
+	}
+	if audio := posts[1].Audio; audio == nil || audio.Length != 0 || audio.Duration != time.Hour+2*time.Minute+3*time.Second {
+		t.Errorf("expected an episode of 1:02:03 of unknown size, got %+v", audio)
+	}
+	if posts[2].Audio != nil {
+		t.Errorf("an image enclosure should not make an episode, got %+v", posts[2].Audio)
+	}
+}
+
+// TestFilterByAuthor_KeepsPostsByAnyRequestedAuthor documents author filtering:
+// - Case-insensitive match on any of the post's authors
+// - No requested authors keeps every post
+func TestFilterByAuthor_KeepsPostsByAnyRequestedAuthor(t *testing.T) {
+	posts, _ := parseRSS([]byte(multiAuthorRSSXML), 0)
+
+	johns := FilterByAuthor(posts, []string{"john roe"})
+	if len(johns) != 1 || johns[0].Title != "Duet" {
+		t.Errorf("filtering by John Roe should keep only the co-authored post, got %+v", johns)
+	}
+	if got := FilterByAuthor(posts, nil); len(got) != len(posts) {
+		t.Errorf("no author filter should keep all %d posts, got %d", len(posts), len(got))
+	}
+}
+
+// TestClient_FetchPosts_ServesUnchangedFeedFromCache documents conditional GET:
+// - ETag and Last-Modified from a 200 are sent back on the next request
+// - a 304 Not Modified response is answered from the cached body
+func TestClient_FetchPosts_ServesUnchangedFeedFromCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 01 Jan 2024 12:00:00 GMT" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 12:00:00 GMT")
+		fmt.Fprint(w, validRSSXML)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCacheDir(t.TempDir()))
+	first, err := client.FetchPosts(context.Background(), server.URL, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := client.FetchPosts(context.Background(), server.URL, 10)
+	if err != nil {
+		t.Fatalf("unchanged feed should be served from cache, got error: %v", err)
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("each fetch should still revalidate with the server, got %d requests", requests.Load())
+	}
+	if len(second) != len(first) || second[0].Title != first[0].Title {
+		t.Errorf("cached posts should match the original response, got %+v", second)
+	}
+}
+
+// TestClient_FetchPosts_NotModifiedWithoutCacheIsAnError documents that a 304
+// without a cached body is reported rather than returning an empty feed.
+func TestClient_FetchPosts_NotModifiedWithoutCacheIsAnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithCacheDir(t.TempDir()))
+	if _, err := client.FetchPosts(context.Background(), server.URL, 10); err == nil {
+		t.Error("expected error for 304 without a cached feed")
+	}
+}
+
+// TestClient_FetchPosts_SendsPerPublicationHeaders documents custom headers:
+// - headers configured for a publication are sent with its feed request
+func TestClient_FetchPosts_SendsPerPublicationHeaders(t *testing.T) {
+	var cookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie = r.Header.Get("Cookie")
+		fmt.Fprint(w, validRSSXML)
+	}))
+	defer server.Close()
+
+	headers := func(publicationURL string) http.Header {
+		if publicationURL != server.URL {
+			return nil
+		}
+		return http.Header{"Cookie": {"substack.sid=secret"}}
+	}
+	client := NewClient(WithHeaders(headers))
+	if _, err := client.FetchPosts(context.Background(), server.URL, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cookie != "substack.sid=secret" {
+		t.Errorf("paid publication should receive its session cookie, got %q", cookie)
+	}
+}
+
+// TestClient_FetchNotes_ReturnsNotesOfProfile documents Notes fetching:
+// - Looks up the handle's profile, then reads notes from its activity feed
+// - Skips posts and restacks mixed into the feed, and applies the limit
+// - Titles a note with its first line, shortened
+func TestClient_FetchNotes_ReturnsNotesOfProfile(t *testing.T) {
+	long := strings.Repeat("word ", 30)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/user/jane/public_profile":
+			fmt.Fprint(w, `{"id":7,"name":"Jane Doe","handle":"jane"}`)
+		case "/api/v1/reader/feed/profile/7":
+			fmt.Fprintf(w, `{"items":[
+				{"type":"post","post":{"id":1}},
+				{"type":"comment","comment":{"id":42,"body":"\nFirst line\nsecond line","date":"2024-01-02T10:00:00Z","name":"Jane Doe","handle":"jane","reaction_count":3,"children_count":1}},
+				{"type":"comment","comment":{"id":43,"body":%q,"date":"2024-01-01T10:00:00Z"}},
+				{"type":"comment","comment":{"id":44,"body":"over the limit"}}
+			]}`, long)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	notes, err := NewClient(WithAPIURL(server.URL)).FetchNotes(context.Background(), "@jane", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("expected the 2 notes within the limit, got %+v", notes)
+	}
+	first := notes[0]
+	if first.ID != "c-42" || first.Title != "First line" || first.Body != "First line\nsecond line" || first.URL != "https://substack.com/@jane/note/c-42" {
+		t.Errorf("unexpected note: %+v", first)
+	}
+	if first.Likes != 3 || first.Replies != 1 || !first.PublishedAt.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("the note should keep its date and engagement, got %+v", first)
+	}
+	if second := notes[1]; second.Author != "Jane Doe" || second.Handle != "jane" || !strings.HasSuffix(second.Title, "…") || len([]rune(second.Title)) != 80 {
+		t.Errorf("a note without an author should get the profile's, and a long first line a shortened title, got %+v", second)
+	}
+
+	if _, err := NewClient(WithAPIURL(server.URL)).FetchNotes(context.Background(), "ghost", 0); err == nil {
+		t.Error("an unknown handle should be an error")
+	}
+}
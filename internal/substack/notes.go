@@ -0,0 +1,162 @@
+package substack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultAPIURL is the Substack site whose API serves profiles and notes.
+const DefaultAPIURL = "https://substack.com"
+
+// noteTitleLength caps the title a note gets from its first line, in runes.
+const noteTitleLength = 80
+
+// WithAPIURL overrides the Substack site used for notes (useful for testing).
+func WithAPIURL(url string) ClientOption {
+	return func(c *Client) {
+		c.apiURL = url
+	}
+}
+
+// Note is a Substack Note, a short-form post on a writer's profile.
+type Note struct {
+	ID string
+	// Title is the note's first line, shortened; notes have no title of
+	// their own.
+	Title string
+	// Body is the note's text, with its line breaks.
+	Body        string
+	Author      string
+	Handle      string
+	URL         string
+	PublishedAt time.Time
+	Likes       int64
+	Replies     int64
+}
+
+// FetchNotes fetches recent notes by the writer with the given handle
+// ("jane" for substack.com/@jane), newest first, up to limit (0 for all on
+// the first page). Notes have no feed; they come from the JSON API behind
+// the writer's profile page, which isn't documented and may change.
+func (c *Client) FetchNotes(ctx context.Context, handle string, limit int) ([]Note, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+
+	var profile struct {
+		ID     int64  `json:"id"`
+		Name   string `json:"name"`
+		Handle string `json:"handle"`
+	}
+	if err := c.getJSON(ctx, "/api/v1/user/"+url.PathEscape(handle)+"/public_profile", &profile); err != nil {
+		return nil, fmt.Errorf("failed to look up Substack profile @%s: %w", handle, err)
+	}
+	if profile.ID == 0 {
+		return nil, fmt.Errorf("substack profile @%s not found", handle)
+	}
+
+	var page struct {
+		Items []struct {
+			Type    string `json:"type"`
+			Comment *struct {
+				ID            int64  `json:"id"`
+				Body          string `json:"body"`
+				Date          string `json:"date"`
+				Name          string `json:"name"`
+				Handle        string `json:"handle"`
+				ReactionCount int64  `json:"reaction_count"`
+				ChildrenCount int64  `json:"children_count"`
+			} `json:"comment"`
+		} `json:"items"`
+	}
+	if err := c.getJSON(ctx, "/api/v1/reader/feed/profile/"+strconv.FormatInt(profile.ID, 10), &page); err != nil {
+		return nil, fmt.Errorf("failed to fetch Substack notes of @%s: %w", handle, err)
+	}
+
+	var notes []Note
+	for _, item := range page.Items {
+		comment := item.Comment
+		if item.Type != "comment" || comment == nil || strings.TrimSpace(comment.Body) == "" {
+			continue
+		}
+		author, authorHandle := comment.Name, comment.Handle
+		if author == "" {
+			author = profile.Name
+		}
+		if authorHandle == "" {
+			authorHandle = profile.Handle
+		}
+		if authorHandle == "" {
+			authorHandle = handle
+		}
+		id := "c-" + strconv.FormatInt(comment.ID, 10)
+		published, _ := time.Parse(time.RFC3339, comment.Date)
+		notes = append(notes, Note{
+			ID:          id,
+			Title:       noteTitle(comment.Body),
+			Body:        strings.TrimSpace(comment.Body),
+			Author:      author,
+			Handle:      authorHandle,
+			URL:         "https://substack.com/@" + authorHandle + "/note/" + id,
+			PublishedAt: published,
+			Likes:       comment.ReactionCount,
+			Replies:     comment.ChildrenCount,
+		})
+		if limit > 0 && len(notes) == limit {
+			break
+		}
+	}
+	return notes, nil
+}
+
+// getJSON decodes the response to a GET of path on the API site into v.
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	base := c.apiURL
+	if base == "" {
+		base = DefaultAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(base, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.headers != nil {
+		for name, values := range c.headers(base) {
+			req.Header[name] = values
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("substack API returned HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// noteTitle returns the first non-empty line of body, shortened to
+// noteTitleLength runes.
+func noteTitle(body string) string {
+	var title string
+	for _, line := range strings.Split(body, "\n") {
+		if title = strings.Join(strings.Fields(line), " "); title != "" {
+			break
+		}
+	}
+	if utf8.RuneCountInString(title) <= noteTitleLength {
+		return title
+	}
+	runes := []rune(title)[:noteTitleLength-1]
+	return strings.TrimRight(string(runes), " ") + "…"
+}
@@ -1,7 +1,10 @@
 // Package substack provides a client for fetching Substack publication RSS feeds.
 package substack
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Post represents a Substack newsletter post.
 type Post struct {
@@ -9,6 +12,75 @@ type Post struct {
 	Title       string
 	Description string
 	Author      string
+	Authors     []string
+	Publication string
 	URL         string
 	PublishedAt time.Time
+	// Content is the full post as HTML, from content:encoded; empty when
+	// the feed only carries an excerpt, as for paywalled posts.
+	Content string
+	// Paywalled is set on posts for paid subscribers whose content stops
+	// at the paywall for this feed.
+	Paywalled bool
+	// Audio is the episode of a podcast post, from its enclosure; nil for
+	// other posts.
+	Audio *Audio
+}
+
+// Audio is a podcast episode's audio file.
+type Audio struct {
+	URL string
+	// Type is the MIME type, e.g. audio/mpeg.
+	Type string
+	// Length is the size of the file in bytes, or 0 when unknown.
+	Length int64
+	// Duration is 0 when the feed doesn't give it.
+	Duration time.Duration
+}
+
+// paywallMarkers appear where Substack cuts a paid post short in its feed:
+// the paywall element itself, or the call to subscribe that replaces it.
+var paywallMarkers = []string{
+	`class="paywall`,
+	`data-component-name="PaywallToDOM"`,
+	"This post is for paid subscribers",
+	"This post is for paying subscribers",
+	"Keep reading with a 7-day free trial",
+}
+
+// paywalled reports whether content is the truncated preview of a paid
+// post. A paid post fetched with a subscriber's cookie has no marker.
+func paywalled(content string) bool {
+	for _, marker := range paywallMarkers {
+		if strings.Contains(content, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByAuthor returns the posts written (or co-written) by any of authors.
+// Matching is case-insensitive. An empty authors list returns posts unchanged.
+func FilterByAuthor(posts []Post, authors []string) []Post {
+	if len(authors) == 0 {
+		return posts
+	}
+	filtered := make([]Post, 0, len(posts))
+	for _, post := range posts {
+		if post.writtenByAny(authors) {
+			filtered = append(filtered, post)
+		}
+	}
+	return filtered
+}
+
+func (p Post) writtenByAny(authors []string) bool {
+	for _, have := range p.Authors {
+		for _, want := range authors {
+			if strings.EqualFold(have, strings.TrimSpace(want)) {
+				return true
+			}
+		}
+	}
+	return false
 }
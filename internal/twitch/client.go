@@ -0,0 +1,263 @@
+// Package twitch provides a client for the Twitch Helix API: the channels a
+// user follows, which of them are live, and their past broadcasts.
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+// DefaultBaseURL is the Helix API endpoint.
+const DefaultBaseURL = "https://api.twitch.tv/helix"
+
+// pageSize is the most items Helix returns per page.
+const pageSize = 100
+
+// User is a Twitch account.
+type User struct {
+	ID    string
+	Login string
+	Name  string
+}
+
+// Stream is a live broadcast.
+type Stream struct {
+	ID        string
+	UserID    string
+	UserLogin string
+	UserName  string
+	Game      string
+	Title     string
+	Viewers   int64
+	StartedAt time.Time
+	// Thumbnail is a URL template with {width} and {height} placeholders.
+	Thumbnail string
+}
+
+// Video is a past broadcast (VOD).
+type Video struct {
+	ID          string
+	UserLogin   string
+	UserName    string
+	Title       string
+	Description string
+	URL         string
+	Views       int64
+	CreatedAt   time.Time
+	Duration    time.Duration
+	// Thumbnail is a URL template with %{width} and %{height} placeholders.
+	Thumbnail string
+	// StreamID is the broadcast the video records; while it is live, the
+	// video is still being recorded.
+	StreamID string
+}
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the Helix API endpoint (useful for testing).
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// Client calls the Helix API on behalf of a user.
+type Client struct {
+	httpClient HTTPClient
+	baseURL    string
+	clientID   string
+	tokens     oauth.TokenSource
+}
+
+// NewClient creates a Helix client for the application clientID, with the
+// user's tokens.
+func NewClient(clientID string, tokens oauth.TokenSource, opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, baseURL: DefaultBaseURL, clientID: clientID, tokens: tokens}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CurrentUser returns the user the tokens belong to.
+func (c *Client) CurrentUser(ctx context.Context) (User, error) {
+	var resp struct {
+		Data []struct {
+			ID          string `json:"id"`
+			Login       string `json:"login"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/users", nil, &resp); err != nil {
+		return User{}, err
+	}
+	if len(resp.Data) == 0 {
+		return User{}, fmt.Errorf("twitch returned no user for the token")
+	}
+	user := resp.Data[0]
+	return User{ID: user.ID, Login: user.Login, Name: user.DisplayName}, nil
+}
+
+// FollowedChannels returns every channel userID follows.
+func (c *Client) FollowedChannels(ctx context.Context, userID string) ([]User, error) {
+	var channels []User
+	cursor := ""
+	for {
+		var resp struct {
+			Data []struct {
+				ID    string `json:"broadcaster_id"`
+				Login string `json:"broadcaster_login"`
+				Name  string `json:"broadcaster_name"`
+			} `json:"data"`
+			Pagination pagination `json:"pagination"`
+		}
+		query := url.Values{"user_id": {userID}, "first": {strconv.Itoa(pageSize)}}
+		if cursor != "" {
+			query.Set("after", cursor)
+		}
+		if err := c.get(ctx, "/channels/followed", query, &resp); err != nil {
+			return nil, err
+		}
+		for _, channel := range resp.Data {
+			channels = append(channels, User{ID: channel.ID, Login: channel.Login, Name: channel.Name})
+		}
+		if cursor = resp.Pagination.Cursor; cursor == "" || len(resp.Data) == 0 {
+			return channels, nil
+		}
+	}
+}
+
+// FollowedStreams returns the live streams of the channels userID follows.
+func (c *Client) FollowedStreams(ctx context.Context, userID string) ([]Stream, error) {
+	var streams []Stream
+	cursor := ""
+	for {
+		var resp struct {
+			Data []struct {
+				ID           string    `json:"id"`
+				UserID       string    `json:"user_id"`
+				UserLogin    string    `json:"user_login"`
+				UserName     string    `json:"user_name"`
+				GameName     string    `json:"game_name"`
+				Title        string    `json:"title"`
+				ViewerCount  int64     `json:"viewer_count"`
+				StartedAt    time.Time `json:"started_at"`
+				ThumbnailURL string    `json:"thumbnail_url"`
+			} `json:"data"`
+			Pagination pagination `json:"pagination"`
+		}
+		query := url.Values{"user_id": {userID}, "first": {strconv.Itoa(pageSize)}}
+		if cursor != "" {
+			query.Set("after", cursor)
+		}
+		if err := c.get(ctx, "/streams/followed", query, &resp); err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Data {
+			streams = append(streams, Stream{
+				ID: s.ID, UserID: s.UserID, UserLogin: s.UserLogin, UserName: s.UserName, Game: s.GameName,
+				Title: s.Title, Viewers: s.ViewerCount, StartedAt: s.StartedAt, Thumbnail: s.ThumbnailURL,
+			})
+		}
+		if cursor = resp.Pagination.Cursor; cursor == "" || len(resp.Data) == 0 {
+			return streams, nil
+		}
+	}
+}
+
+// RecentVideos returns the latest past broadcasts of channelID, newest
+// first, up to limit.
+func (c *Client) RecentVideos(ctx context.Context, channelID string, limit int) ([]Video, error) {
+	var resp struct {
+		Data []struct {
+			ID           string    `json:"id"`
+			UserLogin    string    `json:"user_login"`
+			UserName     string    `json:"user_name"`
+			Title        string    `json:"title"`
+			Description  string    `json:"description"`
+			URL          string    `json:"url"`
+			ViewCount    int64     `json:"view_count"`
+			CreatedAt    time.Time `json:"created_at"`
+			Duration     string    `json:"duration"`
+			ThumbnailURL string    `json:"thumbnail_url"`
+			StreamID     string    `json:"stream_id"`
+		} `json:"data"`
+	}
+	query := url.Values{"user_id": {channelID}, "type": {"archive"}, "first": {strconv.Itoa(min(max(limit, 1), pageSize))}}
+	if err := c.get(ctx, "/videos", query, &resp); err != nil {
+		return nil, err
+	}
+	videos := make([]Video, 0, len(resp.Data))
+	for _, v := range resp.Data {
+		duration, _ := time.ParseDuration(v.Duration)
+		videos = append(videos, Video{
+			ID: v.ID, UserLogin: v.UserLogin, UserName: v.UserName, Title: v.Title, Description: v.Description,
+			URL: v.URL, Views: v.ViewCount, CreatedAt: v.CreatedAt, Duration: duration, Thumbnail: v.ThumbnailURL,
+			StreamID: v.StreamID,
+		})
+	}
+	return videos, nil
+}
+
+type pagination struct {
+	Cursor string `json:"cursor"`
+}
+
+// get decodes the response to a GET of path with query into v.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	endpoint := strings.TrimRight(c.baseURL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get Twitch token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Client-Id", c.clientID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != "" {
+			return fmt.Errorf("twitch API returned HTTP %d for %s: %s", resp.StatusCode, path, apiErr.Message)
+		}
+		return fmt.Errorf("twitch API returned HTTP %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", path, err)
+	}
+	return nil
+}
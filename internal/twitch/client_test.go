@@ -0,0 +1,88 @@
+package twitch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+type staticTokens struct{}
+
+func (staticTokens) Token(context.Context) (*oauth.Token, error) {
+	return &oauth.Token{AccessToken: "access"}, nil
+}
+
+// TestClient_FollowedChannels documents calling Helix:
+// - every request carries the user's token and the application's client ID
+// - followed channels are read across pages until the cursor runs out
+func TestClient_FollowedChannels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" || r.Header.Get("Client-Id") != "app" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "invalid token"})
+			return
+		}
+		if r.URL.Query().Get("user_id") != "42" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		page := map[string]interface{}{
+			"data":       []map[string]string{{"broadcaster_id": "1", "broadcaster_login": "alpha", "broadcaster_name": "Alpha"}},
+			"pagination": map[string]string{"cursor": "next"},
+		}
+		if r.URL.Query().Get("after") == "next" {
+			page = map[string]interface{}{
+				"data":       []map[string]string{{"broadcaster_id": "2", "broadcaster_login": "beta", "broadcaster_name": "Beta"}},
+				"pagination": map[string]string{},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	channels, err := NewClient("app", staticTokens{}, WithBaseURL(server.URL)).FollowedChannels(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(channels) != 2 || channels[0].Login != "alpha" || channels[1].Name != "Beta" {
+		t.Errorf("expected the channels of both pages, got %+v", channels)
+	}
+
+	_, err = NewClient("other", staticTokens{}, WithBaseURL(server.URL)).FollowedChannels(context.Background(), "42")
+	if err == nil || err.Error() != "twitch API returned HTTP 401 for /channels/followed: invalid token" {
+		t.Errorf("expected Twitch's error message, got %v", err)
+	}
+}
+
+func TestClient_RecentVideos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("type") != "archive" || q.Get("first") != "3" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{
+				"id": "v1", "user_login": "alpha", "user_name": "Alpha", "title": "Speedrun", "url": "https://www.twitch.tv/videos/v1",
+				"view_count": 1200, "created_at": "2024-01-15T10:00:00Z", "duration": "1h2m3s", "stream_id": "s1",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	videos, err := NewClient("app", staticTokens{}, WithBaseURL(server.URL)).RecentVideos(context.Background(), "1", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(videos) != 1 {
+		t.Fatalf("expected 1 video, got %+v", videos)
+	}
+	video := videos[0]
+	if video.Duration != time.Hour+2*time.Minute+3*time.Second || video.Views != 1200 || video.StreamID != "s1" {
+		t.Errorf("expected the duration, views and stream of the broadcast, got %+v", video)
+	}
+}
@@ -0,0 +1,129 @@
+// Package wayback provides a client for the Internet Archive's Wayback
+// Machine, finding archived copies of pages and archiving pages with Save
+// Page Now.
+package wayback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the Wayback Machine's API endpoint.
+const DefaultBaseURL = "https://archive.org"
+
+// DefaultSaveURL is where Save Page Now archives pages and serves them.
+const DefaultSaveURL = "https://web.archive.org"
+
+// HTTPClient interface for making HTTP requests (allows injection for testing).
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(httpClient HTTPClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the Wayback Machine API endpoint (useful for testing).
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithSaveURL overrides the Save Page Now endpoint (useful for testing).
+func WithSaveURL(url string) ClientOption {
+	return func(c *Client) {
+		c.saveURL = url
+	}
+}
+
+// Client looks pages up on the Wayback Machine and archives them.
+type Client struct {
+	httpClient HTTPClient
+	baseURL    string
+	saveURL    string
+}
+
+// NewClient creates a new Wayback Machine client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{httpClient: &http.Client{}, baseURL: DefaultBaseURL, saveURL: DefaultSaveURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type availabilityResponse struct {
+	ArchivedSnapshots struct {
+		Closest struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+// Closest returns the URL of the archived copy of page closest to now, or
+// "" if the Wayback Machine has none.
+func (c *Client) Closest(ctx context.Context, page string) (string, error) {
+	endpoint := c.baseURL + "/wayback/available?" + url.Values{"url": {page}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("wayback machine returned HTTP %d", resp.StatusCode)
+	}
+
+	var body availabilityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse wayback machine response: %w", err)
+	}
+	if !body.ArchivedSnapshots.Closest.Available {
+		return "", nil
+	}
+	return body.ArchivedSnapshots.Closest.URL, nil
+}
+
+// Save archives page with Save Page Now and returns the URL of the new
+// snapshot. Archiving a page can take a minute.
+func (c *Client) Save(ctx context.Context, page string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.saveURL+"/save/"+page, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return "", fmt.Errorf("wayback machine is busy archiving your earlier pages: try again later")
+	default:
+		return "", fmt.Errorf("wayback machine returned HTTP %d", resp.StatusCode)
+	}
+
+	if location := resp.Header.Get("Content-Location"); strings.HasPrefix(location, "/web/") {
+		return c.saveURL + location, nil
+	}
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/web/") {
+		return resp.Request.URL.String(), nil
+	}
+	return "", fmt.Errorf("wayback machine didn't say where it archived %s", page)
+}
@@ -0,0 +1,67 @@
+package wayback
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Closest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wayback/available" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("url") == "https://example.com/post?id=1" {
+			fmt.Fprint(w, `{"archived_snapshots":{"closest":{"available":true,"url":"http://web.archive.org/web/20240115120000/https://example.com/post?id=1","status":"200"}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"archived_snapshots":{}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithHTTPClient(server.Client()), WithBaseURL(server.URL))
+	got, err := client.Closest(context.Background(), "https://example.com/post?id=1")
+	if err != nil || got != "http://web.archive.org/web/20240115120000/https://example.com/post?id=1" {
+		t.Errorf("expected the archived copy, got %q (err %v)", got, err)
+	}
+	if got, err := client.Closest(context.Background(), "https://example.com/never"); err != nil || got != "" {
+		t.Errorf("a page never archived should have no copy, got %q (err %v)", got, err)
+	}
+}
+
+// TestClient_Save documents Save Page Now:
+//   - the snapshot is where the Content-Location header says, or where the
+//     save redirected to
+//   - a busy Wayback Machine is reported as such
+func TestClient_Save(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/save/https://example.com/post":
+			w.Header().Set("Content-Location", "/web/20240115120000/https://example.com/post")
+		case r.URL.Path == "/save/https://example.com/moved":
+			w.Header().Set("Location", "/web/20240115120000/https://example.com/moved")
+			w.WriteHeader(http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/web/"):
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithHTTPClient(server.Client()), WithSaveURL(server.URL))
+	for page, want := range map[string]string{
+		"https://example.com/post":  server.URL + "/web/20240115120000/https://example.com/post",
+		"https://example.com/moved": server.URL + "/web/20240115120000/https://example.com/moved",
+	} {
+		if got, err := client.Save(context.Background(), page); err != nil || got != want {
+			t.Errorf("%s: expected snapshot %s, got %q (err %v)", page, want, got, err)
+		}
+	}
+	if _, err := client.Save(context.Background(), "https://example.com/busy"); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Errorf("a rate-limited save should be reported, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Availability of a video: its privacy status, or VideoRemoved.
+const (
+	VideoPublic   = "public"
+	VideoUnlisted = "unlisted"
+	VideoPrivate  = "private"
+	VideoRemoved  = "removed"
+)
+
+// videosPerRequest is the most IDs videos.list accepts at once.
+const videosPerRequest = 50
+
+type videoStatusResponse struct {
+	Items []struct {
+		ID     string `json:"id"`
+		Status struct {
+			PrivacyStatus string `json:"privacyStatus"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// FetchAvailability returns the availability of each video, keyed by
+// video ID. Videos YouTube no longer returns, deleted ones and others'
+// private ones, are VideoRemoved.
+func (c *Client) FetchAvailability(ctx context.Context, videoIDs []string) (map[string]string, error) {
+	availability := make(map[string]string, len(videoIDs))
+	for batch := range slices.Chunk(videoIDs, videosPerRequest) {
+		body, err := c.doRequest(ctx, newRequest(videosEndpoint, "status").ids("id", batch))
+		if err != nil {
+			return nil, err
+		}
+		var response videoStatusResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse videos response: %w", err)
+		}
+		for _, id := range batch {
+			availability[id] = VideoRemoved
+		}
+		for _, item := range response.Items {
+			availability[item.ID] = item.Status.PrivacyStatus
+		}
+	}
+	return availability, nil
+}
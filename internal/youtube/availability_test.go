@@ -0,0 +1,30 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func TestClient_FetchAvailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/youtube/v3/videos" || r.URL.Query().Get("part") != "status" || r.URL.Query().Get("id") != "v1,v2,v3" {
+			t.Errorf("expected one videos.list call for the statuses, got %s", r.URL)
+		}
+		fmt.Fprint(w, `{"items":[{"id":"v1","status":{"privacyStatus":"public"}},{"id":"v2","status":{"privacyStatus":"unlisted"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL))
+	got, err := client.FetchAvailability(context.Background(), []string{"v1", "v2", "v3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["v1"] != VideoPublic || got["v2"] != VideoUnlisted || got["v3"] != VideoRemoved {
+		t.Errorf("expected public, unlisted and removed videos, got %v", got)
+	}
+}
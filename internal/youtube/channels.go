@@ -0,0 +1,146 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// channelTTL is how long looked-up channel details are trusted. Channels
+// rarely change their handle or topics, so lookups are repeated about once a
+// month.
+const channelTTL = 30 * 24 * time.Hour
+
+// channelsPerRequest is the most IDs channels.list accepts at once.
+const channelsPerRequest = 50
+
+// WithChannelCache keeps channel details looked up by FetchChannels in the
+// file at path, so later runs don't request them again.
+func WithChannelCache(path string) ClientOption {
+	return func(c *Client) {
+		c.channelCache = path
+	}
+}
+
+type channelEntry struct {
+	ChannelDetails
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// FetchChannels returns the details of each channel, keyed by channel ID.
+func (c *Client) FetchChannels(ctx context.Context, channelIDs []string) (map[string]ChannelDetails, error) {
+	entries, err := c.loadChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	var missing []string
+	for _, id := range channelIDs {
+		if entry, ok := entries[id]; !ok || now.Sub(entry.FetchedAt) > channelTTL {
+			missing = append(missing, id)
+		}
+	}
+
+	for start := 0; start < len(missing); start += channelsPerRequest {
+		batch := missing[start:min(start+channelsPerRequest, len(missing))]
+		details, err := c.fetchChannels(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range batch {
+			entries[id] = channelEntry{ChannelDetails: details[id], FetchedAt: now}
+		}
+	}
+	if len(missing) > 0 {
+		if err := c.saveChannels(entries); err != nil {
+			return nil, err
+		}
+	}
+
+	channels := make(map[string]ChannelDetails, len(channelIDs))
+	for _, id := range channelIDs {
+		channels[id] = entries[id].ChannelDetails
+	}
+	return channels, nil
+}
+
+func (c *Client) fetchChannels(ctx context.Context, channelIDs []string) (map[string]ChannelDetails, error) {
+	req := newRequest(channelsEndpoint, "snippet", "topicDetails").ids("id", channelIDs).maxResults(channelsPerRequest)
+	body, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response channelsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse channels response: %w", err)
+	}
+
+	details := make(map[string]ChannelDetails, len(response.Items))
+	for _, item := range response.Items {
+		var channel ChannelDetails
+		if handle := item.Snippet.CustomURL; handle != "" {
+			channel.Handle = "@" + strings.TrimPrefix(handle, "@")
+		}
+		for _, topic := range item.TopicDetails.TopicCategories {
+			channel.Topics = append(channel.Topics, topic[strings.LastIndex(topic, "/")+1:])
+		}
+		details[item.ID] = channel
+	}
+	return details, nil
+}
+
+func (c *Client) loadChannels() (map[string]channelEntry, error) {
+	entries := make(map[string]channelEntry)
+	if c.channelCache == "" {
+		return entries, nil
+	}
+
+	data, err := os.ReadFile(c.channelCache) // #nosec G304 - path is the channel cache in the user's cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channel cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse channel cache: %w", err)
+	}
+	return entries, nil
+}
+
+func (c *Client) saveChannels(entries map[string]channelEntry) error {
+	if c.channelCache == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.channelCache), 0700); err != nil {
+		return fmt.Errorf("failed to create channel cache directory: %w", err)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode channel cache: %w", err)
+	}
+	if err := os.WriteFile(c.channelCache, data, 0600); err != nil {
+		return fmt.Errorf("failed to write channel cache: %w", err)
+	}
+	return nil
+}
+
+type channelsResponse struct {
+	Items []struct {
+		ID      string `json:"id"`
+		Snippet struct {
+			CustomURL string `json:"customUrl"`
+		} `json:"snippet"`
+		TopicDetails struct {
+			TopicCategories []string `json:"topicCategories"`
+		} `json:"topicDetails"`
+	} `json:"items"`
+}
@@ -0,0 +1,78 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+// TestClient_FetchChannels documents channel detail lookup:
+// - Handles come from channels.list snippet.customUrl, always with a leading @
+// - Topics are the article names of topicDetails.topicCategories
+// - Details are cached, so a second lookup makes no request
+func TestClient_FetchChannels(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/youtube/v3/channels" || r.URL.Query().Get("id") != "UC1,UC2,UC3" {
+			t.Errorf("expected one channels.list call for all IDs, got %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"id": "UC1", "snippet": map[string]interface{}{"customUrl": "@first"}, "topicDetails": map[string]interface{}{
+					"topicCategories": []string{"https://en.wikipedia.org/wiki/Video_game_culture", "https://en.wikipedia.org/wiki/Entertainment"},
+				}},
+				{"id": "UC2", "snippet": map[string]interface{}{"customUrl": "legacyname"}},
+				{"id": "UC3", "snippet": map[string]interface{}{}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cache := filepath.Join(t.TempDir(), "handles.json")
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL), WithChannelCache(cache))
+
+	channels, err := client.FetchChannels(context.Background(), []string{"UC1", "UC2", "UC3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channels["UC1"].Handle != "@first" || channels["UC2"].Handle != "@legacyname" || channels["UC3"].Handle != "" {
+		t.Errorf("expected @-prefixed handles, got %v", channels)
+	}
+	if topics := channels["UC1"].Topics; len(topics) != 2 || topics[0] != "Video_game_culture" {
+		t.Errorf("expected topic article names, got %v", topics)
+	}
+
+	client = NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL), WithChannelCache(cache))
+	if channels, err := client.FetchChannels(context.Background(), []string{"UC1", "UC3"}); err != nil || channels["UC1"].Handle != "@first" || len(channels["UC1"].Topics) != 2 {
+		t.Errorf("cached details should be returned, got %v (err %v)", channels, err)
+	}
+	if requests != 1 {
+		t.Errorf("cached details should not be requested again, got %d requests", requests)
+	}
+}
+
+func TestTopicGroup_PicksMostSpecificGroup(t *testing.T) {
+	tests := []struct {
+		topics []string
+		want   string
+	}{
+		{[]string{"Entertainment", "Video_game_culture"}, GroupGaming},
+		{[]string{"Lifestyle_(sociology)", "Hip_hop_music"}, GroupMusic},
+		{[]string{"Role-playing_video_game"}, GroupGaming},
+		{[]string{"Technology", "Knowledge"}, GroupTech},
+		{[]string{"Unknown_topic"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := TopicGroup(tt.topics); got != tt.want {
+			t.Errorf("TopicGroup(%v) = %q, want %q", tt.topics, got, tt.want)
+		}
+	}
+}
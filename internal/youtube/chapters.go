@@ -0,0 +1,51 @@
+package youtube
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chapterLine matches a description line starting with a timestamp, such as
+// "0:00 Intro", "(1:02:03) Q&A" or "12:30 - Benchmarks".
+var chapterLine = regexp.MustCompile(`^[\[(]?((?:\d{1,2}:)?\d{1,2}:\d{2})[\])]?\s*(?:[-–—:|•.]\s*)?(\S.*)$`)
+
+// minChapters is the fewest timestamps YouTube turns into chapters.
+const minChapters = 3
+
+// ParseChapters returns the chapters listed in a video description, by the
+// rules YouTube applies: at least three timestamps in increasing order, the
+// first at 0:00. Descriptions that don't list chapters yield nil.
+func ParseChapters(description string) []Chapter {
+	var chapters []Chapter
+	for _, line := range strings.Split(description, "\n") {
+		match := chapterLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		start, ok := parseTimestamp(match[1])
+		if !ok || (len(chapters) > 0 && start <= chapters[len(chapters)-1].Start) || (len(chapters) == 0 && start != 0) {
+			return nil
+		}
+		chapters = append(chapters, Chapter{Start: start, Title: strings.TrimSpace(match[2])})
+	}
+	if len(chapters) < minChapters {
+		return nil
+	}
+	return chapters
+}
+
+// parseTimestamp reads m:ss or h:mm:ss.
+func parseTimestamp(s string) (time.Duration, bool) {
+	var total time.Duration
+	fields := strings.Split(s, ":")
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || (i > 0 && n >= 60) {
+			return 0, false
+		}
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second, true
+}
@@ -0,0 +1,38 @@
+package youtube
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseChapters_FollowsYouTubesRules(t *testing.T) {
+	description := "Everything about the new release.\n\n" +
+		"Chapters:\n" +
+		"0:00 Intro\n" +
+		"(1:35) Setup\n" +
+		"12:30 - Benchmarks\n" +
+		"1:02:03 Q&A\n\n" +
+		"Follow me at https://example.com"
+	want := []Chapter{
+		{Start: 0, Title: "Intro"},
+		{Start: 95 * time.Second, Title: "Setup"},
+		{Start: 12*time.Minute + 30*time.Second, Title: "Benchmarks"},
+		{Start: time.Hour + 2*time.Minute + 3*time.Second, Title: "Q&A"},
+	}
+	if got := ParseChapters(description); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for name, description := range map[string]string{
+		"no timestamps":        "Just a video.",
+		"too few":              "0:00 Intro\n5:00 Outro",
+		"not starting at 0:00": "0:30 Intro\n2:00 Middle\n5:00 Outro",
+		"out of order":         "0:00 Intro\n5:00 Middle\n2:00 Outro",
+		"invalid seconds":      "0:00 Intro\n2:75 Middle\n5:00 Outro",
+	} {
+		if got := ParseChapters(description); got != nil {
+			t.Errorf("%s: expected no chapters, got %v", name, got)
+		}
+	}
+}
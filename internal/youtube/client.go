@@ -4,19 +4,23 @@ package youtube
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+	"github.com/gauthierbraillon/feedmix/pkg/httpx"
 	"github.com/gauthierbraillon/feedmix/pkg/oauth"
 )
 
 const defaultBaseURL = "https://www.googleapis.com"
 
+var errQuotaExceeded = errors.New("YouTube API daily quota exceeded - it resets at midnight Pacific time (run 'feedmix quota' for usage)")
+
 // HTTPClient interface for making HTTP requests (allows injection for testing).
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -39,19 +43,79 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithRateLimiter throttles API requests through limiter, which may be shared between clients.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithQuotaMeter records the quota units spent by every request in meter.
+func WithQuotaMeter(meter *QuotaMeter) ClientOption {
+	return func(c *Client) {
+		c.quota = meter
+	}
+}
+
+// WithTokenSource obtains the access token for each request from source,
+// which may refresh it; it replaces the token passed to NewClient.
+func WithTokenSource(source oauth.TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokens = source
+	}
+}
+
+// WithAPIVersion calls the endpoint named resource, such as "videos", at
+// version instead of v3, to try a new API version one endpoint at a time.
+func WithAPIVersion(resource, version string) ClientOption {
+	return func(c *Client) {
+		if c.versions == nil {
+			c.versions = make(map[string]string)
+		}
+		c.versions[resource] = version
+	}
+}
+
+// WithKeyPool reads public data, everything but the user's subscriptions,
+// with the API keys of pool rather than the OAuth token.
+func WithKeyPool(pool *KeyPool) ClientOption {
+	return func(c *Client) {
+		c.keys = pool
+	}
+}
+
+// WithClock ages cached channel details against now instead of the system clock.
+func WithClock(now clock.Clock) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
 // Client is a YouTube Data API client.
 type Client struct {
-	token      *oauth.Token
-	baseURL    string
-	httpClient HTTPClient
+	tokens       oauth.TokenSource
+	baseURL      string
+	timedTextURL string
+	feedURL      string
+	httpClient   HTTPClient
+	limiter      *RateLimiter
+	quota        *QuotaMeter
+	keys         *KeyPool
+	channelCache string
+	versions     map[string]string
+	now          func() time.Time
 }
 
 // NewClient creates a new YouTube API client with the given OAuth token.
+// token may be nil when WithTokenSource is given.
 func NewClient(token *oauth.Token, opts ...ClientOption) *Client {
 	c := &Client{
-		token:      token,
-		baseURL:    defaultBaseURL,
-		httpClient: &http.Client{},
+		tokens:       oauth.StaticTokenSource(token),
+		baseURL:      defaultBaseURL,
+		timedTextURL: defaultTimedTextURL,
+		feedURL:      DefaultFeedURL,
+		httpClient:   &http.Client{},
+		now:          time.Now,
 	}
 
 	for _, opt := range opts {
@@ -63,9 +127,9 @@ func NewClient(token *oauth.Token, opts ...ClientOption) *Client {
 
 // FetchSubscriptions retrieves the authenticated user's subscriptions.
 func (c *Client) FetchSubscriptions(ctx context.Context) ([]Subscription, error) {
-	url := fmt.Sprintf("%s/youtube/v3/subscriptions?part=snippet&mine=true&maxResults=50", c.baseURL)
+	req := newRequest(subscriptionsEndpoint, "snippet").param("mine", "true").maxResults(50)
 
-	body, err := c.doRequest(ctx, url)
+	body, err := c.doRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -97,15 +161,13 @@ func (c *Client) FetchSubscriptions(ctx context.Context) ([]Subscription, error)
 
 // FetchRecentVideos retrieves recent videos from a channel.
 func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit int) ([]Video, error) {
-	params := url.Values{}
-	params.Set("part", "snippet")
-	params.Set("channelId", channelID)
-	params.Set("maxResults", strconv.Itoa(limit))
-	params.Set("order", "date")
-	params.Set("type", "video")
-	searchURL := fmt.Sprintf("%s/youtube/v3/search?%s", c.baseURL, params.Encode())
-
-	body, err := c.doRequest(ctx, searchURL)
+	search := newRequest(searchEndpoint, "snippet").
+		param("channelId", channelID).
+		maxResults(limit).
+		param("order", "date").
+		param("type", "video")
+
+	body, err := c.doRequest(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -121,22 +183,20 @@ func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit
 
 	videoIDs := make([]string, 0, len(searchResp.Items))
 	for _, item := range searchResp.Items {
-		videoIDs = append(videoIDs, item.ID.VideoID)
-	}
-
-	vParams := url.Values{}
-	vParams.Set("part", "statistics,contentDetails")
-	vParams.Set("id", strings.Join(videoIDs, ","))
-	videosURL := fmt.Sprintf("%s/youtube/v3/videos?%s", c.baseURL, vParams.Encode())
-
-	body, err = c.doRequest(ctx, videosURL)
-	if err != nil {
-		return nil, err
+		if item.ID.VideoID != "" {
+			videoIDs = append(videoIDs, item.ID.VideoID)
+		}
 	}
 
 	var videosResp videosResponse
-	if err := json.Unmarshal(body, &videosResp); err != nil {
-		return nil, fmt.Errorf("failed to parse videos response: %w", err)
+	if len(videoIDs) > 0 {
+		body, err = c.doRequest(ctx, newRequest(videosEndpoint, "snippet", "statistics", "contentDetails", "liveStreamingDetails").ids("id", videoIDs))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(body, &videosResp); err != nil {
+			return nil, fmt.Errorf("failed to parse videos response: %w", err)
+		}
 	}
 
 	statsMap := make(map[string]videoStats)
@@ -144,9 +204,14 @@ func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit
 		viewCount, _ := strconv.ParseInt(item.Statistics.ViewCount, 10, 64)
 		likeCount, _ := strconv.ParseInt(item.Statistics.LikeCount, 10, 64)
 		statsMap[item.ID] = videoStats{
-			viewCount: viewCount,
-			likeCount: likeCount,
-			duration:  item.ContentDetails.Duration,
+			viewCount:      viewCount,
+			likeCount:      likeCount,
+			duration:       item.ContentDetails.Duration,
+			ageRestricted:  item.ContentDetails.ContentRating.YTRating == "ytAgeRestricted",
+			allowedRegions: item.ContentDetails.RegionRestriction.Allowed,
+			blockedRegions: item.ContentDetails.RegionRestriction.Blocked,
+			broadcast:      item.LiveStreamingDetails.broadcast(item.ContentDetails.Duration),
+			chapters:       ParseChapters(item.Snippet.Description),
 		}
 	}
 
@@ -159,6 +224,9 @@ func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit
 		}
 
 		stats := statsMap[item.ID.VideoID]
+		if stats.broadcast == nil && (item.Snippet.LiveBroadcastContent == BroadcastUpcoming || item.Snippet.LiveBroadcastContent == BroadcastLive) {
+			stats.broadcast = &Broadcast{Status: item.Snippet.LiveBroadcastContent}
+		}
 		videos = append(videos, Video{
 			ID:           item.ID.VideoID,
 			Title:        item.Snippet.Title,
@@ -171,6 +239,12 @@ func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit
 			LikeCount:    stats.likeCount,
 			Duration:     stats.duration,
 			URL:          fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ID.VideoID),
+
+			AgeRestricted:  stats.ageRestricted,
+			AllowedRegions: stats.allowedRegions,
+			BlockedRegions: stats.blockedRegions,
+			Broadcast:      stats.broadcast,
+			Chapters:       stats.chapters,
 		})
 	}
 
@@ -179,10 +253,9 @@ func (c *Client) FetchRecentVideos(ctx context.Context, channelID string, limit
 
 // FetchLikedVideos retrieves videos the authenticated user has liked.
 func (c *Client) FetchLikedVideos(ctx context.Context, limit int) ([]LikedVideo, error) {
-	url := fmt.Sprintf("%s/youtube/v3/playlistItems?part=snippet&playlistId=LL&maxResults=%d",
-		c.baseURL, limit)
+	req := newRequest(playlistItemsEndpoint, "snippet").param("playlistId", "LL").maxResults(limit)
 
-	body, err := c.doRequest(ctx, url)
+	body, err := c.doRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -218,16 +291,60 @@ func (c *Client) FetchLikedVideos(ctx context.Context, limit int) ([]LikedVideo,
 	return videos, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
+// doRequest reads public data with an API key from the pool while one has
+// quota left, then with the OAuth token and its project's quota.
+func (c *Client) doRequest(ctx context.Context, call *request) ([]byte, error) {
+	if version, ok := c.versions[call.endpoint.path]; ok {
+		call.endpoint.version = version
+	}
+	url, err := call.url(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var key string
+		if c.keys != nil && !call.endpoint.private {
+			key, _ = c.keys.key()
+		}
+		body, err := c.send(ctx, call, url, key)
+		if key != "" && errors.Is(err, errQuotaExceeded) {
+			c.keys.exhausted(key)
+			continue
+		}
+		return body, err
+	}
+}
+
+// send makes one call to url, authorized with key if set, else with the
+// OAuth token. The key goes in a header rather than the URL, so responses
+// are cached whichever key fetched them.
+func (c *Client) send(ctx context.Context, call *request, url, key string) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token.AccessToken))
+	if key != "" {
+		req.Header.Set("X-Goog-Api-Key", key)
+	} else {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh token: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
+	if c.quota != nil && !httpx.FromCache(resp) {
+		c.quota.charge(call.endpoint.cost)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +356,9 @@ func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusForbidden && strings.Contains(string(body), "quotaExceeded") {
+			return nil, errQuotaExceeded
+		}
 		return nil, c.handleAPIError(resp.StatusCode)
 	}
 
@@ -281,23 +401,66 @@ type searchResponse struct {
 					URL string `json:"url"`
 				} `json:"default"`
 			} `json:"thumbnails"`
+			// LiveBroadcastContent is "upcoming", "live" or "none".
+			LiveBroadcastContent string `json:"liveBroadcastContent"`
 		} `json:"snippet"`
 	} `json:"items"`
 }
 
 type videosResponse struct {
 	Items []struct {
-		ID         string `json:"id"`
+		ID      string `json:"id"`
+		Snippet struct {
+			// Description is in full, where search results only carry its
+			// start.
+			Description string `json:"description"`
+		} `json:"snippet"`
 		Statistics struct {
 			ViewCount string `json:"viewCount"`
 			LikeCount string `json:"likeCount"`
 		} `json:"statistics"`
 		ContentDetails struct {
-			Duration string `json:"duration"`
+			Duration      string `json:"duration"`
+			ContentRating struct {
+				YTRating string `json:"ytRating"`
+			} `json:"contentRating"`
+			RegionRestriction struct {
+				Allowed []string `json:"allowed"`
+				Blocked []string `json:"blocked"`
+			} `json:"regionRestriction"`
 		} `json:"contentDetails"`
+		LiveStreamingDetails liveStreamingDetails `json:"liveStreamingDetails"`
 	} `json:"items"`
 }
 
+// liveStreamingDetails is only returned for premieres and live streams.
+type liveStreamingDetails struct {
+	ScheduledStartTime string `json:"scheduledStartTime"`
+	ScheduledEndTime   string `json:"scheduledEndTime"`
+	ActualStartTime    string `json:"actualStartTime"`
+	ActualEndTime      string `json:"actualEndTime"`
+}
+
+// broadcast returns when the premiere or live stream airs, or nil if the
+// video isn't one or it has ended. Live streams have no duration until
+// they end, while premieres air a video of known duration.
+func (d liveStreamingDetails) broadcast(duration string) *Broadcast {
+	if d.ActualEndTime != "" {
+		return nil
+	}
+	end, _ := time.Parse(time.RFC3339, d.ScheduledEndTime)
+	premiere := duration != "" && duration != "P0D"
+	if d.ActualStartTime != "" {
+		start, _ := time.Parse(time.RFC3339, d.ActualStartTime)
+		return &Broadcast{Status: BroadcastLive, Start: start, End: end, Premiere: premiere}
+	}
+	start, err := time.Parse(time.RFC3339, d.ScheduledStartTime)
+	if err != nil {
+		return nil
+	}
+	return &Broadcast{Status: BroadcastUpcoming, Start: start, End: end, Premiere: premiere}
+}
+
 type playlistItemsResponse struct {
 	Items []struct {
 		Snippet struct {
@@ -319,9 +482,14 @@ type playlistItemsResponse struct {
 }
 
 type videoStats struct {
-	viewCount int64
-	likeCount int64
-	duration  string
+	viewCount      int64
+	likeCount      int64
+	duration       string
+	ageRestricted  bool
+	allowedRegions []string
+	blockedRegions []string
+	broadcast      *Broadcast
+	chapters       []Chapter
 }
 
 func (c *Client) handleAPIError(statusCode int) error {
@@ -16,6 +16,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -126,11 +128,11 @@ func TestClient_FetchRecentVideos(t *testing.T) {
 					"videoId": "video123",
 				},
 				"snippet": map[string]interface{}{
-					"title":       "Test Video",
-					"description": "A test video",
-					"channelId":   "UC123",
+					"title":        "Test Video",
+					"description":  "A test video",
+					"channelId":    "UC123",
 					"channelTitle": "Test Channel",
-					"publishedAt": "2024-01-15T12:00:00Z",
+					"publishedAt":  "2024-01-15T12:00:00Z",
 					"thumbnails": map[string]interface{}{
 						"default": map[string]interface{}{
 							"url": "https://example.com/video-thumb.jpg",
@@ -212,11 +214,11 @@ func TestClient_FetchLikedVideos(t *testing.T) {
 					"resourceId": map[string]interface{}{
 						"videoId": "liked123",
 					},
-					"title":       "Liked Video",
-					"description": "A liked video",
-					"channelId":   "UC456",
+					"title":        "Liked Video",
+					"description":  "A liked video",
+					"channelId":    "UC456",
 					"channelTitle": "Another Channel",
-					"publishedAt": "2024-01-10T08:00:00Z",
+					"publishedAt":  "2024-01-10T08:00:00Z",
 					"thumbnails": map[string]interface{}{
 						"default": map[string]interface{}{
 							"url": "https://example.com/liked-thumb.jpg",
@@ -347,3 +349,172 @@ func TestClient_FetchRecentVideos_URLEncodesChannelID(t *testing.T) {
 		t.Error("channel ID must be URL-encoded in the query string to prevent parameter injection")
 	}
 }
+
+func TestClient_WithAPIVersion_MovesOneEndpoint(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	}))
+	defer server.Close()
+
+	token := &oauth.Token{AccessToken: "test-token", TokenType: "Bearer"}
+	client := NewClient(token, WithBaseURL(server.URL), WithAPIVersion("search", "v4"))
+	_, _ = client.FetchRecentVideos(context.Background(), "UC_A", 5)
+	_, _ = client.FetchSubscriptions(context.Background())
+
+	want := []string{"/youtube/v4/search", "/youtube/v3/subscriptions"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("expected requests to %v, got %v", want, paths)
+	}
+}
+
+// TestClient_FetchRecentVideos_ReportsRestrictions documents playback restrictions:
+// - contentRating.ytRating "ytAgeRestricted" marks a video age-restricted
+// - regionRestriction allowed/blocked lists decide where a video plays
+func TestClient_FetchRecentVideos_ReportsRestrictions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/search") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+				{"id": map[string]interface{}{"videoId": "adult"}},
+				{"id": map[string]interface{}{"videoId": "usonly"}},
+				{"id": map[string]interface{}{"videoId": "notde"}},
+			}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+			{"id": "adult", "contentDetails": map[string]interface{}{"contentRating": map[string]interface{}{"ytRating": "ytAgeRestricted"}}},
+			{"id": "usonly", "contentDetails": map[string]interface{}{"regionRestriction": map[string]interface{}{"allowed": []string{"US"}}}},
+			{"id": "notde", "contentDetails": map[string]interface{}{"regionRestriction": map[string]interface{}{"blocked": []string{"DE"}}}},
+		}})
+	}))
+	defer server.Close()
+
+	videos, err := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL)).FetchRecentVideos(context.Background(), "UC123", 5)
+	if err != nil || len(videos) != 3 {
+		t.Fatalf("expected 3 videos, got %d (err %v)", len(videos), err)
+	}
+	if !videos[0].AgeRestricted || videos[1].AgeRestricted {
+		t.Error("only the ytAgeRestricted video should be age-restricted")
+	}
+	if videos[1].PlayableIn("FR") || !videos[1].PlayableIn("US") {
+		t.Error("a video with an allow list should only play in the listed regions")
+	}
+	if videos[2].PlayableIn("DE") || !videos[2].PlayableIn("FR") {
+		t.Error("a video with a block list should play everywhere but the listed regions")
+	}
+	if !videos[2].PlayableIn("") {
+		t.Error("an unknown region should not flag videos")
+	}
+}
+
+func TestClient_FetchRecentVideos_ReportsBroadcasts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/search") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+				{"id": map[string]interface{}{"videoId": "premiere"}},
+				{"id": map[string]interface{}{"videoId": "onair"}},
+				{"id": map[string]interface{}{"videoId": "ended"}},
+				{"id": map[string]interface{}{"videoId": "upload"}},
+			}})
+			return
+		}
+		if !strings.Contains(r.URL.Query().Get("part"), "liveStreamingDetails") {
+			t.Errorf("videos should be fetched with their live streaming details, got part=%s", r.URL.Query().Get("part"))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+			{"id": "premiere", "liveStreamingDetails": map[string]interface{}{"scheduledStartTime": "2024-01-20T18:00:00Z", "scheduledEndTime": "2024-01-20T19:30:00Z"}},
+			{"id": "onair", "liveStreamingDetails": map[string]interface{}{"scheduledStartTime": "2024-01-15T11:00:00Z", "actualStartTime": "2024-01-15T11:05:00Z"}},
+			{"id": "ended", "liveStreamingDetails": map[string]interface{}{"actualStartTime": "2024-01-10T11:00:00Z", "actualEndTime": "2024-01-10T12:00:00Z"}},
+			{"id": "upload"},
+		}})
+	}))
+	defer server.Close()
+
+	videos, err := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL)).FetchRecentVideos(context.Background(), "UC123", 5)
+	if err != nil || len(videos) != 4 {
+		t.Fatalf("expected 4 videos, got %d (err %v)", len(videos), err)
+	}
+	want := Broadcast{Status: BroadcastUpcoming, Start: time.Date(2024, 1, 20, 18, 0, 0, 0, time.UTC), End: time.Date(2024, 1, 20, 19, 30, 0, 0, time.UTC)}
+	if got := videos[0].Broadcast; got == nil || *got != want {
+		t.Errorf("a scheduled premiere should be upcoming at its scheduled times, got %+v", got)
+	}
+	if got := videos[1].Broadcast; got == nil || got.Status != BroadcastLive || !got.Start.Equal(time.Date(2024, 1, 15, 11, 5, 0, 0, time.UTC)) {
+		t.Errorf("a stream on air should be live since its actual start, got %+v", got)
+	}
+	if videos[2].Broadcast != nil || videos[3].Broadcast != nil {
+		t.Error("ended streams and uploads should not be broadcasts")
+	}
+}
+
+func TestClient_FetchRecentVideos_TellsPremieresFromLiveStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/search") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+				{"id": map[string]interface{}{"videoId": "premiere"}, "snippet": map[string]interface{}{"liveBroadcastContent": "upcoming"}},
+				{"id": map[string]interface{}{"videoId": "stream"}, "snippet": map[string]interface{}{"liveBroadcastContent": "upcoming"}},
+				{"id": map[string]interface{}{"videoId": "nodetails"}, "snippet": map[string]interface{}{"liveBroadcastContent": "live"}},
+			}})
+			return
+		}
+		scheduled := map[string]interface{}{"scheduledStartTime": "2024-01-20T18:00:00Z"}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+			{"id": "premiere", "contentDetails": map[string]interface{}{"duration": "PT12M"}, "liveStreamingDetails": scheduled},
+			{"id": "stream", "contentDetails": map[string]interface{}{"duration": "P0D"}, "liveStreamingDetails": scheduled},
+		}})
+	}))
+	defer server.Close()
+
+	videos, err := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL)).FetchRecentVideos(context.Background(), "UC123", 5)
+	if err != nil || len(videos) != 3 {
+		t.Fatalf("expected 3 videos, got %d (err %v)", len(videos), err)
+	}
+	if b := videos[0].Broadcast; b == nil || !b.Premiere {
+		t.Errorf("a scheduled video with a duration should be a premiere, got %+v", b)
+	}
+	if b := videos[1].Broadcast; b == nil || b.Premiere {
+		t.Errorf("a scheduled video without a duration should be a live stream, got %+v", b)
+	}
+	if b := videos[2].Broadcast; b == nil || b.Status != BroadcastLive || !b.Start.IsZero() {
+		t.Errorf("a broadcast without details should keep the search result's status, got %+v", b)
+	}
+}
+
+// BenchmarkClient_FetchRecentVideos measures one channel fetch (search + videos
+// round trips and JSON decoding) against a local server.
+// Run with: go test -bench=. -benchmem ./internal/youtube
+func BenchmarkClient_FetchRecentVideos(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/search") {
+			items := make([]map[string]interface{}, 0, 5)
+			for i := 0; i < 5; i++ {
+				items = append(items, map[string]interface{}{
+					"id":      map[string]interface{}{"videoId": "vid" + strconv.Itoa(i)},
+					"snippet": map[string]interface{}{"title": "Video", "channelId": "UC123", "channelTitle": "Channel", "publishedAt": "2024-01-15T10:00:00Z"},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []map[string]interface{}{
+			{"id": "vid0", "statistics": map[string]interface{}{"viewCount": "1000", "likeCount": "10"}, "contentDetails": map[string]interface{}{"duration": "PT10M"}},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.FetchRecentVideos(ctx, "UC123", 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
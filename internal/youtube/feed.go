@@ -0,0 +1,114 @@
+package youtube
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/internal/rss"
+)
+
+// DefaultFeedURL is where YouTube serves the public feeds of channels.
+const DefaultFeedURL = "https://www.youtube.com/feeds/videos.xml"
+
+// MaxFeedVideos is how many of a channel's latest videos its RSS feed lists.
+const MaxFeedVideos = 15
+
+// WithFeedURL sets the URL channel feeds are fetched from (useful for testing).
+func WithFeedURL(url string) ClientOption {
+	return func(c *Client) {
+		c.feedURL = url
+	}
+}
+
+// FeedURL returns the public feed of the channel with ID channelID, which
+// feed readers can subscribe to.
+func FeedURL(channelID string) string {
+	return channelFeedURL(DefaultFeedURL, channelID)
+}
+
+func channelFeedURL(base, channelID string) string {
+	return base + "?" + url.Values{"channel_id": {channelID}}.Encode()
+}
+
+// FetchChannelFeed returns the limit most recent videos of a channel from
+// its public RSS feed, which needs no credentials and costs no quota. The
+// feed lists at most MaxFeedVideos videos and lacks what only the API
+// knows: durations, region and age restrictions, broadcasts and chapters.
+func (c *Client) FetchChannelFeed(ctx context.Context, channelID string, limit int) ([]Video, error) {
+	feedURL := channelFeedURL(c.feedURL, channelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("no channel with ID %s", channelID)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("channel feed returned HTTP %d", resp.StatusCode)
+	}
+	body, err := rss.ReadFeed(resp, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed channelFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse channel feed: %w", err)
+	}
+	entries := feed.Entries
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	videos := make([]Video, 0, len(entries))
+	for _, entry := range entries {
+		published, _ := time.Parse(time.RFC3339, entry.Published)
+		videos = append(videos, Video{
+			ID:           entry.VideoID,
+			Title:        entry.Title,
+			Description:  entry.Group.Description,
+			ChannelID:    entry.ChannelID,
+			ChannelTitle: entry.Author,
+			Thumbnail:    entry.Group.Thumbnail.URL,
+			PublishedAt:  published.UTC(),
+			ViewCount:    entry.Group.Community.Statistics.Views,
+			LikeCount:    entry.Group.Community.StarRating.Count,
+			URL:          fmt.Sprintf("https://www.youtube.com/watch?v=%s", entry.VideoID),
+		})
+	}
+	return videos, nil
+}
+
+// channelFeed is the Atom feed YouTube serves for a channel, with its yt:
+// and media: (Media RSS) extensions. Elements are matched by local name.
+type channelFeed struct {
+	Entries []struct {
+		VideoID   string `xml:"videoId"`
+		ChannelID string `xml:"channelId"`
+		Title     string `xml:"title"`
+		Author    string `xml:"author>name"`
+		Published string `xml:"published"`
+		Group     struct {
+			Description string `xml:"description"`
+			Thumbnail   struct {
+				URL string `xml:"url,attr"`
+			} `xml:"thumbnail"`
+			Community struct {
+				StarRating struct {
+					Count int64 `xml:"count,attr"`
+				} `xml:"starRating"`
+				Statistics struct {
+					Views int64 `xml:"views,attr"`
+				} `xml:"statistics"`
+			} `xml:"community"`
+		} `xml:"group"`
+	} `xml:"entry"`
+}
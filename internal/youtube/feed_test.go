@@ -0,0 +1,81 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const channelFeedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+ <title>Fireship</title>
+ <entry>
+  <id>yt:video:abc123xyz00</id>
+  <yt:videoId>abc123xyz00</yt:videoId>
+  <yt:channelId>UCsBjURrPoezykLs9EqgamOA</yt:channelId>
+  <title>Go in 100 Seconds</title>
+  <link rel="alternate" href="https://www.youtube.com/watch?v=abc123xyz00"/>
+  <author><name>Fireship</name></author>
+  <published>2024-01-15T12:00:00+00:00</published>
+  <media:group>
+   <media:title>Go in 100 Seconds</media:title>
+   <media:thumbnail url="https://i.ytimg.com/vi/abc123xyz00/hqdefault.jpg" width="480" height="360"/>
+   <media:description>Learn Go fast.</media:description>
+   <media:community>
+    <media:starRating count="4200" average="5.00" min="1" max="5"/>
+    <media:statistics views="120000"/>
+   </media:community>
+  </media:group>
+ </entry>
+ <entry>
+  <yt:videoId>def456uvw00</yt:videoId>
+  <yt:channelId>UCsBjURrPoezykLs9EqgamOA</yt:channelId>
+  <title>Older video</title>
+  <author><name>Fireship</name></author>
+  <published>2024-01-10T12:00:00+00:00</published>
+ </entry>
+</feed>`
+
+// TestClient_FetchChannelFeed documents keyless fetching from channel RSS
+// feeds: no token is sent, and views and likes come from Media RSS.
+func TestClient_FetchChannelFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("channel feeds should be fetched without credentials")
+		}
+		if r.URL.Query().Get("channel_id") != "UCsBjURrPoezykLs9EqgamOA" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, channelFeedXML)
+	}))
+	defer server.Close()
+	client := NewClient(nil, WithFeedURL(server.URL))
+
+	videos, err := client.FetchChannelFeed(context.Background(), "UCsBjURrPoezykLs9EqgamOA", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Video{
+		ID: "abc123xyz00", Title: "Go in 100 Seconds", Description: "Learn Go fast.", ChannelID: "UCsBjURrPoezykLs9EqgamOA", ChannelTitle: "Fireship",
+		Thumbnail: "https://i.ytimg.com/vi/abc123xyz00/hqdefault.jpg", PublishedAt: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		ViewCount: 120000, LikeCount: 4200, URL: "https://www.youtube.com/watch?v=abc123xyz00",
+	}
+	if len(videos) != 1 || !reflect.DeepEqual(videos[0], want) {
+		t.Errorf("expected the latest video %+v, got %+v", want, videos)
+	}
+
+	if _, err := client.FetchChannelFeed(context.Background(), "UCunknownChannel000000000", 5); err == nil {
+		t.Error("an unknown channel should be an error")
+	}
+}
+
+func TestFeedURL_IsTheChannelsPublicFeed(t *testing.T) {
+	if got := FeedURL("UCabc"); got != "https://www.youtube.com/feeds/videos.xml?channel_id=UCabc" {
+		t.Errorf("unexpected feed URL: %s", got)
+	}
+}
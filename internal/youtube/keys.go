@@ -0,0 +1,124 @@
+package youtube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// KeyPool spreads requests for public data over several API keys, each
+// from its own Google Cloud project with its own daily quota. Requests use
+// the first key whose quota hasn't run out; a key that runs out rests until
+// quotas reset at midnight Pacific time. Resting keys are remembered across
+// runs. A KeyPool is safe for concurrent use and may be shared between
+// clients.
+type KeyPool struct {
+	path string
+	keys []string
+	now  clock.Clock
+
+	mu sync.Mutex
+	// resting maps a key's fingerprint to when its quota resets; keys
+	// themselves are never written to disk.
+	resting map[string]time.Time
+}
+
+// OpenKeyPool creates a pool of keys, loading at path when their quota ran
+// out; a missing file means none has.
+func OpenKeyPool(path string, keys []string, now clock.Clock) (*KeyPool, error) {
+	p := &KeyPool{path: path, keys: keys, now: now, resting: make(map[string]time.Time)}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the key state in the user's cache directory
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key state: %w", err)
+	}
+	if err := json.Unmarshal(data, &p.resting); err != nil {
+		return nil, fmt.Errorf("failed to parse API key state: %w", err)
+	}
+	return p, nil
+}
+
+// Available returns how many keys still have quota today.
+func (p *KeyPool) Available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, key := range p.keys {
+		if p.ready(key) {
+			n++
+		}
+	}
+	return n
+}
+
+// key returns the first key with quota left, or false when all ran out.
+func (p *KeyPool) key() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range p.keys {
+		if p.ready(key) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// exhausted rests key until quotas reset.
+func (p *KeyPool) exhausted(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resting[fingerprint(key)] = quotaReset(p.now())
+}
+
+func (p *KeyPool) ready(key string) bool {
+	until, ok := p.resting[fingerprint(key)]
+	return !ok || !p.now().Before(until)
+}
+
+// Save writes when resting keys' quota resets to disk, forgetting keys that
+// are ready again.
+func (p *KeyPool) Save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for id, until := range p.resting {
+		if !now.Before(until) {
+			delete(p.resting, id)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return fmt.Errorf("failed to create API key state directory: %w", err)
+	}
+	data, err := json.Marshal(p.resting)
+	if err != nil {
+		return fmt.Errorf("failed to encode API key state: %w", err)
+	}
+	if err := os.WriteFile(p.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write API key state: %w", err)
+	}
+	return nil
+}
+
+// fingerprint identifies key without revealing it.
+func fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// quotaReset returns the next midnight Pacific time after t.
+func quotaReset(t time.Time) time.Time {
+	local := t.In(pacific())
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, local.Location())
+}
@@ -0,0 +1,97 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+// TestClient_RotatesAPIKeys documents spreading quota over API keys:
+// - public data is read with the first key that has quota left
+// - a key that runs out rests until midnight Pacific, across runs
+// - subscriptions, and everything once all keys ran out, use the OAuth token
+func TestClient_RotatesAPIKeys(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential := r.Header.Get("X-Goog-Api-Key")
+		if credential == "" {
+			credential = r.Header.Get("Authorization")
+		}
+		mu.Lock()
+		seen = append(seen, r.URL.Path+" "+credential)
+		mu.Unlock()
+		if credential == "key-a" || credential == "key-b" && r.URL.Path == "/youtube/v3/videos" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":403,"errors":[{"reason":"quotaExceeded"}]}}`))
+			return
+		}
+		if r.URL.Path == "/youtube/v3/search" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]interface{}{{"id": map[string]interface{}{"videoId": "v1"}}},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "youtube_keys.json")
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	current := now
+	keys, err := OpenKeyPool(path, []string{"key-a", "key-b"}, func() time.Time { return current })
+	if err != nil {
+		t.Fatalf("a missing file should open empty, got: %v", err)
+	}
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL), WithKeyPool(keys))
+
+	if _, err := client.FetchSubscriptions(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.FetchRecentVideos(context.Background(), "UC123", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"/youtube/v3/subscriptions Bearer test",
+		"/youtube/v3/search key-a",
+		"/youtube/v3/search key-b",
+		"/youtube/v3/videos key-b",
+		"/youtube/v3/videos Bearer test",
+	}
+	if len(seen) != len(want) {
+		t.Fatalf("expected requests %q, got %q", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Errorf("request %d: expected %q, got %q", i, want[i], seen[i])
+		}
+	}
+	if err := keys.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 23:59 Pacific is 07:59 UTC the next day; quotas reset a minute later.
+	for _, tc := range []struct {
+		at   time.Time
+		want int
+	}{
+		{now.Add(19*time.Hour + 59*time.Minute), 0},
+		{now.Add(20 * time.Hour), 2},
+	} {
+		current = tc.at
+		keys, err := OpenKeyPool(path, []string{"key-a", "key-b"}, func() time.Time { return current })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := keys.Available(); got != tc.want {
+			t.Errorf("at %s: expected %d keys with quota, got %d", tc.at, tc.want, got)
+		}
+	}
+}
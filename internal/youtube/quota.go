@@ -0,0 +1,113 @@
+package youtube
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Quota costs in units, as documented at
+// https://developers.google.com/youtube/v3/determine_quota_cost.
+const (
+	SearchQuotaCost = 100
+	ListQuotaCost   = 1
+)
+
+// QuotaMeter counts the quota units spent by the requests of one run;
+// responses served from the local cache are free. It is safe for
+// concurrent use and may be shared between clients.
+type QuotaMeter struct {
+	mu    sync.Mutex
+	units int
+}
+
+// NewQuotaMeter creates an empty QuotaMeter.
+func NewQuotaMeter() *QuotaMeter {
+	return &QuotaMeter{}
+}
+
+// Units returns the quota units spent so far.
+func (m *QuotaMeter) Units() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.units
+}
+
+func (m *QuotaMeter) charge(cost int) {
+	m.mu.Lock()
+	m.units += cost
+	m.mu.Unlock()
+}
+
+// QuotaUsage is the quota consumed on one quota day. YouTube resets quotas
+// at midnight Pacific time, so days are counted in that time zone.
+type QuotaUsage struct {
+	Day     string `json:"day"`
+	Units   int    `json:"units"`
+	LastRun int    `json:"last_run"`
+}
+
+// QuotaDay returns the quota day containing t, formatted as YYYY-MM-DD.
+func QuotaDay(t time.Time) string {
+	return t.In(pacific()).Format(time.DateOnly)
+}
+
+// LoadQuotaUsage reads the usage recorded at path for the quota day containing now.
+// A missing file or a file from an earlier day yields zero units used; the
+// last run is kept so the next run can still be estimated.
+func LoadQuotaUsage(path string, now time.Time) (QuotaUsage, error) {
+	usage := QuotaUsage{Day: QuotaDay(now)}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the quota file in the user's config directory
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	}
+	if err != nil {
+		return usage, fmt.Errorf("failed to read quota usage: %w", err)
+	}
+
+	var stored QuotaUsage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return usage, fmt.Errorf("failed to parse quota usage: %w", err)
+	}
+	usage.LastRun = stored.LastRun
+	if stored.Day == usage.Day {
+		usage.Units = stored.Units
+	}
+	return usage, nil
+}
+
+// Add records a run that spent units. Runs answered entirely from the
+// cache do not replace the previous run's estimate.
+func (u *QuotaUsage) Add(units int) {
+	u.Units += units
+	if units > 0 {
+		u.LastRun = units
+	}
+}
+
+// Save writes the usage to path.
+func (u QuotaUsage) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create quota directory: %w", err)
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota usage: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write quota usage: %w", err)
+	}
+	return nil
+}
+
+func pacific() *time.Location {
+	if loc, err := time.LoadLocation("America/Los_Angeles"); err == nil {
+		return loc
+	}
+	return time.FixedZone("PST", -8*60*60)
+}
@@ -0,0 +1,76 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func TestQuotaMeter_ChargesSearchAndListCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]interface{}{{"id": map[string]interface{}{"videoId": "v1"}}},
+		})
+	}))
+	defer server.Close()
+
+	meter := NewQuotaMeter()
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL), WithQuotaMeter(meter))
+
+	_, _ = client.FetchSubscriptions(context.Background())
+	_, _ = client.FetchRecentVideos(context.Background(), "UC123", 5)
+
+	want := ListQuotaCost + SearchQuotaCost + ListQuotaCost
+	if got := meter.Units(); got != want {
+		t.Errorf("subscriptions + search + videos should cost %d units, got %d", want, got)
+	}
+}
+
+func TestClient_ReportsQuotaExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"errors":[{"reason":"quotaExceeded"}]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL))
+	_, err := client.FetchSubscriptions(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("user should be told the daily quota is exhausted, got: %v", err)
+	}
+}
+
+func TestQuotaUsage_ResetsAtPacificMidnight(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	day1 := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	usage, err := LoadQuotaUsage(path, day1)
+	if err != nil {
+		t.Fatalf("missing file should load as empty usage, got: %v", err)
+	}
+	usage.Add(303)
+	if err := usage.Save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sameDay, _ := LoadQuotaUsage(path, day1.Add(6*time.Hour))
+	if sameDay.Units != 303 {
+		t.Errorf("usage should accumulate until midnight Pacific (08:00 UTC), got %d", sameDay.Units)
+	}
+
+	nextDay, _ := LoadQuotaUsage(path, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC))
+	if nextDay.Units != 0 {
+		t.Errorf("usage should reset on a new quota day, got %d", nextDay.Units)
+	}
+	if nextDay.LastRun != 303 {
+		t.Errorf("last run should be kept to estimate the next run, got %d", nextDay.LastRun)
+	}
+}
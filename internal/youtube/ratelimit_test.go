@@ -0,0 +1,71 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func TestRateLimiter_AllowsBurstThenSpacesRequests(t *testing.T) {
+	limiter := NewRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("requests within the burst should not wait, took %v", elapsed)
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = limiter.Wait(ctx)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("3 requests beyond the burst at 50/s should take at least 60ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitRespectsContext(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	_ = limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Wait should return the context error instead of blocking for a second")
+	}
+}
+
+func TestClient_SharedRateLimiterThrottlesConcurrentFetches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&oauth.Token{AccessToken: "test"}, WithBaseURL(server.URL), WithRateLimiter(NewRateLimiter(100, 1)))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.FetchRecentVideos(context.Background(), "UC123", 5)
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("6 concurrent channel fetches at 100 req/s should not burst the API, took only %v", elapsed)
+	}
+}
@@ -0,0 +1,103 @@
+package youtube
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// apiV3 is the YouTube Data API version every endpoint is called at
+// unless the client is told otherwise with WithAPIVersion.
+const apiV3 = "v3"
+
+// endpoint is a YouTube Data API resource: the API version it is called
+// at, the parts it can return, the largest page it serves and what a call
+// costs in quota units. A version bump can then move one endpoint at a time.
+type endpoint struct {
+	// private endpoints read the user's own data, which needs the OAuth token.
+	private    bool
+	version    string
+	path       string
+	parts      []string
+	maxResults int
+	cost       int
+}
+
+var (
+	subscriptionsEndpoint = endpoint{private: true, version: apiV3, path: "subscriptions", parts: []string{"snippet", "contentDetails"}, maxResults: 50, cost: ListQuotaCost}
+	searchEndpoint        = endpoint{version: apiV3, path: "search", parts: []string{"snippet"}, maxResults: 50, cost: SearchQuotaCost}
+	videosEndpoint        = endpoint{version: apiV3, path: "videos", parts: []string{"snippet", "statistics", "contentDetails", "liveStreamingDetails", "status"}, maxResults: 50, cost: ListQuotaCost}
+	channelsEndpoint      = endpoint{version: apiV3, path: "channels", parts: []string{"id", "snippet", "topicDetails"}, maxResults: 50, cost: ListQuotaCost}
+	playlistItemsEndpoint = endpoint{version: apiV3, path: "playlistItems", parts: []string{"snippet", "contentDetails"}, maxResults: 50, cost: ListQuotaCost}
+)
+
+// request builds a call to an endpoint. Parameters are encoded when the URL
+// is built; the first invalid parameter is reported by url.
+type request struct {
+	endpoint endpoint
+	params   url.Values
+	err      error
+}
+
+// newRequest starts a call to e returning the given parts.
+func newRequest(e endpoint, parts ...string) *request {
+	r := &request{endpoint: e, params: url.Values{}}
+	if len(parts) == 0 {
+		r.fail(fmt.Errorf("no part selected"))
+	}
+	for _, part := range parts {
+		if !slices.Contains(e.parts, part) {
+			r.fail(fmt.Errorf("unknown part %q: must be one of %s", part, strings.Join(e.parts, ", ")))
+		}
+	}
+	r.params.Set("part", strings.Join(parts, ","))
+	return r
+}
+
+// param sets a parameter, which must not be empty.
+func (r *request) param(name, value string) *request {
+	if value == "" {
+		r.fail(fmt.Errorf("empty %s", name))
+	}
+	r.params.Set(name, value)
+	return r
+}
+
+// ids sets a parameter to a comma-separated list of IDs.
+func (r *request) ids(name string, ids []string) *request {
+	if len(ids) == 0 {
+		r.fail(fmt.Errorf("no %s given", name))
+	}
+	for _, id := range ids {
+		if id == "" || strings.Contains(id, ",") {
+			r.fail(fmt.Errorf("invalid %s %q", name, id))
+		}
+	}
+	r.params.Set(name, strings.Join(ids, ","))
+	return r
+}
+
+// maxResults sets the page size, which the endpoint caps.
+func (r *request) maxResults(n int) *request {
+	if n < 1 || n > r.endpoint.maxResults {
+		r.fail(fmt.Errorf("invalid maxResults %d: must be from 1 to %d", n, r.endpoint.maxResults))
+	}
+	r.params.Set("maxResults", strconv.Itoa(n))
+	return r
+}
+
+// url returns the request URL against the API at baseURL.
+func (r *request) url(baseURL string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	return baseURL + "/youtube/" + r.endpoint.version + "/" + r.endpoint.path + "?" + r.params.Encode(), nil
+}
+
+func (r *request) fail(err error) {
+	if r.err == nil {
+		r.err = fmt.Errorf("invalid %s request: %w", r.endpoint.path, err)
+	}
+}
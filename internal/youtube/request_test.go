@@ -0,0 +1,38 @@
+package youtube
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequest_EncodesParametersAndSelectsParts(t *testing.T) {
+	got, err := newRequest(searchEndpoint, "snippet").param("channelId", "UC+special/id&x=1").maxResults(5).url("https://api.test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://api.test/youtube/v3/search?channelId=UC%2Bspecial%2Fid%26x%3D1&maxResults=5&part=snippet"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	got, _ = newRequest(videosEndpoint, "statistics", "contentDetails").ids("id", []string{"a", "b"}).url("")
+	if !strings.Contains(got, "id=a%2Cb") || !strings.Contains(got, "part=statistics%2CcontentDetails") {
+		t.Errorf("IDs and parts should be comma-separated, got %s", got)
+	}
+}
+
+func TestRequest_RejectsInvalidParameters(t *testing.T) {
+	for name, req := range map[string]*request{
+		"unknown part":       newRequest(searchEndpoint, "statistics"),
+		"no part":            newRequest(videosEndpoint),
+		"empty channel":      newRequest(searchEndpoint, "snippet").param("channelId", ""),
+		"page too large":     newRequest(searchEndpoint, "snippet").maxResults(51),
+		"no IDs":             newRequest(channelsEndpoint, "snippet").ids("id", nil),
+		"comma in an ID":     newRequest(channelsEndpoint, "snippet").ids("id", []string{"UC_A,UC_B"}),
+		"zero results asked": newRequest(playlistItemsEndpoint, "snippet").maxResults(0),
+	} {
+		if _, err := req.url("https://api.test"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
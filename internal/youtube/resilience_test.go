@@ -17,12 +17,12 @@ func TestAC400_YouTubeAPI_IgnoresUnexpectedFields(t *testing.T) {
 		"items": []map[string]interface{}{
 			{
 				"snippet": map[string]interface{}{
-					"resourceId":        map[string]interface{}{"channelId": "UC123"},
-					"title":             "Test Channel",
+					"resourceId":         map[string]interface{}{"channelId": "UC123"},
+					"title":              "Test Channel",
 					"newFieldFromGoogle": "surprise feature!",
-					"anotherNewField":   []string{"we", "added", "this"},
-					"thumbnails":        map[string]interface{}{"default": map[string]interface{}{"url": "https://example.com/thumb.jpg"}},
-					"publishedAt":       "2024-01-01T00:00:00Z",
+					"anotherNewField":    []string{"we", "added", "this"},
+					"thumbnails":         map[string]interface{}{"default": map[string]interface{}{"url": "https://example.com/thumb.jpg"}},
+					"publishedAt":        "2024-01-01T00:00:00Z",
 				},
 			},
 		},
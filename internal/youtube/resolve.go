@@ -0,0 +1,130 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// channelIDPattern matches a channel ID, which is "UC" and 22 more characters.
+var channelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+
+// channelRef is a channel as someone might refer to it, reduced to the one
+// lookup that finds its ID: a channel ID needs none, otherwise it is found
+// by @handle, legacy /user/ name, legacy /c/ custom URL name, or the ID of
+// one of its videos.
+type channelRef struct {
+	id       string
+	handle   string
+	username string
+	custom   string
+	video    string
+}
+
+// parseChannelRef reads a channel ID, an @handle, or a youtube.com or
+// youtu.be URL of a channel or one of its videos.
+func parseChannelRef(ref string) (channelRef, error) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case channelIDPattern.MatchString(ref):
+		return channelRef{id: ref}, nil
+	case strings.HasPrefix(ref, "@") && len(ref) > 1 && !strings.Contains(ref, "/"):
+		return channelRef{handle: ref[1:]}, nil
+	}
+
+	if !strings.Contains(ref, "://") {
+		ref = "https://" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return channelRef{}, fmt.Errorf("invalid channel %q: %w", ref, err)
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(u.Hostname(), "www."), "m.")
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if host == "youtu.be" && segments[0] != "" {
+		return channelRef{video: segments[0]}, nil
+	}
+	if host == "youtube.com" {
+		switch {
+		case strings.HasPrefix(segments[0], "@") && len(segments[0]) > 1:
+			return channelRef{handle: segments[0][1:]}, nil
+		case segments[0] == "watch" && u.Query().Get("v") != "":
+			return channelRef{video: u.Query().Get("v")}, nil
+		case len(segments) >= 2 && segments[1] != "":
+			switch segments[0] {
+			case "channel":
+				if channelIDPattern.MatchString(segments[1]) {
+					return channelRef{id: segments[1]}, nil
+				}
+			case "user":
+				return channelRef{username: segments[1]}, nil
+			case "c":
+				return channelRef{custom: segments[1]}, nil
+			case "shorts", "live", "embed":
+				return channelRef{video: segments[1]}, nil
+			}
+		}
+	}
+	return channelRef{}, fmt.Errorf("invalid channel %q: expected a channel ID, an @handle, or a YouTube channel or video URL", ref)
+}
+
+// ResolveChannel returns the ID of the channel ref refers to: a channel ID,
+// an @handle, or the URL of a channel (by ID, handle, /user/ or /c/ name) or
+// of one of its videos. Legacy /c/ URLs can only be found by searching, which
+// costs SearchQuotaCost; the others cost a list call, or nothing for an ID.
+func (c *Client) ResolveChannel(ctx context.Context, ref string) (string, error) {
+	parsed, err := parseChannelRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var call *request
+	switch {
+	case parsed.id != "":
+		return parsed.id, nil
+	case parsed.handle != "":
+		call = newRequest(channelsEndpoint, "id").param("forHandle", "@"+parsed.handle)
+	case parsed.username != "":
+		call = newRequest(channelsEndpoint, "id").param("forUsername", parsed.username)
+	case parsed.video != "":
+		call = newRequest(videosEndpoint, "snippet").ids("id", []string{parsed.video})
+	default:
+		call = newRequest(searchEndpoint, "snippet").param("q", parsed.custom).param("type", "channel").maxResults(1)
+	}
+	body, err := c.doRequest(ctx, call)
+	if err != nil {
+		return "", err
+	}
+
+	var response resolveResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse %s response: %w", call.endpoint.path, err)
+	}
+	if len(response.Items) == 0 {
+		return "", fmt.Errorf("no YouTube channel found for %q", strings.TrimSpace(ref))
+	}
+	item := response.Items[0]
+	if parsed.video != "" || parsed.custom != "" {
+		return item.Snippet.ChannelID, nil
+	}
+	var id string
+	if err := json.Unmarshal(item.ID, &id); err != nil {
+		return "", fmt.Errorf("failed to parse %s response: %w", call.endpoint.path, err)
+	}
+	return id, nil
+}
+
+// resolveResponse holds what ResolveChannel needs from a channels, videos
+// or search response. The ID is a string in channels responses but an
+// object in search ones, so it is decoded only where it is used.
+type resolveResponse struct {
+	Items []struct {
+		ID      json.RawMessage `json:"id"`
+		Snippet struct {
+			ChannelID string `json:"channelId"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
@@ -0,0 +1,77 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gauthierbraillon/feedmix/pkg/oauth"
+)
+
+func TestParseChannelRef_AcceptsHandlesAndURLs(t *testing.T) {
+	const id = "UC_x5XG1OV2P6uZZ5FSM9Ttw"
+	for ref, want := range map[string]channelRef{
+		id:                  {id: id},
+		"@GoogleDevelopers": {handle: "GoogleDevelopers"},
+		"https://www.youtube.com/@GoogleDevelopers":     {handle: "GoogleDevelopers"},
+		"youtube.com/@GoogleDevelopers/videos":          {handle: "GoogleDevelopers"},
+		"https://www.youtube.com/channel/" + id:         {id: id},
+		"https://www.youtube.com/user/GoogleDevelopers": {username: "GoogleDevelopers"},
+		"https://www.youtube.com/c/GoogleDevelopers":    {custom: "GoogleDevelopers"},
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ":   {video: "dQw4w9WgXcQ"},
+		"https://m.youtube.com/shorts/dQw4w9WgXcQ":      {video: "dQw4w9WgXcQ"},
+		"https://youtu.be/dQw4w9WgXcQ?t=42":             {video: "dQw4w9WgXcQ"},
+	} {
+		got, err := parseChannelRef(ref)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", ref, err)
+		} else if got != want {
+			t.Errorf("%s: expected %+v, got %+v", ref, want, got)
+		}
+	}
+
+	for _, ref := range []string{"", "@", "GoogleDevelopers", "https://example.com/@GoogleDevelopers", "https://www.youtube.com/channel/not-an-id"} {
+		if _, err := parseChannelRef(ref); err == nil {
+			t.Errorf("%q: expected an error", ref)
+		}
+	}
+}
+
+func TestClient_ResolveChannel(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/youtube/v3/channels":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "UC_HANDLE"}}})
+		case "/youtube/v3/videos":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "dQw4w9WgXcQ", "snippet": map[string]interface{}{"channelId": "UC_VIDEO"}}}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{}})
+		}
+	}))
+	defer server.Close()
+
+	token := &oauth.Token{AccessToken: "test-token", TokenType: "Bearer"}
+	client := NewClient(token, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if id, err := client.ResolveChannel(ctx, "@someone"); err != nil || id != "UC_HANDLE" {
+		t.Errorf("expected UC_HANDLE for a handle, got %q, %v", id, err)
+	}
+	if queries[0] != "/youtube/v3/channels?forHandle=%40someone&part=id" {
+		t.Errorf("a handle should be looked up with forHandle, got %s", queries[0])
+	}
+	if id, err := client.ResolveChannel(ctx, "https://youtu.be/dQw4w9WgXcQ"); err != nil || id != "UC_VIDEO" {
+		t.Errorf("expected the video's channel, got %q, %v", id, err)
+	}
+	if _, err := client.ResolveChannel(ctx, "https://www.youtube.com/c/nobody"); err == nil {
+		t.Error("expected an error when no channel is found")
+	}
+	if id, _ := client.ResolveChannel(ctx, "UC_x5XG1OV2P6uZZ5FSM9Ttw"); id != "UC_x5XG1OV2P6uZZ5FSM9Ttw" || len(queries) != 3 {
+		t.Errorf("a channel ID should be returned without a request, got %q after %d requests", id, len(queries))
+	}
+}
@@ -0,0 +1,92 @@
+package youtube
+
+import "strings"
+
+// Channel groups derived from topic categories.
+const (
+	GroupGaming        = "gaming"
+	GroupMusic         = "music"
+	GroupSports        = "sports"
+	GroupTech          = "tech"
+	GroupEducation     = "education"
+	GroupNews          = "news"
+	GroupEntertainment = "entertainment"
+	GroupLifestyle     = "lifestyle"
+)
+
+// groupPriority orders groups from most to least specific: a channel tagged
+// both Video_game_culture and Entertainment is a gaming channel.
+var groupPriority = []string{
+	GroupGaming, GroupMusic, GroupSports, GroupTech, GroupEducation, GroupNews, GroupEntertainment, GroupLifestyle,
+}
+
+// topicGroups maps the topic categories YouTube assigns to channels
+// (https://developers.google.com/youtube/v3/docs/channels#topicDetails) to
+// groups. Music genres and game genres are matched by suffix in TopicGroup.
+var topicGroups = map[string]string{
+	"Video_game_culture":      GroupGaming,
+	"Music":                   GroupMusic,
+	"Jazz":                    GroupMusic,
+	"Reggae":                  GroupMusic,
+	"Rhythm_and_blues":        GroupMusic,
+	"Music_of_Asia":           GroupMusic,
+	"Music_of_Latin_America":  GroupMusic,
+	"Sport":                   GroupSports,
+	"American_football":       GroupSports,
+	"Association_football":    GroupSports,
+	"Baseball":                GroupSports,
+	"Basketball":              GroupSports,
+	"Boxing":                  GroupSports,
+	"Cricket":                 GroupSports,
+	"Golf":                    GroupSports,
+	"Ice_hockey":              GroupSports,
+	"Mixed_martial_arts":      GroupSports,
+	"Motorsport":              GroupSports,
+	"Professional_wrestling":  GroupSports,
+	"Tennis":                  GroupSports,
+	"Volleyball":              GroupSports,
+	"Physical_fitness":        GroupSports,
+	"Technology":              GroupTech,
+	"Knowledge":               GroupEducation,
+	"Politics":                GroupNews,
+	"Society":                 GroupNews,
+	"Military":                GroupNews,
+	"Entertainment":           GroupEntertainment,
+	"Film":                    GroupEntertainment,
+	"Humour":                  GroupEntertainment,
+	"Performing_arts":         GroupEntertainment,
+	"Television_program":      GroupEntertainment,
+	"Lifestyle_(sociology)":   GroupLifestyle,
+	"Fashion":                 GroupLifestyle,
+	"Food":                    GroupLifestyle,
+	"Health":                  GroupLifestyle,
+	"Hobby":                   GroupLifestyle,
+	"Pet":                     GroupLifestyle,
+	"Physical_attractiveness": GroupLifestyle,
+	"Tourism":                 GroupLifestyle,
+	"Vehicle":                 GroupLifestyle,
+}
+
+// TopicGroup returns the most specific group among topics, or "" when none
+// of them is known.
+func TopicGroup(topics []string) string {
+	found := make(map[string]bool)
+	for _, topic := range topics {
+		switch {
+		case strings.HasSuffix(topic, "_game"):
+			found[GroupGaming] = true
+		case strings.HasSuffix(topic, "_music"):
+			found[GroupMusic] = true
+		default:
+			if group, ok := topicGroups[topic]; ok {
+				found[group] = true
+			}
+		}
+	}
+	for _, group := range groupPriority {
+		if found[group] {
+			return group
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package youtube
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimedTextURL = "https://www.youtube.com/api/timedtext"
+
+// ErrNoTranscript is returned for videos without captions in the language
+// asked for, or without any captions.
+var ErrNoTranscript = errors.New("no transcript available")
+
+// videoIDPattern matches a video ID, which is 11 URL-safe characters.
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// WithTimedTextURL sets the URL captions are fetched from (useful for testing).
+func WithTimedTextURL(url string) ClientOption {
+	return func(c *Client) {
+		c.timedTextURL = url
+	}
+}
+
+// Transcript is the captions of a video in one language.
+type Transcript struct {
+	// Language is the BCP 47 code of the captions, e.g. "en".
+	Language string
+	Cues     []Cue
+}
+
+// Cue is a caption shown Start into the video.
+type Cue struct {
+	Start    time.Duration
+	Duration time.Duration
+	Text     string
+}
+
+// Text is the transcript as running text, without timings.
+func (t Transcript) Text() string {
+	texts := make([]string, 0, len(t.Cues))
+	for _, cue := range t.Cues {
+		texts = append(texts, cue.Text)
+	}
+	return strings.Join(strings.Fields(strings.Join(texts, " ")), " ")
+}
+
+// ParseVideoID returns the ID of the video ref refers to: a video ID, or a
+// youtube.com or youtu.be URL of the video.
+func ParseVideoID(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if videoIDPattern.MatchString(ref) {
+		return ref, nil
+	}
+	if parsed, err := parseChannelRef(ref); err == nil && videoIDPattern.MatchString(parsed.video) {
+		return parsed.video, nil
+	}
+	return "", fmt.Errorf("invalid video %q: expected a video ID or a YouTube video URL", ref)
+}
+
+// FetchTranscript returns the captions of a video in lang, or when lang is
+// empty in English if it has them, else in the first language listed.
+// Captions come from the timedtext endpoint, which needs no credentials and
+// costs no quota.
+func (c *Client) FetchTranscript(ctx context.Context, videoID, lang string) (Transcript, error) {
+	var list trackList
+	if err := c.getTimedText(ctx, url.Values{"type": {"list"}, "v": {videoID}}, &list); err != nil {
+		return Transcript{}, err
+	}
+	track, ok := list.pick(lang)
+	if !ok {
+		if lang != "" {
+			return Transcript{}, fmt.Errorf("%w in %s for video %s", ErrNoTranscript, lang, videoID)
+		}
+		return Transcript{}, fmt.Errorf("%w for video %s", ErrNoTranscript, videoID)
+	}
+
+	query := url.Values{"v": {videoID}, "lang": {track.Lang}}
+	if track.Name != "" {
+		query.Set("name", track.Name)
+	}
+	var captions timedText
+	if err := c.getTimedText(ctx, query, &captions); err != nil {
+		return Transcript{}, err
+	}
+	transcript := Transcript{Language: track.Lang}
+	for _, text := range captions.Texts {
+		start, _ := strconv.ParseFloat(text.Start, 64)
+		duration, _ := strconv.ParseFloat(text.Duration, 64)
+		if body := strings.TrimSpace(html.UnescapeString(text.Body)); body != "" {
+			transcript.Cues = append(transcript.Cues, Cue{Start: seconds(start), Duration: seconds(duration), Text: body})
+		}
+	}
+	if len(transcript.Cues) == 0 {
+		return Transcript{}, fmt.Errorf("%w for video %s", ErrNoTranscript, videoID)
+	}
+	return transcript, nil
+}
+
+func (c *Client) getTimedText(ctx context.Context, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.timedTextURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captions request returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read captions: %w", err)
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	if err := xml.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse captions: %w", err)
+	}
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+type trackList struct {
+	Tracks []track `xml:"track"`
+}
+
+type track struct {
+	Lang    string `xml:"lang_code,attr"`
+	Name    string `xml:"name,attr"`
+	Default bool   `xml:"lang_default,attr"`
+}
+
+// pick returns the track in lang, or without one the English track, else
+// the default track, else the first.
+func (l trackList) pick(lang string) (track, bool) {
+	if len(l.Tracks) == 0 {
+		return track{}, false
+	}
+	find := func(lang string) (track, bool) {
+		for _, t := range l.Tracks {
+			if strings.EqualFold(t.Lang, lang) {
+				return t, true
+			}
+		}
+		return track{}, false
+	}
+	if lang != "" {
+		return find(lang)
+	}
+	if t, ok := find("en"); ok {
+		return t, true
+	}
+	for _, t := range l.Tracks {
+		if t.Default {
+			return t, true
+		}
+	}
+	return l.Tracks[0], true
+}
+
+type timedText struct {
+	Texts []struct {
+		Start    string `xml:"start,attr"`
+		Duration string `xml:"dur,attr"`
+		Body     string `xml:",chardata"`
+	} `xml:"text"`
+}
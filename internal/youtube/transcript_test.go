@@ -0,0 +1,69 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClient_FetchTranscript documents caption fetching:
+// - without a language, English captions are preferred over the default track
+// - caption text is unescaped and timings are kept per cue
+// - videos without captions return ErrNoTranscript
+func TestClient_FetchTranscript(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("v") == "nocaptions00":
+			// YouTube answers with an empty body.
+		case query.Get("type") == "list":
+			fmt.Fprint(w, `<transcript_list docid="1"><track id="0" name="" lang_code="fr" lang_default="true"/><track id="1" name="CC" lang_code="en"/></transcript_list>`)
+		case query.Get("lang") == "en" && query.Get("name") == "CC":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8" ?><transcript><text start="0.5" dur="2.1">Welcome back &amp;amp; hi</text><text start="2.6" dur="1.5">it&amp;#39;s
+time</text></transcript>`)
+		case query.Get("lang") == "fr":
+			fmt.Fprint(w, `<transcript><text start="0" dur="1">Bonjour</text></transcript>`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(nil, WithTimedTextURL(server.URL))
+	ctx := context.Background()
+
+	transcript, err := client.FetchTranscript(ctx, "dQw4w9WgXcQ", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transcript.Language != "en" || len(transcript.Cues) != 2 || transcript.Cues[1].Start != 2600*time.Millisecond {
+		t.Errorf("expected the two English cues, got %+v", transcript)
+	}
+	if got := transcript.Text(); got != "Welcome back & hi it's time" {
+		t.Errorf("expected running text, got %q", got)
+	}
+
+	if transcript, _ := client.FetchTranscript(ctx, "dQw4w9WgXcQ", "fr"); transcript.Text() != "Bonjour" {
+		t.Errorf("expected the French captions, got %+v", transcript)
+	}
+	if _, err := client.FetchTranscript(ctx, "dQw4w9WgXcQ", "de"); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("expected ErrNoTranscript for a missing language, got %v", err)
+	}
+	if _, err := client.FetchTranscript(ctx, "nocaptions00", ""); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("expected ErrNoTranscript without captions, got %v", err)
+	}
+}
+
+func TestParseVideoID(t *testing.T) {
+	for _, ref := range []string{"dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=1", "https://youtu.be/dQw4w9WgXcQ"} {
+		if id, err := ParseVideoID(ref); err != nil || id != "dQw4w9WgXcQ" {
+			t.Errorf("%s: expected dQw4w9WgXcQ, got %q, %v", ref, id, err)
+		}
+	}
+	if _, err := ParseVideoID("https://www.youtube.com/@someone"); err == nil {
+		t.Error("a channel URL should be rejected")
+	}
+}
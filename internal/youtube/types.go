@@ -7,7 +7,10 @@
 // - Retrieve liked videos
 package youtube
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Subscription represents a YouTube channel subscription.
 type Subscription struct {
@@ -18,6 +21,14 @@ type Subscription struct {
 	SubscribedAt time.Time `json:"subscribed_at"`
 }
 
+// ChannelDetails describes a channel beyond its title.
+type ChannelDetails struct {
+	// Handle is the channel's @handle, or empty if it has none.
+	Handle string `json:"handle,omitempty"`
+	// Topics are the channel's topic categories as Wikipedia article names, e.g. "Video_game_culture".
+	Topics []string `json:"topics,omitempty"`
+}
+
 // Video represents a YouTube video.
 type Video struct {
 	ID           string    `json:"id"`
@@ -31,6 +42,55 @@ type Video struct {
 	LikeCount    int64     `json:"like_count"`
 	Duration     string    `json:"duration"`
 	URL          string    `json:"url"`
+
+	// AgeRestricted videos can only be watched signed in, by adults.
+	AgeRestricted bool `json:"age_restricted,omitempty"`
+	// AllowedRegions, when set, lists the only ISO 3166-1 regions where the
+	// video plays; BlockedRegions lists regions where it doesn't.
+	AllowedRegions []string `json:"allowed_regions,omitempty"`
+	BlockedRegions []string `json:"blocked_regions,omitempty"`
+
+	// Broadcast is set on scheduled premieres and live streams that haven't
+	// ended.
+	Broadcast *Broadcast `json:"broadcast,omitempty"`
+	// Chapters are the sections the description lists, if any.
+	Chapters []Chapter `json:"chapters,omitempty"`
+}
+
+// Chapter is a section of a video, from its description's timestamps.
+type Chapter struct {
+	Start time.Duration `json:"start"`
+	Title string        `json:"title"`
+}
+
+// Broadcast states.
+const (
+	BroadcastUpcoming = "upcoming"
+	BroadcastLive     = "live"
+)
+
+// Broadcast is when a premiere or live stream airs.
+type Broadcast struct {
+	// Status is BroadcastUpcoming or BroadcastLive.
+	Status string `json:"status"`
+	// Start is the scheduled start, or the actual one once on air.
+	Start time.Time `json:"start"`
+	// End is the scheduled end, which channels often leave unset.
+	End time.Time `json:"end,omitempty"`
+	// Premiere is set when an uploaded video airs, rather than a live stream.
+	Premiere bool `json:"premiere,omitempty"`
+}
+
+// PlayableIn reports whether the video plays in region, an ISO 3166-1
+// alpha-2 code. An empty region is assumed to play everything.
+func (v Video) PlayableIn(region string) bool {
+	if region == "" {
+		return true
+	}
+	if v.AllowedRegions != nil && !slices.Contains(v.AllowedRegions, region) {
+		return false
+	}
+	return !slices.Contains(v.BlockedRegions, region)
 }
 
 // LikedVideo represents a video the user has liked.
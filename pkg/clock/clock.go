@@ -0,0 +1,36 @@
+// Package clock abstracts the current time, so that relative timestamps,
+// cache expiry and quota days can be pinned in tests and demos.
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clock returns the current time. Its signature matches time.Now, so any
+// func() time.Time field accepts a Clock.
+type Clock func() time.Time
+
+// System is the real clock.
+var System Clock = time.Now
+
+// Fixed returns a Clock that is always at t.
+func Fixed(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
+// layouts are the formats accepted by Parse, most precise first.
+var layouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+// Parse reads a point in time such as 2024-01-15T12:00:00Z, 2024-01-15T12:00
+// or 2024-01-15. Times without a zone are in the local time zone.
+func Parse(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: must look like 2024-01-15T12:00:00Z, 2024-01-15T12:00 or 2024-01-15", raw)
+}
@@ -0,0 +1,30 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_AcceptsTimestampsAndDates(t *testing.T) {
+	for raw, want := range map[string]time.Time{
+		"2024-01-15T12:00:00Z": time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		"2024-01-15T12:00":     time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local),
+		" 2024-01-15 ":         time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local),
+	} {
+		got, err := Parse(raw)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("Parse(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	if _, err := Parse("yesterday"); err == nil {
+		t.Error("expected an error for an unparsable time")
+	}
+}
+
+func TestFixed_StaysAtTheGivenTime(t *testing.T) {
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	now := Fixed(at)
+	if !now().Equal(at) || !now().Equal(now()) {
+		t.Errorf("a fixed clock should always return %v, got %v", at, now())
+	}
+}
@@ -0,0 +1,200 @@
+package httpx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gauthierbraillon/feedmix/pkg/clock"
+)
+
+// CacheHeader is set on responses served by a CacheTransport without
+// contacting the server.
+const CacheHeader = "X-Feedmix-Cache"
+
+// CacheTransport serves repeated GET requests from files in a directory
+// while the stored 200 response is younger than the TTL. Cache reads and
+// writes are best-effort: any failure falls through to the network. The
+// first time a transport stores a response, it removes the entries that
+// have outlived the TTL, so the directory only keeps live ones.
+type CacheTransport struct {
+	base    http.RoundTripper
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+	now     func() time.Time
+	pruned  sync.Once
+}
+
+type cachedResponse struct {
+	StoredAt time.Time   `json:"stored_at"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// CacheOption configures a CacheTransport.
+type CacheOption func(*CacheTransport)
+
+// WithCacheClock ages cached responses against now instead of the system clock.
+func WithCacheClock(now clock.Clock) CacheOption {
+	return func(t *CacheTransport) { t.now = now }
+}
+
+// WithCacheMaxSize caps the entries kept in the directory at maxSize bytes:
+// when pruning finds more, the oldest are removed first. A run may go over
+// the cap until the next one prunes.
+func WithCacheMaxSize(maxSize int64) CacheOption {
+	return func(t *CacheTransport) { t.maxSize = maxSize }
+}
+
+// NewCacheTransport creates a CacheTransport storing responses in dir.
+// A nil base uses http.DefaultTransport.
+func NewCacheTransport(base http.RoundTripper, dir string, ttl time.Duration, opts ...CacheOption) *CacheTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &CacheTransport{base: base, dir: dir, ttl: ttl, now: time.Now}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// FromCache reports whether resp was served by a CacheTransport.
+func FromCache(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(CacheHeader) == "hit"
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	if resp, ok := t.load(path, req); ok {
+		return resp, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.store(path, resp.Header, body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *CacheTransport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:12])+".json")
+}
+
+func (t *CacheTransport) load(path string, req *http.Request) (*http.Response, bool) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is derived from a hash inside the cache directory
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || t.now().Sub(cached.StoredAt) >= t.ttl {
+		return nil, false
+	}
+
+	header := cached.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheHeader, "hit")
+	header.Set("Age", strconv.Itoa(int(t.now().Sub(cached.StoredAt).Seconds())))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}, true
+}
+
+func (t *CacheTransport) store(path string, header http.Header, body []byte) {
+	t.pruned.Do(t.prune)
+	storedAt := t.now()
+	data, err := json.Marshal(cachedResponse{StoredAt: storedAt, Header: header, Body: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(t.dir, "response-*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Rename(tmp.Name(), path) != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	_ = os.Chtimes(path, storedAt, storedAt)
+}
+
+// prune removes the entries, and the files of interrupted writes, last
+// modified longer than the TTL ago, then the oldest entries over the size
+// cap. Entries are stamped with the time they were stored, so this ages
+// them against the transport's clock.
+func (t *CacheTransport) prune() {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+	expired := t.now().Add(-t.ttl)
+	var kept []os.FileInfo
+	var size int64
+	for _, entry := range entries {
+		if entry.IsDir() || !(strings.HasSuffix(entry.Name(), ".json") || strings.HasSuffix(entry.Name(), ".tmp")) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().After(expired) {
+			_ = os.Remove(filepath.Join(t.dir, entry.Name()))
+			continue
+		}
+		kept = append(kept, info)
+		size += info.Size()
+	}
+	if t.maxSize <= 0 {
+		return
+	}
+	slices.SortFunc(kept, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, info := range kept {
+		if size <= t.maxSize {
+			return
+		}
+		if os.Remove(filepath.Join(t.dir, info.Name())) == nil {
+			size -= info.Size()
+		}
+	}
+}
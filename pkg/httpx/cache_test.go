@@ -0,0 +1,116 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingServer(t *testing.T, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte{byte('0' + n)})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func getBody(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp
+}
+
+func TestCacheTransport_ServesFreshResponsesFromDisk(t *testing.T) {
+	server, calls := countingServer(t, http.StatusOK)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	transport := NewCacheTransport(nil, t.TempDir(), 5*time.Minute)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	first, resp := getBody(t, client, server.URL)
+	if FromCache(resp) {
+		t.Error("first response should come from the server")
+	}
+
+	now = now.Add(4 * time.Minute)
+	second, resp := getBody(t, client, server.URL)
+	if *calls != 1 || second != first || !FromCache(resp) {
+		t.Errorf("a repeat within the TTL should be served from cache, got %d calls, body %q", *calls, second)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if third, _ := getBody(t, client, server.URL); *calls != 2 || third == first {
+		t.Errorf("an expired entry should be refetched, got %d calls, body %q", *calls, third)
+	}
+}
+
+func TestCacheTransport_DoesNotCacheErrors(t *testing.T) {
+	server, calls := countingServer(t, http.StatusInternalServerError)
+	client := &http.Client{Transport: NewCacheTransport(nil, t.TempDir(), time.Hour)}
+
+	getBody(t, client, server.URL)
+	getBody(t, client, server.URL)
+	if *calls != 2 {
+		t.Errorf("error responses should not be cached, got %d calls", *calls)
+	}
+}
+
+func TestCacheTransport_PrunesExpiredEntriesWhenStoring(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	client := &http.Client{Transport: NewCacheTransport(nil, dir, time.Hour, WithCacheClock(func() time.Time { return now }))}
+	getBody(t, client, server.URL+"/old")
+	getBody(t, client, server.URL+"/recent")
+
+	now = now.Add(90 * time.Minute)
+	client = &http.Client{Transport: NewCacheTransport(nil, dir, 2*time.Hour, WithCacheClock(func() time.Time { return now }))}
+	getBody(t, client, server.URL+"/new")
+	if entries, _ := os.ReadDir(dir); len(entries) != 3 {
+		t.Fatalf("entries within the TTL should be kept, got %d files", len(entries))
+	}
+
+	now = now.Add(time.Hour)
+	client = &http.Client{Transport: NewCacheTransport(nil, dir, time.Hour, WithCacheClock(func() time.Time { return now }))}
+	getBody(t, client, server.URL+"/newest")
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("entries older than the TTL should be removed, leaving only the newest, got %d files", len(entries))
+	}
+}
+
+func TestCacheTransport_PrunesOldestEntriesOverTheSizeCap(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	client := &http.Client{Transport: NewCacheTransport(nil, dir, time.Hour, WithCacheClock(clock))}
+	for _, path := range []string{"/a", "/b", "/c"} {
+		getBody(t, client, server.URL+path)
+		now = now.Add(time.Minute)
+	}
+	entries, _ := os.ReadDir(dir)
+	info, _ := entries[0].Info()
+
+	client = &http.Client{Transport: NewCacheTransport(nil, dir, time.Hour, WithCacheClock(clock), WithCacheMaxSize(2*info.Size()))}
+	getBody(t, client, server.URL+"/d")
+	if _, resp := getBody(t, client, server.URL+"/a"); FromCache(resp) {
+		t.Error("the oldest entry should be removed to fit the size cap")
+	}
+	if _, resp := getBody(t, client, server.URL+"/c"); !FromCache(resp) {
+		t.Error("the newest entries should be kept")
+	}
+}
@@ -0,0 +1,78 @@
+package httpx
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Exchange describes one request sent by a RecordTransport.
+type Exchange struct {
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// secretParams are query parameters whose values are redacted from recorded URLs.
+var secretParams = []string{"token", "key", "secret", "sig", "signature", "auth", "password"}
+
+// RecordTransport reports every request it sends, with its status and
+// duration, to a callback. Wrapped around a transport that retries, each
+// attempt is reported.
+type RecordTransport struct {
+	base   http.RoundTripper
+	record func(Exchange)
+	now    func() time.Time
+}
+
+// NewRecordTransport creates a RecordTransport sending requests through base
+// and passing each exchange to record, which may be called concurrently.
+// A nil base uses http.DefaultTransport.
+func NewRecordTransport(base http.RoundTripper, record func(Exchange)) *RecordTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RecordTransport{base: base, record: record, now: time.Now}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.now()
+	resp, err := t.base.RoundTrip(req)
+
+	exchange := Exchange{Method: req.Method, URL: redactURL(req.URL), Duration: t.now().Sub(start)}
+	if resp != nil {
+		exchange.Status = resp.StatusCode
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+	t.record(exchange)
+	return resp, err
+}
+
+// redactURL hides credentials that some feeds carry in their URL, such as
+// private podcast or newsletter tokens.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	query := redacted.Query()
+	changed := false
+	for name := range query {
+		lower := strings.ToLower(name)
+		for _, secret := range secretParams {
+			if strings.Contains(lower, secret) {
+				query.Set(name, "REDACTED")
+				changed = true
+				break
+			}
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
@@ -0,0 +1,37 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecordTransport_ReportsEachRequestWithSecretsRedacted(t *testing.T) {
+	server, _ := countingServer(t, http.StatusNotFound)
+
+	var mu sync.Mutex
+	var exchanges []Exchange
+	client := &http.Client{Transport: NewRecordTransport(nil, func(e Exchange) {
+		mu.Lock()
+		exchanges = append(exchanges, e)
+		mu.Unlock()
+	})}
+
+	resp, err := client.Get(server.URL + "/feed?token=abc123&page=2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(exchanges) != 1 {
+		t.Fatalf("expected 1 recorded exchange, got %d", len(exchanges))
+	}
+	e := exchanges[0]
+	if e.Method != http.MethodGet || e.Status != http.StatusNotFound {
+		t.Errorf("exchange should carry method and status, got %+v", e)
+	}
+	if strings.Contains(e.URL, "abc123") || !strings.Contains(e.URL, "page=2") {
+		t.Errorf("secret query values should be redacted and others kept, got %s", e.URL)
+	}
+}
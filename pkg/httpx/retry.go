@@ -0,0 +1,164 @@
+// Package httpx provides shared HTTP plumbing for feedmix API clients.
+package httpx
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries    = 3
+	defaultBaseDelay     = 500 * time.Millisecond
+	defaultMaxDelay      = 10 * time.Second
+	maxDrainBytes        = 64 << 10
+	defaultClientTimeout = 30 * time.Second
+)
+
+// RetryTransport retries idempotent requests that fail transiently (network
+// errors, 429 and 5xx responses) using jittered exponential backoff.
+// A Retry-After header is honored when it does not exceed the maximum delay;
+// longer waits are not attempted and the response is returned as-is.
+type RetryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// Option configures a RetryTransport.
+type Option func(*RetryTransport)
+
+// WithTransport sets the underlying transport (default http.DefaultTransport).
+func WithTransport(rt http.RoundTripper) Option {
+	return func(t *RetryTransport) { t.base = rt }
+}
+
+// WithMaxRetries sets how many times a request is retried after the first attempt.
+func WithMaxRetries(n int) Option {
+	return func(t *RetryTransport) { t.maxRetries = n }
+}
+
+// WithBackoff sets the initial and maximum delay between attempts.
+func WithBackoff(base, maxDelay time.Duration) Option {
+	return func(t *RetryTransport) {
+		t.baseDelay = base
+		t.maxDelay = maxDelay
+	}
+}
+
+// NewRetryTransport creates a RetryTransport with sensible defaults.
+func NewRetryTransport(opts ...Option) *RetryTransport {
+	t := &RetryTransport{
+		base:       http.DefaultTransport,
+		maxRetries: defaultMaxRetries,
+		baseDelay:  defaultBaseDelay,
+		maxDelay:   defaultMaxDelay,
+		sleep:      sleepContext,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewClient returns an *http.Client that retries transient failures.
+func NewClient(opts ...Option) *http.Client {
+	return &http.Client{
+		Transport: NewRetryTransport(opts...),
+		Timeout:   defaultClientTimeout,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req.Method) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !retryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if wait, ok := retryAfter(resp, time.Now()); ok {
+				if wait > t.maxDelay {
+					return resp, nil
+				}
+				delay = max(delay, wait)
+			}
+			drain(resp)
+		}
+
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	d := t.baseDelay << attempt
+	if d <= 0 || d > t.maxDelay {
+		d = t.maxDelay
+	}
+	half := d / 2
+	return half + rand.N(half+1) // #nosec G404 -- jitter does not need a cryptographic source
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given as seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	_ = resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingClient returns a client whose transport records backoff delays instead of sleeping.
+func recordingClient(delays *[]time.Duration, opts ...Option) *http.Client {
+	t := NewRetryTransport(append([]Option{WithBackoff(100*time.Millisecond, 2*time.Second)}, opts...)...)
+	t.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return &http.Client{Transport: t}
+}
+
+func flakyServer(t *testing.T, failures int32, status int, header map[string]string) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRetryTransport_RecoversFromTransientServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		var delays []time.Duration
+		server, calls := flakyServer(t, 2, status, nil)
+
+		resp, err := recordingClient(&delays).Get(server.URL)
+		if err != nil {
+			t.Fatalf("status %d: a flaky response should be retried, got: %v", status, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status %d: should eventually succeed, got %d", status, resp.StatusCode)
+		}
+		if *calls != 3 {
+			t.Errorf("status %d: expected 3 attempts, got %d", status, *calls)
+		}
+	}
+}
+
+func TestRetryTransport_BacksOffExponentiallyWithJitter(t *testing.T) {
+	var delays []time.Duration
+	server, _ := flakyServer(t, 3, http.StatusServiceUnavailable, nil)
+
+	resp, err := recordingClient(&delays).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(delays) != 3 {
+		t.Fatalf("expected 3 backoff waits, got %d", len(delays))
+	}
+	for i, d := range delays {
+		ceiling := 100 * time.Millisecond << i
+		if d < ceiling/2 || d > ceiling {
+			t.Errorf("attempt %d: delay %v should be jittered within [%v, %v]", i, d, ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var delays []time.Duration
+	server, calls := flakyServer(t, 100, http.StatusInternalServerError, nil)
+
+	resp, err := recordingClient(&delays, WithMaxRetries(2)).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("final failing response should be returned to the caller, got %d", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 1 attempt + 2 retries, got %d", *calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	var delays []time.Duration
+	server, calls := flakyServer(t, 100, http.StatusForbidden, nil)
+
+	resp, err := recordingClient(&delays).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if *calls != 1 {
+		t.Errorf("403 (e.g. quota exceeded) is not transient and should not be retried, got %d attempts", *calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	var delays []time.Duration
+	server, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+
+	resp, err := recordingClient(&delays).Post(server.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if *calls != 1 {
+		t.Errorf("POST should not be retried, got %d attempts", *calls)
+	}
+}
+
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	var delays []time.Duration
+	server, _ := flakyServer(t, 1, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+
+	resp, err := recordingClient(&delays).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("should wait for the server's Retry-After of 1s, got %v", delays)
+	}
+}
+
+func TestRetryTransport_ReturnsResponseWhenRetryAfterExceedsMaxDelay(t *testing.T) {
+	var delays []time.Duration
+	server, calls := flakyServer(t, 100, http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"})
+
+	resp, err := recordingClient(&delays).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if *calls != 1 || len(delays) != 0 {
+		t.Errorf("an hour-long Retry-After should not block the run, got %d attempts and delays %v", *calls, delays)
+	}
+}
+
+func TestRetryTransport_RetriesNetworkErrors(t *testing.T) {
+	var delays []time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	_, err := recordingClient(&delays, WithMaxRetries(2)).Get(url)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if len(delays) != 2 {
+		t.Errorf("network errors should be retried, got %d retries", len(delays))
+	}
+}
+
+func TestRetryTransport_StopsWhenContextCancelled(t *testing.T) {
+	server, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(WithBackoff(time.Second, time.Second))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err := client.Do(req)
+
+	if err == nil {
+		t.Fatal("cancelled context should abort retries")
+	}
+	if *calls != 1 {
+		t.Errorf("no retries should be attempted after cancellation, got %d attempts", *calls)
+	}
+}
+
+func TestRetryAfter_ParsesSecondsAndHTTPDate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tc := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tc.value != "" {
+			resp.Header.Set("Retry-After", tc.value)
+		}
+		got, ok := retryAfter(resp, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
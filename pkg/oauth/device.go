@@ -0,0 +1,157 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deviceGrantType is the RFC 8628 grant type used when polling for the token.
+const deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	defaultDeviceInterval = 5 * time.Second
+	slowDownIncrement     = 5 * time.Second
+)
+
+// ErrDeviceAuthDenied is returned when the user declines the device authorization.
+var ErrDeviceAuthDenied = errors.New("authorization denied")
+
+// ErrDeviceCodeExpired is returned when the user did not authorize in time.
+var ErrDeviceCodeExpired = errors.New("device code expired")
+
+// DeviceCode is the response to a device authorization request (RFC 8628).
+// The user opens VerificationURL on any device and enters UserCode.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int64  `json:"expires_in"`
+	Interval        int64  `json:"interval"`
+}
+
+// RequestDeviceCode starts the device authorization grant, for machines
+// without a browser or a reachable loopback redirect (SSH sessions, servers).
+func (f *Flow) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	data := url.Values{}
+	data.Set("client_id", f.config.ClientID)
+	data.Set(f.scopeParam(), f.config.Scope)
+
+	var code struct {
+		DeviceCode
+		VerificationURI string `json:"verification_uri"`
+	}
+	status, body, err := f.postForm(ctx, f.config.DeviceAuthURL, data)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("device authorization failed: status %d", status)
+	}
+	if err := json.Unmarshal(body, &code); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if code.VerificationURL == "" {
+		code.VerificationURL = code.VerificationURI
+	}
+	return &code.DeviceCode, nil
+}
+
+// PollDeviceToken polls the token endpoint at the server-provided interval
+// until the user authorizes the device, declines, or the code expires.
+func (f *Flow) PollDeviceToken(ctx context.Context, code *DeviceCode) (*Token, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDeviceInterval
+	}
+
+	data := url.Values{}
+	data.Set("client_id", f.config.ClientID)
+	f.setSecret(data)
+	data.Set("device_code", code.DeviceCode)
+	data.Set("grant_type", deviceGrantType)
+	if f.config.ScopeParam != "" {
+		data.Set(f.config.ScopeParam, f.config.Scope)
+	}
+
+	for {
+		if err := f.sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		status, body, err := f.postForm(ctx, f.config.TokenURL, data)
+		if err != nil {
+			return nil, err
+		}
+		if status == http.StatusOK {
+			var token Token
+			if err := json.Unmarshal(body, &token); err != nil {
+				return nil, fmt.Errorf("failed to parse response: %w", err)
+			}
+			if token.ExpiresIn > 0 {
+				token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+			}
+			return &token, nil
+		}
+
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &oauthErr)
+		switch oauthErr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownIncrement
+		case "access_denied":
+			return nil, ErrDeviceAuthDenied
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, fmt.Errorf("device token request failed: status %d %s", status, oauthErr.Error)
+		}
+	}
+}
+
+func (f *Flow) scopeParam() string {
+	if f.config.ScopeParam != "" {
+		return f.config.ScopeParam
+	}
+	return "scope"
+}
+
+func (f *Flow) postForm(ctx context.Context, endpoint string, data url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// deviceServer answers the device authorization endpoint and replies to
+// token polls with the given OAuth error codes before issuing a token.
+func deviceServer(t *testing.T, pollErrors ...string) *httptest.Server {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/device/code" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"device_code":      "dev-123",
+				"user_code":        "ABCD-EFGH",
+				"verification_url": "https://www.google.com/device",
+				"expires_in":       1800,
+				"interval":         5,
+			})
+			return
+		}
+		if r.FormValue("grant_type") != deviceGrantType || r.FormValue("device_code") != "dev-123" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if polls < len(pollErrors) {
+			polls++
+			w.WriteHeader(http.StatusPreconditionRequired)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": pollErrors[polls-1]})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "ya29.device-access",
+			"refresh_token": "1//device-refresh",
+			"expires_in":    3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func deviceFlow(server *httptest.Server, waits *[]time.Duration) *Flow {
+	flow := NewFlow(Config{ClientID: "id", TokenURL: server.URL + "/token", DeviceAuthURL: server.URL + "/device/code"})
+	flow.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return flow
+}
+
+func TestDeviceFlow_UserAuthorizesFromAnotherDevice(t *testing.T) {
+	var waits []time.Duration
+	flow := deviceFlow(deviceServer(t, "authorization_pending", "slow_down"), &waits)
+
+	code, err := flow.RequestDeviceCode(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code.UserCode != "ABCD-EFGH" || code.VerificationURL != "https://www.google.com/device" {
+		t.Errorf("user should be shown a code and URL to open elsewhere, got %+v", code)
+	}
+
+	token, err := flow.PollDeviceToken(context.Background(), code)
+	if err != nil {
+		t.Fatalf("polling should succeed once the user authorizes, got: %v", err)
+	}
+	if token.RefreshToken != "1//device-refresh" || token.Expiry.IsZero() {
+		t.Errorf("user should receive a refresh token, got %+v", token)
+	}
+
+	want := []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}
+	if len(waits) != len(want) || waits[0] != want[0] || waits[2] != want[2] {
+		t.Errorf("polling should honor the interval and back off on slow_down, got %v", waits)
+	}
+}
+
+func TestDeviceFlow_ReportsDenialAndExpiry(t *testing.T) {
+	for pollErr, want := range map[string]error{"access_denied": ErrDeviceAuthDenied, "expired_token": ErrDeviceCodeExpired} {
+		var waits []time.Duration
+		flow := deviceFlow(deviceServer(t, pollErr), &waits)
+		code, _ := flow.RequestDeviceCode(context.Background())
+		if _, err := flow.PollDeviceToken(context.Background(), code); !errors.Is(err, want) {
+			t.Errorf("%s should surface as %v, got %v", pollErr, want, err)
+		}
+	}
+}
+
+func TestDeviceFlow_PublicClientWithOwnScopeParameter(t *testing.T) {
+	var forms []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/device" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"device_code": "dev", "user_code": "CODE", "verification_uri": "https://www.twitch.tv/activate", "expires_in": 1800})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "refresh_token": "refresh", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	config := TwitchOAuthConfig("id", "")
+	config.DeviceAuthURL, config.TokenURL = server.URL+"/device", server.URL+"/token"
+	flow := NewFlow(config)
+	flow.sleep = func(context.Context, time.Duration) error { return nil }
+	code, err := flow.RequestDeviceCode(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := flow.PollDeviceToken(context.Background(), code); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, form := range forms {
+		if form.Get("scopes") != "user:read:follows" || form.Has("scope") || form.Has("client_secret") {
+			t.Errorf("requests should carry the scopes parameter and no secret, got %v", form)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrKeyringUnavailable is returned when the OS keyring cannot be used on this system.
+var ErrKeyringUnavailable = errors.New("OS keyring unavailable")
+
+// TokenStore persists OAuth tokens per provider.
+// TokenStorage (files) and KeyringStorage (OS keyring) implement it.
+type TokenStore interface {
+	Save(provider string, token *Token) error
+	Load(provider string) (*Token, error)
+}
+
+// keyring stores one secret per (service, account) pair.
+// Implementations return ErrTokenNotFound for a missing entry.
+type keyring interface {
+	get(service, account string) (string, error)
+	set(service, account, secret string) error
+}
+
+// KeyringStorage stores tokens in the OS keyring: macOS Keychain,
+// the Secret Service on Linux (via secret-tool) or Windows Credential Manager.
+type KeyringStorage struct {
+	service string
+	backend keyring
+}
+
+// NewKeyringStorage creates a KeyringStorage whose entries are filed under service.
+func NewKeyringStorage(service string) *KeyringStorage {
+	return &KeyringStorage{service: service, backend: systemKeyring{}}
+}
+
+func (s *KeyringStorage) Save(provider string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+	if err := s.backend.set(s.service, provider, string(data)); err != nil {
+		return fmt.Errorf("failed to save token to keyring: %w", err)
+	}
+	return nil
+}
+
+func (s *KeyringStorage) Load(provider string) (*Token, error) {
+	secret, err := s.backend.get(s.service, provider)
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token from keyring: %w", err)
+	}
+
+	var token Token
+	if err := json.Unmarshal([]byte(secret), &token); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+	return &token, nil
+}
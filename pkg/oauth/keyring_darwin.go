@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemKeyring uses the macOS Keychain through the security(1) tool.
+// Secrets are passed on stdin (security -i) so they never appear in argv,
+// and base64-encoded so they need no shell-style quoting.
+type systemKeyring struct{}
+
+// errSecItemNotFound is the exit status of security(1) for a missing item.
+const errSecItemNotFound = 44
+
+func (systemKeyring) get(service, account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output() // #nosec G204 -- fixed binary, arguments are not shell-interpreted
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return "", ErrTokenNotFound
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", ErrKeyringUnavailable
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("unexpected keychain entry: %w", err)
+	}
+	return string(secret), nil
+}
+
+func (systemKeyring) set(service, account, secret string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		service, account, base64.StdEncoding.EncodeToString([]byte(secret))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return ErrKeyringUnavailable
+		}
+		return fmt.Errorf("security: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemKeyring uses the freedesktop Secret Service (GNOME Keyring, KWallet)
+// through secret-tool(1), which reads the secret from stdin.
+type systemKeyring struct{}
+
+func (systemKeyring) get(service, account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output() // #nosec G204 -- fixed binary, arguments are not shell-interpreted
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", ErrKeyringUnavailable
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 && len(exitErr.Stderr) == 0 {
+		return "", ErrTokenNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (systemKeyring) set(service, account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+account, "service", service, "account", account) // #nosec G204 -- fixed binary, arguments are not shell-interpreted
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return ErrKeyringUnavailable
+		}
+		return fmt.Errorf("secret-tool: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
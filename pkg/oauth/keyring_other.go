@@ -0,0 +1,10 @@
+//go:build !darwin && !linux && !windows
+
+package oauth
+
+// systemKeyring reports the keyring as unavailable on platforms without a supported backend.
+type systemKeyring struct{}
+
+func (systemKeyring) get(string, string) (string, error) { return "", ErrKeyringUnavailable }
+
+func (systemKeyring) set(string, string, string) error { return ErrKeyringUnavailable }
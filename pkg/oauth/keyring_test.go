@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeKeyring map[string]string
+
+func (k fakeKeyring) get(service, account string) (string, error) {
+	secret, ok := k[service+"/"+account]
+	if !ok {
+		return "", ErrTokenNotFound
+	}
+	return secret, nil
+}
+
+func (k fakeKeyring) set(service, account, secret string) error {
+	k[service+"/"+account] = secret
+	return nil
+}
+
+func TestKeyringStorage_PersistsTokensBetweenSessions(t *testing.T) {
+	backend := fakeKeyring{}
+	storage := &KeyringStorage{service: "feedmix", backend: backend}
+
+	if err := storage.Save("youtube", &Token{RefreshToken: "1//user-refresh-token"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := backend["feedmix/youtube"]; !ok {
+		t.Error("token should be filed under the service and provider")
+	}
+
+	token, err := storage.Load("youtube")
+	if err != nil {
+		t.Fatalf("user should be able to reuse the keyring token, got: %v", err)
+	}
+	if token.RefreshToken != "1//user-refresh-token" {
+		t.Errorf("refresh token should round-trip, got %q", token.RefreshToken)
+	}
+}
+
+func TestKeyringStorage_ReturnsErrTokenNotFound(t *testing.T) {
+	storage := &KeyringStorage{service: "feedmix", backend: fakeKeyring{}}
+	if _, err := storage.Load("youtube"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("missing entry should be ErrTokenNotFound, got: %v", err)
+	}
+}
+
+var _ TokenStore = (*TokenStorage)(nil)
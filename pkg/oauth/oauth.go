@@ -12,21 +12,43 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 var ErrTokenNotFound = errors.New("token not found")
 
 type Config struct {
-	ClientID     string
-	ClientSecret string // #nosec G117 - JSON field for OAuth config, not an exposed secret
-	TokenURL     string
+	ClientID      string
+	ClientSecret  string // #nosec G117 - JSON field for OAuth config, not an exposed secret
+	TokenURL      string
+	DeviceAuthURL string
+	Scope         string
+	// ScopeParam names the parameter device authorization sends Scope in;
+	// "scope" when empty.
+	ScopeParam string
 }
 
 func YouTubeOAuthConfig(clientID, clientSecret string) Config {
 	return Config{ // #nosec G101 -- OAuth URLs are public API endpoints, not hardcoded credentials
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		TokenURL:     "https://oauth2.googleapis.com/token",
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		TokenURL:      "https://oauth2.googleapis.com/token",
+		DeviceAuthURL: "https://oauth2.googleapis.com/device/code",
+		Scope:         "https://www.googleapis.com/auth/youtube.readonly",
+	}
+}
+
+// TwitchOAuthConfig returns the configuration of a Twitch application, which
+// may be a public client without a secret. Device authorization at Twitch
+// takes scopes in a "scopes" parameter.
+func TwitchOAuthConfig(clientID, clientSecret string) Config {
+	return Config{ // #nosec G101 -- OAuth URLs are public API endpoints, not hardcoded credentials
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		TokenURL:      "https://id.twitch.tv/oauth2/token",
+		DeviceAuthURL: "https://id.twitch.tv/oauth2/device",
+		Scope:         "user:read:follows",
+		ScopeParam:    "scopes",
 	}
 }
 
@@ -35,6 +57,8 @@ type Token struct {
 	RefreshToken string `json:"refresh_token"` // #nosec G117 - JSON field for OAuth token, not an exposed secret
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
+	// Expiry is computed from ExpiresIn when the token is issued.
+	Expiry time.Time `json:"expiry,omitempty"`
 }
 
 type HTTPClient interface {
@@ -44,6 +68,7 @@ type HTTPClient interface {
 type Flow struct {
 	config     Config
 	httpClient HTTPClient
+	sleep      func(ctx context.Context, d time.Duration) error
 }
 
 type FlowOption func(*Flow)
@@ -53,7 +78,7 @@ func WithHTTPClient(client HTTPClient) FlowOption {
 }
 
 func NewFlow(config Config, opts ...FlowOption) *Flow {
-	f := &Flow{config: config, httpClient: http.DefaultClient}
+	f := &Flow{config: config, httpClient: http.DefaultClient, sleep: sleepContext}
 	for _, opt := range opts {
 		opt(f)
 	}
@@ -64,7 +89,7 @@ func (f *Flow) RefreshAccessToken(ctx context.Context, refreshToken string) (*To
 	data := url.Values{}
 	data.Set("refresh_token", refreshToken)
 	data.Set("client_id", f.config.ClientID)
-	data.Set("client_secret", f.config.ClientSecret)
+	f.setSecret(data)
 	data.Set("grant_type", "refresh_token")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.config.TokenURL, strings.NewReader(data.Encode()))
@@ -92,10 +117,21 @@ func (f *Flow) RefreshAccessToken(ctx context.Context, refreshToken string) (*To
 	if err := json.Unmarshal(body, &token); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
 
 	return &token, nil
 }
 
+// setSecret adds the client secret to data, unless the client is public
+// and has none.
+func (f *Flow) setSecret(data url.Values) {
+	if f.config.ClientSecret != "" {
+		data.Set("client_secret", f.config.ClientSecret)
+	}
+}
+
 type TokenStorage struct {
 	dir string
 }
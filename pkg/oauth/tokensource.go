@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// expiryMargin refreshes tokens slightly early so a request started just
+// before expiry does not reach the API with a stale token.
+const expiryMargin = time.Minute
+
+// TokenSource supplies an access token that is valid for the next request.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+type staticTokenSource struct {
+	token *Token
+}
+
+// StaticTokenSource always returns token; it never refreshes.
+func StaticTokenSource(token *Token) TokenSource {
+	return staticTokenSource{token: token}
+}
+
+func (s staticTokenSource) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// RefreshingTokenSource refreshes the access token with the refresh token
+// once it expires, keeping any refresh token the server rotates in. It is
+// safe for concurrent use: concurrent callers share one refresh.
+type RefreshingTokenSource struct {
+	mu       sync.Mutex
+	flow     *Flow
+	token    Token
+	store    TokenStore
+	provider string
+	now      func() time.Time
+}
+
+// TokenSourceOption configures a RefreshingTokenSource.
+type TokenSourceOption func(*RefreshingTokenSource)
+
+// WithTokenStore saves every refreshed token to store under provider,
+// so the next run can reuse it until it expires.
+func WithTokenStore(store TokenStore, provider string) TokenSourceOption {
+	return func(s *RefreshingTokenSource) {
+		s.store = store
+		s.provider = provider
+	}
+}
+
+// NewRefreshingTokenSource creates a RefreshingTokenSource starting from token,
+// which must carry a refresh token. An access token without an expiry is
+// treated as expired.
+func NewRefreshingTokenSource(flow *Flow, token *Token, opts ...TokenSourceOption) *RefreshingTokenSource {
+	s := &RefreshingTokenSource{flow: flow, token: *token, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Token returns the current access token, refreshing it first if it has expired.
+func (s *RefreshingTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.AccessToken != "" && s.now().Add(expiryMargin).Before(s.token.Expiry) {
+		token := s.token
+		return &token, nil
+	}
+
+	refreshed, err := s.flow.RefreshAccessToken(ctx, s.token.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = s.token.RefreshToken
+	}
+	s.token = *refreshed
+
+	if s.store != nil {
+		if err := s.store.Save(s.provider, refreshed); err != nil {
+			return nil, fmt.Errorf("failed to persist refreshed token: %w", err)
+		}
+	}
+
+	token := s.token
+	return &token, nil
+}
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func tokenServer(t *testing.T, rotatedRefreshToken string) (*httptest.Server, *int32) {
+	t.Helper()
+	var refreshes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&refreshes, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("ya29.access-%d", n),
+			"refresh_token": rotatedRefreshToken,
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &refreshes
+}
+
+type memoryStore map[string]*Token
+
+func (m memoryStore) Save(provider string, token *Token) error { m[provider] = token; return nil }
+
+func (m memoryStore) Load(provider string) (*Token, error) {
+	if token, ok := m[provider]; ok {
+		return token, nil
+	}
+	return nil, ErrTokenNotFound
+}
+
+func TestRefreshingTokenSource_RefreshesOnlyWhenExpired(t *testing.T) {
+	server, refreshes := tokenServer(t, "")
+	source := NewRefreshingTokenSource(NewFlow(Config{TokenURL: server.URL}), &Token{RefreshToken: "1//refresh"})
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	first, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("token without access token should be refreshed, got: %v", err)
+	}
+	second, _ := source.Token(context.Background())
+	if *refreshes != 1 || second.AccessToken != first.AccessToken {
+		t.Errorf("a valid access token should be reused, got %d refreshes", *refreshes)
+	}
+
+	now = now.Add(time.Hour)
+	third, _ := source.Token(context.Background())
+	if *refreshes != 2 || third.AccessToken == first.AccessToken {
+		t.Errorf("an expired access token should be refreshed, got %d refreshes", *refreshes)
+	}
+	if third.RefreshToken != "1//refresh" {
+		t.Errorf("refresh token should be kept when the server does not rotate it, got %q", third.RefreshToken)
+	}
+}
+
+func TestRefreshingTokenSource_PersistsRotatedTokens(t *testing.T) {
+	server, _ := tokenServer(t, "1//rotated")
+	store := memoryStore{}
+	source := NewRefreshingTokenSource(NewFlow(Config{TokenURL: server.URL}), &Token{RefreshToken: "1//original"}, WithTokenStore(store, "youtube"))
+
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	saved, err := store.Load("youtube")
+	if err != nil {
+		t.Fatalf("refreshed token should be persisted, got: %v", err)
+	}
+	if saved.RefreshToken != "1//rotated" || saved.Expiry.IsZero() {
+		t.Errorf("persisted token should carry the rotated refresh token and an expiry, got %+v", saved)
+	}
+}
+
+func TestRefreshingTokenSource_ConcurrentCallersShareOneRefresh(t *testing.T) {
+	server, refreshes := tokenServer(t, "")
+	source := NewRefreshingTokenSource(NewFlow(Config{TokenURL: server.URL}), &Token{RefreshToken: "1//refresh"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = source.Token(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if *refreshes != 1 {
+		t.Errorf("concurrent requests should trigger a single refresh, got %d", *refreshes)
+	}
+}